@@ -0,0 +1,107 @@
+// completion.go
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// Completion scripts for the supported shells. They only complete the
+// subcommands and their fixed arguments/flags.
+const bashCompletion = `_iqfetch() {
+    local cur prev
+    cur="${COMP_WORDS[COMP_CWORD]}"
+    prev="${COMP_WORDS[COMP_CWORD-1]}"
+    case "$prev" in
+        list) COMPREPLY=($(compgen -W "apps orgs --json" -- "$cur")); return ;;
+        completion) COMPREPLY=($(compgen -W "bash zsh fish" -- "$cur")); return ;;
+        config) COMPREPLY=($(compgen -W "--json" -- "$cur")); return ;;
+        version) COMPREPLY=($(compgen -W "--server" -- "$cur")); return ;;
+    esac
+    if [ "$COMP_CWORD" -eq 1 ]; then
+        COMPREPLY=($(compgen -W "run list history enrich waive merge lifecycle config completion version" -- "$cur"))
+    elif [ "${COMP_WORDS[1]}" = "list" ]; then
+        COMPREPLY=($(compgen -W "apps orgs --json" -- "$cur"))
+    elif [ "${COMP_WORDS[1]}" = "run" ]; then
+        COMPREPLY=($(compgen -W "--profile --quiet --app --report-id --oneshot --result-file --run-id --triggered-by --reason --as-of" -- "$cur"))
+    elif [ "${COMP_WORDS[1]}" = "enrich" ]; then
+        COMPREPLY=($(compgen -W "--dir" -- "$cur"))
+    elif [ "${COMP_WORDS[1]}" = "waive" ]; then
+        COMPREPLY=($(compgen -W "--apply" -f -- "$cur"))
+    elif [ "${COMP_WORDS[1]}" = "merge" ]; then
+        COMPREPLY=($(compgen -W "-o" -f -- "$cur"))
+    elif [ "${COMP_WORDS[1]}" = "lifecycle" ]; then
+        COMPREPLY=($(compgen -W "--dir -o" -f -- "$cur"))
+    fi
+}
+complete -F _iqfetch iqfetch
+`
+
+const zshCompletion = `#compdef iqfetch
+_iqfetch() {
+    local -a commands
+    commands=('run:generate the policy violation report' 'list:list applications or organizations' 'history:export the scan timeline of applications' 'enrich:build the enrichment snapshots for offline use' 'waive:create waivers in bulk from a reviewed file' 'merge:combine partial reports into one' 'lifecycle:report violations opened and closed per month across runs' 'config:print the effective configuration' 'completion:print a shell completion script' 'version:print build and IQ Server version information')
+    if (( CURRENT == 2 )); then
+        _describe 'command' commands
+        return
+    fi
+    case "$words[2]" in
+        run) _arguments '--profile[write CPU and heap profiles]:directory:_files -/' '--quiet[only print the report path or errors]' '--app[application public ID]:app:' '--report-id[report ID to export]:report:' '--oneshot[write a result file and exit with a code per failure category]' '--result-file[result file of --oneshot]:file:_files' '--run-id[ID of this run]:id:' '--triggered-by[user or system that triggered the run]:user:' '--reason[why the run was triggered]:reason:' '--as-of[export the reports that were the latest at this date]:date:' ;;
+        list) _values 'list' apps orgs --json ;;
+        enrich) _arguments '--dir[directory of the snapshots]:directory:_files -/' '*:app:' ;;
+        waive) _arguments '--apply[create the waivers instead of a dry run]' '1:file:_files' ;;
+        merge) _arguments '-o[path of the merged report]:file:_files' '*:report:_files -g "*.csv"' ;;
+        lifecycle) _arguments '--dir[directory of past runs]:directory:_files -/' '-o[path of the lifecycle report]:file:_files' ;;
+        config) _arguments '--json[print JSON]' ;;
+        completion) _values 'shell' bash zsh fish ;;
+        version) _arguments '--server[query the IQ Server version and check compatibility]' ;;
+    esac
+}
+compdef _iqfetch iqfetch
+`
+
+const fishCompletion = `complete -c iqfetch -f
+complete -c iqfetch -n '__fish_use_subcommand' -a 'run list history enrich waive merge lifecycle config completion version'
+complete -c iqfetch -n '__fish_seen_subcommand_from run' -l profile -r -d 'write CPU and heap profiles'
+complete -c iqfetch -n '__fish_seen_subcommand_from run' -l quiet -d 'only print the report path or errors'
+complete -c iqfetch -n '__fish_seen_subcommand_from run' -l app -r -d 'application public ID'
+complete -c iqfetch -n '__fish_seen_subcommand_from run' -l report-id -r -d 'report ID to export'
+complete -c iqfetch -n '__fish_seen_subcommand_from run' -l oneshot -d 'write a result file and exit with a code per failure category'
+complete -c iqfetch -n '__fish_seen_subcommand_from run' -l result-file -r -F -d 'result file of --oneshot'
+complete -c iqfetch -n '__fish_seen_subcommand_from run' -l run-id -r -d 'ID of this run'
+complete -c iqfetch -n '__fish_seen_subcommand_from run' -l triggered-by -r -d 'user or system that triggered the run'
+complete -c iqfetch -n '__fish_seen_subcommand_from run' -l reason -r -d 'why the run was triggered'
+complete -c iqfetch -n '__fish_seen_subcommand_from run' -l as-of -r -d 'export the reports that were the latest at this date'
+complete -c iqfetch -n '__fish_seen_subcommand_from list' -a 'apps orgs'
+complete -c iqfetch -n '__fish_seen_subcommand_from list' -l json -d 'print JSON'
+complete -c iqfetch -n '__fish_seen_subcommand_from enrich' -l dir -r -F -d 'directory of the snapshots'
+complete -c iqfetch -n '__fish_seen_subcommand_from waive' -F
+complete -c iqfetch -n '__fish_seen_subcommand_from waive' -l apply -d 'create the waivers instead of a dry run'
+complete -c iqfetch -n '__fish_seen_subcommand_from merge' -F
+complete -c iqfetch -n '__fish_seen_subcommand_from merge' -s o -r -F -d 'path of the merged report'
+complete -c iqfetch -n '__fish_seen_subcommand_from lifecycle' -l dir -r -F -d 'directory of past runs'
+complete -c iqfetch -n '__fish_seen_subcommand_from lifecycle' -s o -r -F -d 'path of the lifecycle report'
+complete -c iqfetch -n '__fish_seen_subcommand_from config' -l json -d 'print JSON'
+complete -c iqfetch -n '__fish_seen_subcommand_from completion' -a 'bash zsh fish'
+complete -c iqfetch -n '__fish_seen_subcommand_from version' -l server -d 'query the IQ Server version and check compatibility'
+`
+
+// runCompletion prints the completion script for the requested shell.
+func runCompletion(args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "usage: iqfetch completion bash|zsh|fish") //nolint:errcheck
+		return 2
+	}
+	switch args[0] {
+	case "bash":
+		fmt.Print(bashCompletion)
+	case "zsh":
+		fmt.Print(zshCompletion)
+	case "fish":
+		fmt.Print(fishCompletion)
+	default:
+		fmt.Fprintf(os.Stderr, "unsupported shell %q (expected bash, zsh or fish)\n", args[0]) //nolint:errcheck
+		return 2
+	}
+	return 0
+}
@@ -0,0 +1,39 @@
+// config.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/config"
+)
+
+// runConfig implements "config [--json]": it prints the effective value of
+// every setting, after defaults, config/.env and the environment are
+// applied, and where it came from. Secrets are masked.
+func runConfig(args []string) int {
+	fs := flag.NewFlagSet("config", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "print machine-readable JSON instead of a table")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "FATAL: load config: %v\n", err) //nolint:errcheck
+		return 1
+	}
+	settings := cfg.Settings()
+	if *asJSON {
+		return printJSON(os.Stdout, settings)
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "SETTING\tVALUE\tSOURCE") //nolint:errcheck
+	for _, s := range settings {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", s.Env, s.Value, s.Source) //nolint:errcheck
+	}
+	_ = tw.Flush()
+	return 0
+}
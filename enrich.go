@@ -0,0 +1,62 @@
+// enrich.go
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/services"
+	"github.com/rs/zerolog/log"
+)
+
+// defaultEnrichmentDir is where "enrich" writes the snapshots unless --dir or
+// ENRICHMENT_DIR says otherwise.
+const defaultEnrichmentDir = "enrichment"
+
+// runEnrich implements "enrich [--dir DIR] [appPublicId...]": it builds or
+// updates the enrichment snapshots on a connected machine, to be copied to
+// air-gapped deployments reading them with ENRICHMENT_DIR.
+func runEnrich(args []string) int {
+	fs := flag.NewFlagSet("enrich", flag.ContinueOnError)
+	dir := fs.String("dir", "", "directory of the snapshots (default: ENRICHMENT_DIR, or "+defaultEnrichmentDir+")")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: iqfetch enrich [--dir DIR] [appPublicId...]") //nolint:errcheck
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg, pool, cleanup, err := setup(os.Stdout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "FATAL: %v\n", err) //nolint:errcheck
+		return 1
+	}
+	defer cleanup()
+	if *dir == "" {
+		*dir = cfg.EnrichmentDir
+	}
+	if *dir == "" {
+		*dir = defaultEnrichmentDir
+	}
+	// The snapshots are built from IQ Server, never from older snapshots
+	cfg.EnrichmentDir = ""
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	reportService := services.NewIQReportServiceWithPool(cfg, pool, log.Logger)
+	paths, err := reportService.BuildEnrichmentSnapshots(ctx, *dir, fs.Args())
+	for _, p := range paths {
+		fmt.Printf("Wrote snapshot: %s\n", filepath.Clean(p)) //nolint:errcheck
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("enrichment snapshot failed")
+		return 1
+	}
+	return 0
+}
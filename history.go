@@ -0,0 +1,47 @@
+// history.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/services"
+	"github.com/rs/zerolog/log"
+)
+
+// runHistory implements "history [appPublicId...]": it writes the scan
+// timeline of the given applications, or of all applications, to
+// OutputDir/history/.
+func runHistory(args []string) int {
+	fs := flag.NewFlagSet("history", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: iqfetch history [appPublicId...]") //nolint:errcheck
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg, pool, cleanup, err := setup(os.Stdout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "FATAL: %v\n", err) //nolint:errcheck
+		return 1
+	}
+	defer cleanup()
+
+	ctx, cancel := fetchContext(cfg)
+	defer cancel()
+
+	reportService := services.NewIQReportServiceWithPool(cfg, pool, log.Logger)
+	paths, err := reportService.ExportReportHistory(ctx, fs.Args())
+	for _, p := range paths {
+		fmt.Printf("Wrote history: %s\n", filepath.Clean(p)) //nolint:errcheck
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("report history export failed")
+		return 1
+	}
+	return 0
+}
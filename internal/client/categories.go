@@ -0,0 +1,46 @@
+// internal/client/categories.go
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// ApplicationTag assigns an application category to an application.
+type ApplicationTag struct {
+	TagID string `json:"tagId"`
+}
+
+// ApplicationCategory is an application category (tag) defined in an
+// organization. Applications of the organization and its descendants can be
+// tagged with it.
+type ApplicationCategory struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// GetApplicationCategories fetches the application categories defined in the
+// organization with the given ID.
+func (c *Client) GetApplicationCategories(ctx context.Context, orgID string) ([]ApplicationCategory, error) {
+	c.logger.Debug().Str("orgId", orgID).Msg("Fetching application categories")
+
+	endpoint := fmt.Sprintf("applicationCategories/organization/%s", url.PathEscape(orgID))
+	var categories []ApplicationCategory
+	resp, err := c.request(ctx, "applicationCategories/organization/{id}").
+		SetResult(&categories).
+		Get(endpoint)
+	if err != nil {
+		return nil, transportError(err)
+	}
+	if resp.IsError() {
+		return nil, httpError(resp, resp.Status())
+	}
+	if err := checkJSON(resp); err != nil {
+		return nil, err
+	}
+
+	c.logger.Debug().Int("count", len(categories)).Str("orgId", orgID).Msg("Retrieved application categories")
+	return categories, nil
+}
@@ -3,9 +3,11 @@ package client
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
-	"path"
 	"strings"
 	"time"
 
@@ -21,6 +23,13 @@ type Client struct {
 	baseURL    string
 	logger     zerolog.Logger
 	httpClient *resty.Client
+	timings    *timingStats
+	transfer   *transferStats
+
+	bodyLogging   BodyLogging
+	readOnly      bool
+	responseLimit ResponseLimit
+	closer        closer
 }
 
 // =================================================================
@@ -30,9 +39,10 @@ type Client struct {
 // Application represents a single application returned by IQ Server.
 // Application describes a single IQ Server application record returned by the API.
 type Application struct {
-	ID             string `json:"id"`
-	PublicID       string `json:"publicId"`
-	OrganizationID string `json:"organizationId"`
+	ID              string           `json:"id"`
+	PublicID        string           `json:"publicId"`
+	OrganizationID  string           `json:"organizationId"`
+	ApplicationTags []ApplicationTag `json:"applicationTags,omitempty"`
 }
 
 type applicationsEnvelope struct {
@@ -53,8 +63,39 @@ type organizationsEnvelope struct {
 // ReportInfo contains metadata about an application's latest report.
 // ReportInfo describes metadata for a report (most recent report for an application).
 type ReportInfo struct {
-	Stage         string `json:"stage"`
-	ReportHTMLURL string `json:"reportHtmlUrl"`
+	Stage          string `json:"stage"`
+	EvaluationDate string `json:"evaluationDate"`
+	ReportHTMLURL  string `json:"reportHtmlUrl"`
+	// ReportDataURL is the API location of the report data, e.g.
+	// "api/v2/applications/{publicId}/reports/{reportId}". IQ Server returns
+	// it relative to the server root; absolute URLs are accepted as well.
+	ReportDataURL string `json:"reportDataUrl"`
+	// ReportPDFURL is the UI location of the PDF rendering of the report,
+	// relative to the server root.
+	ReportPDFURL string `json:"reportPdfUrl"`
+}
+
+// ReportID returns the ID of the report. It is taken from ReportDataURL
+// (absolute or relative) when present and otherwise parsed from the part of
+// ReportHTMLURL after "/report/". An error wrapping ErrParse is returned when
+// neither URL yields an ID.
+func (ri ReportInfo) ReportID() (string, error) {
+	if raw := strings.TrimSpace(ri.ReportDataURL); raw != "" {
+		if u, err := url.Parse(raw); err == nil {
+			segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+			if n := len(segments); n >= 2 && segments[n-2] == "reports" && segments[n-1] != "" {
+				return segments[n-1], nil
+			}
+		}
+	}
+
+	// Fallback for servers that only return the HTML report URL
+	if _, id, found := strings.Cut(ri.ReportHTMLURL, "/report/"); found {
+		if id, _, _ = strings.Cut(id, "?"); strings.Trim(id, "/") != "" {
+			return strings.Trim(id, "/"), nil
+		}
+	}
+	return "", parseError("no report ID in reportDataUrl %q or reportHtmlUrl %q", ri.ReportDataURL, ri.ReportHTMLURL)
 }
 
 // =================================================================
@@ -63,7 +104,10 @@ type ReportInfo struct {
 
 // Condition is the lowest level detail within a constraint.
 type Condition struct {
-	ConditionSummary string `json:"conditionSummary"`
+	ConditionSummary string          `json:"conditionSummary"`
+	ConditionReason  string          `json:"conditionReason"` // e.g. "Found security vulnerability CVE-2019-10086 with severity >= 7"
+	Reference        *Reference      `json:"reference,omitempty"`
+	Exploitability   *Exploitability `json:"exploitability,omitempty"`
 }
 
 // Constraint is a group of conditions within a policy violation.
@@ -74,9 +118,13 @@ type Constraint struct {
 
 // Violation details a specific policy break for a component.
 type Violation struct {
-	PolicyName        string       `json:"policyName"`
-	PolicyThreatLevel float64      `json:"policyThreatLevel"` // IQ Server returns numeric fields as float64
-	Constraints       []Constraint `json:"constraints"`
+	PolicyViolationID    string       `json:"policyViolationId"`
+	Waived               bool         `json:"waived"`
+	PolicyName           string       `json:"policyName"`
+	PolicyThreatLevel    float64      `json:"policyThreatLevel"`    // IQ Server returns numeric fields as float64
+	PolicyThreatCategory string       `json:"policyThreatCategory"` // SECURITY, LICENSE, QUALITY or OTHER
+	OpenTime             string       `json:"openTime"`             // set with includeViolationTimes
+	Constraints          []Constraint `json:"constraints"`
 }
 
 type ComponentIdentifier struct {
@@ -85,9 +133,61 @@ type ComponentIdentifier struct {
 
 // Component is a library/asset with associated violations.
 type Component struct {
-	DisplayName         string      `json:"displayName"`
-	Violations          []Violation `json:"violations"`
-	ComponentIdentifier `json:"componentIdentifier"`
+	DisplayName          string          `json:"displayName"`
+	Hash                 string          `json:"hash"` // SHA-1 prefix identifying the artifact
+	Proprietary          bool            `json:"proprietary"`
+	MatchState           string          `json:"matchState"`           // exact, similar, unknown or innersource
+	IdentificationSource string          `json:"identificationSource"` // Sonatype, or Manual for claimed components
+	Labels               ComponentLabels `json:"labels"`
+	Violations           []Violation     `json:"violations"`
+	ComponentIdentifier  `json:"componentIdentifier"`
+}
+
+// Internal reports whether the component is one of the organization's own:
+// flagged proprietary, or matched as an InnerSource component.
+func (c Component) Internal() bool {
+	return c.Proprietary || strings.EqualFold(c.MatchState, "innersource")
+}
+
+// Claimed reports whether the component was identified manually (claimed)
+// in IQ Server instead of matched by Sonatype.
+func (c Component) Claimed() bool {
+	return strings.EqualFold(c.IdentificationSource, "manual")
+}
+
+// ComponentLabels are the names of the labels assigned to a component.
+// IQ Server lists them as names or as label objects, depending on the
+// version; both decode to names.
+type ComponentLabels []string
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (l *ComponentLabels) UnmarshalJSON(b []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	labels := make(ComponentLabels, 0, len(raw))
+	for _, item := range raw {
+		var name string
+		if err := json.Unmarshal(item, &name); err != nil {
+			var obj struct {
+				Label string `json:"label"`
+				Name  string `json:"name"`
+			}
+			if err := json.Unmarshal(item, &obj); err != nil {
+				return err
+			}
+			name = obj.Label
+			if name == "" {
+				name = obj.Name
+			}
+		}
+		if name != "" {
+			labels = append(labels, name)
+		}
+	}
+	*l = labels
+	return nil
 }
 
 // PolicyViolationReport is the top-level structure for the policy violations report API.
@@ -99,61 +199,217 @@ type PolicyViolationReport struct {
 // Client Initialization
 // =================================================================
 
+// apiPrefix is the path prefix of the IQ Server REST API used by this client.
+const apiPrefix = "/api/v2"
+
+// Option configures optional Client behaviour in NewClient.
+type Option func(*options)
+
+type options struct {
+	strictBaseURL bool
+	bodyLogging   *BodyLogging
+	transport     http.RoundTripper
+	readOnly      bool
+	responseLimit ResponseLimit
+	hosts         map[string]string
+	localAddr     string
+	sessionAuth   bool
+	headerAuth    HeaderAuth
+	userToken     UserToken
+	retry         RetryPolicy
+	limiter       *RateLimiter
+	pathOverrides map[string]string
+}
+
+// WithStrictBaseURL disables base URL normalization when strict is true: the
+// server URL is then used as given and must already include /api/v2.
+func WithStrictBaseURL(strict bool) Option {
+	return func(o *options) { o.strictBaseURL = strict }
+}
+
+// WithTransport sends requests through rt instead of the default HTTP
+// transport, e.g. to inject faults in tests.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(o *options) { o.transport = rt }
+}
+
 // NewClient creates a new Client configured with credentials and base URL.
 // The provided logger is used for informational and debug output only.
-func NewClient(serverURL, username, password string, logger zerolog.Logger) (*Client, error) {
+// Unless WithStrictBaseURL is set, serverURL may point at the server root or
+// a UI page; the /api/v2 prefix is appended automatically.
+func NewClient(serverURL, username, password string, logger zerolog.Logger, opts ...Option) (*Client, error) {
 	// Defense checks
 	if strings.TrimSpace(serverURL) == "" {
 		return nil, fmt.Errorf("serverURL is required")
 	}
+	// The logger is a struct, so it cannot be nil. No check needed.
+
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.userToken != (UserToken{}) {
+		if o.userToken.UserCode == "" || o.userToken.PassCode == "" {
+			return nil, fmt.Errorf("user token needs a user code and a passcode")
+		}
+		if o.headerAuth.Header != "" {
+			return nil, fmt.Errorf("header and token authentication are exclusive")
+		}
+		username, password = o.userToken.UserCode, o.userToken.PassCode
+	}
 	if username == "" {
 		return nil, fmt.Errorf("username is required")
 	}
-	if password == "" {
+	if password == "" && o.headerAuth.Header == "" {
 		return nil, fmt.Errorf("password is required")
 	}
-	// The logger is a struct, so it cannot be nil. No check needed.
+	if o.headerAuth.Header != "" && o.sessionAuth {
+		return nil, fmt.Errorf("header and session authentication are exclusive")
+	}
 
-	// Expect serverURL to already include /api/v2
 	baseURL := strings.TrimSuffix(serverURL, "/")
 	u, err := url.Parse(baseURL)
 	if err != nil {
 		return nil, fmt.Errorf("invalid baseURL: %w", err)
 	}
-	u.Path = path.Clean(u.Path)
+	// Query parameters of a strict base URL (e.g. required by a gateway) are
+	// sent with every request rather than being embedded in the base URL
+	var baseQuery url.Values
+	if o.strictBaseURL {
+		// Expect serverURL to already include /api/v2; keep the path as given
+		baseQuery = u.Query()
+		setEscapedPath(u, strings.TrimRight(u.EscapedPath(), "/"))
+		u.RawQuery = ""
+		u.Fragment = ""
+	} else {
+		normalizeBaseURL(u)
+	}
 	baseURL = u.String()
 	baseURL = strings.TrimRight(baseURL, "/") + "/"
 
 	r := resty.New().
 		SetBaseURL(baseURL).
-		SetBasicAuth(username, password).
 		SetHeader("Accept", "application/json").
-		SetTimeout(30 * time.Second)
+		SetTimeout(30 * time.Second).
+		EnableTrace()
+	if o.headerAuth.Header != "" {
+		r.SetHeader(o.headerAuth.Header, username).SetHeaders(o.headerAuth.Extra)
+	} else {
+		r.SetBasicAuth(username, password)
+	}
+	if o.transport != nil {
+		r.SetTransport(o.transport)
+	}
+	if o.localAddr != "" {
+		t, err := bindLocalAddress(r.GetClient().Transport, o.localAddr)
+		if err != nil {
+			return nil, err
+		}
+		r.SetTransport(t)
+	}
+	if len(o.hosts) > 0 {
+		t, err := overrideHosts(r.GetClient().Transport, o.hosts)
+		if err != nil {
+			return nil, err
+		}
+		r.SetTransport(t)
+	}
+	if o.limiter != nil {
+		r.SetTransport(rateLimitTransport{next: r.GetClient().Transport, limiter: o.limiter, logger: logger})
+	}
+	if o.sessionAuth {
+		t, err := newSessionTransport(r.GetClient().Transport, baseURL, username, password)
+		if err != nil {
+			return nil, err
+		}
+		r.SetTransport(t)
+	}
+	if o.retry.MaxAttempts > 1 {
+		r.SetTransport(retryTransport{next: r.GetClient().Transport, policy: o.retry, logger: logger})
+	}
+	if o.readOnly {
+		r.SetTransport(readOnlyTransport{next: r.GetClient().Transport})
+	}
+	if o.responseLimit.MaxBytes > 0 {
+		r.SetResponseBodyLimit(o.responseLimit.MaxBytes)
+	}
+	var paths *pathRewriter
+	if len(o.pathOverrides) > 0 {
+		if paths, err = newPathRewriter(o.pathOverrides, baseURL); err != nil {
+			return nil, err
+		}
+	}
+	for key, values := range baseQuery {
+		for _, v := range values {
+			r.QueryParam.Add(key, v)
+		}
+	}
+	timings := newTimingStats()
+	transfer := newTransferStats()
+
+	cl := &Client{
+		baseURL:       baseURL,
+		logger:        logger,
+		httpClient:    r,
+		timings:       timings,
+		transfer:      transfer,
+		bodyLogging:   DefaultBodyLogging,
+		readOnly:      o.readOnly,
+		responseLimit: o.responseLimit,
+	}
+	if o.bodyLogging != nil {
+		cl.bodyLogging = *o.bodyLogging
+	}
 
 	// Resty hooks for logging
 	r.OnBeforeRequest(func(c *resty.Client, req *resty.Request) error {
+		if cl.closer.isClosed() {
+			return ErrClosed
+		}
+		if paths != nil {
+			paths.rewrite(req)
+		}
+		logger := logger
+		if md, ok := MetadataFromContext(req.Context()); ok {
+			req.SetHeaders(md.headers())
+			logger = md.Fields(logger.With()).Logger()
+		}
 		logger.Debug().
 			Str("method", req.Method).
 			Str("url", req.URL).
-			Str("query", req.QueryParam.Encode()).
+			Str("query", redactQuery(req.QueryParam)).
 			Msg("Executing request")
+		logger.Trace().Interface("headers", redactHeaders(req.Header)).Msg("Request headers")
 		return nil
 	})
 	r.OnAfterResponse(func(c *resty.Client, resp *resty.Response) error {
+		ti := resp.Request.TraceInfo()
+		endpoint := endpointFromContext(resp.Request.Context())
+		timings.record(endpoint, ti)
+		transfer.record(endpoint, applicationFromContext(resp.Request.Context()), resp.Size())
+		if fn := rawResponseFromContext(resp.Request.Context()); fn != nil && resp.IsSuccess() {
+			fn(resp.Body())
+		}
+		logger := logger
+		if md, ok := MetadataFromContext(resp.Request.Context()); ok {
+			logger = md.Fields(logger.With()).Logger()
+		}
 		logger.Debug().
 			Int("status", resp.StatusCode()).
 			Str("url", resp.Request.URL).
 			Str("method", resp.Request.Method).
+			Dur("dns", ti.DNSLookup).
+			Dur("connect", ti.ConnTime).
+			Dur("tls", ti.TLSHandshake).
+			Dur("ttfb", ti.ServerTime).
+			Dur("total", ti.TotalTime).
+			Int64("bytes", resp.Size()).
 			Msg("Request completed")
+		logger.Trace().Interface("headers", redactHeaders(resp.Header())).Msg("Response headers")
 		return nil
 	})
 
-	cl := &Client{
-		baseURL:    baseURL,
-		logger:     logger,
-		httpClient: r,
-	}
-	logger.Info().Str("baseURL", baseURL).Msg("Initialized IQServer API client")
+	logger.Info().Str("baseURL", baseURL).Bool("readOnly", o.readOnly).Bool("sessionAuth", o.sessionAuth).Str("authHeader", o.headerAuth.Header).Msg("Initialized IQServer API client")
 	return cl, nil
 }
 
@@ -161,6 +417,12 @@ func NewClient(serverURL, username, password string, logger zerolog.Logger) (*Cl
 // Public Client Methods
 // =================================================================
 
+// request creates a resty request bound to ctx and labelled with the logical
+// endpoint name used for timing aggregation.
+func (c *Client) request(ctx context.Context, endpoint string) *resty.Request {
+	return c.httpClient.R().SetContext(context.WithValue(ctx, endpointKey{}, endpoint))
+}
+
 // GetApplications fetches a list of applications from the IQ Server.
 func (c *Client) GetApplications(ctx context.Context) ([]Application, error) {
 	endpoint := "applications"
@@ -168,47 +430,126 @@ func (c *Client) GetApplications(ctx context.Context) ([]Application, error) {
 	logger.Debug().Msg("Fetching applications")
 
 	var env applicationsEnvelope
-	resp, err := c.httpClient.R().
-		SetContext(ctx).
+	resp, err := c.request(ctx, "applications").
 		SetResult(&env).
 		SetError(&map[string]any{}).
 		Get(endpoint)
 	if err != nil {
+		return nil, transportError(err)
+	}
+
+	c.logBody(resp)
+	if resp.IsError() {
+		return nil, httpError(resp, truncate(resp.String(), maxErrorBody))
+	}
+	if err := checkJSON(resp); err != nil {
 		return nil, err
 	}
+	if env.Applications == nil {
+		return nil, parseError("unexpected response from %s: missing \"applications\" field", endpoint)
+	}
+
+	return env.Applications, nil
+}
+
+// GetApplicationByPublicID looks up a single application by its public ID
+// without listing all applications. An unknown public ID is reported as an
+// ErrNotFound error.
+func (c *Client) GetApplicationByPublicID(ctx context.Context, publicID string) (*Application, error) {
+	const endpoint = "applications"
+	c.logger.Debug().Str("publicId", publicID).Msg("Looking up application")
 
-	c.logger.Debug().Int("status", resp.StatusCode()).Str("body", resp.String()).Msg("raw response")
+	var env applicationsEnvelope
+	resp, err := c.request(ctx, "applications?publicId").
+		SetQueryParam("publicId", publicID).
+		SetResult(&env).
+		Get(endpoint)
+	if err != nil {
+		return nil, transportError(err)
+	}
 	if resp.IsError() {
-		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode(), resp.String())
+		return nil, httpError(resp, resp.Status())
 	}
+	if err := checkJSON(resp); err != nil {
+		return nil, err
+	}
+	if env.Applications == nil {
+		return nil, parseError("unexpected response from %s: missing \"applications\" field", endpoint)
+	}
+	// Guard against servers ignoring the filter
+	for _, app := range env.Applications {
+		if app.PublicID == publicID {
+			return &app, nil
+		}
+	}
+	return nil, notFoundError("application %q not found", publicID)
+}
 
+// GetApplicationsByOrganization fetches the applications of a single
+// organization. On large instances listing per organization avoids a single
+// /applications call that times out.
+func (c *Client) GetApplicationsByOrganization(ctx context.Context, orgID string) ([]Application, error) {
+	endpoint := fmt.Sprintf("applications/organization/%s", url.PathEscape(orgID))
+	c.logger.Debug().Str("orgId", orgID).Msg("Fetching applications of organization")
+
+	var env applicationsEnvelope
+	resp, err := c.request(ctx, "applications/organization/{id}").
+		SetResult(&env).
+		Get(endpoint)
+	if err != nil {
+		return nil, transportError(err)
+	}
+	if resp.IsError() {
+		return nil, httpError(resp, resp.Status())
+	}
+	if err := checkJSON(resp); err != nil {
+		return nil, err
+	}
+	if env.Applications == nil {
+		return nil, parseError("unexpected response from %s: missing \"applications\" field", endpoint)
+	}
 	return env.Applications, nil
 }
 
 // GetLatestReportInfo fetches the metadata for the most recent report for a given internal application ID.
 func (c *Client) GetLatestReportInfo(ctx context.Context, appID string) (*ReportInfo, error) {
+	reports, err := c.GetReportInfos(ctx, appID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(reports) > 0 {
+		r := reports[0]
+		return &r, nil
+	}
+	return nil, nil
+}
+
+// GetReportInfos fetches the metadata of the latest report of every stage
+// (e.g. build, release, operate) for a given internal application ID.
+func (c *Client) GetReportInfos(ctx context.Context, appID string) ([]ReportInfo, error) {
 	endpoint := fmt.Sprintf("reports/applications/%s", appID)
 	var reports []ReportInfo
 
-	resp, err := c.httpClient.R().
-		SetContext(ctx).
+	resp, err := c.request(ctx, "reports/applications/{id}").
 		SetResult(&reports).
 		Get(endpoint)
 	if err != nil {
-		return nil, err
+		return nil, transportError(err)
 	}
 	if resp.IsError() {
-		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode(), resp.Status())
+		return nil, httpError(resp, resp.Status())
+	}
+	if err := checkJSON(resp); err != nil {
+		return nil, err
 	}
 
 	if len(reports) > 0 {
 		c.logger.Debug().Int("count", len(reports)).Str("appId", appID).Msg("Found reports")
-		r := reports[0]
-		return &r, nil
+	} else {
+		c.logger.Debug().Str("appId", appID).Msg("No reports found")
 	}
-
-	c.logger.Debug().Str("appId", appID).Msg("No reports found")
-	return nil, nil
+	return reports, nil
 }
 
 // GetPolicyViolations fetches the detailed policy violation report for a specific application and report ID.
@@ -219,46 +560,172 @@ func (c *Client) GetPolicyViolations(ctx context.Context, publicID, reportID, or
 	params := url.Values{"includeViolationTimes": []string{"true"}}
 
 	var report PolicyViolationReport // Use the explicit struct
-	resp, err := c.httpClient.R().
-		SetContext(ctx).
+	resp, err := c.request(ctx, "applications/{publicId}/reports/{reportId}/policy").
 		SetQueryParamsFromValues(params).
 		SetResult(&report). // Unmarshal directly into struct
 		Get(endpoint)
 	if err != nil {
-		return nil, err
+		if errors.Is(err, resty.ErrResponseBodyTooLarge) {
+			if c.responseLimit.StreamPolicyReports {
+				return c.streamPolicyViolations(ctx, endpoint, params, publicID, orgName)
+			}
+			return nil, &Error{Kind: ErrTooLarge, msg: fmt.Sprintf("report too large: policy report %s of %s exceeds %d bytes", reportID, publicID, c.responseLimit.MaxBytes), err: err}
+		}
+		return nil, transportError(err)
 	}
 	if resp.IsError() {
-		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode(), resp.Status())
+		return nil, httpError(resp, resp.Status())
+	}
+	if err := checkJSON(resp); err != nil {
+		return nil, err
+	}
+	if report.Components == nil {
+		return nil, parseError("unexpected response from %s: missing \"components\" field", endpoint)
 	}
 
 	// Parse and filter to report rows using the structured data
 	return parseReportRows(report, publicID, orgName), nil
 }
 
+// GetPolicyViolationsByReportID fetches the policy violations of a specific
+// report of an application, e.g. a historical scan given by the user, rather
+// than one listed by GetReportInfos. The IDs are escaped as path segments.
+// The rows carry no organization name; callers set it when known.
+func (c *Client) GetPolicyViolationsByReportID(ctx context.Context, publicID, reportID string) ([]report.Row, error) {
+	if strings.TrimSpace(publicID) == "" || strings.TrimSpace(reportID) == "" {
+		return nil, fmt.Errorf("application public ID and report ID are required")
+	}
+	rows, err := c.GetPolicyViolations(ctx, url.PathEscape(publicID), url.PathEscape(reportID), "")
+	if err != nil {
+		return nil, err
+	}
+	for i := range rows {
+		rows[i].Application = publicID
+	}
+	return rows, nil
+}
+
 // GetOrganizations fetches the list of all organizations.
 func (c *Client) GetOrganizations(ctx context.Context) ([]Organization, error) {
 	c.logger.Debug().Msg("Fetching organizations")
 
 	var env organizationsEnvelope
-	resp, err := c.httpClient.R().
-		SetContext(ctx).
+	resp, err := c.request(ctx, "organizations").
 		SetResult(&env).
 		Get("organizations")
 	if err != nil {
-		return nil, err
+		return nil, transportError(err)
 	}
 	if resp.IsError() {
-		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode(), resp.String())
+		return nil, httpError(resp, truncate(resp.String(), maxErrorBody))
+	}
+	if err := checkJSON(resp); err != nil {
+		return nil, err
+	}
+	if env.Organizations == nil {
+		return nil, parseError("unexpected response from organizations: missing \"organizations\" field")
 	}
 
 	c.logger.Debug().Int("count", len(env.Organizations)).Msg("Retrieved organizations")
 	return env.Organizations, nil
 }
 
+// GetOrganization fetches the organization with the given ID, e.g. one
+// referenced by an application but missing from GetOrganizations. An unknown
+// ID is reported as an ErrNotFound error.
+func (c *Client) GetOrganization(ctx context.Context, orgID string) (*Organization, error) {
+	c.logger.Debug().Str("orgId", orgID).Msg("Fetching organization")
+
+	endpoint := fmt.Sprintf("organizations/%s", url.PathEscape(orgID))
+	var org Organization
+	resp, err := c.request(ctx, "organizations/{id}").
+		SetResult(&org).
+		Get(endpoint)
+	if err != nil {
+		return nil, transportError(err)
+	}
+	if resp.IsError() {
+		return nil, httpError(resp, resp.Status())
+	}
+	if err := checkJSON(resp); err != nil {
+		return nil, err
+	}
+	if org.ID == "" {
+		return nil, parseError("unexpected response from %s: missing \"id\" field", endpoint)
+	}
+	return &org, nil
+}
+
 // =================================================================
 // Helper Functions
 // =================================================================
 
+// uiPathMarkers start the IQ Server web UI part of a URL copied from the
+// browser; everything from the marker on is dropped. They are specific
+// enough not to match context paths of reverse proxies.
+var uiPathMarkers = []string{"/assets/index.html", "/ui/links/"}
+
+// normalizeBaseURL rewrites u in place so that its path ends with the API
+// prefix: UI paths and fragments are stripped, any endpoint path after
+// /api/v2 is dropped and the prefix is appended when missing. Context paths
+// in front of the prefix (e.g. /nexus-iq or gateway segments) are preserved
+// as given, including escaped characters.
+func normalizeBaseURL(u *url.URL) {
+	p := u.EscapedPath()
+	for _, marker := range uiPathMarkers {
+		if i := strings.Index(p+"/", marker); i >= 0 {
+			p = p[:i]
+		}
+	}
+	if i := strings.Index(p+"/", apiPrefix+"/"); i >= 0 {
+		p = p[:i]
+	}
+	setEscapedPath(u, strings.TrimRight(p, "/")+apiPrefix)
+	u.Fragment = ""
+	u.RawQuery = ""
+}
+
+// setEscapedPath sets the path of u from its escaped form, so that escaped
+// characters such as %2F within a segment survive u.String().
+func setEscapedPath(u *url.URL, escaped string) {
+	unescaped, err := url.PathUnescape(escaped)
+	if err != nil {
+		unescaped = escaped
+	}
+	u.Path = unescaped
+	u.RawPath = escaped
+}
+
+// checkJSON verifies that a successful response actually carries JSON. Proxy
+// error pages and SSO login redirects are returned with a 2xx status and an
+// HTML body, which would otherwise unmarshal into empty structs silently.
+func checkJSON(resp *resty.Response) error {
+	contentType := strings.ToLower(resp.Header().Get("Content-Type"))
+	switch {
+	case strings.Contains(contentType, "json"):
+		return nil
+	case strings.Contains(contentType, "text/html"):
+		return parseError("got HTML instead of JSON from %s; is IQ_SERVER_URL pointing at the UI URL instead of /api/v2?", resp.Request.URL)
+	default:
+		return parseError("unexpected content type %q from %s, expected JSON", contentType, resp.Request.URL)
+	}
+}
+
+// timeLayout is the timestamp layout used by IQ Server, e.g.
+// "2025-01-31T12:00:00.000+0000".
+const timeLayout = "2006-01-02T15:04:05.000-0700"
+
+// parseTime parses an IQ Server timestamp; empty or unparsable values yield
+// the zero time.
+func parseTime(s string) time.Time {
+	for _, layout := range []string{timeLayout, time.RFC3339} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
 // parseReportRows converts the structured API response into flat report.Row slice.
 func parseReportRows(rawReport PolicyViolationReport, appPublicID string, orgName string) []report.Row {
 	var rows []report.Row
@@ -271,23 +738,36 @@ func parseReportRows(rawReport PolicyViolationReport, appPublicID string, orgNam
 			// Threat level comes as float64, cast to int
 			threat := int(v.PolicyThreatLevel)
 			policyAction := fmt.Sprintf("Security-%d", threat)
+			category := strings.ToLower(v.PolicyThreatCategory)
 			for _, constr := range v.Constraints {
 				constraintName := constr.ConstraintName
 				var condSummaries []string
 				for _, cond := range constr.Conditions {
 					condSummaries = append(condSummaries, cond.ConditionSummary)
 				}
+				kev, maturity, reachable := exploitability(constr.Conditions)
 				rows = append(rows, report.Row{
-					Application:    appPublicID,
-					Organization:   orgName,
-					Policy:         policyName,
-					Format:         format,
-					Component:      compName,
-					Threat:         threat,
-					PolicyAction:   policyAction,
-					ConstraintName: constraintName,
-					Condition:      strings.Join(condSummaries, " | "),
-					CVE:            "",
+					ViolationID:     v.PolicyViolationID,
+					Waived:          v.Waived,
+					Application:     appPublicID,
+					Organization:    orgName,
+					Policy:          policyName,
+					Format:          format,
+					Component:       compName,
+					Threat:          threat,
+					Category:        category,
+					PolicyAction:    policyAction,
+					ConstraintName:  constraintName,
+					Condition:       strings.Join(condSummaries, " | "),
+					CVE:             report.JoinCVEs(vulnerabilityIDs(constr.Conditions)),
+					Hash:            comp.Hash,
+					Proprietary:     comp.Internal(),
+					Labels:          comp.Labels,
+					Claimed:         comp.Claimed(),
+					OpenTime:        parseTime(v.OpenTime),
+					KEVListed:       kev,
+					ExploitMaturity: maturity,
+					Reachable:       reachable,
 				})
 			}
 		}
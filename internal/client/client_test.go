@@ -4,6 +4,8 @@ package client
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -51,13 +53,17 @@ func TestClient_EndToEndAgainstStub(t *testing.T) {
 				"components": []any{
 					map[string]any{
 						"displayName": "setuptools 80.9.0 (.tar.gz)",
+						"hash":        "0a1b2c3d4e5f60718293",
+						"proprietary": true,
 						"componentIdentifier": map[string]any{
 							"format": "pypi",
 						},
 						"violations": []any{
 							map[string]any{
-								"policyName":        "Security-Medium",
-								"policyThreatLevel": 7,
+								"policyName":           "Security-Medium",
+								"policyThreatLevel":    7,
+								"policyThreatCategory": "SECURITY",
+								"openTime":             "2025-01-31T12:00:00.000+0000",
 								"constraints": []any{
 									map[string]any{
 										"constraintName": "Medium risk CVSS score",
@@ -71,7 +77,10 @@ func TestClient_EndToEndAgainstStub(t *testing.T) {
 						},
 					},
 					map[string]any{
-						"displayName": "setuptools (py3-none-any) 80.9.0 (.whl)",
+						"displayName":          "setuptools (py3-none-any) 80.9.0 (.whl)",
+						"matchState":           "innersource",
+						"identificationSource": "Manual",
+						"labels":               []any{"approved-fork", map[string]any{"id": "l-2", "label": "Curated"}},
 						"componentIdentifier": map[string]any{
 							"format": "pypi",
 						},
@@ -146,12 +155,32 @@ func TestClient_EndToEndAgainstStub(t *testing.T) {
 	if violationRows[0].Threat != 7 || violationRows[0].PolicyAction != "Security-7" {
 		t.Errorf("row mapping unexpected: %#v", violationRows[0])
 	}
+	if violationRows[0].Category != "security" {
+		t.Errorf("expected category 'security', got %q", violationRows[0].Category)
+	}
 	if violationRows[0].Format != "pypi" {
 		t.Errorf("expected format 'pypi', got %q", violationRows[0].Format)
 	}
 	if violationRows[1].Format != "pypi" {
 		t.Errorf("expected format 'pypi', got %q", violationRows[1].Format)
 	}
+	if violationRows[0].Hash != "0a1b2c3d4e5f60718293" || violationRows[1].Hash != "" {
+		t.Errorf("hashes = %q, %q", violationRows[0].Hash, violationRows[1].Hash)
+	}
+	if !violationRows[0].Proprietary || !violationRows[1].Proprietary {
+		t.Errorf("expected proprietary and InnerSource components to be flagged: %#v", violationRows)
+	}
+
+	if len(violationRows[0].Labels) != 0 || violationRows[0].Claimed {
+		t.Errorf("first row labels = %v, claimed = %v", violationRows[0].Labels, violationRows[0].Claimed)
+	}
+	if got := strings.Join(violationRows[1].Labels, ","); got != "approved-fork,Curated" || !violationRows[1].Claimed {
+		t.Errorf("second row labels = %q, claimed = %v", got, violationRows[1].Claimed)
+	}
+
+	if want := time.Date(2025, 1, 31, 12, 0, 0, 0, time.UTC); !violationRows[0].OpenTime.Equal(want) || !violationRows[1].OpenTime.IsZero() {
+		t.Errorf("open times = %v, %v", violationRows[0].OpenTime, violationRows[1].OpenTime)
+	}
 
 	// Orgs
 	orgs, err := iqClient.GetOrganizations(rCtx(t))
@@ -190,6 +219,170 @@ func TestNewClient_Validation(t *testing.T) {
 	}
 }
 
+func TestNewClient_NormalizesBaseURL(t *testing.T) {
+	tests := []struct {
+		name   string
+		url    string
+		strict bool
+		want   string
+	}{
+		{"AlreadyAPI", "http://iq:8070/api/v2", false, "http://iq:8070/api/v2/"},
+		{"ServerRoot", "http://iq:8070", false, "http://iq:8070/api/v2/"},
+		{"ServerRootSlash", "http://iq:8070/", false, "http://iq:8070/api/v2/"},
+		{"ContextPath", "https://gw/nexus-iq", false, "https://gw/nexus-iq/api/v2/"},
+		{"UIPage", "http://iq:8070/assets/index.html#/applicationReport/x", false, "http://iq:8070/api/v2/"},
+		{"UIRoute", "https://gw/nexus-iq/ui/links/application/x", false, "https://gw/nexus-iq/api/v2/"},
+		{"EndpointPath", "http://iq:8070/api/v2/applications", false, "http://iq:8070/api/v2/"},
+		{"StrictKeepsPath", "http://iq:8070/custom", true, "http://iq:8070/custom/"},
+		{"StrictRoot", "http://iq:8070", true, "http://iq:8070/"},
+		{"StrictKeepsDoubleSlash", "https://gw/a//iq/api/v2", true, "https://gw/a//iq/api/v2/"},
+		{"StrictDropsQuery", "https://gw/iq/api/v2?tenant=a", true, "https://gw/iq/api/v2/"},
+		{"EscapedSegment", "https://gw/tenant%2Fa/nexus-iq", false, "https://gw/tenant%2Fa/nexus-iq/api/v2/"},
+		{"UISegmentInContextPath", "https://gw/ui/nexus-iq", false, "https://gw/ui/nexus-iq/api/v2/"},
+		{"NestedContextPath", "https://gw/apps/sec/nexus-iq/", false, "https://gw/apps/sec/nexus-iq/api/v2/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewClient(tt.url, "u", "p", newTestLogger(), WithStrictBaseURL(tt.strict))
+			if err != nil {
+				t.Fatalf("NewClient error = %v", err)
+			}
+			if c.baseURL != tt.want {
+				t.Errorf("baseURL = %q, want %q", c.baseURL, tt.want)
+			}
+		})
+	}
+}
+
+// TestClient_PathPrefixLayouts checks that every endpoint keeps the base
+// path of IQ Server deployments behind reverse proxies and gateways.
+func TestClient_PathPrefixLayouts(t *testing.T) {
+	tests := []struct {
+		name      string
+		prefix    string // escaped path IQ Server is served under
+		serverURL string // relative to the stub URL
+		strict    bool
+		query     string // query expected on every request
+	}{
+		{"ContextPath", "/nexus-iq", "/nexus-iq", false, ""},
+		{"GatewaySegments", "/gw/v1/tenants/acme/nexus-iq", "/gw/v1/tenants/acme/nexus-iq/", false, ""},
+		{"EscapedSegment", "/tenant%2Facme/iq", "/tenant%2Facme/iq", false, ""},
+		{"StrictWithQuery", "/gw/iq", "/gw/iq/api/v2?tenant=acme", true, "tenant=acme"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var paths []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				paths = append(paths, r.URL.EscapedPath())
+				if tt.query != "" && !strings.Contains(r.URL.RawQuery, tt.query) {
+					t.Errorf("%s: query %q lacks %q", r.URL.Path, r.URL.RawQuery, tt.query)
+				}
+				p := strings.TrimPrefix(r.URL.EscapedPath(), tt.prefix)
+				w.Header().Set("Content-Type", "application/json")
+				switch p {
+				case "/api/v2/applications":
+					_, _ = w.Write([]byte(`{"applications": []}`))
+				case "/api/v2/organizations":
+					_, _ = w.Write([]byte(`{"organizations": []}`))
+				case "/api/v2/reports/applications/aid-1", "/api/v2/policyWaivers/application/aid-1":
+					_, _ = w.Write([]byte(`[]`))
+				case "/api/v2/reports/applications/aid-1/history":
+					_, _ = w.Write([]byte(`{"reports": []}`))
+				case "/api/v2/applications/app-1/reports/rpt-1/policy":
+					_, _ = w.Write([]byte(`{"components": []}`))
+				case "/ui/links/application/app-1/report/rpt-1/pdf":
+					w.Header().Set("Content-Type", "application/pdf")
+					_, _ = w.Write([]byte("%PDF-1.4"))
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			defer server.Close()
+
+			c, err := NewClient(server.URL+tt.serverURL, "u", "p", newTestLogger(), WithStrictBaseURL(tt.strict))
+			if err != nil {
+				t.Fatalf("NewClient: %v", err)
+			}
+			ctx := rCtx(t)
+			if _, err := c.GetApplications(ctx); err != nil {
+				t.Errorf("GetApplications: %v", err)
+			}
+			if _, err := c.GetOrganizations(ctx); err != nil {
+				t.Errorf("GetOrganizations: %v", err)
+			}
+			if _, err := c.GetReportInfos(ctx, "aid-1"); err != nil {
+				t.Errorf("GetReportInfos: %v", err)
+			}
+			if _, err := c.GetReportHistory(ctx, "aid-1"); err != nil {
+				t.Errorf("GetReportHistory: %v", err)
+			}
+			if _, err := c.GetPolicyViolations(ctx, "app-1", "rpt-1", "org"); err != nil {
+				t.Errorf("GetPolicyViolations: %v", err)
+			}
+			if _, err := c.GetPolicyWaivers(ctx, "aid-1"); err != nil {
+				t.Errorf("GetPolicyWaivers: %v", err)
+			}
+			// PDF links are returned root-relative, without the context path
+			info := ReportInfo{ReportPDFURL: "/ui/links/application/app-1/report/rpt-1/pdf"}
+			if _, err := c.DownloadReportPDF(ctx, info, "app-1", "rpt-1", io.Discard); err != nil {
+				t.Errorf("DownloadReportPDF: %v", err)
+			}
+			for _, p := range paths {
+				if !strings.HasPrefix(p, tt.prefix+"/") {
+					t.Errorf("request path %q lost prefix %q", p, tt.prefix)
+				}
+			}
+		})
+	}
+}
+
+func TestClient_GetApplicationByPublicID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("publicId") == "my app" {
+			_, _ = w.Write([]byte(`{"applications": [{"id": "aid-1", "publicId": "my app", "organizationId": "org-1"}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"applications": []}`))
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(server.URL, "u", "p", newTestLogger())
+	app, err := c.GetApplicationByPublicID(rCtx(t), "my app")
+	if err != nil || app.ID != "aid-1" || app.OrganizationID != "org-1" {
+		t.Errorf("GetApplicationByPublicID = %+v, %v", app, err)
+	}
+	if _, err := c.GetApplicationByPublicID(rCtx(t), "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
+
+func TestClient_GetApplicationsByOrganization(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v2/applications/organization/org-1":
+			_, _ = w.Write([]byte(`{"applications": [{"id": "aid-1", "publicId": "app-1", "organizationId": "org-1"}]}`))
+		case "/api/v2/applications/organization/org-2":
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(server.URL, "u", "p", newTestLogger())
+	apps, err := c.GetApplicationsByOrganization(rCtx(t), "org-1")
+	if err != nil || len(apps) != 1 || apps[0].PublicID != "app-1" {
+		t.Errorf("GetApplicationsByOrganization = %+v, %v", apps, err)
+	}
+	if _, err := c.GetApplicationsByOrganization(rCtx(t), "org-2"); !errors.Is(err, ErrParse) {
+		t.Errorf("expected parse error for missing field, got %v", err)
+	}
+}
+
 func TestClient_GetApplications_Error(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -224,6 +417,32 @@ func TestClient_GetLatestReportInfo_Empty(t *testing.T) {
 	}
 }
 
+func TestClient_GetPolicyViolationsByReportID(t *testing.T) {
+	var gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.EscapedPath()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"components":[{"displayName":"lib 1.0","componentIdentifier":{"format":"maven"},"violations":[{"policyName":"Security-High","policyThreatLevel":9,"constraints":[{"constraintName":"CVSS >= 7","conditions":[{"conditionSummary":"CVE-2024-0001"}]}]}]}]}`))
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(server.URL+"/api/v2", "u", "p", newTestLogger())
+	rows, err := c.GetPolicyViolationsByReportID(context.Background(), "my app", "old/report")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "/api/v2/applications/my%20app/reports/old%2Freport/policy"; gotPath != want {
+		t.Errorf("path = %q, want %q", gotPath, want)
+	}
+	if len(rows) != 1 || rows[0].Application != "my app" || rows[0].Organization != "" {
+		t.Errorf("rows = %+v", rows)
+	}
+
+	if _, err := c.GetPolicyViolationsByReportID(context.Background(), "my app", " "); err == nil {
+		t.Error("expected error for empty report ID")
+	}
+}
+
 func TestClient_GetOrganizations_Error(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusForbidden)
@@ -240,6 +459,61 @@ func TestClient_GetOrganizations_Error(t *testing.T) {
 	}
 }
 
+func TestClient_GetOrganization(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/organizations/org-1" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": "org-1", "name": "Archived"}`))
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(server.URL, "u", "p", newTestLogger())
+	org, err := c.GetOrganization(rCtx(t), "org-1")
+	if err != nil || org.Name != "Archived" {
+		t.Errorf("GetOrganization = %+v, %v", org, err)
+	}
+	if _, err := c.GetOrganization(rCtx(t), "org-gone"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
+
+func TestClient_GetApplications_HTMLResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte("<html><body>Sign in</body></html>"))
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(server.URL+"/api/v2", "u", "p", newTestLogger())
+	_, err := c.GetApplications(context.Background())
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), "got HTML") {
+		t.Errorf("expected HTML diagnostic, got %v", err)
+	}
+}
+
+func TestClient_GetOrganizations_MissingField(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"error": "proxy"}`))
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(server.URL+"/api/v2", "u", "p", newTestLogger())
+	_, err := c.GetOrganizations(context.Background())
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), `missing "organizations" field`) {
+		t.Errorf("expected missing field error, got %v", err)
+	}
+}
+
 // rCtx returns a cancellable context with a small timeout and ensures cancel via t.Cleanup.
 func rCtx(t *testing.T) context.Context {
 	t.Helper()
@@ -247,3 +521,68 @@ func rCtx(t *testing.T) context.Context {
 	t.Cleanup(cancel)
 	return ctx
 }
+
+func TestReportInfo_ReportID(t *testing.T) {
+	tests := []struct {
+		name    string
+		info    ReportInfo
+		want    string
+		wantErr bool
+	}{
+		{"RelativeDataURL", ReportInfo{ReportDataURL: "api/v2/applications/app/reports/rpt-1"}, "rpt-1", false},
+		{"AbsoluteDataURL", ReportInfo{ReportDataURL: "https://gw/nexus-iq/api/v2/applications/app/reports/rpt-2/"}, "rpt-2", false},
+		{"DataURLPreferred", ReportInfo{ReportDataURL: "api/v2/applications/app/reports/rpt-3", ReportHTMLURL: "http://iq/ui/links/application/app/report/other"}, "rpt-3", false},
+		{"HTMLFallback", ReportInfo{ReportHTMLURL: "http://iq/ui/links/application/app/report/rpt-4"}, "rpt-4", false},
+		{"MalformedDataURLFallsBack", ReportInfo{ReportDataURL: "api/v2/unexpected", ReportHTMLURL: "http://iq/report/rpt-5"}, "rpt-5", false},
+		{"Neither", ReportInfo{ReportHTMLURL: "http://iq/ui/links/application/app"}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.info.ReportID()
+			if tt.wantErr {
+				if !errors.Is(err, ErrParse) {
+					t.Errorf("err = %v, want ErrParse", err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("ReportID() = %q, %v; want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+// syntheticReport builds a policy violation report with components ×
+// violations rows for benchmarks.
+func syntheticReport(components, violations int) PolicyViolationReport {
+	var rpt PolicyViolationReport
+	for c := range components {
+		comp := Component{DisplayName: fmt.Sprintf("org.example:lib-%d:1.0.%d", c, c%10)}
+		comp.Format = "maven"
+		for v := range violations {
+			comp.Violations = append(comp.Violations, Violation{
+				PolicyViolationID:    fmt.Sprintf("pv-%d-%d", c, v),
+				PolicyName:           "Security-High",
+				PolicyThreatLevel:    float64(v % 11),
+				PolicyThreatCategory: "SECURITY",
+				Constraints: []Constraint{{
+					ConstraintName: "High Risk CVEs",
+					Conditions:     []Condition{{ConditionSummary: "Security Vulnerability Severity >= 7"}, {ConditionSummary: "CVE Count >= 1"}},
+				}},
+			})
+		}
+		rpt.Components = append(rpt.Components, comp)
+	}
+	return rpt
+}
+
+func BenchmarkParseReportRows(b *testing.B) {
+	rpt := syntheticReport(10_000, 10) // 100k rows
+	b.ReportAllocs()
+	for b.Loop() {
+		if rows := parseReportRows(rpt, "app", "org"); len(rows) != 100_000 {
+			b.Fatalf("got %d rows", len(rows))
+		}
+	}
+}
@@ -0,0 +1,51 @@
+// internal/client/close.go
+package client
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+)
+
+// ErrClosed is returned for requests made after Close. It is classified as
+// a network error by KindOf.
+var ErrClosed = errors.New("client closed")
+
+// closer tracks whether a Client was closed.
+type closer struct {
+	mu     sync.Mutex
+	closed bool
+}
+
+// isClosed reports whether Close was called.
+func (c *closer) isClosed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closed
+}
+
+// Close closes the client's idle connections. Requests in flight complete
+// normally; later requests fail with ErrClosed. Close is safe to call more
+// than once and always returns nil; it implements io.Closer.
+func (c *Client) Close() error {
+	c.closer.mu.Lock()
+	if c.closer.closed {
+		c.closer.mu.Unlock()
+		return nil
+	}
+	c.closer.closed = true
+	c.closer.mu.Unlock()
+
+	c.httpClient.GetClient().CloseIdleConnections()
+	c.logger.Debug().Msg("IQ client closed")
+	return nil
+}
+
+// closeIdleConnections closes the idle connections of rt, if it keeps any.
+// The client's transport wrappers forward their CloseIdleConnections to it,
+// so that http.Client.CloseIdleConnections reaches the pooling transport.
+func closeIdleConnections(rt http.RoundTripper) {
+	if c, ok := rt.(interface{ CloseIdleConnections() }); ok {
+		c.CloseIdleConnections()
+	}
+}
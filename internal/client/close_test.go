@@ -0,0 +1,103 @@
+// internal/client/close_test.go
+package client
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// connServer starts a test server answering with an empty application list
+// and returns it with a function reporting its open connections.
+func connServer(t *testing.T) (*httptest.Server, func() int64) {
+	t.Helper()
+	var open atomic.Int64
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "CLMSESSIONID", Value: "s"})
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"applications": []}`))
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		switch state {
+		case http.StateNew:
+			open.Add(1)
+		case http.StateClosed, http.StateHijacked:
+			open.Add(-1)
+		}
+	}
+	server.Start()
+	t.Cleanup(server.Close)
+	return server, open.Load
+}
+
+// waitConns waits up to a second for the open connections to drop to want.
+func waitConns(open func() int64, want int64) int64 {
+	deadline := time.Now().Add(time.Second)
+	for open() != want && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	return open()
+}
+
+func TestClient_Close(t *testing.T) {
+	// Close must reach the pooling transport through every wrapper
+	for name, opts := range map[string][]Option{
+		"plain":      nil,
+		"rate limit": {WithRateLimiter(NewRateLimiter(0))},
+		"retry":      {WithRetry(RetryPolicy{MaxAttempts: 2})},
+		"read-only":  {WithReadOnly(true)},
+		"session":    {WithSessionAuth(true)},
+		"all": {
+			WithRateLimiter(NewRateLimiter(0)), WithRetry(RetryPolicy{MaxAttempts: 2}),
+			WithReadOnly(true), WithSessionAuth(true),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			server, open := connServer(t)
+
+			c, err := NewClient(server.URL, "u", "p", newTestLogger(), opts...)
+			if err != nil {
+				t.Fatalf("NewClient: %v", err)
+			}
+			if _, err := c.GetApplications(rCtx(t)); err != nil {
+				t.Fatalf("GetApplications before Close: %v", err)
+			}
+			if n := open(); n != 1 {
+				t.Fatalf("%d connections open before Close, want 1 pooled", n)
+			}
+
+			if err := c.Close(); err != nil {
+				t.Fatalf("Close: %v", err)
+			}
+			if err := c.Close(); err != nil {
+				t.Fatalf("second Close: %v", err)
+			}
+			if n := waitConns(open, 0); n != 0 {
+				t.Errorf("%d connections open after Close, want 0", n)
+			}
+
+			_, err = c.GetApplications(rCtx(t))
+			if !errors.Is(err, ErrClosed) || !errors.Is(err, ErrNetwork) {
+				t.Errorf("GetApplications after Close error = %v, want ErrClosed", err)
+			}
+		})
+	}
+}
+
+func TestPool_Close(t *testing.T) {
+	def, _ := NewClient("http://localhost", "u", "p", newTestLogger())
+	org, _ := NewClient("http://localhost", "u", "p", newTestLogger())
+	pool := NewPool(def)
+	pool.Add("org-1", org)
+
+	if err := pool.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if !def.closer.isClosed() || !org.closer.isClosed() {
+		t.Error("expected every client of the pool to be closed")
+	}
+}
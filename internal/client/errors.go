@@ -0,0 +1,194 @@
+// internal/client/errors.go
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// Sentinel errors for the error taxonomy. Errors returned by Client wrap an
+// *Error whose kind matches exactly one of these via errors.Is, so callers
+// can branch on the category without inspecting status codes.
+var (
+	ErrAuth        = errors.New("authentication failed")
+	ErrNotFound    = errors.New("not found")
+	ErrRateLimited = errors.New("rate limited")
+	ErrServer      = errors.New("server error")
+	ErrParse       = errors.New("unexpected response")
+	ErrTimeout     = errors.New("timeout")
+	ErrNetwork     = errors.New("network error")
+	ErrTooLarge    = errors.New("response too large")
+)
+
+// Error is a classified client error. Kind is one of the sentinel errors
+// above (or nil when the failure could not be classified).
+type Error struct {
+	Kind       error
+	StatusCode int
+	// RetryAfter is the delay the server asked for with a Retry-After
+	// header, e.g. during maintenance; zero when none was given.
+	RetryAfter time.Duration
+	msg        string
+	err        error
+}
+
+func (e *Error) Error() string { return e.msg }
+
+// Unwrap returns the underlying cause, if any.
+func (e *Error) Unwrap() error { return e.err }
+
+// Is reports whether target is the error's kind.
+func (e *Error) Is(target error) bool {
+	return e.Kind != nil && target == e.Kind
+}
+
+// Retryable reports whether repeating the request may succeed.
+func (e *Error) Retryable() bool {
+	switch e.Kind {
+	case ErrRateLimited, ErrServer, ErrTimeout, ErrNetwork:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsRetryable reports whether err is a classified client error that may
+// succeed when the request is repeated.
+func IsRetryable(err error) bool {
+	var e *Error
+	return errors.As(err, &e) && e.Retryable()
+}
+
+// RetryAfter returns the delay the server asked for before repeating the
+// request that failed with err, or zero.
+func RetryAfter(err error) time.Duration {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.RetryAfter
+	}
+	return 0
+}
+
+// KindOf returns the sentinel kind of err, or nil when err is not classified.
+func KindOf(err error) error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+	return nil
+}
+
+// KindName returns a short, stable name for the kind of err, for use in logs
+// and reports.
+func KindName(err error) string {
+	switch KindOf(err) {
+	case ErrAuth:
+		return "auth"
+	case ErrNotFound:
+		return "not_found"
+	case ErrRateLimited:
+		return "rate_limited"
+	case ErrServer:
+		return "server"
+	case ErrParse:
+		return "parse"
+	case ErrTimeout:
+		return "timeout"
+	case ErrNetwork:
+		return "network"
+	case ErrTooLarge:
+		return "too_large"
+	default:
+		return "other"
+	}
+}
+
+// statusKind maps an HTTP error status code to its error kind.
+func statusKind(code int) error {
+	switch {
+	case code == http.StatusUnauthorized || code == http.StatusForbidden:
+		return ErrAuth
+	case code == http.StatusNotFound || code == http.StatusGone:
+		return ErrNotFound
+	case code == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case code == http.StatusRequestTimeout || code == http.StatusGatewayTimeout:
+		return ErrTimeout
+	case code >= 500:
+		return ErrServer
+	default:
+		return nil
+	}
+}
+
+// httpError classifies an error response. detail is appended to the status
+// code in the message.
+func httpError(resp *resty.Response, detail string) error {
+	return &Error{
+		Kind:       statusKind(resp.StatusCode()),
+		StatusCode: resp.StatusCode(),
+		RetryAfter: parseRetryAfter(resp.Header().Get("Retry-After"), time.Now()),
+		msg:        fmt.Sprintf("HTTP %d: %s", resp.StatusCode(), detail),
+	}
+}
+
+// parseRetryAfter parses a Retry-After header value, either seconds or an
+// HTTP date, relative to now. Invalid and past values return zero.
+func parseRetryAfter(v string, now time.Time) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return max(time.Duration(secs)*time.Second, 0)
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(t.Sub(now), 0)
+	}
+	return 0
+}
+
+// parseError reports a response that could not be interpreted.
+func parseError(format string, args ...any) error {
+	return &Error{Kind: ErrParse, msg: fmt.Sprintf(format, args...)}
+}
+
+// notFoundError reports a resource missing from a successful response, e.g.
+// an empty search result.
+func notFoundError(format string, args ...any) error {
+	return &Error{Kind: ErrNotFound, msg: fmt.Sprintf(format, args...)}
+}
+
+// transportError classifies an error returned by resty before a response
+// was available (network failures, timeouts, decoding errors).
+func transportError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var (
+		netErr    net.Error
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+	)
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, ErrReadOnly):
+		return err
+	case errors.Is(err, context.DeadlineExceeded):
+		return &Error{Kind: ErrTimeout, msg: err.Error(), err: err}
+	case errors.Is(err, resty.ErrResponseBodyTooLarge):
+		return &Error{Kind: ErrTooLarge, msg: err.Error(), err: err}
+	case errors.As(err, &syntaxErr), errors.As(err, &typeErr):
+		return &Error{Kind: ErrParse, msg: "decode response: " + err.Error(), err: err}
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return &Error{Kind: ErrTimeout, msg: err.Error(), err: err}
+	default:
+		return &Error{Kind: ErrNetwork, msg: err.Error(), err: err}
+	}
+}
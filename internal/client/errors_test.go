@@ -0,0 +1,91 @@
+// internal/client/errors_test.go
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient_ErrorsAreClassified(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		kind      error
+		retryable bool
+	}{
+		{"Unauthorized", http.StatusUnauthorized, ErrAuth, false},
+		{"Forbidden", http.StatusForbidden, ErrAuth, false},
+		{"NotFound", http.StatusNotFound, ErrNotFound, false},
+		{"TooManyRequests", http.StatusTooManyRequests, ErrRateLimited, true},
+		{"BadGateway", http.StatusBadGateway, ErrServer, true},
+		{"GatewayTimeout", http.StatusGatewayTimeout, ErrTimeout, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			c, _ := NewClient(server.URL+"/api/v2", "u", "p", newTestLogger())
+			_, err := c.GetLatestReportInfo(context.Background(), "app-1")
+			if !errors.Is(err, tt.kind) {
+				t.Fatalf("error %v is not %v", err, tt.kind)
+			}
+			if got := IsRetryable(fmt.Errorf("wrapped: %w", err)); got != tt.retryable {
+				t.Errorf("IsRetryable = %v, want %v", got, tt.retryable)
+			}
+		})
+	}
+}
+
+func TestClient_ParseAndNetworkErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html></html>"))
+	}))
+	c, _ := NewClient(server.URL+"/api/v2", "u", "p", newTestLogger())
+	if _, err := c.GetOrganizations(context.Background()); !errors.Is(err, ErrParse) || KindName(err) != "parse" {
+		t.Errorf("expected parse error, got %v", err)
+	}
+
+	// A closed server yields a connection error
+	server.Close()
+	_, err := c.GetOrganizations(context.Background())
+	if !errors.Is(err, ErrNetwork) || !IsRetryable(err) {
+		t.Errorf("expected retryable network error, got %v", err)
+	}
+}
+
+func TestClient_RetryAfter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(server.URL+"/api/v2", "u", "p", newTestLogger())
+	_, err := c.GetOrganizations(context.Background())
+	if !errors.Is(err, ErrServer) || RetryAfter(fmt.Errorf("wrapped: %w", err)) != 2*time.Minute {
+		t.Errorf("error %v has Retry-After %s, want 2m0s", err, RetryAfter(err))
+	}
+
+	now := time.Date(2025, 3, 1, 2, 0, 0, 0, time.UTC)
+	for v, want := range map[string]time.Duration{
+		"":                              0,
+		"-5":                            0,
+		"soon":                          0,
+		"Sat, 01 Mar 2025 03:00:00 GMT": time.Hour,
+		"Sat, 01 Mar 2025 01:00:00 GMT": 0,
+	} {
+		if got := parseRetryAfter(v, now); got != want {
+			t.Errorf("parseRetryAfter(%q) = %s, want %s", v, got, want)
+		}
+	}
+}
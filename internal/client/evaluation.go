@@ -0,0 +1,116 @@
+// internal/client/evaluation.go
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Evaluation statuses reported by IQ Server, see EvaluationStatus.Status.
+const (
+	EvaluationPending   = "PENDING"
+	EvaluationCompleted = "COMPLETED"
+	EvaluationFailed    = "FAILED"
+)
+
+// EvaluationStatus is the state of an application evaluation requested from
+// IQ Server, identified by its result ID.
+type EvaluationStatus struct {
+	Status        string `json:"status"`
+	ApplicationID string `json:"applicationId"`
+	ResultID      string `json:"resultId"`
+	ScanID        string `json:"scanId"`
+	ReportHTMLURL string `json:"reportHtmlUrl"`
+	IsError       bool   `json:"isError"`
+	ErrorMessage  string `json:"errorMessage"`
+}
+
+// Done reports whether the evaluation has finished, successfully or not.
+func (e EvaluationStatus) Done() bool {
+	return e.Failed() || strings.EqualFold(e.Status, EvaluationCompleted)
+}
+
+// Failed reports whether the evaluation has finished with an error.
+func (e EvaluationStatus) Failed() bool {
+	return e.IsError || strings.EqualFold(e.Status, EvaluationFailed)
+}
+
+// GetEvaluationStatus fetches the status of the evaluation with the given
+// result ID of the application with the given internal ID. IQ Server answers
+// 404 while the result is not available yet, reported as ErrNotFound.
+func (c *Client) GetEvaluationStatus(ctx context.Context, appID, resultID string) (*EvaluationStatus, error) {
+	if appID == "" || resultID == "" {
+		return nil, errors.New("application ID and result ID are required")
+	}
+	c.logger.Debug().Str("appId", appID).Str("resultId", resultID).Msg("Fetching evaluation status")
+
+	endpoint := fmt.Sprintf("evaluation/applications/%s/results/%s", url.PathEscape(appID), url.PathEscape(resultID))
+	var status EvaluationStatus
+	resp, err := c.request(ctx, "evaluation/applications/{id}/results/{resultId}").
+		SetResult(&status).
+		Get(endpoint)
+	if err != nil {
+		return nil, transportError(err)
+	}
+	if resp.IsError() {
+		return nil, httpError(resp, resp.Status())
+	}
+	if err := checkJSON(resp); err != nil {
+		return nil, err
+	}
+	if status.Status == "" {
+		return nil, parseError("unexpected response from %s: missing \"status\" field", endpoint)
+	}
+	return &status, nil
+}
+
+// WaitForEvaluation polls the status of an evaluation every interval until
+// it is done, calling progress (when not nil) after every poll with the
+// latest status and the time waited so far. A result that is not available
+// yet counts as pending. The wait is bounded by the deadline of ctx; when it
+// expires, the returned error is classified as ErrTimeout. An evaluation
+// that finished with an error is returned together with an error.
+func (c *Client) WaitForEvaluation(ctx context.Context, appID, resultID string, interval time.Duration, progress func(EvaluationStatus, time.Duration)) (*EvaluationStatus, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("poll interval must be positive, got %s", interval)
+	}
+	start := time.Now()
+	last := EvaluationStatus{Status: EvaluationPending, ResultID: resultID}
+	for {
+		status, err := c.GetEvaluationStatus(ctx, appID, resultID)
+		switch {
+		case errors.Is(err, ErrNotFound):
+			last = EvaluationStatus{Status: EvaluationPending, ResultID: resultID}
+		case err != nil && ctx.Err() == nil:
+			return nil, err
+		case err == nil:
+			last = *status
+		}
+		if progress != nil && ctx.Err() == nil {
+			progress(last, time.Since(start))
+		}
+		if last.Failed() {
+			return &last, fmt.Errorf("evaluation %s of application %s failed: %s", resultID, appID, last.ErrorMessage)
+		}
+		if last.Done() {
+			return &last, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return &last, &Error{
+					Kind: ErrTimeout,
+					msg:  fmt.Sprintf("evaluation %s of application %s still %s after %s", resultID, appID, strings.ToLower(last.Status), time.Since(start).Round(time.Second)),
+					err:  ctx.Err(),
+				}
+			}
+			return &last, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,81 @@
+// internal/client/evaluation_test.go
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_WaitForEvaluation(t *testing.T) {
+	var polls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/evaluation/applications/aid-1/results/res-1" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch polls.Add(1) {
+		case 1:
+			http.Error(w, "not ready", http.StatusNotFound)
+		case 2:
+			_, _ = w.Write([]byte(`{"status": "PENDING"}`))
+		default:
+			_, _ = w.Write([]byte(`{"status": "COMPLETED", "applicationId": "aid-1", "scanId": "scan-1", "reportHtmlUrl": "ui/links/application/app-1/report/scan-1"}`))
+		}
+	}))
+	defer server.Close()
+
+	c, err := NewClient(server.URL, "u", "p", newTestLogger())
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	var seen []string
+	status, err := c.WaitForEvaluation(rCtx(t), "aid-1", "res-1", 10*time.Millisecond, func(s EvaluationStatus, _ time.Duration) {
+		seen = append(seen, s.Status)
+	})
+	if err != nil {
+		t.Fatalf("WaitForEvaluation: %v", err)
+	}
+	if status.ScanID != "scan-1" || !status.Done() {
+		t.Errorf("status = %+v", status)
+	}
+	if want := []string{"PENDING", "PENDING", "COMPLETED"}; len(seen) != 3 || seen[0] != want[0] || seen[1] != want[1] || seen[2] != want[2] {
+		t.Errorf("progress = %v, want %v", seen, want)
+	}
+}
+
+func TestClient_WaitForEvaluation_FailedAndTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v2/evaluation/applications/aid-1/results/failed":
+			_, _ = w.Write([]byte(`{"status": "FAILED", "isError": true, "errorMessage": "scan rejected"}`))
+		default:
+			_, _ = w.Write([]byte(`{"status": "PENDING"}`))
+		}
+	}))
+	defer server.Close()
+
+	c, err := NewClient(server.URL, "u", "p", newTestLogger())
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	status, err := c.WaitForEvaluation(rCtx(t), "aid-1", "failed", time.Millisecond, nil)
+	if err == nil || status == nil || !status.Failed() {
+		t.Errorf("failed evaluation: status = %+v, err = %v", status, err)
+	}
+
+	ctx, cancel := context.WithTimeout(rCtx(t), 50*time.Millisecond)
+	defer cancel()
+	_, err = c.WaitForEvaluation(ctx, "aid-1", "pending", 10*time.Millisecond, nil)
+	if !errors.Is(err, ErrTimeout) {
+		t.Errorf("pending evaluation: err = %v, want ErrTimeout", err)
+	}
+}
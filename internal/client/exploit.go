@@ -0,0 +1,48 @@
+// internal/client/exploit.go
+package client
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Exploitability describes how likely the vulnerability matched by a
+// security condition is to be exploited. Newer IQ Server versions report
+// it; older ones leave Condition.Exploitability nil.
+type Exploitability struct {
+	// KnownExploited is set for vulnerabilities listed in the CISA Known
+	// Exploited Vulnerabilities (KEV) catalog.
+	KnownExploited bool `json:"knownExploited"`
+	// ExploitMaturity is one of exploitMaturities, or empty when unknown.
+	ExploitMaturity string `json:"exploitMaturity"`
+	// Reachable tells whether reachability analysis found the vulnerable
+	// code called from the application; nil when it was not analyzed.
+	Reachable *bool `json:"reachable"`
+}
+
+// exploitMaturities are the exploit maturity levels, least mature first.
+var exploitMaturities = []string{"unproven", "proof-of-concept", "functional", "high"}
+
+// exploitability combines the exploitability of the conditions of a
+// constraint: known exploited if any vulnerability is, the most mature
+// exploit, and reachable if any vulnerability is ("true"), unreachable if
+// all analyzed ones are not ("false"), or "" when none was analyzed.
+func exploitability(conds []Condition) (kev bool, maturity, reachable string) {
+	rank := -1
+	for _, cond := range conds {
+		e := cond.Exploitability
+		if e == nil {
+			continue
+		}
+		kev = kev || e.KnownExploited
+		m := strings.ToLower(strings.TrimSpace(e.ExploitMaturity))
+		if r := slices.Index(exploitMaturities, m); r > rank || (maturity == "" && m != "") {
+			rank, maturity = max(r, rank), m
+		}
+		if e.Reachable != nil && reachable != "true" {
+			reachable = strconv.FormatBool(*e.Reachable)
+		}
+	}
+	return kev, maturity, reachable
+}
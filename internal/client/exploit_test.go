@@ -0,0 +1,42 @@
+// internal/client/exploit_test.go
+package client
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseReportRows_Exploitability(t *testing.T) {
+	raw := `{"components":[{"displayName":"lib","violations":[{"policyName":"Security-Critical","policyThreatLevel":10,"constraints":[
+		{"constraintName":"Exploitable","conditions":[
+			{"conditionSummary":"CVE-1","exploitability":{"knownExploited":false,"exploitMaturity":"Proof-of-Concept","reachable":false}},
+			{"conditionSummary":"CVE-2","exploitability":{"knownExploited":true,"exploitMaturity":"functional","reachable":true}},
+			{"conditionSummary":"CVE-3","exploitability":{"exploitMaturity":"unproven"}}
+		]},
+		{"constraintName":"Unreachable","conditions":[
+			{"conditionSummary":"CVE-4","exploitability":{"reachable":false}}
+		]},
+		{"constraintName":"Older server","conditions":[{"conditionSummary":"CVE-5"}]}
+	]}]}]}`
+	var rpt PolicyViolationReport
+	if err := json.Unmarshal([]byte(raw), &rpt); err != nil {
+		t.Fatal(err)
+	}
+	rows := parseReportRows(rpt, "app", "org")
+	if len(rows) != 3 {
+		t.Fatalf("got %d rows, want 3", len(rows))
+	}
+	for i, want := range []struct {
+		kev                 bool
+		maturity, reachable string
+	}{
+		{true, "functional", "true"},
+		{false, "", "false"},
+		{false, "", ""},
+	} {
+		r := rows[i]
+		if r.KEVListed != want.kev || r.ExploitMaturity != want.maturity || r.Reachable != want.reachable {
+			t.Errorf("row %d (%s) = %v, %q, %q; want %v, %q, %q", i, r.ConstraintName, r.KEVListed, r.ExploitMaturity, r.Reachable, want.kev, want.maturity, want.reachable)
+		}
+	}
+}
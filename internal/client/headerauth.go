@@ -0,0 +1,21 @@
+// internal/client/headerauth.go
+package client
+
+// HeaderAuth authenticates like a trusted reverse proxy in front of IQ
+// Server: where the proxy performs the SAML/SSO login and IQ Server trusts
+// a header it injects (e.g. REMOTE_USER), the client sets that header to
+// the username itself instead of sending basic auth.
+type HeaderAuth struct {
+	// Header carries the username, e.g. "REMOTE_USER". Empty disables
+	// header authentication.
+	Header string
+	// Extra are further headers the proxy requires, e.g. a shared secret
+	// proving that the request passed it.
+	Extra map[string]string
+}
+
+// WithHeaderAuth authenticates requests with headers instead of basic auth,
+// see HeaderAuth. No password is needed.
+func WithHeaderAuth(auth HeaderAuth) Option {
+	return func(o *options) { o.headerAuth = auth }
+}
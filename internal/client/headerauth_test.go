@@ -0,0 +1,39 @@
+// internal/client/headerauth_test.go
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_HeaderAuth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, _, ok := r.BasicAuth(); ok {
+			t.Errorf("basic auth sent with header authentication")
+		}
+		if r.Header.Get("REMOTE_USER") != "svc-report" || r.Header.Get("X-Proxy-Secret") != "s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"organizations": []}`))
+	}))
+	defer server.Close()
+
+	auth := HeaderAuth{Header: "REMOTE_USER", Extra: map[string]string{"X-Proxy-Secret": "s3cret"}}
+	c, err := NewClient(server.URL, "svc-report", "", newTestLogger(), WithHeaderAuth(auth))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if _, err := c.GetOrganizations(rCtx(t)); err != nil {
+		t.Errorf("GetOrganizations: %v", err)
+	}
+
+	if _, err := NewClient(server.URL, "svc-report", "", newTestLogger()); err == nil {
+		t.Error("expected an error for a missing password without header authentication")
+	}
+	if _, err := NewClient(server.URL, "svc-report", "p", newTestLogger(), WithHeaderAuth(auth), WithSessionAuth(true)); err == nil {
+		t.Error("expected an error for header and session authentication together")
+	}
+}
@@ -0,0 +1,55 @@
+// internal/client/history.go
+package client
+
+import (
+	"context"
+	"fmt"
+)
+
+// PolicyEvaluationResult summarizes the policy evaluation of a report:
+// affected components and violation counts per threat band.
+type PolicyEvaluationResult struct {
+	AffectedComponentCount       int `json:"affectedComponentCount"`
+	TotalComponentCount          int `json:"totalComponentCount"`
+	CriticalPolicyViolationCount int `json:"criticalPolicyViolationCount"`
+	SeverePolicyViolationCount   int `json:"severePolicyViolationCount"`
+	ModeratePolicyViolationCount int `json:"moderatePolicyViolationCount"`
+}
+
+// HistoricalReport is one evaluation in the report history of an application.
+type HistoricalReport struct {
+	ReportInfo
+	PolicyEvaluationResult PolicyEvaluationResult `json:"policyEvaluationResult"`
+}
+
+type reportHistoryEnvelope struct {
+	Reports []HistoricalReport `json:"reports"`
+}
+
+// GetReportHistory fetches all report evaluations of the application with
+// the given internal ID, across stages, as returned by IQ Server (newest
+// first).
+func (c *Client) GetReportHistory(ctx context.Context, appID string) ([]HistoricalReport, error) {
+	c.logger.Debug().Str("appId", appID).Msg("Fetching report history")
+
+	endpoint := fmt.Sprintf("reports/applications/%s/history", appID)
+	var env reportHistoryEnvelope
+	resp, err := c.request(ctx, "reports/applications/{id}/history").
+		SetResult(&env).
+		Get(endpoint)
+	if err != nil {
+		return nil, transportError(err)
+	}
+	if resp.IsError() {
+		return nil, httpError(resp, resp.Status())
+	}
+	if err := checkJSON(resp); err != nil {
+		return nil, err
+	}
+	if env.Reports == nil {
+		return nil, parseError("unexpected response from %s: missing \"reports\" field", endpoint)
+	}
+
+	c.logger.Debug().Int("count", len(env.Reports)).Str("appId", appID).Msg("Retrieved report history")
+	return env.Reports, nil
+}
@@ -0,0 +1,48 @@
+// internal/client/history_test.go
+package client
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetReportHistory(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/reports/applications/aid-1/history" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"applicationId": "aid-1", "reports": [
+			{"stage": "build", "evaluationDate": "2025-01-15T10:00:00.000+0000", "reportHtmlUrl": "ui/links/application/apid-1/report/rpt-2",
+			 "policyEvaluationResult": {"affectedComponentCount": 3, "totalComponentCount": 40, "criticalPolicyViolationCount": 2, "severePolicyViolationCount": 1, "moderatePolicyViolationCount": 4}},
+			{"stage": "build", "evaluationDate": "2025-01-01T10:00:00.000+0000", "reportHtmlUrl": "ui/links/application/apid-1/report/rpt-1"}
+		]}`))
+	}))
+	defer server.Close()
+
+	c, err := NewClient(server.URL, "u", "p", newTestLogger())
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	history, err := c.GetReportHistory(rCtx(t), "aid-1")
+	if err != nil {
+		t.Fatalf("GetReportHistory: %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("got %d reports, want 2", len(history))
+	}
+	got := history[0]
+	if id, _ := got.ReportID(); id != "rpt-2" || got.Stage != "build" {
+		t.Errorf("report = %+v (id %q)", got.ReportInfo, id)
+	}
+	if got.PolicyEvaluationResult.CriticalPolicyViolationCount != 2 || got.PolicyEvaluationResult.TotalComponentCount != 40 {
+		t.Errorf("evaluation = %+v", got.PolicyEvaluationResult)
+	}
+
+	if _, err := c.GetReportHistory(rCtx(t), "aid-missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
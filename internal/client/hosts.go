@@ -0,0 +1,50 @@
+// internal/client/hosts.go
+package client
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// WithHostOverrides resolves the given host names to fixed IP addresses
+// instead of asking DNS, like entries in /etc/hosts, e.g. in air-gapped
+// environments whose DNS does not know the IQ Server host. Names match case
+// insensitively; other hosts are resolved as usual. TLS certificates are
+// still verified against the host name of the URL.
+func WithHostOverrides(hosts map[string]string) Option {
+	return func(o *options) { o.hosts = hosts }
+}
+
+// overrideHosts installs a dialer resolving hosts on the transport t.
+func overrideHosts(t http.RoundTripper, hosts map[string]string) (http.RoundTripper, error) {
+	ht, ok := t.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("host overrides need an *http.Transport, got %T", t)
+	}
+	byName := make(map[string]string, len(hosts))
+	for name, ip := range hosts {
+		if net.ParseIP(ip) == nil {
+			return nil, fmt.Errorf("host override %s: invalid IP address %q", name, ip)
+		}
+		byName[strings.ToLower(name)] = ip
+	}
+
+	ht = ht.Clone()
+	dial := ht.DialContext
+	if dial == nil {
+		dial = (&net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}).DialContext
+	}
+	ht.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if host, port, err := net.SplitHostPort(addr); err == nil {
+			if ip, ok := byName[strings.ToLower(host)]; ok {
+				addr = net.JoinHostPort(ip, port)
+			}
+		}
+		return dial(ctx, network, addr)
+	}
+	return ht, nil
+}
@@ -0,0 +1,32 @@
+// internal/client/hosts_test.go
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestClient_HostOverrides(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"applications": []}`))
+	}))
+	defer server.Close()
+
+	u, _ := url.Parse(server.URL)
+	baseURL := "http://iq.invalid:" + u.Port()
+	c, err := NewClient(baseURL, "u", "p", newTestLogger(), WithHostOverrides(map[string]string{"IQ.invalid": u.Hostname()}))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if _, err := c.GetApplications(rCtx(t)); err != nil {
+		t.Fatalf("GetApplications with host override: %v", err)
+	}
+
+	if _, err := NewClient(baseURL, "u", "p", newTestLogger(), WithHostOverrides(map[string]string{"iq.invalid": "not-an-ip"})); err == nil || !strings.Contains(err.Error(), "invalid IP address") {
+		t.Errorf("NewClient with invalid IP error = %v", err)
+	}
+}
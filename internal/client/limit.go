@@ -0,0 +1,132 @@
+// internal/client/limit.go
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/report"
+)
+
+// ResponseLimit bounds the size of response bodies read into memory.
+type ResponseLimit struct {
+	// MaxBytes fails responses with larger bodies with an ErrTooLarge
+	// error; zero does not limit them.
+	MaxBytes int
+	// StreamPolicyReports fetches policy reports exceeding MaxBytes again
+	// and decodes them component by component instead of failing, so that
+	// only the resulting rows are held in memory.
+	StreamPolicyReports bool
+}
+
+// WithResponseLimit sets the maximum response body size, see ResponseLimit.
+func WithResponseLimit(l ResponseLimit) Option {
+	return func(o *options) { o.responseLimit = l }
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// decodePolicyReportStream decodes a policy report from r one component at
+// a time and returns its rows, without holding the body or the whole
+// report in memory.
+func decodePolicyReportStream(r io.Reader, appPublicID, orgName string) ([]report.Row, error) {
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '{'); err != nil {
+		return nil, err
+	}
+	var rows []report.Row
+	found := false
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		if key, _ := tok.(string); key != "components" {
+			// Skip the value of any other field
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		found = true
+		if err := expectDelim(dec, '['); err != nil {
+			return nil, err
+		}
+		for dec.More() {
+			var comp Component
+			if err := dec.Decode(&comp); err != nil {
+				return nil, err
+			}
+			rows = append(rows, parseReportRows(PolicyViolationReport{Components: []Component{comp}}, appPublicID, orgName)...)
+		}
+		if err := expectDelim(dec, ']'); err != nil {
+			return nil, err
+		}
+	}
+	if !found {
+		return nil, parseError("missing \"components\" field")
+	}
+	return rows, nil
+}
+
+// expectDelim reads the next token and checks that it is delim.
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := tok.(json.Delim); !ok || d != delim {
+		return parseError("expected %q, got %v", delim, tok)
+	}
+	return nil
+}
+
+// streamPolicyViolations fetches a policy report without a size limit and
+// decodes it with decodePolicyReportStream.
+func (c *Client) streamPolicyViolations(ctx context.Context, endpoint string, params url.Values, publicID, orgName string) ([]report.Row, error) {
+	const label = "applications/{publicId}/reports/{reportId}/policy"
+	c.logger.Info().Str("publicId", publicID).Int("maxBytes", c.responseLimit.MaxBytes).Msg("Policy report exceeds the response limit, decoding it as a stream")
+
+	resp, err := c.request(ctx, label).
+		SetQueryParamsFromValues(params).
+		SetDoNotParseResponse(true).
+		Get(endpoint)
+	if err != nil {
+		return nil, transportError(err)
+	}
+	body := resp.RawBody()
+	defer body.Close() //nolint:errcheck
+
+	if resp.IsError() {
+		return nil, httpError(resp, resp.Status())
+	}
+	if err := checkJSON(resp); err != nil {
+		return nil, err
+	}
+	cr := &countingReader{r: body}
+	rows, err := decodePolicyReportStream(cr, publicID, orgName)
+	// The body is streamed, so bytes are counted here rather than in the
+	// response hook
+	c.transfer.record(label, applicationFromContext(ctx), cr.n)
+	if err != nil {
+		if KindOf(err) == nil {
+			err = transportError(err)
+		}
+		return nil, fmt.Errorf("decode %s: %w", endpoint, err)
+	}
+	return rows, nil
+}
@@ -0,0 +1,82 @@
+// internal/client/limit_test.go
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// largePolicyReport returns a policy report of n components with one
+// violation each.
+func largePolicyReport(n int) string {
+	var b strings.Builder
+	b.WriteString(`{"reportTime": 1, "application": {"publicId": "app-1"}, "components": [`)
+	for i := range n {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		fmt.Fprintf(&b, `{"displayName": "lib-%d 1.0", "labels": ["approved-fork"], "componentIdentifier": {"format": "maven"}, "violations": [{"policyName": "Security-High", "policyThreatLevel": 9, "constraints": [{"constraintName": "C", "conditions": [{"conditionSummary": "CVSS >= 7"}]}]}]}`, i)
+	}
+	b.WriteString(`], "counts": {}}`)
+	return b.String()
+}
+
+func TestClient_ResponseLimit(t *testing.T) {
+	body := largePolicyReport(200)
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	limit := ResponseLimit{MaxBytes: len(body) / 2}
+	c, _ := NewClient(server.URL, "u", "p", newTestLogger(), WithResponseLimit(limit))
+	_, err := c.GetPolicyViolations(rCtx(t), "app-1", "rpt-1", "org")
+	if !errors.Is(err, ErrTooLarge) || KindName(err) != "too_large" || IsRetryable(err) {
+		t.Fatalf("error = %v (kind %s), want a non-retryable ErrTooLarge", err, KindName(err))
+	}
+	if !strings.Contains(err.Error(), "report too large") {
+		t.Errorf("error message = %q", err)
+	}
+
+	limit.StreamPolicyReports = true
+	c, _ = NewClient(server.URL, "u", "p", newTestLogger(), WithResponseLimit(limit))
+	requests.Store(0)
+	rows, err := c.GetPolicyViolations(WithApplication(rCtx(t), "app-1"), "app-1", "rpt-1", "org")
+	if err != nil {
+		t.Fatalf("streamed GetPolicyViolations: %v", err)
+	}
+	if len(rows) != 200 || rows[199].Component != "lib-199 1.0" || rows[0].Organization != "org" || rows[0].Labels[0] != "approved-fork" {
+		t.Errorf("got %d rows, first %+v", len(rows), rows[0])
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("requests = %d, want the limited attempt and the streamed one", n)
+	}
+	if got := c.Transfer().ByApplication["app-1"]; got != int64(len(body)) {
+		t.Errorf("streamed bytes recorded = %d, want %d", got, len(body))
+	}
+
+	// Other endpoints are limited too, but never streamed
+	if _, err := c.GetApplications(rCtx(t)); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("GetApplications error = %v, want ErrTooLarge", err)
+	}
+}
+
+func TestDecodePolicyReportStream_Malformed(t *testing.T) {
+	for name, body := range map[string]string{
+		"no components": `{"reportTime": 1}`,
+		"not an object": `[]`,
+		"truncated":     `{"components": [{"displayName": `,
+	} {
+		if _, err := decodePolicyReportStream(strings.NewReader(body), "app", "org"); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
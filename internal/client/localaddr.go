@@ -0,0 +1,83 @@
+// internal/client/localaddr.go
+package client
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// WithLocalAddress makes outbound connections from a local address, e.g.
+// where firewall rules only admit traffic from one interface. addr is an IP
+// address or a network interface name, optionally with a port, e.g.
+// "10.0.4.12", "eth1" or "10.0.4.12:40000". An interface binds to its first
+// IPv4 address, or its first address when it has none. A fixed port allows
+// only one connection at a time: the transport is limited accordingly, and
+// other clients must not use the same port.
+func WithLocalAddress(addr string) Option {
+	return func(o *options) { o.localAddr = addr }
+}
+
+// bindLocalAddress installs a dialer connecting from addr on the transport t.
+func bindLocalAddress(t http.RoundTripper, addr string) (http.RoundTripper, error) {
+	ht, ok := t.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("local address needs an *http.Transport, got %T", t)
+	}
+	local, err := resolveLocalAddress(addr)
+	if err != nil {
+		return nil, fmt.Errorf("local address %q: %w", addr, err)
+	}
+
+	ht = ht.Clone()
+	ht.DialContext = (&net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, LocalAddr: local}).DialContext
+	if local.Port != 0 {
+		ht.MaxConnsPerHost = 1
+	}
+	return ht, nil
+}
+
+// resolveLocalAddress parses an IP address or interface name with optional
+// port into a TCP address.
+func resolveLocalAddress(addr string) (*net.TCPAddr, error) {
+	host, port := addr, 0
+	if h, p, err := net.SplitHostPort(addr); err == nil {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 || n > 65535 {
+			return nil, fmt.Errorf("invalid port %q", p)
+		}
+		host, port = h, n
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return &net.TCPAddr{IP: ip, Port: port}, nil
+	}
+
+	iface, err := net.InterfaceByName(host)
+	if err != nil {
+		return nil, fmt.Errorf("neither an IP address nor a network interface: %w", err)
+	}
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return nil, fmt.Errorf("addresses of interface %s: %w", host, err)
+	}
+	var ip net.IP
+	for _, a := range addrs {
+		ipNet, ok := a.(*net.IPNet)
+		if !ok {
+			continue
+		}
+		if ipNet.IP.To4() != nil {
+			ip = ipNet.IP
+			break
+		}
+		if ip == nil {
+			ip = ipNet.IP
+		}
+	}
+	if ip == nil {
+		return nil, fmt.Errorf("interface %s has no IP address", host)
+	}
+	return &net.TCPAddr{IP: ip, Port: port}, nil
+}
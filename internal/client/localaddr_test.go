@@ -0,0 +1,66 @@
+// internal/client/localaddr_test.go
+package client
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestClient_LocalAddress(t *testing.T) {
+	remote := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		remote <- r.RemoteAddr
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"applications": []}`))
+	}))
+	defer server.Close()
+
+	// Find a free local port to connect from
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := l.Addr().(*net.TCPAddr).Port
+	l.Close()
+
+	local := net.JoinHostPort("127.0.0.1", strconv.Itoa(port))
+	c, err := NewClient(server.URL, "u", "p", newTestLogger(), WithLocalAddress(local))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if _, err := c.GetApplications(rCtx(t)); err != nil {
+		t.Fatalf("GetApplications from %s: %v", local, err)
+	}
+	if got := <-remote; got != local {
+		t.Errorf("request came from %s, want %s", got, local)
+	}
+
+	if _, err := NewClient(server.URL, "u", "p", newTestLogger(), WithLocalAddress("no-such-if0")); err == nil || !strings.Contains(err.Error(), "network interface") {
+		t.Errorf("NewClient with unknown interface error = %v", err)
+	}
+}
+
+func TestResolveLocalAddress_Interface(t *testing.T) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		t.Skipf("list interfaces: %v", err)
+	}
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagLoopback == 0 {
+			continue
+		}
+		addr, err := resolveLocalAddress(iface.Name + ":0")
+		if err != nil {
+			t.Fatalf("resolveLocalAddress(%s): %v", iface.Name, err)
+		}
+		if !addr.IP.IsLoopback() {
+			t.Errorf("loopback interface %s resolved to %s", iface.Name, addr.IP)
+		}
+		return
+	}
+	t.Skip("no loopback interface")
+}
@@ -0,0 +1,100 @@
+// internal/client/logging.go
+package client
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/rs/zerolog"
+)
+
+// BodyLogging controls how response bodies are written to the logs.
+type BodyLogging struct {
+	// Level is the level at which bodies are logged; they only appear when
+	// the logger is enabled for it. zerolog.Disabled turns body logging off.
+	Level zerolog.Level
+	// MaxBytes truncates logged bodies to this many bytes; zero logs them
+	// whole.
+	MaxBytes int
+}
+
+// DefaultBodyLogging logs bodies at trace level, truncated to 4 KiB.
+var DefaultBodyLogging = BodyLogging{Level: zerolog.TraceLevel, MaxBytes: 4096}
+
+// maxErrorBody bounds the response body quoted in error messages.
+const maxErrorBody = 1024
+
+// WithBodyLogging sets how response bodies are logged, see BodyLogging.
+func WithBodyLogging(b BodyLogging) Option {
+	return func(o *options) { o.bodyLogging = &b }
+}
+
+// sensitiveHeaders are replaced by a placeholder when headers are logged.
+var sensitiveHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key"}
+
+// sensitiveParams are substrings of query parameter names whose values are
+// replaced by a placeholder when URLs are logged.
+var sensitiveParams = []string{"token", "key", "secret", "password", "signature", "auth"}
+
+const redacted = "[REDACTED]"
+
+// logBody logs the body of resp as configured by BodyLogging.
+func (c *Client) logBody(resp *resty.Response) {
+	ev := c.logger.WithLevel(c.bodyLogging.Level)
+	if !ev.Enabled() {
+		return
+	}
+	body := resp.String()
+	ev.Int("status", resp.StatusCode()).
+		Int("bytes", len(body)).
+		Str("body", truncate(body, c.bodyLogging.MaxBytes)).
+		Msg("raw response")
+}
+
+// truncate shortens s to at most max bytes, noting the number of bytes cut.
+// A max of zero or less keeps s whole.
+func truncate(s string, max int) string {
+	if max <= 0 || len(s) <= max {
+		return s
+	}
+	return s[:max] + "…[" + strconv.Itoa(len(s)-max) + " bytes truncated]"
+}
+
+// redactHeaders returns h for logging, with the values of credentials
+// headers replaced.
+func redactHeaders(h http.Header) http.Header {
+	out := h.Clone()
+	for _, name := range sensitiveHeaders {
+		if out.Get(name) != "" {
+			out.Set(name, redacted)
+		}
+	}
+	return out
+}
+
+// redactQuery returns the encoded query for logging, with the values of
+// parameters that look like credentials replaced.
+func redactQuery(q url.Values) string {
+	out := make(url.Values, len(q))
+	for key, values := range q {
+		lower := strings.ToLower(key)
+		sensitive := false
+		for _, s := range sensitiveParams {
+			if strings.Contains(lower, s) {
+				sensitive = true
+				break
+			}
+		}
+		if !sensitive {
+			out[key] = values
+			continue
+		}
+		for range values {
+			out.Add(key, redacted)
+		}
+	}
+	return out.Encode()
+}
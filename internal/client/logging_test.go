@@ -0,0 +1,81 @@
+// internal/client/logging_test.go
+package client
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestClient_BodyLogging(t *testing.T) {
+	body := `{"applications": [{"id": "a1", "publicId": "` + strings.Repeat("x", 200) + `", "organizationId": "o1"}]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		level    zerolog.Level
+		bodies   BodyLogging
+		wantBody bool
+	}{
+		{"default hides bodies at debug", zerolog.DebugLevel, DefaultBodyLogging, false},
+		{"debug bodies", zerolog.DebugLevel, BodyLogging{Level: zerolog.DebugLevel, MaxBytes: 32}, true},
+		{"disabled", zerolog.TraceLevel, BodyLogging{Level: zerolog.Disabled}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			logger := zerolog.New(&logs).Level(tt.level)
+			c, err := NewClient(server.URL, "u", "p", logger, WithBodyLogging(tt.bodies))
+			if err != nil {
+				t.Fatalf("NewClient: %v", err)
+			}
+			if _, err := c.GetApplications(rCtx(t)); err != nil {
+				t.Fatalf("GetApplications: %v", err)
+			}
+			out := logs.String()
+			if got := strings.Contains(out, `"raw response"`); got != tt.wantBody {
+				t.Fatalf("body logged = %v, want %v", got, tt.wantBody)
+			}
+			if tt.wantBody && (strings.Contains(out, strings.Repeat("x", 200)) || !strings.Contains(out, "bytes truncated")) {
+				t.Errorf("body not truncated: %s", out)
+			}
+			if strings.Contains(out, "dTpw") { // base64 of u:p
+				t.Errorf("credentials logged: %s", out)
+			}
+		})
+	}
+}
+
+func TestRedaction(t *testing.T) {
+	h := http.Header{}
+	h.Set("Authorization", "Basic dTpw")
+	h.Set("Accept", "application/json")
+	got := redactHeaders(h)
+	if got.Get("Authorization") != redacted || got.Get("Accept") != "application/json" {
+		t.Errorf("redactHeaders = %v", got)
+	}
+	if h.Get("Authorization") != "Basic dTpw" {
+		t.Error("redactHeaders modified its input")
+	}
+
+	q := url.Values{"apiKey": {"s3cret"}, "publicId": {"app-1"}}
+	if got := redactQuery(q); strings.Contains(got, "s3cret") || !strings.Contains(got, "publicId=app-1") {
+		t.Errorf("redactQuery = %q", got)
+	}
+
+	if got := truncate("abcdef", 3); got != "abc…[3 bytes truncated]" {
+		t.Errorf("truncate = %q", got)
+	}
+	if got := truncate("abc", 0); got != "abc" {
+		t.Errorf("truncate without limit = %q", got)
+	}
+}
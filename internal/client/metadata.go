@@ -0,0 +1,75 @@
+// internal/client/metadata.go
+package client
+
+import (
+	"context"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// Request metadata headers sent to IQ Server, so that administrators can
+// trace who triggered a report pull and why.
+const (
+	HeaderRunID       = "X-IQFetch-Run-ID"
+	HeaderTriggeredBy = "X-IQFetch-Triggered-By"
+	HeaderReason      = "X-IQFetch-Reason"
+)
+
+// Metadata describes the run a request is made for. Empty fields are not
+// sent.
+type Metadata struct {
+	RunID       string
+	TriggeredBy string // user or system that triggered the run
+	Reason      string
+}
+
+// metadataKey is the context key carrying the request metadata.
+type metadataKey struct{}
+
+// WithMetadata returns a context whose requests carry md as headers and in
+// their log lines.
+func WithMetadata(ctx context.Context, md Metadata) context.Context {
+	return context.WithValue(ctx, metadataKey{}, md)
+}
+
+// MetadataFromContext returns the metadata attached with WithMetadata.
+func MetadataFromContext(ctx context.Context) (Metadata, bool) {
+	md, ok := ctx.Value(metadataKey{}).(Metadata)
+	return md, ok
+}
+
+// headers returns the non-empty metadata fields by header name. Control
+// characters are replaced, as they are not allowed in header values.
+func (m Metadata) headers() map[string]string {
+	h := make(map[string]string, 3)
+	for name, v := range map[string]string{HeaderRunID: m.RunID, HeaderTriggeredBy: m.TriggeredBy, HeaderReason: m.Reason} {
+		if v = strings.TrimSpace(headerSafe(v)); v != "" {
+			h[name] = v
+		}
+	}
+	return h
+}
+
+// Fields adds the non-empty metadata fields to a log context.
+func (m Metadata) Fields(lc zerolog.Context) zerolog.Context {
+	if m.RunID != "" {
+		lc = lc.Str("runId", m.RunID)
+	}
+	if m.TriggeredBy != "" {
+		lc = lc.Str("triggeredBy", m.TriggeredBy)
+	}
+	if m.Reason != "" {
+		lc = lc.Str("reason", m.Reason)
+	}
+	return lc
+}
+
+func headerSafe(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return ' '
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,41 @@
+// internal/client/metadata_test.go
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_MetadataHeaders(t *testing.T) {
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"applications": []}`))
+	}))
+	defer server.Close()
+
+	c, err := NewClient(server.URL, "u", "p", newTestLogger())
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	if _, err := c.GetApplications(rCtx(t)); err != nil {
+		t.Fatalf("GetApplications: %v", err)
+	}
+	if got.Get(HeaderRunID) != "" {
+		t.Errorf("unexpected run ID header without metadata: %q", got.Get(HeaderRunID))
+	}
+
+	ctx := WithMetadata(rCtx(t), Metadata{RunID: "run-1", TriggeredBy: "alice", Reason: "audit\r\nX-Injected: 1"})
+	if _, err := c.GetApplications(ctx); err != nil {
+		t.Fatalf("GetApplications: %v", err)
+	}
+	if got.Get(HeaderRunID) != "run-1" || got.Get(HeaderTriggeredBy) != "alice" {
+		t.Errorf("metadata headers = %v", got)
+	}
+	if reason := got.Get(HeaderReason); reason != "audit  X-Injected: 1" || got.Get("X-Injected") != "" {
+		t.Errorf("reason header = %q", reason)
+	}
+}
@@ -0,0 +1,133 @@
+// internal/client/owners.go
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Role is an IQ Server role such as Owner or Developer.
+type Role struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type rolesEnvelope struct {
+	Roles []Role `json:"roles"`
+}
+
+// Member types of role memberships.
+const (
+	MemberUser  = "USER"
+	MemberGroup = "GROUP"
+)
+
+// RoleMember is a user or group holding a role.
+type RoleMember struct {
+	Type            string `json:"type"` // MemberUser or MemberGroup
+	UserOrGroupName string `json:"userOrGroupName"`
+}
+
+// RoleMembership lists the members holding one role.
+type RoleMembership struct {
+	RoleID  string       `json:"roleId"`
+	Members []RoleMember `json:"members"`
+}
+
+type roleMembershipsEnvelope struct {
+	MemberMappings []RoleMembership `json:"memberMappings"`
+}
+
+// User is an IQ Server user.
+type User struct {
+	Username  string `json:"username"`
+	FirstName string `json:"firstName"`
+	LastName  string `json:"lastName"`
+	Email     string `json:"email"`
+}
+
+// DisplayName returns the full name of the user, or the username when no
+// name is set.
+func (u User) DisplayName() string {
+	if name := strings.TrimSpace(u.FirstName + " " + u.LastName); name != "" {
+		return name
+	}
+	return u.Username
+}
+
+// GetRoles fetches the roles defined in IQ Server.
+func (c *Client) GetRoles(ctx context.Context) ([]Role, error) {
+	const endpoint = "roles"
+	c.logger.Debug().Msg("Fetching roles")
+
+	var env rolesEnvelope
+	resp, err := c.request(ctx, endpoint).
+		SetResult(&env).
+		Get(endpoint)
+	if err != nil {
+		return nil, transportError(err)
+	}
+	if resp.IsError() {
+		return nil, httpError(resp, resp.Status())
+	}
+	if err := checkJSON(resp); err != nil {
+		return nil, err
+	}
+	if env.Roles == nil {
+		return nil, parseError("unexpected response from %s: missing \"roles\" field", endpoint)
+	}
+	return env.Roles, nil
+}
+
+// GetApplicationRoleMemberships fetches the role memberships granted
+// directly on the application with the given internal ID. Memberships
+// inherited from organizations are not included.
+func (c *Client) GetApplicationRoleMemberships(ctx context.Context, appID string) ([]RoleMembership, error) {
+	c.logger.Debug().Str("appId", appID).Msg("Fetching application role memberships")
+
+	endpoint := fmt.Sprintf("roleMemberships/application/%s", url.PathEscape(appID))
+	var env roleMembershipsEnvelope
+	resp, err := c.request(ctx, "roleMemberships/application/{id}").
+		SetResult(&env).
+		Get(endpoint)
+	if err != nil {
+		return nil, transportError(err)
+	}
+	if resp.IsError() {
+		return nil, httpError(resp, resp.Status())
+	}
+	if err := checkJSON(resp); err != nil {
+		return nil, err
+	}
+	if env.MemberMappings == nil {
+		return nil, parseError("unexpected response from %s: missing \"memberMappings\" field", endpoint)
+	}
+	return env.MemberMappings, nil
+}
+
+// GetUser fetches the user with the given username. An unknown username is
+// reported as an ErrNotFound error.
+func (c *Client) GetUser(ctx context.Context, username string) (*User, error) {
+	c.logger.Debug().Str("username", username).Msg("Fetching user")
+
+	endpoint := fmt.Sprintf("users/%s", url.PathEscape(username))
+	var user User
+	resp, err := c.request(ctx, "users/{username}").
+		SetResult(&user).
+		Get(endpoint)
+	if err != nil {
+		return nil, transportError(err)
+	}
+	if resp.IsError() {
+		return nil, httpError(resp, resp.Status())
+	}
+	if err := checkJSON(resp); err != nil {
+		return nil, err
+	}
+	if user.Username == "" {
+		return nil, parseError("unexpected response from %s: missing \"username\" field", endpoint)
+	}
+	return &user, nil
+}
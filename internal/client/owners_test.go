@@ -0,0 +1,58 @@
+// internal/client/owners_test.go
+package client
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_Owners(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v2/roles":
+			w.Write([]byte(`{"roles": [{"id": "r-owner", "name": "Owner"}, {"id": "r-dev", "name": "Developer"}]}`))
+		case "/api/v2/roleMemberships/application/aid-1":
+			w.Write([]byte(`{"memberMappings": [{"roleId": "r-owner", "members": [{"type": "USER", "userOrGroupName": "jdoe"}, {"type": "GROUP", "userOrGroupName": "team-a"}]}]}`))
+		case "/api/v2/users/jdoe":
+			w.Write([]byte(`{"username": "jdoe", "firstName": "Jane", "lastName": "Doe", "email": "jane@example.com"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(server.URL+"/api/v2", "u", "p", newTestLogger())
+	roles, err := c.GetRoles(rCtx(t))
+	if err != nil {
+		t.Fatalf("GetRoles error = %v", err)
+	}
+	if len(roles) != 2 || roles[0].ID != "r-owner" || roles[0].Name != "Owner" {
+		t.Errorf("unexpected roles: %#v", roles)
+	}
+
+	memberships, err := c.GetApplicationRoleMemberships(rCtx(t), "aid-1")
+	if err != nil {
+		t.Fatalf("GetApplicationRoleMemberships error = %v", err)
+	}
+	if len(memberships) != 1 || len(memberships[0].Members) != 2 || memberships[0].Members[1].Type != MemberGroup {
+		t.Errorf("unexpected memberships: %#v", memberships)
+	}
+
+	user, err := c.GetUser(rCtx(t), "jdoe")
+	if err != nil {
+		t.Fatalf("GetUser error = %v", err)
+	}
+	if user.DisplayName() != "Jane Doe" || user.Email != "jane@example.com" {
+		t.Errorf("unexpected user: %#v", user)
+	}
+	if got := (User{Username: "bot"}).DisplayName(); got != "bot" {
+		t.Errorf("DisplayName without name = %q, want username", got)
+	}
+
+	if _, err := c.GetUser(rCtx(t), "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetUser(missing) error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,112 @@
+// internal/client/paths.go
+package client
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// apiEndpoints are the IQ Server API endpoints requested below the base URL,
+// as path templates relative to it. They are the keys of path overrides.
+var apiEndpoints = []string{
+	"applications",
+	"applications/organization/{id}",
+	"applications/{publicId}/reports/{reportId}/policy",
+	"applicationCategories/organization/{id}",
+	"evaluation/applications/{id}/results/{resultId}",
+	"organizations",
+	"organizations/{id}",
+	"policyWaivers/application/{id}",
+	"policyWaivers/application/{id}/{violationId}",
+	"reports/applications/{id}",
+	"reports/applications/{id}/history",
+	"roleMemberships/application/{id}",
+	"roles",
+	"users/{username}",
+}
+
+// APIEndpoints returns the path templates of the API endpoints whose paths
+// can be overridden with WithPathOverrides.
+func APIEndpoints() []string {
+	return slices.Clone(apiEndpoints)
+}
+
+var pathPlaceholder = regexp.MustCompile(`\{[A-Za-z]+\}`)
+
+// WithPathOverrides requests endpoints at other paths, e.g. where an API
+// gateway rewrites IQ Server paths. Keys are endpoint templates (see
+// APIEndpoints); values are the paths to request instead, relative to the
+// base URL or, when starting with "/", to the server host. Values may use
+// the placeholders of their key, e.g.
+//
+//	"reports/applications/{id}": "/gateway/iq/reports/{id}"
+func WithPathOverrides(overrides map[string]string) Option {
+	return func(o *options) { o.pathOverrides = overrides }
+}
+
+// pathRewriter rewrites request paths by path overrides.
+type pathRewriter struct {
+	overrides map[string]string // endpoint template -> override template
+	host      string            // scheme and host of the base URL
+}
+
+// newPathRewriter validates overrides against the known endpoints.
+func newPathRewriter(overrides map[string]string, baseURL string) (*pathRewriter, error) {
+	for endpoint, override := range overrides {
+		if !slices.Contains(apiEndpoints, endpoint) {
+			return nil, fmt.Errorf("path override for unknown endpoint %q (known endpoints: %s)", endpoint, strings.Join(apiEndpoints, ", "))
+		}
+		for _, ph := range pathPlaceholder.FindAllString(override, -1) {
+			if !strings.Contains(endpoint, ph) {
+				return nil, fmt.Errorf("path override for %s: placeholder %s is not part of the endpoint", endpoint, ph)
+			}
+		}
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid baseURL: %w", err)
+	}
+	return &pathRewriter{overrides: overrides, host: u.Scheme + "://" + u.Host}, nil
+}
+
+// rewrite points req at the override of its endpoint, if any. Requests to
+// absolute URLs, such as those at the server root, are left alone.
+func (p *pathRewriter) rewrite(req *resty.Request) {
+	template, _, _ := strings.Cut(endpointFromContext(req.Context()), "?")
+	override, ok := p.overrides[template]
+	if !ok || strings.Contains(req.URL, "://") {
+		return
+	}
+	path, ok := expandOverride(template, strings.TrimPrefix(req.URL, "/"), override)
+	if !ok {
+		return
+	}
+	if strings.HasPrefix(path, "/") {
+		path = p.host + path
+	}
+	req.URL = path
+}
+
+// expandOverride fills the placeholders of override with the values they
+// have in path, which is an instance of template. It reports false when path
+// does not match template.
+func expandOverride(template, path, override string) (string, bool) {
+	tsegs, psegs := strings.Split(template, "/"), strings.Split(path, "/")
+	if len(tsegs) != len(psegs) {
+		return "", false
+	}
+	values := make(map[string]string)
+	for i, seg := range tsegs {
+		if pathPlaceholder.MatchString(seg) && pathPlaceholder.FindString(seg) == seg {
+			values[seg] = psegs[i]
+		} else if seg != psegs[i] {
+			return "", false
+		}
+	}
+	return pathPlaceholder.ReplaceAllStringFunc(override, func(ph string) string { return values[ph] }), true
+}
@@ -0,0 +1,54 @@
+// internal/client/paths_test.go
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient_PathOverrides(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.RequestURI())
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/gateway/reports/"):
+			w.Write([]byte(`[]`))
+		default:
+			w.Write([]byte(`{"applications": [], "organizations": []}`))
+		}
+	}))
+	defer server.Close()
+
+	c, err := NewClient(server.URL, "u", "p", newTestLogger(), WithPathOverrides(map[string]string{
+		"applications":              "apps/all",
+		"reports/applications/{id}": "/gateway/reports/{id}/latest",
+	}))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if _, err := c.GetApplications(rCtx(t)); err != nil {
+		t.Fatalf("GetApplications: %v", err)
+	}
+	if _, err := c.GetReportInfos(rCtx(t), "a b"); err != nil {
+		t.Fatalf("GetReportInfos: %v", err)
+	}
+	if _, err := c.GetOrganizations(rCtx(t)); err != nil {
+		t.Fatalf("GetOrganizations: %v", err)
+	}
+	want := []string{"/api/v2/apps/all", "/gateway/reports/a%20b/latest", "/api/v2/organizations"}
+	if strings.Join(paths, " ") != strings.Join(want, " ") {
+		t.Errorf("requested %q, want %q", paths, want)
+	}
+
+	for name, overrides := range map[string]map[string]string{
+		"unknown endpoint":    {"apps": "x"},
+		"unknown placeholder": {"reports/applications/{id}": "r/{reportId}"},
+	} {
+		if _, err := NewClient(server.URL, "u", "p", newTestLogger(), WithPathOverrides(overrides)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+// internal/client/pdf.go
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// DownloadReportPDF streams the PDF rendering of a report, as produced by IQ
+// Server, to w and returns the number of bytes written. The location is taken
+// from info.ReportPDFURL (absolute or relative to the server root) and
+// derived from publicID and reportID when IQ Server does not return one.
+func (c *Client) DownloadReportPDF(ctx context.Context, info ReportInfo, publicID, reportID string, w io.Writer) (int64, error) {
+	const endpoint = "reportPdf"
+
+	pdfURL, err := c.resolveServerURL(info.ReportPDFURL, fmt.Sprintf("ui/links/application/%s/report/%s/pdf", url.PathEscape(publicID), url.PathEscape(reportID)))
+	if err != nil {
+		return 0, err
+	}
+	c.logger.Debug().Str("publicId", publicID).Str("reportId", reportID).Str("url", pdfURL).Msg("Downloading report PDF")
+
+	resp, err := c.request(ctx, endpoint).
+		SetHeader("Accept", "application/pdf").
+		SetDoNotParseResponse(true).
+		Get(pdfURL)
+	if err != nil {
+		return 0, transportError(err)
+	}
+	body := resp.RawBody()
+	defer body.Close() //nolint:errcheck
+
+	if resp.IsError() {
+		return 0, httpError(resp, resp.Status())
+	}
+	if contentType := strings.ToLower(resp.Header().Get("Content-Type")); !strings.Contains(contentType, "pdf") {
+		return 0, parseError("unexpected content type %q from %s, expected a PDF", contentType, pdfURL)
+	}
+
+	n, err := io.Copy(w, body)
+	// The body is streamed, so bytes are counted here rather than in the
+	// response hook
+	c.transfer.record(endpoint, applicationFromContext(ctx), n)
+	if err != nil {
+		return n, transportError(err)
+	}
+	return n, nil
+}
+
+// resolveServerURL resolves ref against the server root (the base URL
+// without /api/v2). Absolute references are returned unchanged; an empty ref
+// is replaced by fallback.
+func (c *Client) resolveServerURL(ref, fallback string) (string, error) {
+	if strings.TrimSpace(ref) == "" {
+		ref = fallback
+	}
+	root, err := url.Parse(strings.TrimSuffix(c.baseURL, strings.TrimPrefix(apiPrefix, "/")+"/"))
+	if err != nil {
+		return "", fmt.Errorf("invalid base URL: %w", err)
+	}
+	ref = strings.TrimSpace(ref)
+	// Root-relative links omit the context path IQ Server runs under
+	if strings.HasPrefix(ref, "/") && !strings.HasPrefix(ref, "//") && !strings.HasPrefix(ref, root.EscapedPath()) {
+		ref = strings.TrimPrefix(ref, "/")
+	}
+	u, err := url.Parse(ref)
+	if err != nil {
+		return "", parseError("invalid URL %q: %v", ref, err)
+	}
+	return root.ResolveReference(u).String(), nil
+}
@@ -0,0 +1,54 @@
+// internal/client/pdf_test.go
+package client
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_DownloadReportPDF(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		switch r.URL.Path {
+		case "/iq/ui/links/application/app-1/report/rpt-1/pdf", "/iq/ui/links/application/app-1/report/rpt-2/pdf":
+			w.Header().Set("Content-Type", "application/pdf")
+			_, _ = w.Write([]byte("%PDF-1.4 stub"))
+		default:
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte("<html>login</html>"))
+		}
+	}))
+	defer server.Close()
+
+	c, err := NewClient(server.URL+"/iq", "u", "p", newTestLogger())
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	// Relative reportPdfUrl
+	var buf bytes.Buffer
+	info := ReportInfo{ReportPDFURL: "ui/links/application/app-1/report/rpt-1/pdf"}
+	n, err := c.DownloadReportPDF(WithApplication(rCtx(t), "app-1"), info, "app-1", "rpt-1", &buf)
+	if err != nil || n != int64(buf.Len()) || buf.String() != "%PDF-1.4 stub" {
+		t.Fatalf("DownloadReportPDF = %d, %v; body %q", n, err, buf.String())
+	}
+	if got := c.Transfer().ByApplication["app-1"]; got != n {
+		t.Errorf("transfer for app-1 = %d, want %d", got, n)
+	}
+
+	// Derived location when IQ Server returns none
+	buf.Reset()
+	if _, err := c.DownloadReportPDF(rCtx(t), ReportInfo{}, "app-1", "rpt-2", &buf); err != nil {
+		t.Fatalf("DownloadReportPDF fallback: %v", err)
+	}
+
+	// A login page instead of a PDF is a parse error
+	info = ReportInfo{ReportPDFURL: server.URL + "/iq/ui/other"}
+	if _, err := c.DownloadReportPDF(rCtx(t), info, "app-1", "rpt-3", &buf); !errors.Is(err, ErrParse) {
+		t.Errorf("err = %v, want ErrParse", err)
+	}
+}
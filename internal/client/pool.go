@@ -0,0 +1,90 @@
+// internal/client/pool.go
+package client
+
+import (
+	"errors"
+	"sync"
+)
+
+// Pool selects the Client to use for a given organization. Organizations
+// with a dedicated (scoped) service account get their own Client; all other
+// organizations fall back to the default Client.
+type Pool struct {
+	mu    sync.RWMutex
+	def   *Client
+	byOrg map[string]*Client
+}
+
+// NewPool creates a Pool that falls back to def for unmapped organizations.
+func NewPool(def *Client) *Pool {
+	return &Pool{def: def, byOrg: make(map[string]*Client)}
+}
+
+// Add registers cl as the Client for the organization orgID.
+func (p *Pool) Add(orgID string, cl *Client) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.byOrg[orgID] = cl
+}
+
+// Default returns the fallback Client used for unmapped organizations and
+// for instance-wide calls such as listing applications.
+func (p *Pool) Default() *Client {
+	return p.def
+}
+
+// For returns the Client configured for orgID, or the default Client.
+func (p *Pool) For(orgID string) *Client {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if cl, ok := p.byOrg[orgID]; ok {
+		return cl
+	}
+	return p.def
+}
+
+// clients returns the distinct clients of the pool, default first.
+// The caller must hold p.mu.
+func (p *Pool) clients() []*Client {
+	out := []*Client{p.def}
+	for _, cl := range p.byOrg {
+		if cl != p.def {
+			out = append(out, cl)
+		}
+	}
+	return out
+}
+
+// Close closes every client of the pool, see Client.Close.
+func (p *Pool) Close() error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	var errs []error
+	for _, cl := range p.clients() {
+		errs = append(errs, cl.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// Transfer sums the bytes downloaded by every client in the pool.
+func (p *Pool) Transfer() Transfer {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	var transfers []Transfer
+	for _, cl := range p.clients() {
+		transfers = append(transfers, cl.Transfer())
+	}
+	return mergeTransfers(transfers...)
+}
+
+// Timings merges the per-endpoint timings of every client in the pool,
+// sorted by total time (slowest endpoint first).
+func (p *Pool) Timings() []EndpointTiming {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	var sets [][]EndpointTiming
+	for _, cl := range p.clients() {
+		sets = append(sets, cl.timings.snapshot())
+	}
+	return mergeTimings(sets...)
+}
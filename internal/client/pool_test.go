@@ -0,0 +1,28 @@
+// internal/client/pool_test.go
+package client
+
+import "testing"
+
+func TestPool_ForFallsBackToDefault(t *testing.T) {
+	def, err := NewClient("http://localhost/api/v2", "u", "p", newTestLogger())
+	if err != nil {
+		t.Fatalf("NewClient error = %v", err)
+	}
+	scoped, err := NewClient("http://localhost/api/v2", "svc", "p", newTestLogger())
+	if err != nil {
+		t.Fatalf("NewClient error = %v", err)
+	}
+
+	pool := NewPool(def)
+	pool.Add("org-1", scoped)
+
+	if got := pool.For("org-1"); got != scoped {
+		t.Errorf("For(org-1) returned default client, want scoped client")
+	}
+	if got := pool.For("org-2"); got != def {
+		t.Errorf("For(org-2) did not fall back to default client")
+	}
+	if got := pool.Default(); got != def {
+		t.Errorf("Default() returned unexpected client")
+	}
+}
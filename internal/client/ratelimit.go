@@ -0,0 +1,106 @@
+// internal/client/ratelimit.go
+package client
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// RateLimiter spaces out the requests of the clients sharing it, and holds
+// them all back while IQ Server asked to slow down with a Retry-After
+// header. It is safe for concurrent use.
+type RateLimiter struct {
+	interval time.Duration // between request starts; zero does not space them out
+
+	mu     sync.Mutex
+	next   time.Time // earliest start of the next request
+	paused time.Time // no request starts before
+}
+
+// NewRateLimiter returns a RateLimiter allowing perSecond requests per
+// second. Zero or less does not limit the rate; the limiter then only
+// honors Retry-After pauses.
+func NewRateLimiter(perSecond float64) *RateLimiter {
+	l := &RateLimiter{}
+	if perSecond > 0 {
+		l.interval = time.Duration(float64(time.Second) / perSecond)
+	}
+	return l
+}
+
+// Wait blocks until a request may start, or until ctx is done.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	start := time.Now()
+	if l.paused.After(start) {
+		start = l.paused
+	}
+	if l.next.After(start) {
+		start = l.next
+	}
+	l.next = start.Add(l.interval)
+	l.mu.Unlock()
+
+	wait := time.Until(start)
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Pause holds back all requests for d.
+func (l *RateLimiter) Pause(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if until := time.Now().Add(d); until.After(l.paused) {
+		l.paused = until
+	}
+}
+
+// WithRateLimiter makes requests wait for limiter, which may be shared by
+// several clients to limit their combined rate. A response with HTTP 429 or
+// 503 and a Retry-After header pauses the limiter for the delay given.
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(o *options) { o.limiter = limiter }
+}
+
+// rateLimitTransport is an http.RoundTripper that starts requests as the
+// limiter allows.
+type rateLimitTransport struct {
+	next    http.RoundTripper
+	limiter *RateLimiter
+	logger  zerolog.Logger
+}
+
+// CloseIdleConnections closes the idle connections of the next transport.
+func (t rateLimitTransport) CloseIdleConnections() { closeIdleConnections(t.next) }
+
+// RoundTrip implements http.RoundTripper.
+func (t rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(req.Context()); err != nil {
+		if req.Body != nil {
+			_ = req.Body.Close()
+		}
+		return nil, err
+	}
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || (resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable) {
+		return resp, err
+	}
+	if d := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); d > 0 {
+		t.logger.Warn().Int("status", resp.StatusCode).Dur("pause", d).Msg("IQ Server asked to slow down; pausing requests")
+		t.limiter.Pause(d)
+	}
+	return resp, nil
+}
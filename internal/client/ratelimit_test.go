@@ -0,0 +1,74 @@
+// internal/client/ratelimit_test.go
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_SpacesRequests(t *testing.T) {
+	l := NewRateLimiter(100)
+	start := time.Now()
+	for range 5 {
+		if err := l.Wait(rCtx(t)); err != nil {
+			t.Fatalf("Wait: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("5 requests at 100/s took %s, want at least 40ms", elapsed)
+	}
+
+	l.Pause(time.Hour)
+	ctx, cancel := context.WithTimeout(rCtx(t), 10*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait while paused = %v, want the context error", err)
+	}
+}
+
+func TestClient_HonorsRetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"organizations": []}`))
+	}))
+	defer server.Close()
+
+	limiter := NewRateLimiter(0)
+	c, err := NewClient(server.URL, "u", "p", newTestLogger(),
+		WithRateLimiter(limiter), WithRetry(RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	start := time.Now()
+	if _, err := c.GetOrganizations(rCtx(t)); err != nil {
+		t.Fatalf("GetOrganizations: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 900*time.Millisecond {
+		t.Errorf("retried after %s, want the Retry-After delay of 1s", elapsed)
+	}
+
+	// Other clients sharing the limiter are held back too
+	limiter.Pause(200 * time.Millisecond)
+	other, err := NewClient(server.URL, "u", "p", newTestLogger(), WithRateLimiter(limiter))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	start = time.Now()
+	if _, err := other.GetOrganizations(rCtx(t)); err != nil {
+		t.Fatalf("GetOrganizations: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("request of a paused limiter started after %s", elapsed)
+	}
+}
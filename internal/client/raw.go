@@ -0,0 +1,20 @@
+// internal/client/raw.go
+package client
+
+import "context"
+
+// rawResponseKey is the context key carrying the raw response callback.
+type rawResponseKey struct{}
+
+// WithRawResponse returns a context that passes the body of every
+// successful response to requests made with it to fn, e.g. to archive the
+// exact server response a report was built from. fn must not retain body
+// beyond the call unless it copies it.
+func WithRawResponse(ctx context.Context, fn func(body []byte)) context.Context {
+	return context.WithValue(ctx, rawResponseKey{}, fn)
+}
+
+func rawResponseFromContext(ctx context.Context) func([]byte) {
+	fn, _ := ctx.Value(rawResponseKey{}).(func([]byte))
+	return fn
+}
@@ -0,0 +1,31 @@
+// internal/client/raw_test.go
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWithRawResponse(t *testing.T) {
+	const body = `{"components": []}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	c, err := NewClient(server.URL, "u", "p", newTestLogger())
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	var raw []byte
+	ctx := WithRawResponse(rCtx(t), func(b []byte) { raw = append([]byte(nil), b...) })
+	if _, err := c.GetPolicyViolations(ctx, "app", "rpt", "org"); err != nil {
+		t.Fatalf("GetPolicyViolations: %v", err)
+	}
+	if string(raw) != body {
+		t.Errorf("raw body = %q, want %q", raw, body)
+	}
+}
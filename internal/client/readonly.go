@@ -0,0 +1,47 @@
+// internal/client/readonly.go
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrReadOnly is returned for requests that could modify IQ Server state
+// (anything but GET, HEAD and OPTIONS) on a read-only client. It is not
+// classified by KindOf and is never retried.
+var ErrReadOnly = errors.New("request not allowed in read-only mode")
+
+// WithReadOnly rejects every request that could modify IQ Server state,
+// such as triggering evaluations or creating waivers, when readOnly is
+// true. The check sits in the HTTP transport, so it also covers requests
+// added to the client later.
+func WithReadOnly(readOnly bool) Option {
+	return func(o *options) { o.readOnly = readOnly }
+}
+
+// ReadOnly reports whether the client was created with WithReadOnly.
+func (c *Client) ReadOnly() bool {
+	return c.readOnly
+}
+
+// readOnlyTransport is an http.RoundTripper that only lets safe methods
+// through.
+type readOnlyTransport struct {
+	next http.RoundTripper
+}
+
+// CloseIdleConnections closes the idle connections of the next transport.
+func (t readOnlyTransport) CloseIdleConnections() { closeIdleConnections(t.next) }
+
+// RoundTrip implements http.RoundTripper.
+func (t readOnlyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return t.next.RoundTrip(req)
+	}
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+	return nil, fmt.Errorf("%s %s: %w", req.Method, req.URL.Path, ErrReadOnly)
+}
@@ -0,0 +1,51 @@
+// internal/client/readonly_test.go
+package client
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestClient_ReadOnly(t *testing.T) {
+	var writes atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writes.Add(1)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"applications": []}`))
+	}))
+	defer server.Close()
+
+	c, err := NewClient(server.URL, "u", "p", newTestLogger(), WithReadOnly(true))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if !c.ReadOnly() {
+		t.Error("ReadOnly() = false")
+	}
+	if _, err := c.GetApplications(rCtx(t)); err != nil {
+		t.Fatalf("GET in read-only mode: %v", err)
+	}
+
+	for _, method := range []string{http.MethodPost, http.MethodPut, http.MethodDelete, http.MethodPatch} {
+		_, err := c.request(rCtx(t), "test").SetBody(`{}`).Execute(method, "evaluation/applications/aid-1")
+		if !errors.Is(transportError(err), ErrReadOnly) {
+			t.Errorf("%s error = %v, want ErrReadOnly", method, err)
+		}
+		if IsRetryable(transportError(err)) {
+			t.Errorf("%s read-only error is retryable", method)
+		}
+	}
+	if n := writes.Load(); n != 0 {
+		t.Errorf("server received %d write requests", n)
+	}
+
+	c, _ = NewClient(server.URL, "u", "p", newTestLogger())
+	if _, err := c.request(rCtx(t), "test").Execute(http.MethodPost, "evaluation/applications/aid-1"); err != nil {
+		t.Errorf("POST without read-only mode: %v", err)
+	}
+}
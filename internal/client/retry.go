@@ -0,0 +1,128 @@
+// internal/client/retry.go
+package client
+
+import (
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// RetryPolicy repeats requests failing transiently, such as with HTTP 502
+// or 503 during a proxy restart or a connection reset, before the failure
+// is returned to the caller.
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts per request, including the
+	// first; 1 or less disables retries.
+	MaxAttempts int
+	// BaseDelay is the wait before the first retry, doubled for each
+	// further one up to MaxDelay (zero does not cap it).
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// Jitter is the fraction of each wait that is randomized, from 0 to 1,
+	// so that concurrent requests failing together do not retry together.
+	Jitter float64
+}
+
+// WithRetry retries requests per policy. Requests are retried on network
+// errors, timeouts, HTTP 429 and 5xx responses, and only for methods that
+// are safe to repeat (GET, HEAD, OPTIONS, PUT and DELETE): a POST may have
+// taken effect, e.g. started an evaluation, before the failure. A retry
+// waits at least as long as a Retry-After header of the response asks.
+func WithRetry(policy RetryPolicy) Option {
+	return func(o *options) { o.retry = policy }
+}
+
+// delay returns the wait before retry number n, counting from 1.
+func (p RetryPolicy) delay(n int) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < n && (p.MaxDelay <= 0 || d < p.MaxDelay); i++ {
+		d *= 2
+	}
+	if p.MaxDelay > 0 {
+		d = min(d, p.MaxDelay)
+	}
+	if p.Jitter > 0 {
+		d -= time.Duration(float64(d) * min(p.Jitter, 1) * rand.Float64())
+	}
+	return d
+}
+
+// retryTransport is an http.RoundTripper that repeats requests failing
+// transiently.
+type retryTransport struct {
+	next   http.RoundTripper
+	policy RetryPolicy
+	logger zerolog.Logger
+}
+
+// CloseIdleConnections closes the idle connections of the next transport.
+func (t retryTransport) CloseIdleConnections() { closeIdleConnections(t.next) }
+
+// RoundTrip implements http.RoundTripper.
+func (t retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !retrySafe(req) {
+		return t.next.RoundTrip(req)
+	}
+	for attempt := 1; ; attempt++ {
+		areq := req
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			areq = req.Clone(req.Context())
+			areq.Body = body
+		}
+
+		resp, err := t.next.RoundTrip(areq)
+		if attempt >= t.policy.MaxAttempts || req.Context().Err() != nil {
+			return resp, err
+		}
+		event := t.logger.Warn().Str("method", req.Method).Str("url", req.URL.String()).Int("attempt", attempt)
+		switch {
+		case err != nil:
+			event = event.Err(err)
+		case (&Error{Kind: statusKind(resp.StatusCode)}).Retryable():
+			event = event.Int("status", resp.StatusCode)
+		default:
+			return resp, nil
+		}
+
+		wait := t.policy.delay(attempt)
+		if err == nil {
+			wait = max(wait, parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()))
+		}
+		// A retry after the deadline would fail anyway: return this failure
+		// rather than wait for the context to expire
+		if deadline, ok := req.Context().Deadline(); ok && time.Now().Add(wait).After(deadline) {
+			event.Dur("retryIn", wait).Msg("Request failed transiently; not retrying past the deadline")
+			return resp, err
+		}
+		if err == nil {
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxErrorBody))
+			_ = resp.Body.Close()
+		}
+		event.Dur("retryIn", wait).Msg("Request failed transiently; retrying")
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// retrySafe reports whether req may be sent again: its method is idempotent
+// and its body, if any, can be replayed.
+func retrySafe(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+	default:
+		return false
+	}
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
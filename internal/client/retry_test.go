@@ -0,0 +1,133 @@
+// internal/client/retry_test.go
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_RetriesTransientFailures(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch calls.Add(1) {
+		case 1:
+			// Connection reset
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			_ = conn.Close()
+		case 2:
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"organizations": []}`))
+		}
+	}))
+	defer server.Close()
+
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+	c, err := NewClient(server.URL, "u", "p", newTestLogger(), WithRetry(policy))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if _, err := c.GetOrganizations(rCtx(t)); err != nil {
+		t.Errorf("GetOrganizations: %v", err)
+	}
+	if n := calls.Load(); n != 3 {
+		t.Errorf("server called %d times, want 3", n)
+	}
+
+	calls.Store(1)
+	policy.MaxAttempts = 1
+	c, err = NewClient(server.URL, "u", "p", newTestLogger(), WithRetry(policy))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if _, err := c.GetOrganizations(rCtx(t)); !errors.Is(err, ErrServer) {
+		t.Errorf("GetOrganizations without retries = %v, want a server error", err)
+	}
+}
+
+func TestRetryTransport_SkipsUnsafeRequests(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	rt := retryTransport{next: http.DefaultTransport, policy: RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}, logger: newTestLogger()}
+	req, _ := http.NewRequestWithContext(rCtx(t), http.MethodPost, server.URL, strings.NewReader("{}"))
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip: %v", err)
+	}
+	_ = resp.Body.Close()
+	if n := calls.Load(); n != 1 {
+		t.Errorf("POST sent %d times, want 1", n)
+	}
+}
+
+func TestClient_RetryDoesNotOutliveContext(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+	c, err := NewClient(server.URL, "u", "p", newTestLogger(), WithRetry(policy))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	// The retry would start after the deadline: the failure is returned
+	// at once
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	start := time.Now()
+	_, err = c.GetOrganizations(ctx)
+	if !errors.Is(err, ErrServer) {
+		t.Errorf("GetOrganizations error = %v, want the server error", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("GetOrganizations returned after %s, want at once", elapsed)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("server called %d times, want 1", n)
+	}
+
+	// Without a deadline, cancelling the context stops the wait
+	rt := retryTransport{next: http.DefaultTransport, policy: policy, logger: newTestLogger()}
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	start = time.Now()
+	if _, err := rt.RoundTrip(req); !errors.Is(err, context.Canceled) {
+		t.Errorf("RoundTrip error = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("RoundTrip returned after %s, want on cancellation", elapsed)
+	}
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond}
+	for n, want := range []time.Duration{100, 200, 300, 300} {
+		if got := p.delay(n + 1); got != want*time.Millisecond {
+			t.Errorf("delay(%d) = %s, want %s", n+1, got, want*time.Millisecond)
+		}
+	}
+	p.Jitter = 0.5
+	for range 20 {
+		if got := p.delay(1); got < 50*time.Millisecond || got > 100*time.Millisecond {
+			t.Errorf("jittered delay = %s, want 50ms to 100ms", got)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+// internal/client/session.go
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// IQ Server session endpoint and the cookie and header of its CSRF
+// protection, which state-changing requests in a session must echo.
+const (
+	sessionPath    = "rest/user/session"
+	csrfCookieName = "CLM-CSRF-TOKEN"
+	csrfHeaderName = "X-CSRF-TOKEN"
+)
+
+// WithSessionAuth authenticates once with the username and password to
+// establish an IQ Server session, and sends the session cookie instead of
+// basic auth with each request. Where basic auth is delegated to a slow
+// authentication backend (e.g. SSO) this avoids a round trip to it per
+// request. An expired session (HTTP 401) is re-established automatically
+// and the request retried once.
+func WithSessionAuth(enabled bool) Option {
+	return func(o *options) { o.sessionAuth = enabled }
+}
+
+// sessionTransport is an http.RoundTripper that sends requests within an
+// IQ Server session instead of with basic auth.
+type sessionTransport struct {
+	next               http.RoundTripper
+	loginURL           string
+	username, password string
+
+	mu         sync.Mutex
+	generation int // incremented on each login
+	cookies    []*http.Cookie
+	csrf       string
+}
+
+// newSessionTransport returns a sessionTransport for the API at baseURL.
+// The session endpoint is resolved against the server root, i.e. baseURL
+// without the /api/v2 prefix.
+func newSessionTransport(next http.RoundTripper, baseURL, username, password string) (*sessionTransport, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid baseURL: %w", err)
+	}
+	root := strings.TrimSuffix(strings.TrimRight(u.EscapedPath(), "/"), apiPrefix)
+	setEscapedPath(u, root+"/"+sessionPath)
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &sessionTransport{next: next, loginURL: u.String(), username: username, password: password}, nil
+}
+
+// session returns the current session, logging in first when there is
+// none or when the session of generation stale was rejected. A failed login
+// returns its response, so that callers see the status IQ Server returned.
+func (t *sessionTransport) session(req *http.Request, stale int) (generation int, login *http.Response, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.cookies != nil && t.generation != stale {
+		return t.generation, nil, nil
+	}
+
+	lreq, err := http.NewRequestWithContext(req.Context(), http.MethodPost, t.loginURL, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	lreq.SetBasicAuth(t.username, t.password)
+	lreq.Header.Set("Accept", "application/json")
+	resp, err := t.next.RoundTrip(lreq)
+	if err != nil {
+		return 0, nil, fmt.Errorf("establish session: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return 0, resp, nil
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	t.cookies, t.csrf = nil, ""
+	for _, c := range resp.Cookies() {
+		t.cookies = append(t.cookies, &http.Cookie{Name: c.Name, Value: c.Value})
+		if c.Name == csrfCookieName {
+			t.csrf = c.Value
+		}
+	}
+	if len(t.cookies) == 0 {
+		return 0, nil, fmt.Errorf("establish session: %s returned no session cookie", t.loginURL)
+	}
+	t.generation++
+	return t.generation, nil, nil
+}
+
+// CloseIdleConnections closes the idle connections of the next transport.
+func (t *sessionTransport) CloseIdleConnections() { closeIdleConnections(t.next) }
+
+// RoundTrip implements http.RoundTripper.
+func (t *sessionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// The body is replayed when the session expired
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		_ = req.Body.Close()
+	}
+
+	// A rejected session is re-established once per request
+	stale := -1
+	for attempt := 0; ; attempt++ {
+		generation, login, err := t.session(req, stale)
+		if err != nil || login != nil {
+			return login, err
+		}
+
+		t.mu.Lock()
+		cookies, csrf := t.cookies, t.csrf
+		t.mu.Unlock()
+
+		sreq := req.Clone(req.Context())
+		sreq.Header.Del("Authorization")
+		for _, c := range cookies {
+			sreq.AddCookie(c)
+		}
+		if csrf != "" {
+			sreq.Header.Set(csrfHeaderName, csrf)
+		}
+		if body != nil {
+			sreq.Body = io.NopCloser(bytes.NewReader(body))
+			sreq.ContentLength = int64(len(body))
+		}
+
+		resp, err := t.next.RoundTrip(sreq)
+		if err != nil || resp.StatusCode != http.StatusUnauthorized || attempt > 0 {
+			return resp, err
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+		stale = generation
+	}
+}
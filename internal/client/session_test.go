@@ -0,0 +1,79 @@
+// internal/client/session_test.go
+package client
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestClient_SessionAuth(t *testing.T) {
+	var logins, basic atomic.Int32
+	var session atomic.Value
+	session.Store("s1")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, _, ok := r.BasicAuth(); ok {
+			if r.URL.Path != "/"+sessionPath {
+				basic.Add(1)
+			}
+		}
+		if r.URL.Path == "/"+sessionPath {
+			if u, p, _ := r.BasicAuth(); u != "u" || p != "p" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			logins.Add(1)
+			http.SetCookie(w, &http.Cookie{Name: "CLMSESSIONID", Value: session.Load().(string)})
+			http.SetCookie(w, &http.Cookie{Name: csrfCookieName, Value: "csrf"})
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		if c, err := r.Cookie("CLMSESSIONID"); err != nil || c.Value != session.Load().(string) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Method != http.MethodGet && r.Header.Get(csrfHeaderName) != "csrf" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"applications": []}`))
+	}))
+	defer server.Close()
+
+	c, err := NewClient(server.URL, "u", "p", newTestLogger(), WithSessionAuth(true))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	for range 3 {
+		if _, err := c.GetApplications(rCtx(t)); err != nil {
+			t.Fatalf("GetApplications: %v", err)
+		}
+	}
+	if n := logins.Load(); n != 1 {
+		t.Errorf("logins = %d, want 1", n)
+	}
+	if _, err := c.request(rCtx(t), "test").SetBody(`{}`).Post("evaluation/applications/aid-1"); err != nil {
+		t.Errorf("POST in session: %v", err)
+	}
+
+	// An expired session is re-established
+	session.Store("s2")
+	if _, err := c.GetApplications(rCtx(t)); err != nil {
+		t.Fatalf("GetApplications after expiry: %v", err)
+	}
+	if n := logins.Load(); n != 2 {
+		t.Errorf("logins = %d, want 2", n)
+	}
+	if n := basic.Load(); n != 0 {
+		t.Errorf("%d API requests sent basic auth", n)
+	}
+
+	// A failed login surfaces as an authentication error
+	c, _ = NewClient(server.URL, "u", "wrong", newTestLogger(), WithSessionAuth(true))
+	if _, err := c.GetApplications(rCtx(t)); !errors.Is(err, ErrAuth) {
+		t.Errorf("GetApplications with bad credentials error = %v, want ErrAuth", err)
+	}
+}
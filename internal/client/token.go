@@ -0,0 +1,17 @@
+// internal/client/token.go
+package client
+
+// UserToken is an IQ Server user token, generated per user in the IQ
+// Server UI: a user code and passcode that authenticate like a username
+// and password, for instances where password authentication is disabled.
+type UserToken struct {
+	UserCode string
+	PassCode string
+}
+
+// WithUserToken authenticates with the user token instead of the username
+// and password given to NewClient, which may then be empty. It applies to
+// basic and session authentication alike.
+func WithUserToken(token UserToken) Option {
+	return func(o *options) { o.userToken = token }
+}
@@ -0,0 +1,37 @@
+// internal/client/token_test.go
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_UserToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "Ab3xY9" || pass != "tok3n-passcode" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"organizations": []}`))
+	}))
+	defer server.Close()
+
+	token := UserToken{UserCode: "Ab3xY9", PassCode: "tok3n-passcode"}
+	c, err := NewClient(server.URL, "", "", newTestLogger(), WithUserToken(token))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if _, err := c.GetOrganizations(rCtx(t)); err != nil {
+		t.Errorf("GetOrganizations: %v", err)
+	}
+
+	if _, err := NewClient(server.URL, "", "", newTestLogger(), WithUserToken(UserToken{UserCode: "Ab3xY9"})); err == nil {
+		t.Error("expected an error for a user token without passcode")
+	}
+	auth := HeaderAuth{Header: "REMOTE_USER"}
+	if _, err := NewClient(server.URL, "svc", "", newTestLogger(), WithUserToken(token), WithHeaderAuth(auth)); err == nil {
+		t.Error("expected an error for header and token authentication together")
+	}
+}
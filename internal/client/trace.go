@@ -0,0 +1,110 @@
+// internal/client/trace.go
+package client
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// EndpointTiming aggregates the resty trace breakdown of all requests made
+// to one logical endpoint (e.g. "reports/applications/{id}"), so slowness can
+// be attributed to the network (DNS, connect, TLS) or the server (TTFB).
+type EndpointTiming struct {
+	Endpoint     string
+	Requests     int
+	DNSLookup    time.Duration
+	Connect      time.Duration
+	TLSHandshake time.Duration
+	ServerTime   time.Duration // time to first response byte
+	Total        time.Duration
+	Slowest      time.Duration
+}
+
+// endpointKey is the context key carrying the logical endpoint name of a request.
+type endpointKey struct{}
+
+// endpointFromContext returns the logical endpoint name set by Client.request.
+func endpointFromContext(ctx context.Context) string {
+	if name, ok := ctx.Value(endpointKey{}).(string); ok {
+		return name
+	}
+	return "unknown"
+}
+
+// timingStats collects EndpointTiming values; it is safe for concurrent use.
+type timingStats struct {
+	mu     sync.Mutex
+	byName map[string]*EndpointTiming
+}
+
+func newTimingStats() *timingStats {
+	return &timingStats{byName: make(map[string]*EndpointTiming)}
+}
+
+func (s *timingStats) record(endpoint string, ti resty.TraceInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.byName[endpoint]
+	if !ok {
+		t = &EndpointTiming{Endpoint: endpoint}
+		s.byName[endpoint] = t
+	}
+	t.Requests++
+	t.DNSLookup += ti.DNSLookup
+	t.Connect += ti.ConnTime
+	t.TLSHandshake += ti.TLSHandshake
+	t.ServerTime += ti.ServerTime
+	t.Total += ti.TotalTime
+	if ti.TotalTime > t.Slowest {
+		t.Slowest = ti.TotalTime
+	}
+}
+
+func (s *timingStats) snapshot() []EndpointTiming {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]EndpointTiming, 0, len(s.byName))
+	for _, t := range s.byName {
+		out = append(out, *t)
+	}
+	return out
+}
+
+// mergeTimings sums timings per endpoint and sorts them by total time, slowest first.
+func mergeTimings(sets ...[]EndpointTiming) []EndpointTiming {
+	byName := make(map[string]*EndpointTiming)
+	for _, set := range sets {
+		for _, t := range set {
+			m, ok := byName[t.Endpoint]
+			if !ok {
+				m = &EndpointTiming{Endpoint: t.Endpoint}
+				byName[t.Endpoint] = m
+			}
+			m.Requests += t.Requests
+			m.DNSLookup += t.DNSLookup
+			m.Connect += t.Connect
+			m.TLSHandshake += t.TLSHandshake
+			m.ServerTime += t.ServerTime
+			m.Total += t.Total
+			if t.Slowest > m.Slowest {
+				m.Slowest = t.Slowest
+			}
+		}
+	}
+	out := make([]EndpointTiming, 0, len(byName))
+	for _, t := range byName {
+		out = append(out, *t)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Total > out[j].Total })
+	return out
+}
+
+// Timings returns the per-endpoint timing breakdown of all requests made by
+// this client so far, sorted by total time (slowest endpoint first).
+func (c *Client) Timings() []EndpointTiming {
+	return mergeTimings(c.timings.snapshot())
+}
@@ -0,0 +1,71 @@
+// internal/client/trace_test.go
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient_TimingsAggregatedPerEndpoint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v2/organizations":
+			w.Write([]byte(`{"organizations": []}`))
+		default:
+			w.Write([]byte(`[]`))
+		}
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(server.URL+"/api/v2", "u", "p", newTestLogger())
+	for _, id := range []string{"a", "b", "c"} {
+		if _, err := c.GetLatestReportInfo(WithApplication(rCtx(t), "pub-"+id), id); err != nil {
+			t.Fatalf("GetLatestReportInfo error = %v", err)
+		}
+	}
+	if _, err := c.GetOrganizations(rCtx(t)); err != nil {
+		t.Fatalf("GetOrganizations error = %v", err)
+	}
+
+	timings := c.Timings()
+	if len(timings) != 2 {
+		t.Fatalf("expected 2 endpoints, got %#v", timings)
+	}
+	byName := map[string]EndpointTiming{}
+	for _, tm := range timings {
+		byName[tm.Endpoint] = tm
+	}
+	if got := byName["reports/applications/{id}"].Requests; got != 3 {
+		t.Errorf("report info requests = %d, want 3", got)
+	}
+	if got := byName["organizations"].Requests; got != 1 {
+		t.Errorf("organizations requests = %d, want 1", got)
+	}
+
+	transfer := c.Transfer()
+	if transfer.ByEndpoint["reports/applications/{id}"] != 6 || transfer.ByApplication["pub-a"] != 2 {
+		t.Errorf("unexpected transfer: %#v", transfer)
+	}
+	if transfer.TotalBytes != 6+int64(len(`{"organizations": []}`)) {
+		t.Errorf("TotalBytes = %d", transfer.TotalBytes)
+	}
+}
+
+func TestMergeTimings_SortsSlowestFirst(t *testing.T) {
+	merged := mergeTimings(
+		[]EndpointTiming{{Endpoint: "fast", Requests: 1, Total: time.Millisecond, Slowest: time.Millisecond}},
+		[]EndpointTiming{
+			{Endpoint: "slow", Requests: 1, Total: time.Second, Slowest: time.Second},
+			{Endpoint: "fast", Requests: 2, Total: 2 * time.Millisecond, Slowest: 2 * time.Millisecond},
+		},
+	)
+	if len(merged) != 2 || merged[0].Endpoint != "slow" {
+		t.Fatalf("unexpected order: %#v", merged)
+	}
+	if merged[1].Requests != 3 || merged[1].Slowest != 2*time.Millisecond {
+		t.Errorf("fast endpoint not merged: %#v", merged[1])
+	}
+}
@@ -0,0 +1,75 @@
+// internal/client/transfer.go
+package client
+
+import (
+	"context"
+	"sync"
+)
+
+// Transfer holds the number of response body bytes downloaded by a client,
+// broken down per logical endpoint and per application public ID.
+type Transfer struct {
+	TotalBytes    int64
+	ByEndpoint    map[string]int64
+	ByApplication map[string]int64
+}
+
+// applicationKey is the context key carrying the application a request is made for.
+type applicationKey struct{}
+
+// WithApplication returns a context that attributes requests made with it to
+// the application publicID in transfer accounting.
+func WithApplication(ctx context.Context, publicID string) context.Context {
+	return context.WithValue(ctx, applicationKey{}, publicID)
+}
+
+func applicationFromContext(ctx context.Context) string {
+	publicID, _ := ctx.Value(applicationKey{}).(string)
+	return publicID
+}
+
+// transferStats accumulates Transfer counters; it is safe for concurrent use.
+type transferStats struct {
+	mu sync.Mutex
+	t  Transfer
+}
+
+func newTransferStats() *transferStats {
+	return &transferStats{t: Transfer{ByEndpoint: make(map[string]int64), ByApplication: make(map[string]int64)}}
+}
+
+func (s *transferStats) record(endpoint, app string, n int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.t.TotalBytes += n
+	s.t.ByEndpoint[endpoint] += n
+	if app != "" {
+		s.t.ByApplication[app] += n
+	}
+}
+
+func (s *transferStats) snapshot() Transfer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return mergeTransfers(s.t)
+}
+
+// mergeTransfers sums the given transfers into a new Transfer.
+func mergeTransfers(transfers ...Transfer) Transfer {
+	out := Transfer{ByEndpoint: make(map[string]int64), ByApplication: make(map[string]int64)}
+	for _, t := range transfers {
+		out.TotalBytes += t.TotalBytes
+		for k, v := range t.ByEndpoint {
+			out.ByEndpoint[k] += v
+		}
+		for k, v := range t.ByApplication {
+			out.ByApplication[k] += v
+		}
+	}
+	return out
+}
+
+// Transfer returns the bytes downloaded by this client so far.
+func (c *Client) Transfer() Transfer {
+	return c.transfer.snapshot()
+}
@@ -0,0 +1,96 @@
+// internal/client/version.go
+package client
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+// MinServerVersion is the oldest IQ Server release whose REST API provides
+// every endpoint used by this client.
+const MinServerVersion = "1.100.0"
+
+// ServerVersion describes the IQ Server release answering the client.
+type ServerVersion struct {
+	Name      string `json:"name"`
+	Version   string `json:"version"` // e.g. "1.185.0-01"
+	Tag       string `json:"tag"`
+	Build     string `json:"build"`
+	Timestamp string `json:"timestamp"`
+}
+
+// Compatible reports whether the release is MinServerVersion or newer.
+// Unparsable versions are reported as compatible.
+func (v ServerVersion) Compatible() bool {
+	return compareVersions(v.Version, MinServerVersion) >= 0
+}
+
+// GetServerVersion fetches the release of the IQ Server, from the product
+// endpoint at the server root (outside /api/v2).
+func (c *Client) GetServerVersion(ctx context.Context) (*ServerVersion, error) {
+	versionURL, err := c.resolveServerURL("", "rest/product/version")
+	if err != nil {
+		return nil, err
+	}
+	c.logger.Debug().Str("url", versionURL).Msg("Fetching server version")
+
+	var version ServerVersion
+	resp, err := c.request(ctx, "rest/product/version").
+		SetResult(&version).
+		Get(versionURL)
+	if err != nil {
+		return nil, transportError(err)
+	}
+	if resp.IsError() {
+		return nil, httpError(resp, resp.Status())
+	}
+	if err := checkJSON(resp); err != nil {
+		return nil, err
+	}
+	if version.Version == "" {
+		return nil, parseError("unexpected response from %s: missing \"version\" field", versionURL)
+	}
+	return &version, nil
+}
+
+// compareVersions compares dotted release numbers such as "1.185.0-01"
+// numerically, ignoring build suffixes. It returns -1, 0 or +1, and 0 when
+// either version cannot be parsed.
+func compareVersions(a, b string) int {
+	pa, okA := versionParts(a)
+	pb, okB := versionParts(b)
+	if !okA || !okB {
+		return 0
+	}
+	for i := range max(len(pa), len(pb)) {
+		var x, y int
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+	return 0
+}
+
+func versionParts(v string) ([]int, bool) {
+	v, _, _ = strings.Cut(strings.TrimPrefix(strings.TrimSpace(v), "v"), "-")
+	fields := strings.Split(v, ".")
+	parts := make([]int, len(fields))
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil {
+			return nil, false
+		}
+		parts[i] = n
+	}
+	return parts, true
+}
@@ -0,0 +1,50 @@
+// internal/client/version_test.go
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_GetServerVersion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/iq/rest/product/version" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"name": "sonatype-clm-server", "version": "1.185.0-01", "tag": "abc", "build": "build-number"}`))
+	}))
+	defer server.Close()
+
+	c, err := NewClient(server.URL+"/iq", "u", "p", newTestLogger())
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	version, err := c.GetServerVersion(rCtx(t))
+	if err != nil {
+		t.Fatalf("GetServerVersion: %v", err)
+	}
+	if version.Version != "1.185.0-01" || !version.Compatible() {
+		t.Errorf("version = %+v, compatible = %v", version, version.Compatible())
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.185.0-01", "1.100.0", 1},
+		{"1.99.2", "1.100.0", -1},
+		{"1.100", "1.100.0", 0},
+		{"v2.0.0", "1.100.0", 1},
+		{"unknown", "1.100.0", 0},
+	}
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+// internal/client/vulnref.go
+package client
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Reference identifies what a condition matched, e.g. for a security
+// vulnerability condition {"value": "CVE-2019-10086", "type":
+// "SECURITY_VULNERABILITY_REFID"}.
+type Reference struct {
+	Value string `json:"value"`
+	Type  string `json:"type"`
+}
+
+// securityReferenceType is the Reference.Type of vulnerability identifiers:
+// CVE IDs, or Sonatype IDs such as sonatype-2020-0123 for vulnerabilities
+// without a CVE.
+const securityReferenceType = "SECURITY_VULNERABILITY_REFID"
+
+// cvePattern matches CVE IDs in condition reasons.
+var cvePattern = regexp.MustCompile(`\bCVE-\d{4}-\d{4,}\b`)
+
+// vulnerabilityIDs returns the distinct vulnerabilities matched by the
+// conditions of a constraint, in order: the security references, or the
+// CVE IDs named in the condition reasons of servers that return none.
+func vulnerabilityIDs(conds []Condition) []string {
+	var ids []string
+	add := func(id string) {
+		if id = strings.TrimSpace(id); id != "" && !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	for _, cond := range conds {
+		if cond.Reference != nil && cond.Reference.Type == securityReferenceType {
+			add(cond.Reference.Value)
+			continue
+		}
+		for _, id := range cvePattern.FindAllString(cond.ConditionReason, -1) {
+			add(id)
+		}
+	}
+	return ids
+}
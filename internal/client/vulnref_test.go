@@ -0,0 +1,37 @@
+// internal/client/vulnref_test.go
+package client
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseReportRows_CVE(t *testing.T) {
+	raw := `{"components":[{"displayName":"commons-beanutils:1.9.4","violations":[{"policyName":"Security-High","policyThreatLevel":8,"constraints":[
+		{"constraintName":"High risk","conditions":[
+			{"conditionSummary":"Security Vulnerability Severity >= 7","conditionReason":"Found security vulnerability CVE-2019-10086 with severity >= 7","reference":{"value":"CVE-2019-10086","type":"SECURITY_VULNERABILITY_REFID"}},
+			{"conditionSummary":"Security Vulnerability Severity >= 7","reference":{"value":"sonatype-2020-0123","type":"SECURITY_VULNERABILITY_REFID"}},
+			{"conditionSummary":"Security Vulnerability Severity >= 7","reference":{"value":"CVE-2019-10086","type":"SECURITY_VULNERABILITY_REFID"}}
+		]},
+		{"constraintName":"Older server","conditions":[
+			{"conditionSummary":"Security Vulnerability Severity >= 7","conditionReason":"Found security vulnerabilities CVE-2014-0114 and CVE-2014-0114 with severity >= 7"}
+		]},
+		{"constraintName":"License","conditions":[
+			{"conditionSummary":"License Threat Group is Banned","reference":{"value":"GPL-3.0","type":"LICENSE"}}
+		]}
+	]}]}]}`
+	var rpt PolicyViolationReport
+	if err := json.Unmarshal([]byte(raw), &rpt); err != nil {
+		t.Fatal(err)
+	}
+	rows := parseReportRows(rpt, "app", "org")
+	want := []string{"CVE-2019-10086, sonatype-2020-0123", "CVE-2014-0114", ""}
+	if len(rows) != len(want) {
+		t.Fatalf("got %d rows, want %d", len(rows), len(want))
+	}
+	for i, cve := range want {
+		if rows[i].CVE != cve {
+			t.Errorf("row %d (%s) CVE = %q, want %q", i, rows[i].ConstraintName, rows[i].CVE, cve)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+// internal/client/waivers.go
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// PolicyWaiver describes a waiver applied to policy violations of an application.
+type PolicyWaiver struct {
+	PolicyWaiverID    string `json:"policyWaiverId"`
+	PolicyViolationID string `json:"policyViolationId"`
+	Comment           string `json:"comment"`
+	CreateTime        string `json:"createTime"`
+	ExpiryTime        string `json:"expiryTime"` // empty when the waiver never expires
+	CreatorID         string `json:"creatorId"`
+	CreatorName       string `json:"creatorName"`
+}
+
+// GetPolicyWaivers fetches the waivers owned by the application with the given internal ID.
+func (c *Client) GetPolicyWaivers(ctx context.Context, appID string) ([]PolicyWaiver, error) {
+	c.logger.Debug().Str("appId", appID).Msg("Fetching policy waivers")
+
+	endpoint := fmt.Sprintf("policyWaivers/application/%s", appID)
+	var waivers []PolicyWaiver
+	resp, err := c.request(ctx, "policyWaivers/application/{id}").
+		SetResult(&waivers).
+		Get(endpoint)
+	if err != nil {
+		return nil, transportError(err)
+	}
+	if resp.IsError() {
+		return nil, httpError(resp, resp.Status())
+	}
+	if err := checkJSON(resp); err != nil {
+		return nil, err
+	}
+
+	c.logger.Debug().Int("count", len(waivers)).Str("appId", appID).Msg("Retrieved policy waivers")
+	return waivers, nil
+}
+
+// Matcher strategies of a new waiver: which later violations the waiver
+// also covers.
+const (
+	WaiverExactComponent = "EXACT_COMPONENT" // this component version only
+	WaiverAllVersions    = "ALL_VERSIONS"    // every version of the component
+	WaiverAllComponents  = "ALL_COMPONENTS"  // every component violating the policy
+)
+
+// NewPolicyWaiver is the request body of CreatePolicyWaiver.
+type NewPolicyWaiver struct {
+	Comment         string `json:"comment"`
+	MatcherStrategy string `json:"matcherStrategy"`      // one of the Waiver* matcher strategies
+	ExpiryTime      string `json:"expiryTime,omitempty"` // IQ timestamp; empty never expires
+}
+
+// CreatePolicyWaiver waives the policy violation with the given ID on the
+// application with the given internal ID. It fails with ErrReadOnly on a
+// read-only client.
+func (c *Client) CreatePolicyWaiver(ctx context.Context, appID, violationID string, w NewPolicyWaiver) error {
+	c.logger.Debug().Str("appId", appID).Str("violationId", violationID).Msg("Creating policy waiver")
+
+	endpoint := fmt.Sprintf("policyWaivers/application/%s/%s", url.PathEscape(appID), url.PathEscape(violationID))
+	resp, err := c.request(ctx, "policyWaivers/application/{id}/{violationId}").
+		SetHeader("Content-Type", "application/json").
+		SetBody(w).
+		Post(endpoint)
+	if err != nil {
+		return transportError(err)
+	}
+	if resp.IsError() {
+		return httpError(resp, resp.Status())
+	}
+	return nil
+}
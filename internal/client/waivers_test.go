@@ -0,0 +1,58 @@
+// internal/client/waivers_test.go
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_GetPolicyWaivers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/policyWaivers/application/aid-1" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"policyWaiverId": "w-1", "policyViolationId": "pv-1", "expiryTime": "2025-01-31T00:00:00.000+0000", "creatorName": "Jane Admin"}]`))
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(server.URL+"/api/v2", "u", "p", newTestLogger())
+	waivers, err := c.GetPolicyWaivers(rCtx(t), "aid-1")
+	if err != nil {
+		t.Fatalf("GetPolicyWaivers error = %v", err)
+	}
+	if len(waivers) != 1 || waivers[0].PolicyViolationID != "pv-1" || waivers[0].CreatorName != "Jane Admin" {
+		t.Errorf("unexpected waivers: %#v", waivers)
+	}
+}
+
+func TestClient_CreatePolicyWaiver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v2/policyWaivers/application/aid-1/pv-1" {
+			http.NotFound(w, r)
+			return
+		}
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		if body["matcherStrategy"] != WaiverExactComponent || body["comment"] != "accepted" || body["expiryTime"] != "2025-06-30T23:59:59.999+0000" {
+			t.Errorf("unexpected body: %v", body)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(server.URL+"/api/v2", "u", "p", newTestLogger())
+	w := NewPolicyWaiver{Comment: "accepted", MatcherStrategy: WaiverExactComponent, ExpiryTime: "2025-06-30T23:59:59.999+0000"}
+	if err := c.CreatePolicyWaiver(rCtx(t), "aid-1", "pv-1", w); err != nil {
+		t.Fatalf("CreatePolicyWaiver error = %v", err)
+	}
+	if err := c.CreatePolicyWaiver(rCtx(t), "aid-1", "pv-unknown", w); !errors.Is(err, ErrNotFound) {
+		t.Errorf("CreatePolicyWaiver(unknown) error = %v, want ErrNotFound", err)
+	}
+}
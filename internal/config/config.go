@@ -2,6 +2,9 @@
 package config
 
 import (
+	"fmt"
+	"net"
+	"os"
 	"strings"
 
 	"github.com/caarlos0/env/v11"
@@ -15,21 +18,374 @@ import (
 type Config struct {
 	// IQ Server config
 	IQServerURL string `env:"IQ_SERVER_URL,required" validate:"required,url"`
-	IQUsername  string `env:"IQ_USERNAME,required" validate:"required"`
-	IQPassword  string `env:"IQ_PASSWORD,required" validate:"required"`
+	IQUsername  string `env:"IQ_USERNAME" validate:"required_without=IQUserCode"`
+	IQPassword  string `env:"IQ_PASSWORD" secret:"true"`
+	// IQ Server user token, used instead of IQ_USERNAME and IQ_PASSWORD
+	// where password authentication is disabled.
+	IQUserCode string `env:"IQ_USER_CODE" validate:"required_with=IQPassCode"`
+	IQPassCode string `env:"IQ_PASS_CODE" validate:"required_with=IQUserCode" secret:"true"`
+	// How requests authenticate: "basic" sends the credentials with every
+	// request, "session" logs in once and reuses the session cookie, and
+	// "header" sets the username in IQ_AUTH_HEADER like a trusted reverse
+	// proxy, with the further headers in IQ_AUTH_HEADERS.
+	AuthMode       string            `env:"IQ_AUTH_MODE" envDefault:"basic" validate:"oneof=basic session header"`
+	AuthUserHeader string            `env:"IQ_AUTH_HEADER" envDefault:"REMOTE_USER" validate:"required_if=AuthMode header"`
+	AuthHeaders    map[string]string `env:"IQ_AUTH_HEADERS" envKeyValSeparator:"=" secret:"true"`
+	// Use IQ_SERVER_URL exactly as given instead of appending /api/v2 when missing.
+	StrictBaseURL bool `env:"IQ_STRICT_BASE_URL"`
+	// Reject every request that could modify IQ Server state, so the tool
+	// can run with elevated service accounts.
+	ReadOnly bool `env:"READ_ONLY"`
+	// Fixed IP addresses of host names, like /etc/hosts entries, as a
+	// comma-separated list of host=ip pairs. Used instead of DNS.
+	Hosts map[string]string `env:"IQ_HOSTS" envKeyValSeparator:"="`
+	// Local IP address or network interface, optionally with a port, that
+	// connections to IQ Server are made from, e.g. for firewall allow-lists.
+	LocalAddress string `env:"IQ_LOCAL_ADDRESS"`
+	// Retry requests failing with network errors, timeouts, HTTP 429 or 5xx
+	// up to IQ_RETRY_MAX_ATTEMPTS attempts (1 disables retries), waiting
+	// IQ_RETRY_BACKOFF_MS before the first retry and twice as long before
+	// each further one, up to IQ_RETRY_MAX_BACKOFF_SECONDS. IQ_RETRY_JITTER
+	// is the randomized fraction of each wait.
+	RetryMaxAttempts       int     `env:"IQ_RETRY_MAX_ATTEMPTS" envDefault:"3" validate:"gte=1"`
+	RetryBackoffMS         int     `env:"IQ_RETRY_BACKOFF_MS" envDefault:"500" validate:"gte=0"`
+	RetryMaxBackoffSeconds int     `env:"IQ_RETRY_MAX_BACKOFF_SECONDS" envDefault:"30" validate:"gte=0"`
+	RetryJitter            float64 `env:"IQ_RETRY_JITTER" envDefault:"0.5" validate:"gte=0,lte=1"`
+	// Requests per second to IQ Server, shared by all concurrent fetches and
+	// per-organization clients; zero does not limit the rate. A Retry-After
+	// header on HTTP 429 or 503 pauses all requests regardless.
+	RateLimit float64 `env:"IQ_RATE_LIMIT" validate:"gte=0"`
+	// Paths requested instead of IQ Server endpoints, e.g. behind a gateway
+	// rewriting them, as comma-separated endpoint=path pairs.
+	PathOverrides map[string]string `env:"IQ_PATH_OVERRIDES" envKeyValSeparator:"="`
+
+	// Per-organization credentials. IQ_ORG_CREDENTIALS is a comma-separated list
+	// of orgId=username:password entries; organizations not listed use the
+	// default IQ_USERNAME/IQ_PASSWORD. Parsed into OrgCredentials by Load.
+	RawOrgCredentials map[string]string      `env:"IQ_ORG_CREDENTIALS" envKeyValSeparator:"=" secret:"true"`
+	OrgCredentials    map[string]Credentials `env:"-"`
+
+	// What to do when an application or report disappears (HTTP 404) between
+	// listing and fetching: "warn" skips the application, "fail" records an error.
+	NotFoundAction string `env:"REPORT_NOT_FOUND" envDefault:"warn" validate:"oneof=warn fail"`
+
+	// Application list snapshots. APP_LIST_FILE pins the run to a saved list
+	// instead of listing applications; APP_LIST_SAVE writes the live list.
+	PinnedAppsFile string `env:"APP_LIST_FILE"`
+	SaveAppsFile   string `env:"APP_LIST_SAVE"`
+	// List applications per organization, concurrently, instead of with one
+	// call for the whole instance.
+	ListAppsByOrganization bool `env:"APP_LIST_BY_ORG"`
+	// Look up organizations that applications reference but the organization
+	// list lacks by ID before falling back to the organization ID as name.
+	ResolveOrphanedOrgs bool `env:"RESOLVE_ORPHANED_ORGS"`
+	// Sharded execution: process only shard SHARD_INDEX (from zero) of
+	// SHARD_TOTAL, so that parallel instances split the applications.
+	ShardIndex int `env:"SHARD_INDEX" validate:"gte=0"`
+	ShardTotal int `env:"SHARD_TOTAL" validate:"gte=0"`
+
+	// Sanity checks applied before the report is published; zero disables a
+	// check. SANITY_ACTION "fail" aborts without writing the report, "warn"
+	// only logs the violation.
+	SanityMinRows        int     `env:"SANITY_MIN_ROWS" validate:"gte=0"`
+	SanityMinAppCoverage float64 `env:"SANITY_MIN_APP_COVERAGE" validate:"gte=0,lte=100"` // percent of applications fetched without error
+	SanityMaxRowDelta    float64 `env:"SANITY_MAX_ROW_DELTA" validate:"gte=0"`            // percent change vs the previous run
+	SanityAction         string  `env:"SANITY_ACTION" envDefault:"fail" validate:"oneof=warn fail"`
+	// Free space check of the output directory before fetching reports.
+	DiskSpaceCheck string `env:"DISK_SPACE_CHECK" envDefault:"fail" validate:"oneof=fail warn off"`
+
+	// Per-band weights for application risk scores, e.g.
+	// "critical:10,severe:5,moderate:2,low:1". Defaults to those weights.
+	RiskWeights map[string]float64 `env:"RISK_WEIGHTS"`
+
+	// Also write the application and organization rollups and the
+	// vulnerability view next to the report.
+	RollupReports bool `env:"ROLLUP_REPORTS"`
+
+	// Per-band SLAs in days for open violations, e.g.
+	// "critical:7,severe:30". When set, violations open longer than the SLA
+	// of their band are listed in a "<report>.sla.csv" breach report.
+	SLADays map[string]int `env:"SLA_DAYS"`
+
+	// Report stages to export, in order, e.g. "build,operate" to merge
+	// continuous monitoring (operate) findings with build findings. Empty
+	// exports the first report returned by IQ Server.
+	ReportStages []string `env:"REPORT_STAGES"`
+
+	// How a report is chosen when several candidates exist (per stage when
+	// REPORT_STAGES is set): "first" as returned by IQ Server, "latest" by
+	// evaluation date, "highest-stage" furthest along the pipeline, or
+	// "preference" by the stage order in REPORT_STAGE_PREFERENCE.
+	ReportSelection       string   `env:"REPORT_SELECTION" envDefault:"first" validate:"oneof=first latest highest-stage preference"`
+	ReportStagePreference []string `env:"REPORT_STAGE_PREFERENCE" validate:"required_if=ReportSelection preference"`
+
+	// Download the PDF rendering of each exported report into OutputDir/pdf/
+	DownloadPDF bool `env:"REPORT_PDF"`
+
+	// Archive the raw policy report JSON of each exported report, gzip
+	// compressed, into OutputDir/raw/
+	ArchiveRawJSON bool `env:"ARCHIVE_RAW_JSON"`
+
+	// Compare the violations parsed from each report with the counts of its
+	// summary in IQ Server and flag mismatches, catching parsing drift
+	ValidateCounts bool `env:"VALIDATE_COUNTS"`
+
+	// Row filters
+	// Only keep violations of these policy threat categories (security,
+	// license, quality, other). Empty keeps all categories.
+	ThreatCategories []string `env:"THREAT_CATEGORIES" validate:"dive,oneof=security license quality other"`
+
+	// Drop violations of proprietary and InnerSource components, keeping
+	// the report to third-party risk
+	ExcludeProprietary bool `env:"EXCLUDE_PROPRIETARY"`
+
+	// Filter expression rows must match to be kept, e.g.
+	// `Threat >= 7 && Format == "maven"`; see report.ParseFilter.
+	Filter string `env:"FILTER"`
+
+	// YAML file of accepted risks (app/component/policy patterns with expiry
+	// and justification); matching rows are excluded and counted separately.
+	SuppressionsFile string `env:"SUPPRESSIONS_FILE"`
+
+	// Placeholder written instead of empty CSV cells, globally and per column
+	// header as column=value pairs, e.g. "CVE=N/A,Condition=-".
+	CSVEmptyValue  string            `env:"CSV_EMPTY_VALUE"`
+	CSVEmptyValues map[string]string `env:"CSV_EMPTY_VALUES" envKeyValSeparator:"="`
+
+	// Optional CSV columns appended after the standard columns, e.g. "Hash"
+	// for matching rows against repository manager artifacts.
+	CSVOptionalColumns []string `env:"CSV_OPTIONAL_COLUMNS"`
+	// Leave out the "No." column numbering the rows.
+	CSVOmitRowNumbers bool `env:"CSV_OMIT_ROW_NUMBERS"`
+	// Spreadsheet safety: quote cells that Excel would evaluate as formulas,
+	// and mark the files as UTF-8 so that Excel decodes non-ASCII names.
+	CSVEscapeFormulas bool `env:"CSV_ESCAPE_FORMULAS"`
+	CSVUTF8BOM        bool `env:"CSV_UTF8_BOM"`
+
+	// How the Threat column is written: "number" (7), "band" (Severe) or
+	// "labeled" (Severe (7)), in the CSV and XLSX reports and the Google
+	// Sheets sink; JSON reports add it as threatLabel.
+	ThreatFormat string `env:"THREAT_FORMAT" envDefault:"number" validate:"oneof=number band labeled"`
+
+	// Application tag keys written as additional columns, e.g.
+	// "costCenter,owner". IQ application categories named "key:value" or
+	// "key=value" provide the values.
+	AppTagColumns []string `env:"APP_TAG_COLUMNS"`
+
+	// IQ role whose members are written to the OwnerName and OwnerEmail
+	// optional columns.
+	OwnerRole string `env:"OWNER_ROLE" envDefault:"Owner"`
+
+	// Also write a personal report per application owner, see OWNER_ROLE
+	OwnerReports bool `env:"OWNER_REPORTS"`
+
+	// Directory of enrichment snapshots written by "iqfetch enrich", e.g.
+	// the owner mapping. When set, enrichment data is read from there
+	// instead of being fetched, for air-gapped deployments.
+	EnrichmentDir string `env:"ENRICHMENT_DIR"`
+
+	// Split the report into CSV files of at most this many rows
+	// (<report>-001.csv, ...) listed in <report>.index.csv. Zero writes a
+	// single file.
+	CSVChunkRows int `env:"CSV_CHUNK_ROWS" validate:"gte=0"`
+
+	// Report format: "csv", or "xlsx", "json" or "ndjson" to also write
+	// the report as <report>.xlsx, <report>.json or <report>.ndjson.
+	ReportFormat string `env:"REPORT_FORMAT" envDefault:"csv" validate:"oneof=csv xlsx json ndjson"`
+
+	// Point OutputDir/latest.csv at the report of the newest run without
+	// errors, and remove the reports of runs beyond this many newest (zero
+	// keeps all).
+	LatestLink  bool `env:"LATEST_LINK"`
+	KeepReports int  `env:"KEEP_REPORTS" validate:"gte=0"`
+
+	// Abort fetching from IQ Server after this many seconds, including rate
+	// limit pauses and retry backoff. Zero waits forever.
+	FetchTimeoutSeconds int `env:"FETCH_TIMEOUT_SECONDS" envDefault:"3600" validate:"gte=0"`
+
+	// Abort the run when filtering and writing the outputs take longer than
+	// this many seconds, e.g. on a stuck network share. Zero waits forever.
+	AggregationTimeoutSeconds int `env:"AGGREGATION_TIMEOUT_SECONDS" envDefault:"300" validate:"gte=0"`
+
+	// Remove temporary files of crashed runs below OutputDir at startup when
+	// they are older than this many hours. Zero disables the cleanup.
+	TempMaxAgeHours int `env:"TEMP_MAX_AGE_HOURS" envDefault:"24" validate:"gte=0"`
+
+	// Overlapping runs: wait up to this many seconds for a run holding the
+	// output directory lock (zero aborts at once), and take over locks older
+	// than this many minutes left by crashed runs (zero never does).
+	LockWaitSeconds  int `env:"LOCK_WAIT_SECONDS" validate:"gte=0"`
+	LockStaleMinutes int `env:"LOCK_STALE_MINUTES" envDefault:"60" validate:"gte=0"`
+
+	// Run retries while IQ Server is unavailable, e.g. in a maintenance
+	// window: retry the run every this many seconds (zero never retries), or
+	// after the server's Retry-After delay, for up to this many minutes
+	// after the first attempt.
+	RunRetryIntervalSeconds int `env:"RUN_RETRY_INTERVAL_SECONDS" validate:"gte=0"`
+	RunRetryWindowMinutes   int `env:"RUN_RETRY_WINDOW_MINUTES" envDefault:"60" validate:"gte=0"`
+
+	// Blackout windows during which runs should not hit IQ Server, as cron
+	// expressions for their start and a duration, separated by semicolons
+	// (see services.ParseBlackoutWindows), and what a run started in one
+	// does: warn and proceed, or skip the run.
+	BlackoutWindows []string `env:"BLACKOUT_WINDOWS" envSeparator:";"`
+	BlackoutAction  string   `env:"BLACKOUT_ACTION" envDefault:"warn" validate:"oneof=warn skip"`
+
+	// Additionally write one CSV per application below OutputDir at this
+	// path template, e.g. "{{org}}/{{app}}/{{date}}/policy.csv". Placeholders:
+	// {{org}}, {{app}}, {{date}} and {{report}}.
+	OutputLayout string `env:"OUTPUT_LAYOUT"`
+
+	// How violations referencing several CVEs are written: "aggregate" keeps one
+	// row with comma-separated CVEs, "split" emits one row per CVE.
+	CVERows string `env:"CVE_ROWS" envDefault:"aggregate" validate:"oneof=aggregate split"`
+
+	// ServiceNow sink; enabled when SNOW_INSTANCE_URL is set. Critical
+	// violations (threat >= SNOW_MIN_THREAT) open or update records in
+	// SNOW_TABLE. SNOW_CI_MAPPING maps application public IDs to cmdb_ci
+	// values as appId=ci pairs.
+	SnowInstanceURL string            `env:"SNOW_INSTANCE_URL" validate:"omitempty,url"`
+	SnowUsername    string            `env:"SNOW_USERNAME" validate:"required_with=SnowInstanceURL"`
+	SnowPassword    string            `env:"SNOW_PASSWORD" validate:"required_with=SnowInstanceURL" secret:"true"`
+	SnowTable       string            `env:"SNOW_TABLE" envDefault:"incident"`
+	SnowMinThreat   int               `env:"SNOW_MIN_THREAT" envDefault:"8" validate:"gte=0,lte=10"`
+	SnowCIMapping   map[string]string `env:"SNOW_CI_MAPPING" envKeyValSeparator:"="`
+
+	// Issue tracker sink; enabled when ISSUES_PROVIDER is set ("github" or
+	// "gitlab"). ISSUES_REPOS maps application public IDs to repositories
+	// (owner/repo or GitLab project path) as appId=repo pairs.
+	IssuesProvider  string            `env:"ISSUES_PROVIDER" validate:"omitempty,oneof=github gitlab"`
+	IssuesBaseURL   string            `env:"ISSUES_BASE_URL" validate:"omitempty,url"`
+	IssuesToken     string            `env:"ISSUES_TOKEN" validate:"required_with=IssuesProvider" secret:"true"`
+	IssuesLabel     string            `env:"ISSUES_LABEL" envDefault:"iqfetch"`
+	IssuesMinThreat int               `env:"ISSUES_MIN_THREAT" envDefault:"8" validate:"gte=0,lte=10"`
+	IssuesRepos     map[string]string `env:"ISSUES_REPOS" envKeyValSeparator:"="`
+
+	// Google Sheets sink; enabled when GSHEETS_SPREADSHEET_ID is set. The
+	// report is written with the service account in GSHEETS_CREDENTIALS_FILE,
+	// either overwriting GSHEETS_WORKSHEET or adding a worksheet per run.
+	GSheetsSpreadsheetID   string `env:"GSHEETS_SPREADSHEET_ID"`
+	GSheetsCredentialsFile string `env:"GSHEETS_CREDENTIALS_FILE" validate:"required_with=GSheetsSpreadsheetID"`
+	GSheetsMode            string `env:"GSHEETS_MODE" envDefault:"overwrite" validate:"oneof=overwrite new"`
+	GSheetsWorksheet       string `env:"GSHEETS_WORKSHEET" envDefault:"Report"`
+
+	// Machine-readable progress events (JSON lines) are written to this file,
+	// or to stdout when set to "-". Empty disables them.
+	ProgressEvents string `env:"PROGRESS_EVENTS"`
+
+	// Logging: minimum level of console and app.log output, and the console
+	// format ("pretty" colored, "console" plain, "json" one object per line).
+	LogLevel  string `env:"LOG_LEVEL" envDefault:"debug" validate:"oneof=trace debug info warn error"`
+	LogFormat string `env:"LOG_FORMAT" envDefault:"pretty" validate:"oneof=pretty console json"`
+
+	// HTTP response bodies are logged at this level ("off" never logs them),
+	// truncated to this many bytes (zero logs them whole). Credentials in
+	// logged headers and query parameters are always redacted.
+	LogBodyLevel    string `env:"LOG_BODY_LEVEL" envDefault:"trace" validate:"oneof=trace debug off"`
+	LogBodyMaxBytes int    `env:"LOG_BODY_MAX_BYTES" envDefault:"4096" validate:"gte=0"`
+
+	// Responses with larger bodies fail instead of being read into memory
+	// (zero does not limit them). Policy reports exceeding the limit are
+	// recorded as "too large" errors, or decoded as a stream.
+	MaxResponseBytes int    `env:"MAX_RESPONSE_BYTES" validate:"gte=0"`
+	OversizedReports string `env:"OVERSIZED_REPORTS" envDefault:"skip" validate:"oneof=skip stream"`
 
 	// IO config
 	// Report output directory. Can be set via REPORT_OUTPUT_DIR, defaults to "reports_output" when empty.
 	OutputDir string `env:"REPORT_OUTPUT_DIR" validate:"required"`
+
+	// Variables taken from the .env file rather than the environment, see
+	// Settings.
+	fromFile map[string]bool
+}
+
+// envFile is the optional dotenv file read by Load.
+const envFile = "config/.env"
+
+// Values for Config.LogFormat.
+const (
+	LogFormatPretty  = "pretty"
+	LogFormatConsole = "console"
+	LogFormatJSON    = "json"
+)
+
+// LogBodyOff is the Config.LogBodyLevel value that disables body logging.
+const LogBodyOff = "off"
+
+// Values for Config.AuthMode.
+const (
+	AuthBasic   = "basic"
+	AuthSession = "session"
+	AuthHeader  = "header"
+)
+
+// Values for Config.NotFoundAction.
+const (
+	NotFoundWarn = "warn"
+	NotFoundFail = "fail"
+)
+
+// Values for Config.SanityAction.
+const (
+	SanityWarn = "warn"
+	SanityFail = "fail"
+)
+
+// Values for Config.BlackoutAction.
+const (
+	BlackoutWarn = "warn"
+	BlackoutSkip = "skip"
+)
+
+// Values for Config.DiskSpaceCheck.
+const (
+	DiskSpaceFail = "fail"
+	DiskSpaceWarn = "warn"
+	DiskSpaceOff  = "off"
+)
+
+// Values for Config.ReportSelection.
+const (
+	SelectFirst        = "first"
+	SelectLatest       = "latest"
+	SelectHighestStage = "highest-stage"
+	SelectPreference   = "preference"
+)
+
+// Values for Config.OversizedReports.
+const (
+	OversizedSkip   = "skip"
+	OversizedStream = "stream"
+)
+
+// Values for Config.CVERows.
+const (
+	CVERowsAggregate = "aggregate"
+	CVERowsSplit     = "split"
+)
+
+// Credentials is a username/password pair used to authenticate against IQ Server.
+type Credentials struct {
+	Username string
+	Password string
 }
 
 // Load reads environment variables (and optional config/.env file) and
 // returns a validated Config populated with sensible defaults when needed.
 func Load() (*Config, error) {
 	// Load .env if present; ignore missing file errors to allow env-driven usage.
-	_ = godotenv.Load("config/.env")
+	// Variables already in the environment take precedence over the file.
+	fromFile := make(map[string]bool)
+	if vars, err := godotenv.Read(envFile); err == nil {
+		for k := range vars {
+			if _, ok := os.LookupEnv(k); !ok {
+				fromFile[k] = true
+			}
+		}
+	}
+	_ = godotenv.Load(envFile)
 
-	cfg := &Config{}
+	cfg := &Config{fromFile: fromFile}
 	if err := env.Parse(cfg); err != nil {
 		return nil, err
 	}
@@ -39,6 +395,73 @@ func Load() (*Config, error) {
 		cfg.OutputDir = "reports_output"
 	}
 
+	for i, stage := range cfg.ReportStages {
+		cfg.ReportStages[i] = strings.ToLower(strings.TrimSpace(stage))
+	}
+	for i, stage := range cfg.ReportStagePreference {
+		cfg.ReportStagePreference[i] = strings.ToLower(strings.TrimSpace(stage))
+	}
+
+	for i, c := range cfg.ThreatCategories {
+		cfg.ThreatCategories[i] = strings.ToLower(strings.TrimSpace(c))
+	}
+	// Headers such as "Threat Category" contain spaces; only trim around them
+	for i, column := range cfg.CSVOptionalColumns {
+		cfg.CSVOptionalColumns[i] = strings.TrimSpace(column)
+	}
+
+	for band := range cfg.RiskWeights {
+		switch band {
+		case "critical", "severe", "moderate", "low", "none":
+		default:
+			return nil, fmt.Errorf("RISK_WEIGHTS: unknown threat band %q", band)
+		}
+	}
+	for band, days := range cfg.SLADays {
+		switch band {
+		case "critical", "severe", "moderate", "low", "none":
+		default:
+			return nil, fmt.Errorf("SLA_DAYS: unknown threat band %q", band)
+		}
+		if days < 0 {
+			return nil, fmt.Errorf("SLA_DAYS: negative SLA for %s", band)
+		}
+	}
+
+	if cfg.ShardIndex > 0 && cfg.ShardIndex >= cfg.ShardTotal {
+		return nil, fmt.Errorf("SHARD_INDEX: %d is not below SHARD_TOTAL %d", cfg.ShardIndex, cfg.ShardTotal)
+	}
+
+	// A deadline shorter than one wait between retries fails every retried
+	// request instead of retrying it
+	if cfg.FetchTimeoutSeconds > 0 && cfg.RetryMaxAttempts > 1 && cfg.FetchTimeoutSeconds <= cfg.RetryMaxBackoffSeconds {
+		return nil, fmt.Errorf("FETCH_TIMEOUT_SECONDS: %d is not above IQ_RETRY_MAX_BACKOFF_SECONDS %d", cfg.FetchTimeoutSeconds, cfg.RetryMaxBackoffSeconds)
+	}
+
+	for host, ip := range cfg.Hosts {
+		if net.ParseIP(strings.TrimSpace(ip)) == nil {
+			return nil, fmt.Errorf("IQ_HOSTS: invalid IP address %q for %s", ip, host)
+		}
+		cfg.Hosts[host] = strings.TrimSpace(ip)
+	}
+
+	switch {
+	case cfg.IQUserCode != "" && cfg.AuthMode == AuthHeader:
+		return nil, fmt.Errorf("IQ_USER_CODE: a user token cannot be used with IQ_AUTH_MODE=header")
+	case cfg.IQUserCode == "" && cfg.IQPassCode == "" && cfg.IQPassword == "" && cfg.AuthMode != AuthHeader:
+		return nil, fmt.Errorf("IQ_PASSWORD is required unless IQ_AUTH_MODE=header or a user token is set")
+	}
+
+	if _, port, err := net.SplitHostPort(cfg.LocalAddress); err == nil && port != "0" && len(cfg.RawOrgCredentials) > 0 {
+		return nil, fmt.Errorf("IQ_LOCAL_ADDRESS: a fixed source port cannot be shared by the clients of IQ_ORG_CREDENTIALS")
+	}
+
+	orgCreds, err := parseOrgCredentials(cfg.RawOrgCredentials)
+	if err != nil {
+		return nil, err
+	}
+	cfg.OrgCredentials = orgCreds
+
 	// Validate the config once defaults are applied
 	validate := validator.New()
 	if err := validate.Struct(cfg); err != nil {
@@ -47,3 +470,18 @@ func Load() (*Config, error) {
 
 	return cfg, nil
 }
+
+// parseOrgCredentials converts orgId -> "username:password" entries into
+// Credentials. The password may itself contain colons.
+func parseOrgCredentials(raw map[string]string) (map[string]Credentials, error) {
+	creds := make(map[string]Credentials, len(raw))
+	for orgID, value := range raw {
+		orgID = strings.TrimSpace(orgID)
+		username, password, found := strings.Cut(value, ":")
+		if orgID == "" || !found || username == "" || password == "" {
+			return nil, fmt.Errorf("IQ_ORG_CREDENTIALS: entry for org %q must be orgId=username:password", orgID)
+		}
+		creds[orgID] = Credentials{Username: username, Password: password}
+	}
+	return creds, nil
+}
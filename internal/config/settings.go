@@ -0,0 +1,117 @@
+// internal/config/settings.go
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Sources of a Setting.
+const (
+	SourceEnv     = "env"     // set in the environment
+	SourceFile    = "file"    // set in config/.env
+	SourceDefault = "default" // not set; the default applies
+)
+
+// Masked replaces secret values in Settings.
+const Masked = "********"
+
+// Setting is one resolved configuration value.
+type Setting struct {
+	Env    string `json:"env"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+// Settings returns the effective value of every setting of cfg, in the
+// order of the Config fields, formatted like the environment variable that
+// sets it. Secrets are masked: fields tagged secret:"true" show Masked, or
+// for lists of key=value pairs the keys with masked values, and passwords
+// in URLs are replaced as well.
+func (c *Config) Settings() []Setting {
+	v := reflect.ValueOf(c).Elem()
+	t := v.Type()
+	var settings []Setting
+	for i := range t.NumField() {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("env"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		secret := f.Tag.Get("secret") == "true"
+		settings = append(settings, Setting{
+			Env:    name,
+			Value:  formatSetting(v.Field(i), f.Tag, secret),
+			Source: c.source(name),
+		})
+	}
+	return settings
+}
+
+// source returns where the variable name was set.
+func (c *Config) source(name string) string {
+	if c.fromFile[name] {
+		return SourceFile
+	}
+	if _, ok := os.LookupEnv(name); ok {
+		return SourceEnv
+	}
+	return SourceDefault
+}
+
+// formatSetting formats v the way its environment variable is written.
+func formatSetting(v reflect.Value, tag reflect.StructTag, secret bool) string {
+	switch v.Kind() {
+	case reflect.Slice:
+		sep := tag.Get("envSeparator")
+		if sep == "" {
+			sep = ","
+		}
+		items := make([]string, v.Len())
+		for i := range items {
+			items[i] = formatSetting(v.Index(i), "", secret)
+		}
+		return strings.Join(items, sep)
+	case reflect.Map:
+		kv := tag.Get("envKeyValSeparator")
+		if kv == "" {
+			kv = ":"
+		}
+		pairs := make([]string, 0, v.Len())
+		for _, k := range v.MapKeys() {
+			pairs = append(pairs, fmt.Sprint(k.Interface())+kv+formatSetting(v.MapIndex(k), "", secret))
+		}
+		slices.Sort(pairs)
+		return strings.Join(pairs, ",")
+	case reflect.String:
+		s := v.String()
+		if secret && s != "" {
+			return Masked
+		}
+		return maskURLPassword(s)
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
+
+// maskURLPassword masks the password of s when it is a URL with
+// credentials, e.g. a proxy URL.
+func maskURLPassword(s string) string {
+	if !strings.Contains(s, "://") {
+		return s
+	}
+	u, err := url.Parse(s)
+	if err != nil || u.User == nil {
+		return s
+	}
+	if _, ok := u.User.Password(); !ok {
+		return s
+	}
+	u.User = url.UserPassword(u.User.Username(), Masked)
+	// url escapes the asterisks of the mask; keep them readable.
+	return strings.Replace(u.String(), url.PathEscape(Masked), Masked, 1)
+}
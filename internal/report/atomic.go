@@ -0,0 +1,193 @@
+// internal/report/atomic.go
+package report
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// tempPrefix starts the name of every temporary file created by the writers
+// of this package.
+const tempPrefix = ".tmp-"
+
+// runTag identifies the temporary files of this process, so that
+// CleanupTempFiles never removes files of the current run.
+var runTag = strconv.Itoa(os.Getpid()) + "-" + strconv.FormatInt(time.Now().UnixNano(), 36)
+
+// writeFileAtomic creates destPath by calling write with a temporary file in
+// the same directory and renaming it into place once write succeeds, so
+// readers never observe a partially written file. The destination directory
+// is created when missing. It returns the path the file was placed at, in
+// the directory of destPath as given: destPath itself, or an alternate name
+// when the destination stays locked (see placeFile). Once ctx is done, writing fails and the file is not placed;
+// only a rename already under way completes.
+func writeFileAtomic(ctx context.Context, destPath string, logger zerolog.Logger, write func(w io.Writer) error) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	// Ensure absolute path with proper separators for Windows compatibility
+	absPath, err := filepath.Abs(destPath)
+	if err != nil {
+		return "", fmt.Errorf("get absolute path: %w", err)
+	}
+
+	dir := filepath.Dir(absPath)
+	logger.Debug().Str("dir", dir).Msg("preparing output directory")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		logger.Error().Err(err).Str("dir", dir).Msg("failed to create output dir")
+		return "", fmt.Errorf("prepare output dir: %w", err)
+	}
+
+	// Create temp file in SAME directory as final file to ensure os.Rename works on Windows
+	tmp, err := os.CreateTemp(dir, tempPrefix+runTag+"-*"+filepath.Ext(absPath))
+	if err != nil {
+		return "", fmt.Errorf("create temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	// Ensure the temporary file is closed and removed when we return.
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+	}()
+	logger.Debug().Str("tmp", tmpPath).Msg("created temp file")
+
+	if err := write(ctxWriter{ctx: ctx, w: tmp}); err != nil {
+		return "", err
+	}
+	if err := tmp.Sync(); err != nil {
+		return "", fmt.Errorf("fsync temp: %w", err)
+	}
+
+	// Close temp file BEFORE rename (Windows requires file to be closed)
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("close temp: %w", err)
+	}
+
+	placed, err := placeFile(ctx, tmpPath, absPath, logger)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.Chmod(placed, 0o644); err != nil {
+		return "", fmt.Errorf("chmod: %w", err)
+	}
+
+	logger.Info().Str("path", placed).Msg("file written successfully")
+	if placed != absPath {
+		return filepath.Join(filepath.Dir(destPath), filepath.Base(placed)), nil
+	}
+	return destPath, nil
+}
+
+// ctxWriter is an io.Writer failing once ctx is done, so that an abandoned
+// write stops at the next block written.
+type ctxWriter struct {
+	ctx context.Context
+	w   io.Writer
+}
+
+func (c ctxWriter) Write(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.w.Write(p)
+}
+
+// renameRetryDelays are the waits between attempts to move a written file
+// into place, e.g. while Excel or a virus scanner holds the destination open
+// on Windows.
+var renameRetryDelays = []time.Duration{100 * time.Millisecond, 250 * time.Millisecond, 500 * time.Millisecond, time.Second, 2 * time.Second}
+
+// rename is os.Rename, replaced in tests.
+var rename = os.Rename
+
+// placeFile renames tmpPath to destPath, retrying with backoff while the
+// destination cannot be replaced. When it stays locked, the file is placed
+// at a timestamped alternate name next to it instead, with a warning. It
+// returns the path the file was placed at. Nothing is placed once ctx is
+// done.
+func placeFile(ctx context.Context, tmpPath, destPath string, logger zerolog.Logger) (string, error) {
+	var err error
+	for attempt := 0; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		// Remove existing destination file if it exists (Windows requirement)
+		_ = os.Remove(destPath)
+
+		// Atomic rename (works on Windows since both files are in same directory)
+		if err = rename(tmpPath, destPath); err == nil {
+			return destPath, nil
+		}
+		if attempt == len(renameRetryDelays) {
+			break
+		}
+		logger.Debug().Err(err).Str("path", destPath).Dur("retryIn", renameRetryDelays[attempt]).Msg("destination busy, retrying rename")
+		timer := time.NewTimer(renameRetryDelays[attempt])
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return "", ctx.Err()
+		case <-timer.C:
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	ext := filepath.Ext(destPath)
+	alt := strings.TrimSuffix(destPath, ext) + "." + time.Now().Format("20060102-150405") + ext
+	if altErr := rename(tmpPath, alt); altErr != nil {
+		return "", fmt.Errorf("atomic rename: %w", errors.Join(err, altErr))
+	}
+	logger.Warn().Err(err).Str("path", destPath).Str("written", alt).Msg("Destination is locked, e.g. open in another program; file written to an alternate name")
+	return alt, nil
+}
+
+// CleanupTempFiles removes temporary files left below root by writers of
+// earlier runs that crashed or were killed, when they were last modified more
+// than maxAge ago. Temporary files of the current process are kept. It
+// returns the number of files removed.
+func CleanupTempFiles(root string, maxAge time.Duration, logger zerolog.Logger) (int, error) {
+	cutoff := time.Now().Add(-maxAge)
+	removed := 0
+	var errs []error
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			errs = append(errs, err)
+			return nil
+		}
+		name := d.Name()
+		if !d.Type().IsRegular() || !strings.HasPrefix(name, tempPrefix) || strings.HasPrefix(name, tempPrefix+runTag+"-") {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil || !info.ModTime().Before(cutoff) {
+			return nil
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			errs = append(errs, err)
+			return nil
+		}
+		logger.Debug().Str("path", path).Time("modified", info.ModTime()).Msg("removed orphaned temp file")
+		removed++
+		return nil
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return removed, errors.Join(errs...)
+}
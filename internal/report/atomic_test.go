@@ -0,0 +1,172 @@
+// internal/report/atomic_test.go
+package report
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestCleanupTempFiles(t *testing.T) {
+	root := t.TempDir()
+	old := time.Now().Add(-48 * time.Hour)
+	touch := func(path string, mtime time.Time) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	orphan := filepath.Join(root, "history", ".tmp-1234-abc-99.csv")
+	recent := filepath.Join(root, ".tmp-1234-abc-98.csv")
+	current := filepath.Join(root, tempPrefix+runTag+"-97.csv")
+	final := filepath.Join(root, "2025-01-01_00-00-00.csv")
+	touch(orphan, old)
+	touch(recent, time.Now())
+	touch(current, old)
+	touch(final, old)
+
+	n, err := CleanupTempFiles(root, 24*time.Hour, zerolog.New(io.Discard))
+	if err != nil {
+		t.Fatalf("CleanupTempFiles error = %v", err)
+	}
+	if n != 1 {
+		t.Errorf("removed %d files, want 1", n)
+	}
+	if _, err := os.Stat(orphan); !os.IsNotExist(err) {
+		t.Error("orphaned temp file not removed")
+	}
+	for _, keep := range []string{recent, current, final} {
+		if _, err := os.Stat(keep); err != nil {
+			t.Errorf("%s removed: %v", filepath.Base(keep), err)
+		}
+	}
+}
+
+func TestWriteFileAtomic_TempFilesTaggedWithRun(t *testing.T) {
+	dir := t.TempDir()
+	dest := filepath.Join(dir, "out.csv")
+	_, err := writeFileAtomic(context.Background(), dest, zerolog.New(io.Discard), func(io.Writer) error {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return err
+		}
+		if len(entries) != 1 || !strings.HasPrefix(entries[0].Name(), tempPrefix+runTag+"-") {
+			t.Errorf("temp files = %v, want one tagged with the run", entries)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("writeFileAtomic error = %v", err)
+	}
+}
+
+func TestWriteFileAtomic_RetriesLockedDestination(t *testing.T) {
+	delays := renameRetryDelays
+	renameRetryDelays = []time.Duration{time.Millisecond, time.Millisecond}
+	defer func() { renameRetryDelays, rename = delays, os.Rename }()
+
+	dir := t.TempDir()
+	dest := filepath.Join(dir, "out.csv")
+	write := func(w io.Writer) error {
+		_, err := io.WriteString(w, "a,b\n")
+		return err
+	}
+
+	// Locked for fewer attempts than allowed: written in place
+	failures := 2
+	rename = func(from, to string) error {
+		if to == dest && failures > 0 {
+			failures--
+			return &os.LinkError{Op: "rename", Old: from, New: to, Err: os.ErrPermission}
+		}
+		return os.Rename(from, to)
+	}
+	written, err := writeFileAtomic(context.Background(), dest, zerolog.New(io.Discard), write)
+	if err != nil {
+		t.Fatalf("writeFileAtomic error = %v", err)
+	}
+	if written != dest {
+		t.Errorf("written to %s, want %s", written, dest)
+	}
+	if _, err := os.Stat(dest); err != nil {
+		t.Errorf("destination not written after retries: %v", err)
+	}
+
+	// Locked throughout: written to an alternate name, which is returned
+	failures = 10
+	written, err = writeFileAtomic(context.Background(), dest, zerolog.New(io.Discard), write)
+	if err != nil {
+		t.Fatalf("writeFileAtomic error = %v", err)
+	}
+	alts, _ := filepath.Glob(filepath.Join(dir, "out.*.csv"))
+	if len(alts) != 1 {
+		t.Fatalf("alternate files = %v, want one", alts)
+	}
+	if written != alts[0] {
+		t.Errorf("written to %s, want the alternate %s", written, alts[0])
+	}
+	if failures != 7 {
+		t.Errorf("rename attempts on the destination = %d, want 3", 10-failures)
+	}
+}
+
+func TestWriteFileAtomic_Cancelled(t *testing.T) {
+	dir := t.TempDir()
+	dest := filepath.Join(dir, "report.csv")
+
+	// Cancelled while writing, the file is not placed
+	ctx, cancel := context.WithCancel(context.Background())
+	_, err := writeFileAtomic(ctx, dest, zerolog.New(io.Discard), func(w io.Writer) error {
+		if _, err := io.WriteString(w, "a,b\n"); err != nil {
+			return err
+		}
+		cancel()
+		_, err := io.WriteString(w, "c,d\n")
+		return err
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("writeFileAtomic error = %v, want context.Canceled", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("files left behind: %v", entries)
+	}
+}
+
+func TestWriteCSV_ReturnsAlternatePath(t *testing.T) {
+	delays := renameRetryDelays
+	renameRetryDelays = nil
+	defer func() { renameRetryDelays, rename = delays, os.Rename }()
+
+	// A relative destination keeps its form
+	t.Chdir(t.TempDir())
+	dest := filepath.Join("out", "report.csv")
+	rename = func(from, to string) error {
+		if filepath.Base(to) == "report.csv" {
+			return &os.LinkError{Op: "rename", Old: from, New: to, Err: os.ErrPermission}
+		}
+		return os.Rename(from, to)
+	}
+	written, err := WriteCSV(context.Background(), dest, []Row{{Application: "web-app"}}, zerolog.New(io.Discard))
+	if err != nil {
+		t.Fatalf("WriteCSV error = %v", err)
+	}
+	if filepath.Dir(written) != "out" || !strings.HasPrefix(filepath.Base(written), "report.") || written == dest {
+		t.Errorf("written to %q, want an alternate name in out", written)
+	}
+	if _, err := os.Stat(written); err != nil {
+		t.Errorf("returned path not written: %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+// internal/report/chunk.go
+package report
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// ChunkPath returns the location of the n-th (one-based) chunk of the report
+// at reportPath, e.g. report-001.csv for report.csv.
+func ChunkPath(reportPath string, n int) string {
+	ext := filepath.Ext(reportPath)
+	return fmt.Sprintf("%s-%03d%s", strings.TrimSuffix(reportPath, ext), n, ext)
+}
+
+// IndexPath returns the chunk index location for the report at reportPath:
+// the report path with its extension replaced by ".index.csv".
+func IndexPath(reportPath string) string {
+	return strings.TrimSuffix(reportPath, filepath.Ext(reportPath)) + ".index.csv"
+}
+
+// WriteCSVChunks writes rows as CSV files of at most chunkRows rows each
+// (see ChunkPath), every chunk with the header and row numbers continuing
+// across chunks, followed by an index listing the chunk files with their row
+// ranges (see IndexPath). No chunk is written for an empty report. It returns
+// the chunk paths.
+func WriteCSVChunks(ctx context.Context, reportPath string, rows []Row, chunkRows int, logger zerolog.Logger, opts ...CSVOption) ([]string, error) {
+	dir := filepath.Dir(reportPath)
+	names, err := WriteCSVChunksFS(DirFS(ctx, dir, logger), filepath.Base(reportPath), rows, chunkRows, opts...)
+	paths := make([]string, len(names))
+	for i, name := range names {
+		paths[i] = filepath.Join(dir, name)
+	}
+	if err != nil {
+		return paths, err
+	}
+	logger.Debug().Int("rows", len(rows)).Int("chunks", len(paths)).Msg("csv chunks encoded")
+	return paths, nil
+}
+
+// WriteCSVChunksFS is like WriteCSVChunks, but writes the chunks and the
+// index of the report name into fsys. It returns the chunk names.
+func WriteCSVChunksFS(fsys OutputFS, name string, rows []Row, chunkRows int, opts ...CSVOption) ([]string, error) {
+	if chunkRows <= 0 {
+		return nil, fmt.Errorf("chunk size must be positive, got %d", chunkRows)
+	}
+	layout, err := newCSVLayout(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	var index [][]string
+	for start := 0; start < len(rows); start += chunkRows {
+		end := min(start+chunkRows, len(rows))
+		chunk := ChunkPath(name, len(names)+1)
+		if err := fsys.WriteFile(chunk, func(f io.Writer) error {
+			return writeRecords(f, rows[start:end], start, layout)
+		}); err != nil {
+			return names, fmt.Errorf("write chunk %s: %w", path.Base(chunk), err)
+		}
+		names = append(names, chunk)
+		index = append(index, []string{
+			path.Base(chunk),
+			strconv.Itoa(end - start),
+			strconv.Itoa(start + 1),
+			strconv.Itoa(end),
+		})
+	}
+
+	err = fsys.WriteFile(IndexPath(name), func(f io.Writer) error {
+		w := csv.NewWriter(f)
+		if err := w.Write([]string{"File", "Rows", "First No.", "Last No."}); err != nil {
+			return fmt.Errorf("write header: %w", err)
+		}
+		if err := w.WriteAll(index); err != nil {
+			return fmt.Errorf("write index: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return names, fmt.Errorf("write chunk index: %w", err)
+	}
+	return names, nil
+}
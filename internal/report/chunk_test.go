@@ -0,0 +1,66 @@
+// internal/report/chunk_test.go
+package report
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func readCSV(t *testing.T, path string) [][]string {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open %s: %v", path, err)
+	}
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	return records
+}
+
+func TestWriteCSVChunks(t *testing.T) {
+	dest := filepath.Join(t.TempDir(), "report.csv")
+	rows := make([]Row, 5)
+	for i := range rows {
+		rows[i] = Row{Application: "app-" + strconv.Itoa(i+1)}
+	}
+
+	paths, err := WriteCSVChunks(context.Background(), dest, rows, 2, zerolog.New(io.Discard), WithEmptyValue("-"))
+	if err != nil {
+		t.Fatalf("WriteCSVChunks: %v", err)
+	}
+	want := []string{ChunkPath(dest, 1), ChunkPath(dest, 2), ChunkPath(dest, 3)}
+	if !slices.Equal(paths, want) || filepath.Base(paths[0]) != "report-001.csv" {
+		t.Fatalf("paths = %v, want %v", paths, want)
+	}
+
+	last := readCSV(t, paths[2])
+	if len(last) != 2 || last[0][0] != "No." || last[1][0] != "5" || last[1][1] != "app-5" || last[1][2] != "-" {
+		t.Errorf("last chunk = %v", last)
+	}
+
+	index := readCSV(t, IndexPath(dest))
+	wantIndex := [][]string{
+		{"File", "Rows", "First No.", "Last No."},
+		{"report-001.csv", "2", "1", "2"},
+		{"report-002.csv", "2", "3", "4"},
+		{"report-003.csv", "1", "5", "5"},
+	}
+	if !slices.EqualFunc(index, wantIndex, slices.Equal) {
+		t.Errorf("index = %v, want %v", index, wantIndex)
+	}
+
+	if _, err := WriteCSVChunks(context.Background(), dest, rows, 0, zerolog.New(io.Discard)); err == nil {
+		t.Error("expected error for chunk size 0")
+	}
+}
@@ -2,11 +2,15 @@
 package report
 
 import (
+	"context"
 	"encoding/csv"
+	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
+	"io"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/rs/zerolog"
 )
@@ -21,10 +25,33 @@ type Row struct {
 	Format         string
 	Component      string
 	Threat         int
+	Category       string // policy threat category: security, license, quality or other
+	ViolationID    string // IQ policyViolationId; written as Row ID when set, see RowID
+	Waived         bool
+	WaiverExpiry   string // expiry date of the matching waiver, "never" when it does not expire
+	WaiverCreator  string
+	Stage          string // IQ stage of the report the row comes from, e.g. build or operate
 	PolicyAction   string
 	ConstraintName string
 	Condition      string
 	CVE            string
+	Hash           string    // component hash (SHA-1 prefix) reported by IQ Server
+	Proprietary    bool      // proprietary or InnerSource component
+	Labels         []string  // component labels assigned in IQ Server, e.g. approved-fork
+	Claimed        bool      // component identified manually (claimed) in IQ Server
+	OpenTime       time.Time // when the violation was first reported; zero when unknown
+	// Exploitability of the vulnerabilities matched, where IQ Server
+	// reports it: listed in the CISA KEV catalog, the most mature exploit
+	// (e.g. proof-of-concept or high), and "true" or "false" whether the
+	// vulnerable code is reachable, empty when not analyzed.
+	KEVListed       bool
+	ExploitMaturity string
+	Reachable       string
+	OwnerName       string // names of the application owners, joined with ", "
+	OwnerEmail      string // email addresses of the application owners, joined with ", "
+	// Tags are the values of the application's tags by key, shared by all
+	// rows of the application.
+	Tags map[string]string
 }
 
 // csvHeaders returns the CSV header row in the required order.
@@ -46,61 +73,288 @@ func csvHeaders() []string {
 	}
 }
 
+// optionalColumns are appended after the standard columns, in this order,
+// when enabled with WithOptionalColumns.
+var optionalColumns = []struct {
+	header string
+	value  func(Row) string
+}{
+	{ColumnThreatCategory, func(r Row) string { return r.Category }},
+	{ColumnWaived, func(r Row) string { return strconv.FormatBool(r.Waived) }},
+	{ColumnWaiverExpiry, func(r Row) string { return r.WaiverExpiry }},
+	{ColumnWaiverCreator, func(r Row) string { return r.WaiverCreator }},
+	{ColumnStage, func(r Row) string { return r.Stage }},
+	{ColumnRowID, Row.RowID},
+	{"Hash", func(r Row) string { return r.Hash }},
+	{"IsProprietary", func(r Row) string { return strconv.FormatBool(r.Proprietary) }},
+	{"Labels", func(r Row) string { return strings.Join(r.Labels, ", ") }},
+	{"Claimed", func(r Row) string { return strconv.FormatBool(r.Claimed) }},
+	{ColumnOwnerName, func(r Row) string { return r.OwnerName }},
+	{ColumnOwnerEmail, func(r Row) string { return r.OwnerEmail }},
+	{"KEVListed", func(r Row) string { return strconv.FormatBool(r.KEVListed) }},
+	{"ExploitMaturity", func(r Row) string { return r.ExploitMaturity }},
+	{"Reachable", func(r Row) string { return r.Reachable }},
+}
+
+// ColumnThreatCategory is the header of the policy threat category column.
+const ColumnThreatCategory = "Threat Category"
+
+// Headers of the waiver columns. Enabling the expiry or creator column makes
+// the service fetch the waivers of every application with waived
+// violations.
+const (
+	ColumnWaived        = "Waived"
+	ColumnWaiverExpiry  = "Waiver Expiry"
+	ColumnWaiverCreator = "Waiver Creator"
+)
+
+// ColumnStage is the header of the column naming the stage of the report a
+// row comes from. The service enables it when it merges several stages.
+const ColumnStage = "Stage"
+
+// ColumnRowID is the header of the column holding Row.RowID, which bulk
+// waiver files take as the violation ID.
+const ColumnRowID = "Row ID"
+
+// Headers of the owner columns. Enabling them makes the service fetch the
+// owners of every application.
+const (
+	ColumnOwnerName  = "OwnerName"
+	ColumnOwnerEmail = "OwnerEmail"
+)
+
+// OptionalColumns returns the headers of the columns that can be enabled
+// with WithOptionalColumns.
+func OptionalColumns() []string {
+	headers := make([]string, len(optionalColumns))
+	for i, c := range optionalColumns {
+		headers[i] = c.header
+	}
+	return headers
+}
+
+// CSVOption configures WriteCSV.
+type CSVOption func(*csvOptions)
+
+type csvOptions struct {
+	emptyValue   string
+	columnEmpty  map[string]string
+	optional     []string
+	tags         []string
+	threatFormat string
+	noRowNumbers bool
+	escape       bool
+	bom          bool
+}
+
+// WithEmptyValue writes v instead of empty cells, e.g. "N/A" or "-", for
+// loaders that reject empty fields.
+func WithEmptyValue(v string) CSVOption {
+	return func(o *csvOptions) { o.emptyValue = v }
+}
+
+// WithColumnEmptyValues sets the placeholder for empty cells per column
+// header, taking precedence over WithEmptyValue. An empty placeholder keeps
+// the column's cells empty.
+func WithColumnEmptyValues(values map[string]string) CSVOption {
+	return func(o *csvOptions) { o.columnEmpty = values }
+}
+
+// WithOptionalColumns enables optional columns by header (see
+// OptionalColumns). They are written after the standard columns in their
+// fixed order, regardless of the order given.
+func WithOptionalColumns(headers ...string) CSVOption {
+	return func(o *csvOptions) { o.optional = headers }
+}
+
+// WithTagColumns appends one column per application tag key, after the
+// optional columns, holding the row's Tags value for that key.
+func WithTagColumns(keys ...string) CSVOption {
+	return func(o *csvOptions) { o.tags = keys }
+}
+
+// WithRowNumbers writes the "No." column numbering the rows when enabled,
+// which is the default. Without it the report starts with the Application
+// column.
+func WithRowNumbers(enabled bool) CSVOption {
+	return func(o *csvOptions) { o.noRowNumbers = !enabled }
+}
+
+// WithFormulaEscaping prefixes cells starting with =, +, -, @, a tab or a
+// carriage return with a single quote when enabled, so that spreadsheet
+// applications such as Excel show them as text instead of evaluating them
+// as formulas (CSV injection). Placeholders for empty cells are written as
+// configured.
+func WithFormulaEscaping(enabled bool) CSVOption {
+	return func(o *csvOptions) { o.escape = enabled }
+}
+
+// WithUTF8BOM starts the file with a UTF-8 byte order mark when enabled.
+// Excel otherwise opens CSV files in the legacy code page of the system and
+// garbles non-ASCII text such as CJK component names.
+func WithUTF8BOM(enabled bool) CSVOption {
+	return func(o *csvOptions) { o.bom = enabled }
+}
+
+// WithThreatFormat sets how the Threat column is written, see FormatThreat.
+// An empty format writes the number.
+func WithThreatFormat(format string) CSVOption {
+	return func(o *csvOptions) { o.threatFormat = format }
+}
+
+// CSVColumns returns the column headers of the CSV report in order, including
+// the optional columns enabled by opts.
+func CSVColumns(opts ...CSVOption) []string {
+	layout, _ := newCSVLayout(opts)
+	return layout.headers
+}
+
+// csvLayout is the column layout of a report for a set of options.
+type csvLayout struct {
+	headers      []string
+	rowNumbers   bool
+	escape       bool
+	bom          bool
+	threatFormat string
+	optional     []func(Row) string
+	tags         []string // tag keys of the tag columns
+	placeholders []string // empty cell placeholder per column
+}
+
+// newCSVLayout returns the layout for opts. Unknown optional columns, tag
+// columns named like another column and placeholders for unknown columns are
+// reported as errors; the returned layout skips them.
+func newCSVLayout(opts []CSVOption) (*csvLayout, error) {
+	var o csvOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var errs []error
+	if o.threatFormat != "" && !slices.Contains(ThreatFormats(), o.threatFormat) {
+		errs = append(errs, fmt.Errorf("unknown threat format %q", o.threatFormat))
+	}
+	for _, h := range o.optional {
+		if !slices.Contains(OptionalColumns(), h) {
+			errs = append(errs, fmt.Errorf("unknown optional column %q", h))
+		}
+	}
+	layout := &csvLayout{headers: csvHeaders(), rowNumbers: !o.noRowNumbers, escape: o.escape, bom: o.bom, threatFormat: o.threatFormat}
+	if !layout.rowNumbers {
+		layout.headers = layout.headers[1:]
+	}
+	for _, c := range optionalColumns {
+		if slices.Contains(o.optional, c.header) {
+			layout.headers = append(layout.headers, c.header)
+			layout.optional = append(layout.optional, c.value)
+		}
+	}
+	for _, key := range o.tags {
+		if slices.Contains(layout.headers, key) {
+			errs = append(errs, fmt.Errorf("tag column %q duplicates a report column", key))
+			continue
+		}
+		layout.headers = append(layout.headers, key)
+		layout.tags = append(layout.tags, key)
+	}
+	for column := range o.columnEmpty {
+		if !slices.Contains(layout.headers, column) {
+			errs = append(errs, fmt.Errorf("empty value placeholder for unknown column %q", column))
+		}
+	}
+
+	layout.placeholders = make([]string, len(layout.headers))
+	for i, h := range layout.headers {
+		layout.placeholders[i] = o.emptyValue
+		if v, ok := o.columnEmpty[h]; ok {
+			layout.placeholders[i] = v
+		}
+	}
+	return layout, errors.Join(errs...)
+}
+
+// record returns the cells of r, the i-th (zero-based) row of the report.
+func (l *csvLayout) record(i int, r Row) []string {
+	rec := record(i, r)
+	if l.threatFormat != "" {
+		rec[threatColumn] = FormatThreat(r.Threat, l.threatFormat)
+	}
+	if !l.rowNumbers {
+		rec = rec[1:]
+	}
+	for _, value := range l.optional {
+		rec = append(rec, value(r))
+	}
+	for _, key := range l.tags {
+		rec = append(rec, r.Tags[key])
+	}
+	return rec
+}
+
 // WriteCSV writes the given rows into a CSV file at destPath. It ensures
 // the destination directory exists and writes to a temporary file in the
-// same directory before renaming it to the final destination. Errors are
-// returned to the caller; this function does not log errors itself.
-func WriteCSV(destPath string, rows []Row, logger zerolog.Logger) error {
-	// Ensure absolute path with proper separators for Windows compatibility
-	absPath, err := filepath.Abs(destPath)
+// same directory before renaming it to the final destination. It returns the
+// path written, an alternate name next to destPath when the destination
+// stays locked. Errors are returned to the caller; this function does not log
+// errors itself.
+func WriteCSV(ctx context.Context, destPath string, rows []Row, logger zerolog.Logger, opts ...CSVOption) (string, error) {
+	layout, err := newCSVLayout(opts)
 	if err != nil {
-		return fmt.Errorf("get absolute path: %w", err)
-	}
-
-	dir := filepath.Dir(absPath)
-	logger.Debug().Str("dir", dir).Msg("preparing output directory")
-	if err := os.MkdirAll(dir, 0o755); err != nil {
-		logger.Error().Err(err).Str("dir", dir).Msg("failed to create output dir")
-		return fmt.Errorf("prepare output dir: %w", err)
+		return "", err
 	}
+	return writeFileAtomic(ctx, destPath, logger, func(f io.Writer) error {
+		if err := writeRecords(f, rows, 0, layout); err != nil {
+			return err
+		}
+		logger.Debug().Int("rows", len(rows)).Msg("csv rows encoded")
+		return nil
+	})
+}
 
-	// Create temp file in SAME directory as final file to ensure os.Rename works on Windows
-	tmp, err := os.CreateTemp(dir, ".tmp-*.csv")
+// WriteCSVTo writes rows as CSV to f with the columns of WriteCSV, e.g.
+// into an archive, an HTTP response or a buffer.
+func WriteCSVTo(f io.Writer, rows []Row, opts ...CSVOption) error {
+	layout, err := newCSVLayout(opts)
 	if err != nil {
-		return fmt.Errorf("create temp file: %w", err)
+		return err
 	}
-	tmpPath := tmp.Name()
-	// Ensure the temporary file is closed and removed when we return.
-	defer func() {
-		_ = tmp.Close()
-		_ = os.Remove(tmpPath)
-	}()
-	logger.Debug().Str("tmp", tmpPath).Msg("created temp file")
+	return writeRecords(f, rows, 0, layout)
+}
 
-	w := csv.NewWriter(tmp)
+// writeRecords writes the header and rows as CSV to f. offset is the
+// zero-based index of rows[0] in the report, so that row numbers continue
+// across chunks.
+func writeRecords(f io.Writer, rows []Row, offset int, layout *csvLayout) error {
+	if layout.bom {
+		if _, err := io.WriteString(f, utf8BOM); err != nil {
+			return fmt.Errorf("write byte order mark: %w", err)
+		}
+	}
+	w := csv.NewWriter(f)
 
 	// header
-	if err := w.Write(csvHeaders()); err != nil {
+	if err := w.Write(layout.headers); err != nil {
 		return fmt.Errorf("write header: %w", err)
 	}
 
 	// rows
 	for i, r := range rows {
-		record := []string{
-			strconv.Itoa(i + 1),
-			r.Application,
-			r.Organization,
-			r.Policy,
-			r.Format,
-			r.Component,
-			strconv.Itoa(r.Threat),
-			r.PolicyAction,
-			r.ConstraintName,
-			r.Condition,
-			r.CVE,
+		rec := layout.record(offset+i, r)
+		for j, cell := range rec {
+			if cell == "" {
+				rec[j] = layout.placeholders[j]
+				continue
+			}
+			// Invalid byte sequences would make the whole file undecodable
+			// as UTF-8 for strict readers
+			cell = strings.ToValidUTF8(cell, "\uFFFD")
+			if layout.escape {
+				cell = EscapeFormula(cell)
+			}
+			rec[j] = cell
 		}
-		if err := w.Write(record); err != nil {
-			return fmt.Errorf("write row %d: %w", i+1, err)
+		if err := w.Write(rec); err != nil {
+			return fmt.Errorf("write row %d: %w", offset+i+1, err)
 		}
 	}
 
@@ -108,27 +362,76 @@ func WriteCSV(destPath string, rows []Row, logger zerolog.Logger) error {
 	if err := w.Error(); err != nil {
 		return fmt.Errorf("flush csv: %w", err)
 	}
-	if err := tmp.Sync(); err != nil {
-		return fmt.Errorf("fsync temp: %w", err)
-	}
+	return nil
+}
 
-	// Close temp file BEFORE rename (Windows requires file to be closed)
-	if err := tmp.Close(); err != nil {
-		return fmt.Errorf("close temp: %w", err)
+// Table returns the report as rows of cells in CSV column order, header
+// first, for writers of other tabular formats. Optional columns enabled by
+// opts are included; placeholders are not applied.
+func Table(rows []Row, opts ...CSVOption) [][]string {
+	layout, _ := newCSVLayout(opts)
+	table := make([][]string, 0, len(rows)+1)
+	table = append(table, layout.headers)
+	for i, r := range rows {
+		table = append(table, layout.record(i, r))
 	}
+	return table
+}
 
-	// Remove existing destination file if it exists (Windows requirement)
-	_ = os.Remove(absPath)
+// utf8BOM is the UTF-8 encoded byte order mark.
+const utf8BOM = "\uFEFF"
 
-	// Atomic rename (now works on Windows since both files are in same directory)
-	if err := os.Rename(tmpPath, absPath); err != nil {
-		return fmt.Errorf("atomic rename: %w", err)
+// EscapeFormula returns cell prefixed with a single quote when a
+// spreadsheet application would evaluate it as a formula, i.e. when it
+// starts with =, +, -, @, a tab or a carriage return.
+func EscapeFormula(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
 	}
+	return cell
+}
+
+// threatColumn is the index of the Threat column in record.
+const threatColumn = 6
 
-	if err := os.Chmod(absPath, 0o644); err != nil {
-		return fmt.Errorf("chmod: %w", err)
+// record returns the standard CSV cells of r, the i-th (zero-based) row of
+// the report.
+func record(i int, r Row) []string {
+	return []string{
+		strconv.Itoa(i + 1),
+		r.Application,
+		r.Organization,
+		r.Policy,
+		r.Format,
+		r.Component,
+		strconv.Itoa(r.Threat),
+		r.PolicyAction,
+		r.ConstraintName,
+		r.Condition,
+		r.CVE,
 	}
+}
 
-	logger.Info().Str("path", absPath).Int("rows", len(rows)).Msg("csv file written successfully")
-	return nil
+// SortRows orders rows by organization, application, stage, policy,
+// component, constraint, Row ID and CVE, so that a report of unchanged
+// violations lists, and numbers, its rows the same way in every run
+// regardless of the order in which applications were fetched.
+func SortRows(rows []Row) {
+	slices.SortStableFunc(rows, func(a, b Row) int {
+		for _, c := range [][2]string{
+			{a.Organization, b.Organization},
+			{a.Application, b.Application},
+			{a.Stage, b.Stage},
+			{a.Policy, b.Policy},
+			{a.Component, b.Component},
+			{a.ConstraintName, b.ConstraintName},
+			{a.RowID(), b.RowID()},
+			{a.CVE, b.CVE},
+		} {
+			if n := strings.Compare(c[0], c[1]); n != 0 {
+				return n
+			}
+		}
+		return 0
+	})
 }
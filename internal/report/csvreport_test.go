@@ -2,11 +2,16 @@
 package report
 
 import (
+	"context"
 	"encoding/csv"
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 	"testing"
+	"unicode/utf8"
 
 	"github.com/rs/zerolog"
 )
@@ -39,11 +44,12 @@ func TestWriteCSV_WritesHeaderAndRows(t *testing.T) {
 			ConstraintName: "High risk CVSS score",
 			Condition:      "Security Vulnerability Severity >= 7",
 			CVE:            "CVE-2024-0001",
+			Category:       "security",
 		},
 	}
 
 	logger := zerolog.New(io.Discard)
-	if err := WriteCSV(dest, rows, logger); err != nil {
+	if _, err := WriteCSV(context.Background(), dest, rows, logger); err != nil {
 		t.Fatalf("WriteCSV error = %v", err)
 	}
 
@@ -80,6 +86,18 @@ func TestWriteCSV_WritesHeaderAndRows(t *testing.T) {
 	if want, got := "CVE-2024-0001", records[2][10]; want != got {
 		t.Errorf("row2 CVE = %q", got)
 	}
+	if slices.Contains(records[0], ColumnThreatCategory) {
+		t.Errorf("header %v has the optional Threat Category column", records[0])
+	}
+
+	table := Table(rows, WithOptionalColumns(ColumnThreatCategory))
+	last := len(table[0]) - 1
+	if want, got := ColumnThreatCategory, table[0][last]; want != got {
+		t.Errorf("last header = %q, want %q", got, want)
+	}
+	if want, got := "security", table[2][last]; want != got {
+		t.Errorf("row2 Threat Category = %q, want %q", got, want)
+	}
 }
 
 func TestWriteCSV_HandlesSpecialCharacters(t *testing.T) {
@@ -94,7 +112,7 @@ func TestWriteCSV_HandlesSpecialCharacters(t *testing.T) {
 		},
 	}
 
-	if err := WriteCSV(dest, rows, zerolog.New(io.Discard)); err != nil {
+	if _, err := WriteCSV(context.Background(), dest, rows, zerolog.New(io.Discard)); err != nil {
 		t.Fatalf("WriteCSV error = %v", err)
 	}
 
@@ -124,3 +142,217 @@ func TestWriteCSV_HandlesSpecialCharacters(t *testing.T) {
 		t.Errorf("Policy = %q", got)
 	}
 }
+
+func TestWriteCSV_EmptyValuePlaceholders(t *testing.T) {
+	dest := filepath.Join(t.TempDir(), "out.csv")
+	rows := []Row{{Application: "app-1", Component: "lib"}}
+
+	_, err := WriteCSV(context.Background(), dest, rows, zerolog.New(io.Discard),
+		WithEmptyValue("N/A"),
+		WithColumnEmptyValues(map[string]string{"CVE": "-", "Constraint Name": ""}),
+	)
+	if err != nil {
+		t.Fatalf("WriteCSV error = %v", err)
+	}
+
+	f, err := os.Open(dest)
+	if err != nil {
+		t.Fatalf("open file: %v", err)
+	}
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatalf("read csv: %v", err)
+	}
+
+	cell := func(column string) string {
+		for i, h := range records[0] {
+			if h == column {
+				return records[1][i]
+			}
+		}
+		t.Fatalf("column %q missing", column)
+		return ""
+	}
+	if got := cell("Application"); got != "app-1" {
+		t.Errorf("Application = %q", got)
+	}
+	if got := cell("Condition"); got != "N/A" {
+		t.Errorf("Condition = %q, want global placeholder", got)
+	}
+	if got := cell("CVE"); got != "-" {
+		t.Errorf("CVE = %q, want column placeholder", got)
+	}
+	if got := cell("Constraint Name"); got != "" {
+		t.Errorf("Constraint Name = %q, want empty", got)
+	}
+
+	if _, err := WriteCSV(context.Background(), dest, rows, zerolog.New(io.Discard), WithColumnEmptyValues(map[string]string{"Nope": "-"})); err == nil {
+		t.Error("expected error for unknown column")
+	}
+}
+
+func TestWriteCSV_OptionalColumns(t *testing.T) {
+	dest := filepath.Join(t.TempDir(), "out.csv")
+	rows := []Row{{Application: "app-1", Hash: "0a1b2c3d"}, {Application: "app-2"}}
+
+	_, err := WriteCSV(context.Background(), dest, rows, zerolog.New(io.Discard),
+		WithOptionalColumns("Hash"),
+		WithColumnEmptyValues(map[string]string{"Hash": "-"}),
+	)
+	if err != nil {
+		t.Fatalf("WriteCSV error = %v", err)
+	}
+
+	f, err := os.Open(dest)
+	if err != nil {
+		t.Fatalf("open file: %v", err)
+	}
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatalf("read csv: %v", err)
+	}
+
+	last := len(csvHeaders())
+	if len(records[0]) != last+1 || records[0][last] != "Hash" {
+		t.Fatalf("header = %v, want Hash appended", records[0])
+	}
+	if records[1][last] != "0a1b2c3d" || records[2][last] != "-" {
+		t.Errorf("Hash cells = %q, %q", records[1][last], records[2][last])
+	}
+	if got := Table(rows, WithOptionalColumns("Hash"))[1][last]; got != "0a1b2c3d" {
+		t.Errorf("Table Hash cell = %q", got)
+	}
+	if got := CSVColumns(WithOptionalColumns("IsProprietary", "Hash")); got[last] != "Hash" || got[last+1] != "IsProprietary" {
+		t.Errorf("optional columns = %v, want fixed order", got[last:])
+	}
+	if got := len(CSVColumns()); got != last {
+		t.Errorf("CSVColumns() has %d columns, want %d without optional columns", got, last)
+	}
+
+	if _, err := WriteCSV(context.Background(), dest, rows, zerolog.New(io.Discard), WithOptionalColumns("Nope")); err == nil {
+		t.Error("expected error for unknown optional column")
+	}
+	if _, err := WriteCSV(context.Background(), dest, rows, zerolog.New(io.Discard), WithColumnEmptyValues(map[string]string{"Hash": "-"})); err == nil {
+		t.Error("expected error for placeholder of a disabled optional column")
+	}
+}
+
+func BenchmarkWriteCSV(b *testing.B) {
+	rows := make([]Row, 100_000)
+	for i := range rows {
+		rows[i] = Row{
+			Application:    "app-" + strconv.Itoa(i%200),
+			Organization:   "org",
+			Policy:         "Security-High",
+			Format:         "maven",
+			Component:      "org.example:lib-" + strconv.Itoa(i) + ":1.0.0",
+			Threat:         i % 11,
+			Category:       "security",
+			Stage:          "build",
+			PolicyAction:   "Security-9",
+			ConstraintName: "High Risk CVEs",
+			Condition:      "Security Vulnerability Severity >= 7 | CVE Count >= 1",
+			CVE:            "CVE-2024-0001, CVE-2024-0002",
+		}
+	}
+	dest := filepath.Join(b.TempDir(), "bench.csv")
+	logger := zerolog.New(io.Discard)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := WriteCSV(context.Background(), dest, rows, logger); err != nil {
+			b.Fatalf("WriteCSV: %v", err)
+		}
+	}
+}
+
+func TestTable_ThreatFormat(t *testing.T) {
+	rows := []Row{{Application: "a", Threat: 9}, {Application: "b", Threat: 2}}
+
+	table := Table(rows, WithThreatFormat(ThreatFormatLabeled))
+	if table[0][threatColumn] != "Threat" {
+		t.Fatalf("column %d is %q, want Threat", threatColumn, table[0][threatColumn])
+	}
+	if table[1][threatColumn] != "Critical (9)" || table[2][threatColumn] != "Moderate (2)" {
+		t.Errorf("threat cells = %q, %q", table[1][threatColumn], table[2][threatColumn])
+	}
+
+	dest := filepath.Join(t.TempDir(), "report.csv")
+	if _, err := WriteCSV(context.Background(), dest, rows, zerolog.New(io.Discard), WithThreatFormat("stars")); err == nil {
+		t.Error("expected error for unknown threat format")
+	}
+}
+
+func TestWriteCSV_WithoutRowNumbers(t *testing.T) {
+	dest := filepath.Join(t.TempDir(), "out.csv")
+	if _, err := WriteCSV(context.Background(), dest, goldenRows(), zerolog.New(io.Discard), WithRowNumbers(false), WithThreatFormat(ThreatFormatBand)); err != nil {
+		t.Fatalf("WriteCSV: %v", err)
+	}
+	f, err := os.Open(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if records[0][0] != "Application" || len(records[0]) != len(csvHeaders())-1 {
+		t.Errorf("header = %v", records[0])
+	}
+	if records[1][0] != "web-app" || records[1][5] != "Critical" {
+		t.Errorf("first row = %v", records[1])
+	}
+}
+
+func TestSortRows(t *testing.T) {
+	rows := goldenRows()
+	shuffled := []Row{rows[3], rows[1], rows[2], rows[0]}
+	SortRows(shuffled)
+	SortRows(rows)
+	for i := range rows {
+		if rows[i].RowID() != shuffled[i].RowID() {
+			t.Fatalf("row %d differs after sorting: %s vs %s", i, rows[i].RowID(), shuffled[i].RowID())
+		}
+	}
+	if rows[0].Organization != "payments" || rows[len(rows)-1].Organization != "platform" {
+		t.Errorf("rows not sorted by organization: %s ... %s", rows[0].Organization, rows[len(rows)-1].Organization)
+	}
+}
+
+func TestWriteCSV_SpreadsheetSafety(t *testing.T) {
+	dest := filepath.Join(t.TempDir(), "out.csv")
+	rows := []Row{
+		{Application: "=HYPERLINK(\"http://evil\")", Organization: "+org", Policy: "-policy", Component: "@SUM(A1)", Condition: "\tcmd", CVE: "safe=value"},
+		{Application: "アプリ", Organization: "组织", Component: "左パッド 1.0", Condition: "bad \xff byte"},
+	}
+	opts := []CSVOption{WithFormulaEscaping(true), WithUTF8BOM(true), WithEmptyValue("-")}
+	if _, err := WriteCSV(context.Background(), dest, rows, zerolog.New(io.Discard), opts...); err != nil {
+		t.Fatalf("WriteCSV: %v", err)
+	}
+	b, err := os.ReadFile(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(b), utf8BOM+"No.,") {
+		t.Fatalf("report does not start with a byte order mark and the header: %q", b[:10])
+	}
+	if !utf8.Valid(b) {
+		t.Error("report is not valid UTF-8")
+	}
+	records, err := csv.NewReader(strings.NewReader(strings.TrimPrefix(string(b), utf8BOM))).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := records[1]
+	for i, want := range map[int]string{1: `'=HYPERLINK("http://evil")`, 2: "'+org", 3: "'-policy", 5: "'@SUM(A1)", 9: "'\tcmd", 10: "safe=value", 4: "-"} {
+		if got[i] != want {
+			t.Errorf("column %s = %q, want %q", records[0][i], got[i], want)
+		}
+	}
+	if got := records[2]; got[1] != "アプリ" || got[2] != "组织" || got[5] != "左パッド 1.0" || got[9] != "bad � byte" {
+		t.Errorf("unicode row = %q", got)
+	}
+}
@@ -0,0 +1,43 @@
+// internal/report/cve.go
+package report
+
+import "strings"
+
+// cveSeparator separates multiple CVE identifiers in an aggregated CVE cell.
+const cveSeparator = ", "
+
+// JoinCVEs aggregates CVE identifiers into a single CVE cell value.
+func JoinCVEs(ids []string) string {
+	return strings.Join(ids, cveSeparator)
+}
+
+// SplitCVEs returns the individual CVE identifiers of an aggregated CVE cell.
+func SplitCVEs(cell string) []string {
+	var ids []string
+	for _, id := range strings.Split(cell, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// SplitCVERows expands every row referencing several CVEs into one row per
+// CVE, for consumers that require exactly one CVE per line. Rows with zero or
+// one CVE are kept as they are.
+func SplitCVERows(rows []Row) []Row {
+	out := make([]Row, 0, len(rows))
+	for _, r := range rows {
+		ids := SplitCVEs(r.CVE)
+		if len(ids) <= 1 {
+			out = append(out, r)
+			continue
+		}
+		for _, id := range ids {
+			split := r
+			split.CVE = id
+			out = append(out, split)
+		}
+	}
+	return out
+}
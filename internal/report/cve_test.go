@@ -0,0 +1,36 @@
+// internal/report/cve_test.go
+package report
+
+import "testing"
+
+func TestJoinAndSplitCVEs(t *testing.T) {
+	cell := JoinCVEs([]string{"CVE-2024-0001", "CVE-2024-0002"})
+	if cell != "CVE-2024-0001, CVE-2024-0002" {
+		t.Errorf("JoinCVEs = %q", cell)
+	}
+	if ids := SplitCVEs(cell); len(ids) != 2 || ids[1] != "CVE-2024-0002" {
+		t.Errorf("SplitCVEs = %#v", ids)
+	}
+	if ids := SplitCVEs(""); len(ids) != 0 {
+		t.Errorf("SplitCVEs(\"\") = %#v", ids)
+	}
+}
+
+func TestSplitCVERows(t *testing.T) {
+	rows := []Row{
+		{Application: "a", CVE: "CVE-2024-0001, CVE-2024-0002"},
+		{Application: "b", CVE: "CVE-2024-0003"},
+		{Application: "c"},
+	}
+
+	got := SplitCVERows(rows)
+	if len(got) != 4 {
+		t.Fatalf("expected 4 rows, got %#v", got)
+	}
+	if got[0].CVE != "CVE-2024-0001" || got[1].CVE != "CVE-2024-0002" || got[1].Application != "a" {
+		t.Errorf("split rows = %#v", got[:2])
+	}
+	if got[2].CVE != "CVE-2024-0003" || got[3].CVE != "" {
+		t.Errorf("unsplit rows changed: %#v", got[2:])
+	}
+}
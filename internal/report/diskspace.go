@@ -0,0 +1,77 @@
+// internal/report/diskspace.go
+package report
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// bytesPerApplication estimates the output size per application when no
+// previous run is available to extrapolate from.
+const bytesPerApplication = 32 << 10
+
+// diskSpaceHeadroom multiplies the estimated report size to cover the
+// temporary file of the atomic write and the rollups written next to it.
+const diskSpaceHeadroom = 2
+
+// EstimateOutputBytes estimates the disk space a run over apps applications
+// needs: the size of the previous report (and its chunks) scaled by the
+// application count when previous is known and its files still exist, or
+// a fixed size per application otherwise, plus headroom.
+func EstimateOutputBytes(previous *Manifest, apps int) uint64 {
+	if previous != nil && previous.Applications > 0 {
+		var size int64
+		for _, path := range append([]string{previous.ReportPath}, previous.Chunks...) {
+			if info, err := os.Stat(path); err == nil {
+				size += info.Size()
+			}
+		}
+		if size > 0 {
+			return uint64(size) * uint64(apps) / uint64(previous.Applications) * diskSpaceHeadroom
+		}
+	}
+	return uint64(apps) * bytesPerApplication * diskSpaceHeadroom
+}
+
+// FreeSpace returns the bytes available to the current user on the file
+// system holding dir. When dir does not exist yet, its closest existing
+// parent is checked.
+func FreeSpace(dir string) (uint64, error) {
+	path, err := filepath.Abs(dir)
+	if err != nil {
+		return 0, fmt.Errorf("get absolute path: %w", err)
+	}
+	for {
+		_, err := os.Stat(path)
+		if err == nil {
+			break
+		}
+		parent := filepath.Dir(path)
+		if !errors.Is(err, fs.ErrNotExist) || parent == path {
+			return 0, fmt.Errorf("stat output directory: %w", err)
+		}
+		path = parent
+	}
+	free, err := freeSpace(path)
+	if err != nil {
+		return 0, fmt.Errorf("free space of %s: %w", path, err)
+	}
+	return free, nil
+}
+
+// FormatBytes formats n with a binary unit, e.g. "1.5 GiB".
+func FormatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,12 @@
+// internal/report/diskspace_other.go
+
+//go:build !unix && !windows
+
+package report
+
+import "errors"
+
+// freeSpace is not supported on this platform.
+func freeSpace(string) (uint64, error) {
+	return 0, errors.ErrUnsupported
+}
@@ -0,0 +1,45 @@
+// internal/report/diskspace_test.go
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEstimateOutputBytes(t *testing.T) {
+	if got := EstimateOutputBytes(nil, 10); got != 10*bytesPerApplication*diskSpaceHeadroom {
+		t.Errorf("without previous run = %d", got)
+	}
+
+	dir := t.TempDir()
+	reportPath := filepath.Join(dir, "report.csv")
+	if err := os.WriteFile(reportPath, make([]byte, 1000), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	previous := &Manifest{ReportPath: reportPath, Applications: 5}
+	if got := EstimateOutputBytes(previous, 10); got != 2000*diskSpaceHeadroom {
+		t.Errorf("scaled from previous run = %d, want %d", got, 2000*diskSpaceHeadroom)
+	}
+
+	// A previous report that was removed since falls back to the fixed size
+	previous.ReportPath = filepath.Join(dir, "gone.csv")
+	if got := EstimateOutputBytes(previous, 10); got != 10*bytesPerApplication*diskSpaceHeadroom {
+		t.Errorf("previous report missing = %d", got)
+	}
+}
+
+func TestFreeSpace_MissingDirectory(t *testing.T) {
+	free, err := FreeSpace(filepath.Join(t.TempDir(), "not", "yet", "created"))
+	if err != nil || free == 0 {
+		t.Errorf("FreeSpace = %d, %v", free, err)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	for n, want := range map[uint64]string{512: "512 B", 1536: "1.5 KiB", 3 << 30: "3.0 GiB"} {
+		if got := FormatBytes(n); got != want {
+			t.Errorf("FormatBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+// internal/report/disposition.go
+package report
+
+import "time"
+
+// Dispositions of applications: what became of each application of a run,
+// telling apart the causes of an application without rows.
+const (
+	DispositionExported = "exported"     // rows written to the report
+	DispositionNoReport = "no_report"    // never evaluated at the exported stages
+	DispositionEmpty    = "empty_report" // evaluated without policy violations
+	DispositionFiltered = "filtered"     // every row removed by filters or suppressions
+	DispositionRemoved  = "removed"      // deleted in IQ Server during the run
+	DispositionFailed   = "error"        // fetching failed, see Error
+)
+
+// AppDisposition records the disposition of one application of a run.
+type AppDisposition struct {
+	Application  string `json:"application"`
+	Organization string `json:"organization,omitempty"`
+	Disposition  string `json:"disposition"`
+	FetchedRows  int    `json:"fetchedRows"` // rows before filters and suppressions
+	Rows         int    `json:"rows"`        // rows in the report
+	// ScanDate is the latest evaluation of the exported reports, so that
+	// stale applications can be told apart.
+	ScanDate *time.Time `json:"scanDate,omitempty"`
+	Error    string     `json:"error,omitempty"`
+}
+
+// CountDispositions returns the number of applications per disposition.
+func CountDispositions(ds []AppDisposition) map[string]int {
+	counts := make(map[string]int)
+	for _, d := range ds {
+		counts[d.Disposition]++
+	}
+	return counts
+}
@@ -0,0 +1,352 @@
+// internal/report/filter.go
+package report
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Filter is a compiled row filter expression, see ParseFilter.
+type Filter struct {
+	expr  string
+	match func(Row) bool
+}
+
+// Match reports whether r satisfies the filter. A nil filter matches every
+// row.
+func (f *Filter) Match(r Row) bool {
+	return f == nil || f.match(r)
+}
+
+// String returns the source expression of the filter.
+func (f *Filter) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.expr
+}
+
+// filterFields are the row fields available in filter expressions. Each
+// accessor returns a string, an int or a bool.
+var filterFields = map[string]func(Row) any{
+	"Application":     func(r Row) any { return r.Application },
+	"Organization":    func(r Row) any { return r.Organization },
+	"Policy":          func(r Row) any { return r.Policy },
+	"Format":          func(r Row) any { return r.Format },
+	"Component":       func(r Row) any { return r.Component },
+	"Threat":          func(r Row) any { return r.Threat },
+	"Category":        func(r Row) any { return r.Category },
+	"PolicyAction":    func(r Row) any { return r.PolicyAction },
+	"ConstraintName":  func(r Row) any { return r.ConstraintName },
+	"Condition":       func(r Row) any { return r.Condition },
+	"CVE":             func(r Row) any { return r.CVE },
+	"Stage":           func(r Row) any { return r.Stage },
+	"Waived":          func(r Row) any { return r.Waived },
+	"WaiverExpiry":    func(r Row) any { return r.WaiverExpiry },
+	"WaiverCreator":   func(r Row) any { return r.WaiverCreator },
+	"Hash":            func(r Row) any { return r.Hash },
+	"Proprietary":     func(r Row) any { return r.Proprietary },
+	"Labels":          func(r Row) any { return strings.Join(r.Labels, ", ") },
+	"Claimed":         func(r Row) any { return r.Claimed },
+	"KEVListed":       func(r Row) any { return r.KEVListed },
+	"ExploitMaturity": func(r Row) any { return r.ExploitMaturity },
+	"Reachable":       func(r Row) any { return r.Reachable },
+	"OwnerName":       func(r Row) any { return r.OwnerName },
+	"OwnerEmail":      func(r Row) any { return r.OwnerEmail },
+	"RowID":           func(r Row) any { return r.RowID() },
+}
+
+// FilterFields returns the names of the fields available in filter
+// expressions, sorted.
+func FilterFields() []string {
+	names := make([]string, 0, len(filterFields))
+	for name := range filterFields {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// ParseFilter compiles a row filter expression such as
+//
+//	Threat >= 7 && Format == "maven" && Organization != "sandbox"
+//
+// Comparisons take a field (see FilterFields) on the left and a literal on
+// the right: a double-quoted string, an integer, or true/false. Strings
+// support ==, != and =~ (regular expression match), integers all of ==, !=,
+// <, <=, > and >=, and booleans == and !=; a boolean field on its own tests
+// for true. Comparisons combine with &&, || and !, and group with
+// parentheses. An empty expression returns a nil filter, which matches
+// every row.
+func ParseFilter(expr string) (*Filter, error) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, nil
+	}
+	tokens, err := tokenizeFilter(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &filterParser{tokens: tokens}
+	match, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %s at offset %d", t, t.pos)
+	}
+	return &Filter{expr: expr, match: match}, nil
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokOp     // comparison operator
+	tokAnd    // &&
+	tokOr     // ||
+	tokNot    // !
+	tokLParen // (
+	tokRParen // )
+)
+
+type filterToken struct {
+	kind tokenKind
+	text string // identifier, operator or unquoted string
+	pos  int
+}
+
+func (t filterToken) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of expression"
+	case tokString:
+		return strconv.Quote(t.text)
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+// tokenizeFilter splits a filter expression into tokens.
+func tokenizeFilter(expr string) ([]filterToken, error) {
+	var tokens []filterToken
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, filterToken{tokLParen, "(", i})
+			i++
+		case c == ')':
+			tokens = append(tokens, filterToken{tokRParen, ")", i})
+			i++
+		case strings.HasPrefix(expr[i:], "&&"):
+			tokens = append(tokens, filterToken{tokAnd, "&&", i})
+			i += 2
+		case strings.HasPrefix(expr[i:], "||"):
+			tokens = append(tokens, filterToken{tokOr, "||", i})
+			i += 2
+		case strings.HasPrefix(expr[i:], "=="), strings.HasPrefix(expr[i:], "!="),
+			strings.HasPrefix(expr[i:], "<="), strings.HasPrefix(expr[i:], ">="),
+			strings.HasPrefix(expr[i:], "=~"):
+			tokens = append(tokens, filterToken{tokOp, expr[i : i+2], i})
+			i += 2
+		case c == '<' || c == '>':
+			tokens = append(tokens, filterToken{tokOp, string(c), i})
+			i++
+		case c == '!':
+			tokens = append(tokens, filterToken{tokNot, "!", i})
+			i++
+		case c == '"':
+			end := i + 1
+			for end < len(expr) && expr[end] != '"' {
+				if expr[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(expr) {
+				return nil, fmt.Errorf("unterminated string at offset %d", i)
+			}
+			s, err := strconv.Unquote(expr[i : end+1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid string at offset %d: %w", i, err)
+			}
+			tokens = append(tokens, filterToken{tokString, s, i})
+			i = end + 1
+		case c == '-' || (c >= '0' && c <= '9'):
+			end := i + 1
+			for end < len(expr) && expr[end] >= '0' && expr[end] <= '9' {
+				end++
+			}
+			tokens = append(tokens, filterToken{tokNumber, expr[i:end], i})
+			i = end
+		case c == '_' || unicode.IsLetter(rune(c)):
+			end := i + 1
+			for end < len(expr) && (expr[end] == '_' || unicode.IsLetter(rune(expr[end])) || unicode.IsDigit(rune(expr[end]))) {
+				end++
+			}
+			tokens = append(tokens, filterToken{tokIdent, expr[i:end], i})
+			i = end
+		default:
+			return nil, fmt.Errorf("unexpected character %q at offset %d", c, i)
+		}
+	}
+	return append(tokens, filterToken{tokEOF, "", len(expr)}), nil
+}
+
+// filterParser is a recursive descent parser over filter tokens. && binds
+// tighter than ||.
+type filterParser struct {
+	tokens []filterToken
+	pos    int
+}
+
+func (p *filterParser) peek() filterToken { return p.tokens[p.pos] }
+
+func (p *filterParser) next() filterToken {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *filterParser) parseOr() (func(Row) bool, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(r Row) bool { return l(r) || right(r) }
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (func(Row) bool, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokAnd {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(r Row) bool { return l(r) && right(r) }
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseUnary() (func(Row) bool, error) {
+	switch t := p.peek(); t.kind {
+	case tokNot:
+		p.next()
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(r Row) bool { return !inner(r) }, nil
+	case tokLParen:
+		p.next()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.next(); t.kind != tokRParen {
+			return nil, fmt.Errorf("expected \")\" at offset %d, got %s", t.pos, t)
+		}
+		return inner, nil
+	default:
+		return p.parseComparison()
+	}
+}
+
+func (p *filterParser) parseComparison() (func(Row) bool, error) {
+	t := p.next()
+	if t.kind != tokIdent {
+		return nil, fmt.Errorf("expected field at offset %d, got %s", t.pos, t)
+	}
+	field, ok := filterFields[t.text]
+	if !ok {
+		return nil, fmt.Errorf("unknown field %q at offset %d (known fields: %s)", t.text, t.pos, strings.Join(FilterFields(), ", "))
+	}
+
+	if p.peek().kind != tokOp {
+		if _, isBool := field(Row{}).(bool); isBool {
+			return func(r Row) bool { return field(r).(bool) }, nil
+		}
+		return nil, fmt.Errorf("expected comparison operator after %s at offset %d", t.text, p.peek().pos)
+	}
+	op := p.next()
+	lit := p.next()
+
+	switch field(Row{}).(type) {
+	case string:
+		if lit.kind != tokString {
+			return nil, fmt.Errorf("%s compares with a string, got %s at offset %d", t.text, lit, lit.pos)
+		}
+		want := lit.text
+		switch op.text {
+		case "==":
+			return func(r Row) bool { return field(r).(string) == want }, nil
+		case "!=":
+			return func(r Row) bool { return field(r).(string) != want }, nil
+		case "=~":
+			re, err := regexp.Compile(want)
+			if err != nil {
+				return nil, fmt.Errorf("invalid regular expression at offset %d: %w", lit.pos, err)
+			}
+			return func(r Row) bool { return re.MatchString(field(r).(string)) }, nil
+		}
+	case int:
+		if lit.kind != tokNumber {
+			return nil, fmt.Errorf("%s compares with an integer, got %s at offset %d", t.text, lit, lit.pos)
+		}
+		want, err := strconv.Atoi(lit.text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer at offset %d: %w", lit.pos, err)
+		}
+		switch op.text {
+		case "==":
+			return func(r Row) bool { return field(r).(int) == want }, nil
+		case "!=":
+			return func(r Row) bool { return field(r).(int) != want }, nil
+		case "<":
+			return func(r Row) bool { return field(r).(int) < want }, nil
+		case "<=":
+			return func(r Row) bool { return field(r).(int) <= want }, nil
+		case ">":
+			return func(r Row) bool { return field(r).(int) > want }, nil
+		case ">=":
+			return func(r Row) bool { return field(r).(int) >= want }, nil
+		}
+	case bool:
+		if lit.kind != tokIdent || (lit.text != "true" && lit.text != "false") {
+			return nil, fmt.Errorf("%s compares with true or false, got %s at offset %d", t.text, lit, lit.pos)
+		}
+		want := lit.text == "true"
+		switch op.text {
+		case "==":
+			return func(r Row) bool { return field(r).(bool) == want }, nil
+		case "!=":
+			return func(r Row) bool { return field(r).(bool) != want }, nil
+		}
+	}
+	return nil, fmt.Errorf("operator %s is not supported for %s at offset %d", op.text, t.text, op.pos)
+}
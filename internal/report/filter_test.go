@@ -0,0 +1,72 @@
+// internal/report/filter_test.go
+package report
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseFilter(t *testing.T) {
+	row := Row{
+		Application:  "web-app",
+		Organization: "payments",
+		Format:       "maven",
+		Component:    "commons-text 1.9",
+		Threat:       8,
+		Waived:       false,
+		Proprietary:  true,
+	}
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{``, true},
+		{`Threat >= 7`, true},
+		{`Threat > 8`, false},
+		{`Threat == 8 && Format == "maven"`, true},
+		{`Format == "npm" || Organization == "payments"`, true},
+		{`Format == "npm" || Organization == "payments" && Threat < 5`, false},
+		{`(Format == "npm" || Organization == "payments") && Threat != 5`, true},
+		{`!(Organization == "sandbox")`, true},
+		{`Component =~ "^commons-"`, true},
+		{`Waived`, false},
+		{`!Waived && Proprietary == true`, true},
+		{`Threat >= -1`, true},
+	}
+	for _, tt := range tests {
+		f, err := ParseFilter(tt.expr)
+		if err != nil {
+			t.Errorf("ParseFilter(%q): %v", tt.expr, err)
+			continue
+		}
+		if got := f.Match(row); got != tt.want {
+			t.Errorf("%q: Match = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestParseFilter_Errors(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr string
+	}{
+		{`Severity >= 7`, "unknown field"},
+		{`Threat >= "7"`, "compares with an integer"},
+		{`Format == maven`, "compares with a string"},
+		{`Format >= "maven"`, "not supported"},
+		{`Waived == 1`, "true or false"},
+		{`Format == "maven`, "unterminated string"},
+		{`(Threat > 1`, `expected ")"`},
+		{`Threat > 1 Format == "x"`, "unexpected"},
+		{`Component =~ "("`, "regular expression"},
+		{`Threat`, "expected comparison operator"},
+		{`Threat > 1 $`, "unexpected character"},
+	}
+	for _, tt := range tests {
+		_, err := ParseFilter(tt.expr)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("ParseFilter(%q) error = %v, want containing %q", tt.expr, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+// internal/report/format.go
+package report
+
+// Report formats, see ReportFormats. The CSV report is written in every
+// format, as history, merging and sinks read it; the other formats add the
+// same rows in another file next to it.
+const (
+	ReportFormatCSV    = "csv"    // the CSV report only
+	ReportFormatXLSX   = "xlsx"   // an Excel workbook, see WriteXLSX
+	ReportFormatJSON   = "json"   // a JSON array of rows, see WriteJSON
+	ReportFormatNDJSON = "ndjson" // one JSON row per line, see WriteNDJSON
+)
+
+// ReportFormats returns the supported report formats.
+func ReportFormats() []string {
+	return []string{ReportFormatCSV, ReportFormatXLSX, ReportFormatJSON, ReportFormatNDJSON}
+}
@@ -0,0 +1,246 @@
+// internal/report/golden_test.go
+package report
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// update rewrites the golden files instead of comparing against them:
+//
+//	go test ./internal/report -run Golden -update
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/golden")
+
+// goldenTime is the fixed time of the golden fixtures.
+var goldenTime = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// goldenRows returns the fixture rows of the golden tests. They cover empty
+// cells, cells that need quoting, waivers, tags and every threat band.
+func goldenRows() []Row {
+	return []Row{
+		{
+			Application: "web-app", Organization: "payments", Policy: "Security-Critical", Format: "maven",
+			Component: "org.apache.commons:commons-text:1.9", Threat: 10, Category: "security",
+			ViolationID: "v-001", PolicyAction: "Security-10", ConstraintName: "Critical risk CVSS score",
+			CVE: "CVE-2022-42889", Stage: "build", Hash: "0a1b2c3d4e5f60718293",
+			OpenTime: goldenTime.AddDate(0, 0, -40), Tags: map[string]string{"tier": "1", "env": "prod"},
+			OwnerName: "Jane Doe, payments-owners", OwnerEmail: "jane.doe@example.com",
+			KEVListed: true, ExploitMaturity: "high", Reachable: "true",
+			Condition: "Security Vulnerability Severity >= 9 | Security Vulnerability Status is not Not Applicable",
+		},
+		{
+			Application: "web-app", Organization: "payments", Policy: "License-Banned", Format: "npm",
+			Component: `left-pad "legacy", 1.0.0`, Threat: 7, Category: "license",
+			ViolationID: "v-002", PolicyAction: "Security-7", ConstraintName: "Banned license",
+			Condition: "License Threat Group is Banned", Stage: "build",
+			Waived: true, WaiverExpiry: "2025-06-30", WaiverCreator: "alice",
+			OpenTime: goldenTime.AddDate(0, 0, -90), Tags: map[string]string{"tier": "1", "env": "prod"},
+		},
+		{
+			Application: "batch-jobs", Organization: "platform", Policy: "Architecture-Quality", Format: "pypi",
+			Component: "setuptools 80.9.0 (.tar.gz)", Threat: 3, Category: "quality",
+			PolicyAction: "Security-3", ConstraintName: "Old component",
+			Condition: "Age >= 3 years", Stage: "operate", Proprietary: true,
+			Labels: []string{"approved-fork", "curated"}, Claimed: true,
+			OpenTime: goldenTime.AddDate(0, 0, -400),
+		},
+		{
+			Application: "batch-jobs", Organization: "platform", Policy: "Component-Unknown", Format: "a-name",
+			Component: "vendor/lib\nwith newline", Threat: 1, Category: "other",
+			PolicyAction: "Security-1", ConstraintName: "Unknown", Stage: "operate",
+		},
+	}
+}
+
+// assertGolden compares the file at path with testdata/golden/<name>, or
+// rewrites the golden file with -update.
+func assertGolden(t *testing.T, name, path string) {
+	t.Helper()
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	golden := filepath.Join("testdata", "golden", name)
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(golden), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(golden, got, 0o644); err != nil {
+			t.Fatalf("update golden file: %v", err)
+		}
+		return
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("read golden file (run with -update to create it): %v", err)
+	}
+	if bytes.Equal(got, want) {
+		return
+	}
+	gotLines, wantLines := strings.Split(string(got), "\n"), strings.Split(string(want), "\n")
+	for i := 0; i < max(len(gotLines), len(wantLines)); i++ {
+		var g, w string
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if g != w {
+			t.Fatalf("%s differs from %s at line %d (run with -update to accept):\n got: %q\nwant: %q", path, golden, i+1, g, w)
+		}
+	}
+}
+
+func TestGolden_CSV(t *testing.T) {
+	dest := filepath.Join(t.TempDir(), "report.csv")
+	opts := []CSVOption{
+		WithEmptyValue("N/A"),
+		WithColumnEmptyValues(map[string]string{"CVE": ""}),
+		WithOptionalColumns(OptionalColumns()...),
+		WithTagColumns("tier", "env"),
+	}
+	if _, err := WriteCSV(context.Background(), dest, goldenRows(), zerolog.New(io.Discard), opts...); err != nil {
+		t.Fatalf("WriteCSV: %v", err)
+	}
+	assertGolden(t, "report.csv", dest)
+}
+
+func TestGolden_JSON(t *testing.T) {
+	dir := t.TempDir()
+	dest := JSONPath(filepath.Join(dir, "report.csv"))
+	if _, err := WriteJSON(context.Background(), dest, goldenRows(), zerolog.New(io.Discard)); err != nil {
+		t.Fatalf("WriteJSON: %v", err)
+	}
+	assertGolden(t, "report.json", dest)
+
+	dest = NDJSONPath(filepath.Join(dir, "report.csv"))
+	if _, err := WriteNDJSON(context.Background(), dest, goldenRows(), zerolog.New(io.Discard)); err != nil {
+		t.Fatalf("WriteNDJSON: %v", err)
+	}
+	assertGolden(t, "report.ndjson", dest)
+}
+
+func TestGolden_CSVChunks(t *testing.T) {
+	dest := filepath.Join(t.TempDir(), "report.csv")
+	chunks, err := WriteCSVChunks(context.Background(), dest, goldenRows(), 3, zerolog.New(io.Discard))
+	if err != nil {
+		t.Fatalf("WriteCSVChunks: %v", err)
+	}
+	for _, chunk := range chunks {
+		assertGolden(t, filepath.Base(chunk), chunk)
+	}
+	assertGolden(t, filepath.Base(IndexPath(dest)), IndexPath(dest))
+}
+
+func TestGolden_Rollups(t *testing.T) {
+	dest := filepath.Join(t.TempDir(), "report.csv")
+	scans := []ApplicationScan{
+		{Application: "web-app", Organization: "payments", Stages: []string{"build"}, ScanDate: goldenTime},
+		{Application: "batch-jobs", Organization: "platform", Stages: []string{"build", "operate"}},
+		{Application: "clean-app", Organization: "platform", Stages: []string{"build"}, ScanDate: goldenTime},
+	}
+	summaries := SummarizeApplications(scans, goldenRows(), DefaultRiskWeights)
+	logger := zerolog.New(io.Discard)
+	if err := WriteApplicationsCSV(context.Background(), ApplicationsPath(dest), summaries, logger); err != nil {
+		t.Fatalf("WriteApplicationsCSV: %v", err)
+	}
+	if err := WriteOrganizationsCSV(context.Background(), OrganizationsPath(dest), SummarizeOrganizations(summaries), logger); err != nil {
+		t.Fatalf("WriteOrganizationsCSV: %v", err)
+	}
+	assertGolden(t, "report.applications.csv", ApplicationsPath(dest))
+	assertGolden(t, "report.organizations.csv", OrganizationsPath(dest))
+}
+
+func TestGolden_Vulnerabilities(t *testing.T) {
+	dest := VulnerabilitiesPath(filepath.Join(t.TempDir(), "report.csv"))
+	rows := append(goldenRows(),
+		Row{Application: "batch-jobs", Organization: "platform", Policy: "Security-High", Component: "org.apache.commons:commons-text:1.9", Threat: 8, CVE: "CVE-2022-42889, CVE-2024-0001", Stage: "operate"},
+		Row{Application: "batch-jobs", Organization: "platform", Policy: "Security-High", Component: "org.apache.commons:commons-text:1.9", Threat: 8, CVE: "CVE-2022-42889, CVE-2024-0001", Stage: "build"},
+	)
+	if err := WriteVulnerabilitiesCSV(context.Background(), dest, SummarizeVulnerabilities(rows), zerolog.New(io.Discard)); err != nil {
+		t.Fatalf("WriteVulnerabilitiesCSV: %v", err)
+	}
+	assertGolden(t, "report.vulnerabilities.csv", dest)
+}
+
+func TestGolden_SLA(t *testing.T) {
+	dest := SLAPath(filepath.Join(t.TempDir(), "report.csv"))
+	breaches := SLABreaches(goldenRows(), map[string]int{BandCritical: 7, BandSevere: 30, BandModerate: 90}, goldenTime)
+	if err := WriteSLACSV(context.Background(), dest, breaches, zerolog.New(io.Discard)); err != nil {
+		t.Fatalf("WriteSLACSV: %v", err)
+	}
+	assertGolden(t, "report.sla.csv", dest)
+}
+
+func TestGolden_History(t *testing.T) {
+	dest := HistoryPath(t.TempDir(), "web-app")
+	entries := []HistoryEntry{
+		{Application: "web-app", Organization: "payments", Stage: "build", EvaluationDate: "2025-02-28T10:00:00.000+0000", ReportID: "r2", Critical: 1, Severe: 1, AffectedComponents: 2, TotalComponents: 120},
+		{Application: "web-app", Organization: "payments", Stage: "build", EvaluationDate: "2025-01-31T10:00:00.000+0000", ReportID: "r1", Critical: 2, Moderate: 4, AffectedComponents: 5, TotalComponents: 118},
+	}
+	if err := WriteHistoryCSV(context.Background(), dest, entries, zerolog.New(io.Discard)); err != nil {
+		t.Fatalf("WriteHistoryCSV: %v", err)
+	}
+	assertGolden(t, "web-app.history.csv", dest)
+}
+
+func TestGolden_Manifest(t *testing.T) {
+	dest := ManifestPath(filepath.Join(t.TempDir(), "report.csv"))
+	m := Manifest{
+		ReportPath:   "reports_output/report.csv",
+		GeneratedAt:  goldenTime,
+		Applications: 3,
+		Processed:    2,
+		Rows:         len(goldenRows()),
+		Selection:    "first",
+		Suppressed:   1,
+		Errors:       1,
+		ErrorsByKind: map[string]int{"timeout": 1},
+		Skipped:      map[string]int{"no_reports": 1},
+		Transfer: Transfer{
+			TotalBytes:    3072,
+			ByEndpoint:    map[string]int64{"applications": 1024, "policyViolations": 2048},
+			ByApplication: map[string]int64{"web-app": 1536, "batch-jobs": 512},
+		},
+		Resources:  &ResourceUsage{PeakMemoryBytes: 48 << 20, PeakGoroutines: 23, HTTPCalls: 9, CPUSeconds: 1.25},
+		RiskScores: RiskScores(goldenRows(), DefaultRiskWeights),
+		Dispositions: []AppDisposition{
+			{Application: "batch-jobs", Organization: "platform", Disposition: DispositionExported, FetchedRows: 2, Rows: 2},
+			{Application: "clean-app", Organization: "platform", Disposition: DispositionEmpty, ScanDate: &goldenTime},
+			{Application: "web-app", Organization: "payments", Disposition: DispositionFiltered, FetchedRows: 2, ScanDate: &goldenTime},
+		},
+		Run: &RunMetadata{RunID: "20250301T120000Z-1a2b3c4d", TriggeredBy: "release-bot", Reason: "quarterly audit"},
+	}
+	if err := WriteManifest(context.Background(), dest, m, zerolog.New(io.Discard)); err != nil {
+		t.Fatalf("WriteManifest: %v", err)
+	}
+	assertGolden(t, "report.manifest.json", dest)
+}
+
+func TestGolden_Lifecycle(t *testing.T) {
+	dest := filepath.Join(t.TempDir(), "lifecycle.csv")
+	rows := []LifecycleRow{
+		{Period: "2025-01", Organization: "payments", Application: "web-app", Opened: 3, Open: 5},
+		{Period: "2025-02", Organization: "payments", Application: "web-app", Opened: 1, Closed: 4, Open: 2, Remediated: 3, RemediationDays: 50},
+		{Period: "2025-02", Organization: "platform, shared", Application: "batch-jobs", Closed: 1, Remediated: 1, RemediationDays: 12.25},
+	}
+	logger := zerolog.New(io.Discard)
+	if err := WriteLifecycleCSV(context.Background(), dest, rows, logger); err != nil {
+		t.Fatalf("WriteLifecycleCSV: %v", err)
+	}
+	if err := WriteLifecycleSummaryCSV(context.Background(), LifecycleSummaryPath(dest), SummarizeLifecycle(rows), logger); err != nil {
+		t.Fatalf("WriteLifecycleSummaryCSV: %v", err)
+	}
+	assertGolden(t, "lifecycle.csv", dest)
+	assertGolden(t, "lifecycle.summary.csv", LifecycleSummaryPath(dest))
+}
@@ -0,0 +1,79 @@
+// internal/report/history.go
+package report
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// HistoryDir is the subdirectory of the output directory that holds the
+// per-application scan timelines.
+const HistoryDir = "history"
+
+// HistoryEntry is one evaluation in the scan timeline of an application.
+type HistoryEntry struct {
+	Application        string
+	Organization       string
+	Stage              string
+	EvaluationDate     string // as reported by IQ Server
+	ReportID           string
+	Critical           int // policy violation counts per threat band
+	Severe             int
+	Moderate           int
+	AffectedComponents int
+	TotalComponents    int
+}
+
+// HistoryPath returns the timeline location of an application:
+// outputDir/history/<application>.csv.
+func HistoryPath(outputDir, application string) string {
+	clean := strings.NewReplacer("/", "-", `\`, "-", ":", "-").Replace
+	return filepath.Join(outputDir, HistoryDir, clean(application)+".csv")
+}
+
+// WriteHistoryCSV writes the scan timeline of an application to destPath,
+// atomically, in the given order.
+func WriteHistoryCSV(ctx context.Context, destPath string, entries []HistoryEntry, logger zerolog.Logger) error {
+	_, err := writeFileAtomic(ctx, destPath, logger, func(f io.Writer) error {
+		return WriteHistoryCSVTo(f, entries)
+	})
+	return err
+}
+
+// WriteHistoryCSVTo writes the scan timeline of an application as CSV to f.
+func WriteHistoryCSVTo(f io.Writer, entries []HistoryEntry) error {
+	w := csv.NewWriter(f)
+	header := []string{"Application", "Organization", "Stage", "Evaluation Date", "Report ID", "Critical", "Severe", "Moderate", "Affected Components", "Total Components"}
+	if err := w.Write(header); err != nil {
+		return fmt.Errorf("write header: %w", err)
+	}
+	for i, e := range entries {
+		record := []string{
+			e.Application,
+			e.Organization,
+			e.Stage,
+			e.EvaluationDate,
+			e.ReportID,
+			strconv.Itoa(e.Critical),
+			strconv.Itoa(e.Severe),
+			strconv.Itoa(e.Moderate),
+			strconv.Itoa(e.AffectedComponents),
+			strconv.Itoa(e.TotalComponents),
+		}
+		if err := w.Write(record); err != nil {
+			return fmt.Errorf("write row %d: %w", i+1, err)
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("flush csv: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+// internal/report/history_test.go
+package report
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestWriteHistoryCSV(t *testing.T) {
+	dest := HistoryPath(t.TempDir(), "my/app")
+	if filepath.Base(dest) != "my-app.csv" || filepath.Base(filepath.Dir(dest)) != HistoryDir {
+		t.Errorf("HistoryPath = %q", dest)
+	}
+
+	entries := []HistoryEntry{
+		{Application: "my/app", Organization: "Org", Stage: "build", EvaluationDate: "2025-01-15T10:00:00.000+0000", ReportID: "rpt-2", Critical: 2, Severe: 1, Moderate: 4, AffectedComponents: 3, TotalComponents: 40},
+		{Application: "my/app", Organization: "Org", Stage: "build", EvaluationDate: "2025-01-01T10:00:00.000+0000", ReportID: "rpt-1"},
+	}
+	if err := WriteHistoryCSV(context.Background(), dest, entries, zerolog.New(io.Discard)); err != nil {
+		t.Fatalf("WriteHistoryCSV: %v", err)
+	}
+	b, err := os.ReadFile(dest)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	want := "Application,Organization,Stage,Evaluation Date,Report ID,Critical,Severe,Moderate,Affected Components,Total Components\n" +
+		"my/app,Org,build,2025-01-15T10:00:00.000+0000,rpt-2,2,1,4,3,40\n" +
+		"my/app,Org,build,2025-01-01T10:00:00.000+0000,rpt-1,0,0,0,0,0\n"
+	if got := string(b); got != want {
+		t.Errorf("csv =\n%s\nwant\n%s", got, want)
+	}
+}
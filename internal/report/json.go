@@ -0,0 +1,200 @@
+// internal/report/json.go
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// JSONRow is a report row as written by WriteJSON and WriteNDJSON. Empty
+// fields are left out, except for the threat level and waiver flag. The
+// threat level is always a number; ThreatLabel holds it as formatted by a
+// threat format other than ThreatFormatNumber.
+type JSONRow struct {
+	RowID           string            `json:"rowId"`
+	Application     string            `json:"application"`
+	Organization    string            `json:"organization"`
+	Stage           string            `json:"stage,omitempty"`
+	Policy          string            `json:"policy"`
+	PolicyAction    string            `json:"policyAction,omitempty"`
+	Category        string            `json:"category,omitempty"`
+	Threat          int               `json:"threat"`
+	ThreatLabel     string            `json:"threatLabel,omitempty"`
+	Format          string            `json:"format,omitempty"`
+	Component       string            `json:"component"`
+	Hash            string            `json:"hash,omitempty"`
+	Proprietary     bool              `json:"proprietary,omitempty"`
+	Claimed         bool              `json:"claimed,omitempty"`
+	Labels          []string          `json:"labels,omitempty"`
+	ConstraintName  string            `json:"constraintName,omitempty"`
+	Condition       string            `json:"condition,omitempty"`
+	CVEs            []string          `json:"cves,omitempty"`
+	KEVListed       bool              `json:"kevListed,omitempty"`
+	ExploitMaturity string            `json:"exploitMaturity,omitempty"`
+	Reachable       string            `json:"reachable,omitempty"`
+	Waived          bool              `json:"waived"`
+	WaiverExpiry    string            `json:"waiverExpiry,omitempty"`
+	WaiverCreator   string            `json:"waiverCreator,omitempty"`
+	OpenTime        *time.Time        `json:"openTime,omitempty"`
+	OwnerName       string            `json:"ownerName,omitempty"`
+	OwnerEmail      string            `json:"ownerEmail,omitempty"`
+	Tags            map[string]string `json:"tags,omitempty"`
+}
+
+// NewJSONRow returns r as written to JSON.
+func NewJSONRow(r Row) JSONRow {
+	j := JSONRow{
+		RowID:           r.RowID(),
+		Application:     r.Application,
+		Organization:    r.Organization,
+		Stage:           r.Stage,
+		Policy:          r.Policy,
+		PolicyAction:    r.PolicyAction,
+		Category:        r.Category,
+		Threat:          r.Threat,
+		Format:          r.Format,
+		Component:       r.Component,
+		Hash:            r.Hash,
+		Proprietary:     r.Proprietary,
+		Claimed:         r.Claimed,
+		Labels:          r.Labels,
+		ConstraintName:  r.ConstraintName,
+		Condition:       r.Condition,
+		CVEs:            SplitCVEs(r.CVE),
+		KEVListed:       r.KEVListed,
+		ExploitMaturity: r.ExploitMaturity,
+		Reachable:       r.Reachable,
+		Waived:          r.Waived,
+		WaiverExpiry:    r.WaiverExpiry,
+		WaiverCreator:   r.WaiverCreator,
+		OwnerName:       r.OwnerName,
+		OwnerEmail:      r.OwnerEmail,
+		Tags:            r.Tags,
+	}
+	if !r.OpenTime.IsZero() {
+		t := r.OpenTime.UTC()
+		j.OpenTime = &t
+	}
+	return j
+}
+
+// JSONPath returns the JSON report location for the report at reportPath:
+// the report path with its extension replaced by ".json".
+func JSONPath(reportPath string) string {
+	return strings.TrimSuffix(reportPath, filepath.Ext(reportPath)) + ".json"
+}
+
+// NDJSONPath returns the NDJSON report location for the report at
+// reportPath: the report path with its extension replaced by ".ndjson".
+func NDJSONPath(reportPath string) string {
+	return strings.TrimSuffix(reportPath, filepath.Ext(reportPath)) + ".ndjson"
+}
+
+// WriteJSON writes rows as a JSON array of JSONRow to destPath, atomically.
+// Of opts, only the threat format applies. It returns the path written, like
+// WriteCSV.
+func WriteJSON(ctx context.Context, destPath string, rows []Row, logger zerolog.Logger, opts ...CSVOption) (string, error) {
+	layout, err := newCSVLayout(opts)
+	if err != nil {
+		return "", err
+	}
+	return writeFileAtomic(ctx, destPath, logger, func(f io.Writer) error {
+		return writeJSON(f, rows, layout)
+	})
+}
+
+// WriteJSONTo writes rows as a JSON array of JSONRow to f, one row per
+// line. Rows are encoded one at a time, so large reports are not held in
+// memory twice.
+func WriteJSONTo(f io.Writer, rows []Row, opts ...CSVOption) error {
+	layout, err := newCSVLayout(opts)
+	if err != nil {
+		return err
+	}
+	return writeJSON(f, rows, layout)
+}
+
+func writeJSON(f io.Writer, rows []Row, layout *csvLayout) error {
+	if _, err := io.WriteString(f, "["); err != nil {
+		return fmt.Errorf("write json: %w", err)
+	}
+	var buf bytes.Buffer
+	enc := newJSONRowEncoder(&buf)
+	for i, r := range rows {
+		buf.Reset()
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteByte('\n')
+		if err := enc.Encode(layout.jsonRow(r)); err != nil {
+			return fmt.Errorf("encode row %d: %w", i+1, err)
+		}
+		buf.Truncate(buf.Len() - 1) // Encode's newline
+		if _, err := f.Write(buf.Bytes()); err != nil {
+			return fmt.Errorf("write row %d: %w", i+1, err)
+		}
+	}
+	if _, err := io.WriteString(f, "\n]\n"); err != nil {
+		return fmt.Errorf("write json: %w", err)
+	}
+	return nil
+}
+
+// WriteNDJSON writes rows as newline-delimited JSON, one JSONRow per line,
+// to destPath, atomically. Of opts, only the threat format applies. It
+// returns the path written, like WriteCSV.
+func WriteNDJSON(ctx context.Context, destPath string, rows []Row, logger zerolog.Logger, opts ...CSVOption) (string, error) {
+	layout, err := newCSVLayout(opts)
+	if err != nil {
+		return "", err
+	}
+	return writeFileAtomic(ctx, destPath, logger, func(f io.Writer) error {
+		return writeNDJSON(f, rows, layout)
+	})
+}
+
+// WriteNDJSONTo writes rows as newline-delimited JSON, one JSONRow per
+// line, to f.
+func WriteNDJSONTo(f io.Writer, rows []Row, opts ...CSVOption) error {
+	layout, err := newCSVLayout(opts)
+	if err != nil {
+		return err
+	}
+	return writeNDJSON(f, rows, layout)
+}
+
+func writeNDJSON(f io.Writer, rows []Row, layout *csvLayout) error {
+	enc := newJSONRowEncoder(f)
+	for i, r := range rows {
+		if err := enc.Encode(layout.jsonRow(r)); err != nil {
+			return fmt.Errorf("write row %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// jsonRow returns r as written to JSON with the threat format of l.
+func (l *csvLayout) jsonRow(r Row) JSONRow {
+	j := NewJSONRow(r)
+	if l.threatFormat != "" && l.threatFormat != ThreatFormatNumber {
+		j.ThreatLabel = FormatThreat(r.Threat, l.threatFormat)
+	}
+	return j
+}
+
+// newJSONRowEncoder returns an encoder of rows to w. Rows are for data
+// pipelines rather than HTML, so conditions such as "Severity >= 7" are
+// written as is.
+func newJSONRowEncoder(w io.Writer) *json.Encoder {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return enc
+}
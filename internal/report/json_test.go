@@ -0,0 +1,35 @@
+// internal/report/json_test.go
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteJSONTo_ThreatFormat(t *testing.T) {
+	rows := []Row{{Application: "web-app", Threat: 7}}
+	for format, want := range map[string]string{
+		"":                  `"threat":7,"component"`,
+		ThreatFormatNumber:  `"threat":7,"component"`,
+		ThreatFormatBand:    `"threat":7,"threatLabel":"Severe"`,
+		ThreatFormatLabeled: `"threat":7,"threatLabel":"Severe (7)"`,
+	} {
+		var j, nd bytes.Buffer
+		if err := WriteJSONTo(&j, rows, WithThreatFormat(format)); err != nil {
+			t.Fatalf("WriteJSONTo(%q): %v", format, err)
+		}
+		if err := WriteNDJSONTo(&nd, rows, WithThreatFormat(format)); err != nil {
+			t.Fatalf("WriteNDJSONTo(%q): %v", format, err)
+		}
+		for name, out := range map[string]string{"json": j.String(), "ndjson": nd.String()} {
+			if !strings.Contains(out, want) {
+				t.Errorf("%s with threat format %q lacks %s: %s", name, format, want, out)
+			}
+		}
+	}
+
+	if err := WriteJSONTo(new(bytes.Buffer), rows, WithThreatFormat("stars")); err == nil {
+		t.Error("WriteJSONTo accepted an unknown threat format")
+	}
+}
@@ -0,0 +1,68 @@
+// internal/report/layout.go
+package report
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// LayoutVars are the values of the placeholders of an output layout.
+type LayoutVars struct {
+	Organization string // {{org}}
+	Application  string // {{app}}
+	Date         string // {{date}}, the run date as YYYY-MM-DD
+	Report       string // {{report}}, the report file name without extension
+}
+
+var layoutPlaceholder = regexp.MustCompile(`\{\{\s*([a-z]+)\s*\}\}`)
+
+// ValidateLayout checks that layout is a relative path using only known
+// placeholders, e.g. "{{org}}/{{app}}/{{date}}/policy.csv".
+func ValidateLayout(layout string) error {
+	if strings.TrimSpace(layout) == "" {
+		return fmt.Errorf("output layout is empty")
+	}
+	for _, m := range layoutPlaceholder.FindAllStringSubmatch(layout, -1) {
+		switch m[1] {
+		case "org", "app", "date", "report":
+		default:
+			return fmt.Errorf("output layout %q: unknown placeholder %s", layout, m[0])
+		}
+	}
+	rest := layoutPlaceholder.ReplaceAllString(layout, "x")
+	if strings.Contains(rest, "{{") || strings.Contains(rest, "}}") {
+		return fmt.Errorf("output layout %q: malformed placeholder", layout)
+	}
+	if filepath.IsAbs(rest) || !filepath.IsLocal(filepath.FromSlash(rest)) {
+		return fmt.Errorf("output layout %q must be a relative path inside the output directory", layout)
+	}
+	return nil
+}
+
+// LayoutPath expands layout, validated by ValidateLayout, with vars below
+// outputDir. Path separators in the values are replaced so that every value
+// stays a single path element.
+func LayoutPath(outputDir, layout string, vars LayoutVars) string {
+	clean := strings.NewReplacer("/", "-", `\`, "-", ":", "-").Replace
+	rel := layoutPlaceholder.ReplaceAllStringFunc(layout, func(ph string) string {
+		var v string
+		switch layoutPlaceholder.FindStringSubmatch(ph)[1] {
+		case "org":
+			v = vars.Organization
+		case "app":
+			v = vars.Application
+		case "date":
+			v = vars.Date
+		case "report":
+			v = vars.Report
+		}
+		v = clean(v)
+		if v == "" || v == "." || v == ".." {
+			v = "_"
+		}
+		return v
+	})
+	return filepath.Join(outputDir, filepath.FromSlash(rel))
+}
@@ -0,0 +1,31 @@
+// internal/report/layout_test.go
+package report
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestValidateLayout(t *testing.T) {
+	for _, layout := range []string{"{{org}}/{{app}}/{{date}}/policy.csv", "{{report}}-{{app}}.csv", "apps/{{ app }}.csv"} {
+		if err := ValidateLayout(layout); err != nil {
+			t.Errorf("ValidateLayout(%q) = %v", layout, err)
+		}
+	}
+	for _, layout := range []string{"", "{{team}}/x.csv", "/abs/{{app}}.csv", "../{{app}}.csv", "{{app}.csv"} {
+		if err := ValidateLayout(layout); err == nil {
+			t.Errorf("ValidateLayout(%q) accepted", layout)
+		}
+	}
+}
+
+func TestLayoutPath(t *testing.T) {
+	vars := LayoutVars{Organization: "Team/A", Application: "my-app", Date: "2025-01-15", Report: "2025-01-15_10-00-00"}
+	got := LayoutPath("out", "{{org}}/{{app}}/{{date}}/policy.csv", vars)
+	if want := filepath.Join("out", "Team-A", "my-app", "2025-01-15", "policy.csv"); got != want {
+		t.Errorf("LayoutPath = %q, want %q", got, want)
+	}
+	if got := LayoutPath("out", "{{org}}/{{app}}.csv", LayoutVars{Application: ".."}); got != filepath.Join("out", "_", "_.csv") {
+		t.Errorf("LayoutPath with empty and dot values = %q", got)
+	}
+}
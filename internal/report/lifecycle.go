@@ -0,0 +1,305 @@
+// internal/report/lifecycle.go
+package report
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Snapshot holds the open violations seen by one run, read back from its
+// report by ReadSnapshots.
+type Snapshot struct {
+	Time time.Time // the run's as-of time, or when it was generated
+	// Violations maps application and Row ID to the application's
+	// organization. Waived violations are left out.
+	Violations map[string]string
+	// Failed lists applications that could not be fetched: their
+	// violations are neither open nor closed by this run.
+	Failed map[string]bool
+}
+
+// violationKey identifies a violation across runs.
+func violationKey(app, rowID string) string {
+	return app + "\x1f" + rowID
+}
+
+// ReadSnapshots reads the runs stored in dir, oldest first: every report
+// with a manifest, except shards, whose violations are covered by their
+// merged report. Runs whose report was removed or lacks the Application or
+// Organization column are skipped with a warning. Reports without the Row
+// ID column identify violations by the hash RowID falls back to.
+func ReadSnapshots(dir string, logger zerolog.Logger) ([]Snapshot, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.manifest.json"))
+	if err != nil {
+		return nil, fmt.Errorf("list manifests: %w", err)
+	}
+	var snaps []Snapshot
+	for _, p := range paths {
+		m, err := ReadManifest(p)
+		if err != nil {
+			logger.Warn().Err(err).Msg("Skipping unreadable manifest")
+			continue
+		}
+		if m.Shard != nil {
+			continue
+		}
+		snap, err := readSnapshot(filepath.Join(dir, filepath.Base(m.ReportPath)), m)
+		if errors.Is(err, os.ErrNotExist) {
+			logger.Warn().Str("manifest", p).Msg("Report of manifest not found; run skipped")
+			continue
+		}
+		if err != nil {
+			logger.Warn().Err(err).Str("manifest", p).Msg("Run skipped")
+			continue
+		}
+		snaps = append(snaps, snap)
+	}
+	sort.SliceStable(snaps, func(i, j int) bool { return snaps[i].Time.Before(snaps[j].Time) })
+	return snaps, nil
+}
+
+// readSnapshot reads the open violations of the report at path, written by
+// the run of m.
+func readSnapshot(path string, m *Manifest) (Snapshot, error) {
+	snap := Snapshot{Time: m.GeneratedAt, Violations: make(map[string]string), Failed: make(map[string]bool)}
+	if m.AsOf != nil {
+		snap.Time = *m.AsOf
+	}
+	for _, d := range m.Dispositions {
+		if d.Disposition == DispositionFailed {
+			snap.Failed[d.Application] = true
+		}
+	}
+	files, err := reportFiles(path)
+	if err != nil {
+		return snap, err
+	}
+	for _, file := range files {
+		header, rows, _, err := readReportCSV(file)
+		if err != nil {
+			return snap, err
+		}
+		cols := make(map[string]int)
+		for _, name := range []string{"Application", "Organization"} {
+			i := slices.Index(header, name)
+			if i < 0 {
+				return snap, fmt.Errorf("%s: no %q column", file, name)
+			}
+			cols[name] = i
+		}
+		rowID, err := rowIDReader(header)
+		if err != nil {
+			return snap, fmt.Errorf("%s: %w", file, err)
+		}
+		waived := slices.Index(header, ColumnWaived)
+		for _, rec := range rows {
+			if waived >= 0 && rec[waived] == "true" {
+				continue
+			}
+			snap.Violations[violationKey(rec[cols["Application"]], rowID(rec))] = rec[cols["Organization"]]
+		}
+	}
+	return snap, nil
+}
+
+// LifecycleRow is one row of the lifecycle report: the violations of an
+// application opened and closed in a period and still open at its end.
+// Rows of the summary sheet leave Organization and Application empty.
+type LifecycleRow struct {
+	Period       string // month, e.g. "2025-03"
+	Organization string
+	Application  string
+	Opened       int
+	Closed       int
+	Open         int // open at the last run of the period
+	// Remediated counts the closed violations whose opening was seen, and
+	// RemediationDays sums the days they were open.
+	Remediated      int
+	RemediationDays float64
+}
+
+// MeanDaysToRemediate returns the mean days closed violations were open,
+// or 0 when none was remediated.
+func (r LifecycleRow) MeanDaysToRemediate() float64 {
+	if r.Remediated == 0 {
+		return 0
+	}
+	return r.RemediationDays / float64(r.Remediated)
+}
+
+// lifecyclePeriod returns the month of t.
+func lifecyclePeriod(t time.Time) string {
+	return t.UTC().Format("2006-01")
+}
+
+// Lifecycle follows violations across snapshots, ordered oldest first, and
+// counts per month and application the violations opened, closed and still
+// open. A violation opens at the first run that reports it and closes at
+// the first later run that does not, unless its application failed in that
+// run; it may open again later. Violations of the first snapshot were open
+// before it: they are not counted as opened, and their time to remediate
+// is unknown. The result is sorted by period, organization and application.
+func Lifecycle(snaps []Snapshot) []LifecycleRow {
+	type openViolation struct {
+		app, org string
+		since    time.Time
+		baseline bool
+	}
+	open := make(map[string]*openViolation)
+	rows := make(map[[2]string]*LifecycleRow) // period, application -> row
+	row := func(period, app, org string) *LifecycleRow {
+		r, ok := rows[[2]string{period, app}]
+		if !ok {
+			r = &LifecycleRow{Period: period, Application: app}
+			rows[[2]string{period, app}] = r
+		}
+		r.Organization = org
+		return r
+	}
+
+	for i, snap := range snaps {
+		period := lifecyclePeriod(snap.Time)
+		for key, org := range snap.Violations {
+			if v, ok := open[key]; ok {
+				v.org = org
+				continue
+			}
+			app, _, _ := strings.Cut(key, "\x1f")
+			open[key] = &openViolation{app: app, org: org, since: snap.Time, baseline: i == 0}
+			if i > 0 {
+				row(period, app, org).Opened++
+			}
+		}
+		for key, v := range open {
+			if _, ok := snap.Violations[key]; ok || snap.Failed[v.app] {
+				continue
+			}
+			r := row(period, v.app, v.org)
+			r.Closed++
+			if !v.baseline {
+				r.Remediated++
+				r.RemediationDays += snap.Time.Sub(v.since).Hours() / 24
+			}
+			delete(open, key)
+		}
+		// Open counts as of this run; a later run of the period overwrites them
+		for _, r := range rows {
+			if r.Period == period {
+				r.Open = 0
+			}
+		}
+		for _, v := range open {
+			row(period, v.app, v.org).Open++
+		}
+	}
+
+	out := make([]LifecycleRow, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, *r)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Period != out[j].Period {
+			return out[i].Period < out[j].Period
+		}
+		if out[i].Organization != out[j].Organization {
+			return out[i].Organization < out[j].Organization
+		}
+		return out[i].Application < out[j].Application
+	})
+	return out
+}
+
+// SummarizeLifecycle totals lifecycle rows per period for the summary
+// sheet, sorted by period.
+func SummarizeLifecycle(rows []LifecycleRow) []LifecycleRow {
+	var out []LifecycleRow
+	for _, r := range rows {
+		if len(out) == 0 || out[len(out)-1].Period != r.Period {
+			out = append(out, LifecycleRow{Period: r.Period})
+		}
+		sum := &out[len(out)-1]
+		sum.Opened += r.Opened
+		sum.Closed += r.Closed
+		sum.Open += r.Open
+		sum.Remediated += r.Remediated
+		sum.RemediationDays += r.RemediationDays
+	}
+	return out
+}
+
+// LifecycleSummaryPath returns the summary sheet location for the lifecycle
+// report at reportPath: the path with its extension replaced by
+// ".summary.csv".
+func LifecycleSummaryPath(reportPath string) string {
+	return strings.TrimSuffix(reportPath, filepath.Ext(reportPath)) + ".summary.csv"
+}
+
+// WriteLifecycleCSV writes the lifecycle report to destPath, atomically.
+func WriteLifecycleCSV(ctx context.Context, destPath string, rows []LifecycleRow, logger zerolog.Logger) error {
+	_, err := writeFileAtomic(ctx, destPath, logger, func(f io.Writer) error {
+		return WriteLifecycleCSVTo(f, rows)
+	})
+	return err
+}
+
+// WriteLifecycleCSVTo writes the lifecycle report as CSV to f.
+func WriteLifecycleCSVTo(f io.Writer, rows []LifecycleRow) error {
+	header := []string{"Period", "Organization", "Application", "Opened", "Closed", "Open", "Mean Days To Remediate"}
+	return writeLifecycle(f, header, rows, func(r LifecycleRow) []string {
+		return []string{r.Period, r.Organization, r.Application}
+	})
+}
+
+// WriteLifecycleSummaryCSV writes the summary sheet of the lifecycle report,
+// rows of SummarizeLifecycle, to destPath, atomically.
+func WriteLifecycleSummaryCSV(ctx context.Context, destPath string, rows []LifecycleRow, logger zerolog.Logger) error {
+	_, err := writeFileAtomic(ctx, destPath, logger, func(f io.Writer) error {
+		return WriteLifecycleSummaryCSVTo(f, rows)
+	})
+	return err
+}
+
+// WriteLifecycleSummaryCSVTo writes the summary sheet of the lifecycle
+// report as CSV to f.
+func WriteLifecycleSummaryCSVTo(f io.Writer, rows []LifecycleRow) error {
+	header := []string{"Period", "Opened", "Closed", "Open", "Mean Days To Remediate"}
+	return writeLifecycle(f, header, rows, func(r LifecycleRow) []string {
+		return []string{r.Period}
+	})
+}
+
+// writeLifecycle writes lifecycle rows as CSV to f, each starting with the
+// cells of key followed by the counts.
+func writeLifecycle(f io.Writer, header []string, rows []LifecycleRow, key func(LifecycleRow) []string) error {
+	w := csv.NewWriter(f)
+	if err := w.Write(header); err != nil {
+		return fmt.Errorf("write header: %w", err)
+	}
+	for i, r := range rows {
+		mttr := ""
+		if r.Remediated > 0 {
+			mttr = strconv.FormatFloat(r.MeanDaysToRemediate(), 'f', 1, 64)
+		}
+		record := append(key(r), strconv.Itoa(r.Opened), strconv.Itoa(r.Closed), strconv.Itoa(r.Open), mttr)
+		if err := w.Write(record); err != nil {
+			return fmt.Errorf("write row %d: %w", i+1, err)
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("flush csv: %w", err)
+	}
+	return nil
+}
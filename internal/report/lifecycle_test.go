@@ -0,0 +1,99 @@
+// internal/report/lifecycle_test.go
+package report
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestLifecycle(t *testing.T) {
+	day := func(m time.Month, d int) time.Time { return time.Date(2025, m, d, 6, 0, 0, 0, time.UTC) }
+	snap := func(at time.Time, failed []string, keys ...string) Snapshot {
+		s := Snapshot{Time: at, Violations: make(map[string]string), Failed: make(map[string]bool)}
+		for _, k := range keys {
+			s.Violations[k] = "org"
+		}
+		for _, app := range failed {
+			s.Failed[app] = true
+		}
+		return s
+	}
+	a1, a2, a3, b1 := violationKey("a", "1"), violationKey("a", "2"), violationKey("a", "3"), violationKey("b", "1")
+	snaps := []Snapshot{
+		snap(day(1, 5), nil, a1, b1),   // baseline
+		snap(day(1, 20), nil, a1, a2),  // b1 closed (baseline), a2 opened
+		snap(day(2, 4), []string{"a"}), // a failed: its violations carry over
+		snap(day(2, 14), nil, a1, a3),  // a2 closed after 25 days, a3 opened
+		snap(day(2, 24), nil, a3, b1),  // a1 closed (baseline), b1 opened again
+		snap(day(3, 6), nil, a3, b1),   // nothing changes
+		snap(day(3, 16), nil, b1),      // a3 closed after 30 days
+	}
+
+	got := Lifecycle(snaps)
+	want := []LifecycleRow{
+		{Period: "2025-01", Organization: "org", Application: "a", Opened: 1, Open: 2},
+		{Period: "2025-01", Organization: "org", Application: "b", Closed: 1},
+		{Period: "2025-02", Organization: "org", Application: "a", Opened: 1, Closed: 2, Open: 1, Remediated: 1, RemediationDays: 25},
+		{Period: "2025-02", Organization: "org", Application: "b", Opened: 1, Open: 1},
+		{Period: "2025-03", Organization: "org", Application: "a", Closed: 1, Remediated: 1, RemediationDays: 30},
+		{Period: "2025-03", Organization: "org", Application: "b", Open: 1},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d rows, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("row %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	sum := SummarizeLifecycle(got)
+	if len(sum) != 3 || sum[1].Opened != 2 || sum[1].Closed != 2 || sum[1].Open != 2 || sum[1].MeanDaysToRemediate() != 25 {
+		t.Errorf("summary = %+v", sum)
+	}
+}
+
+func TestReadSnapshots(t *testing.T) {
+	dir := t.TempDir()
+	logger := zerolog.New(io.Discard)
+	write := func(name string, at time.Time, m Manifest, rows ...Row) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if _, err := WriteCSV(context.Background(), path, rows, logger, WithOptionalColumns(ColumnWaived, ColumnRowID)); err != nil {
+			t.Fatal(err)
+		}
+		m.ReportPath, m.GeneratedAt = "elsewhere/"+name, at
+		if err := WriteManifest(context.Background(), ManifestPath(path), m, logger); err != nil {
+			t.Fatal(err)
+		}
+	}
+	jan, feb := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	write("b.csv", feb, Manifest{Dispositions: []AppDisposition{{Application: "down", Disposition: DispositionFailed}}},
+		Row{Application: "app", Organization: "org", ViolationID: "v1", Threat: 9},
+		Row{Application: "app", Organization: "org", ViolationID: "v2", Threat: 9, Waived: true},
+	)
+	write("a.csv", jan, Manifest{AsOf: &feb}, Row{Application: "app", Organization: "org", ViolationID: "v3", Threat: 9})
+	write("shard.csv", jan, Manifest{Shard: &Shard{Index: 0, Total: 2}})
+
+	snaps, err := ReadSnapshots(dir, logger)
+	if err != nil {
+		t.Fatalf("ReadSnapshots: %v", err)
+	}
+	if len(snaps) != 2 {
+		t.Fatalf("got %d snapshots, want 2", len(snaps))
+	}
+	if !snaps[0].Time.Equal(feb) || !snaps[1].Time.Equal(feb) {
+		t.Errorf("times = %s, %s; want the as-of time and the generation time", snaps[0].Time, snaps[1].Time)
+	}
+	if len(snaps[1].Violations) != 1 || snaps[1].Violations[violationKey("app", "v1")] != "org" {
+		t.Errorf("violations = %v, want v1 only", snaps[1].Violations)
+	}
+	if !snaps[1].Failed["down"] {
+		t.Errorf("failed = %v", snaps[1].Failed)
+	}
+}
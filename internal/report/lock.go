@@ -0,0 +1,172 @@
+// internal/report/lock.go
+package report
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// LockFile is the name of the lock file created in the output directory.
+const LockFile = ".iqfetch.lock"
+
+// ErrLocked reports that another run holds the lock of the output directory.
+var ErrLocked = errors.New("output directory locked by another run")
+
+// lockPollInterval is how often a waiting AcquireLock retries.
+const lockPollInterval = 500 * time.Millisecond
+
+// lockRefreshInterval is how often a held lock file's modification time is
+// refreshed, at most; see AcquireLock.
+const lockRefreshInterval = time.Minute
+
+// Lock is an advisory lock on an output directory, held by at most one run
+// at a time. It only protects against other runs using AcquireLock.
+type Lock struct {
+	path   string
+	file   os.FileInfo // the lock file created, told apart from a successor's
+	logger zerolog.Logger
+	stop   chan struct{}
+	done   chan struct{}
+	once   sync.Once
+}
+
+// AcquireLock locks the output directory dir by creating its LockFile. When
+// another run holds the lock, it retries for up to wait (zero aborts at
+// once) and then returns an error wrapping ErrLocked. A lock file last
+// modified more than stale ago is left over by a crashed run and is taken
+// over; zero never takes over a lock. While held, the lock file's
+// modification time is refreshed, every minute or a third of stale if
+// shorter, so that long runs are not taken for crashed ones.
+func AcquireLock(ctx context.Context, dir string, wait, stale time.Duration, logger zerolog.Logger) (*Lock, error) {
+	path := filepath.Join(dir, LockFile)
+	deadline := time.Now().Add(wait)
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			_, werr := fmt.Fprintf(f, "pid=%d\nstarted=%s\n", os.Getpid(), time.Now().UTC().Format(time.RFC3339))
+			if cerr := f.Close(); werr == nil {
+				werr = cerr
+			}
+			if werr != nil {
+				_ = os.Remove(path)
+				return nil, fmt.Errorf("write lock file: %w", werr)
+			}
+			file, err := os.Stat(path)
+			if err != nil {
+				return nil, fmt.Errorf("inspect lock file: %w", err)
+			}
+			logger.Debug().Str("path", path).Msg("lock acquired")
+			l := &Lock{path: path, file: file, logger: logger, stop: make(chan struct{}), done: make(chan struct{})}
+			interval := lockRefreshInterval
+			if stale/3 > 0 {
+				interval = min(interval, stale/3)
+			}
+			go l.refresh(interval)
+			return l, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, fmt.Errorf("create lock file: %w", err)
+		}
+
+		info, err := os.Stat(path)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			continue // released meanwhile
+		case err != nil:
+			return nil, fmt.Errorf("inspect lock file: %w", err)
+		case stale > 0 && time.Since(info.ModTime()) > stale:
+			logger.Warn().Str("path", path).Time("modified", info.ModTime()).Msg("Taking over stale lock")
+			if err := removeStaleLock(path, info); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			holder, _ := os.ReadFile(path)
+			return nil, fmt.Errorf("%w: %s (%s)", ErrLocked, path, strings.ReplaceAll(strings.TrimSpace(string(holder)), "\n", ", "))
+		}
+		logger.Debug().Str("path", path).Dur("remaining", remaining).Msg("waiting for lock")
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(min(lockPollInterval, remaining)):
+		}
+	}
+}
+
+// removeStaleLock removes the stale lock file at path, seen as stale. It is
+// renamed to a name of its own first, so that of several runs taking over
+// the same stale lock, only one removes it: the others either fail to rename
+// it, or find they renamed the lock file a faster run has created since and
+// put it back.
+func removeStaleLock(path string, stale os.FileInfo) error {
+	aside := fmt.Sprintf("%s.stale-%d-%d", path, os.Getpid(), time.Now().UnixNano())
+	if err := os.Rename(path, aside); errors.Is(err, fs.ErrNotExist) {
+		return nil // taken over or released meanwhile
+	} else if err != nil {
+		return fmt.Errorf("remove stale lock file: %w", err)
+	}
+	if info, err := os.Stat(aside); err == nil && !os.SameFile(info, stale) {
+		// Another run's fresh lock file; the link fails if a third run
+		// created one meanwhile, which then holds the lock
+		_ = os.Link(aside, path)
+	}
+	if err := os.Remove(aside); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("remove stale lock file: %w", err)
+	}
+	return nil
+}
+
+// refresh touches the lock file every interval until the lock is released,
+// or until another run took it over.
+func (l *Lock) refresh(interval time.Duration) {
+	defer close(l.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+		}
+		if !l.held() {
+			l.logger.Warn().Str("path", l.path).Msg("Lock taken over by another run")
+			return
+		}
+		now := time.Now()
+		if err := os.Chtimes(l.path, now, now); err != nil {
+			l.logger.Warn().Err(err).Str("path", l.path).Msg("Could not refresh lock")
+		}
+	}
+}
+
+// held reports whether the lock file is still the one created by l.
+func (l *Lock) held() bool {
+	info, err := os.Stat(l.path)
+	return err == nil && os.SameFile(info, l.file)
+}
+
+// Release stops refreshing the lock and removes the lock file, unless
+// another run has taken it over.
+func (l *Lock) Release() error {
+	l.once.Do(func() { close(l.stop) })
+	<-l.done
+	if !l.held() {
+		return nil
+	}
+	if err := os.Remove(l.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("remove lock file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+// internal/report/lock_test.go
+package report
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestAcquireLock(t *testing.T) {
+	dir := t.TempDir()
+	logger := zerolog.New(io.Discard)
+
+	lock, err := AcquireLock(context.Background(), dir, 0, 0, logger)
+	if err != nil {
+		t.Fatalf("AcquireLock error = %v", err)
+	}
+	if _, err := AcquireLock(context.Background(), dir, 0, 0, logger); !errors.Is(err, ErrLocked) {
+		t.Fatalf("second AcquireLock error = %v, want ErrLocked", err)
+	}
+
+	// A waiting run gets the lock once it is released
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		_ = lock.Release()
+	}()
+	second, err := AcquireLock(context.Background(), dir, 5*time.Second, 0, logger)
+	if err != nil {
+		t.Fatalf("waiting AcquireLock error = %v", err)
+	}
+
+	// A stale lock is taken over
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(filepath.Join(dir, LockFile), old, old); err != nil {
+		t.Fatal(err)
+	}
+	third, err := AcquireLock(context.Background(), dir, 0, time.Hour, logger)
+	if err != nil {
+		t.Fatalf("AcquireLock over stale lock error = %v", err)
+	}
+	if err := third.Release(); err != nil {
+		t.Fatalf("Release error = %v", err)
+	}
+	if err := second.Release(); err != nil {
+		t.Errorf("Release of taken over lock error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, LockFile)); !os.IsNotExist(err) {
+		t.Error("lock file not removed")
+	}
+}
+
+func TestAcquireLock_RefreshedWhileHeld(t *testing.T) {
+	dir := t.TempDir()
+	logger := zerolog.New(io.Discard)
+
+	const stale = 300 * time.Millisecond
+	lock, err := AcquireLock(context.Background(), dir, 0, stale, logger)
+	if err != nil {
+		t.Fatalf("AcquireLock error = %v", err)
+	}
+	defer lock.Release()
+
+	// Held for longer than stale, the lock is still not taken over
+	time.Sleep(3 * stale)
+	if _, err := AcquireLock(context.Background(), dir, 0, stale, logger); !errors.Is(err, ErrLocked) {
+		t.Fatalf("AcquireLock of a long-held lock error = %v, want ErrLocked", err)
+	}
+}
+
+func TestAcquireLock_ConcurrentTakeover(t *testing.T) {
+	dir := t.TempDir()
+	logger := zerolog.New(io.Discard)
+
+	path := filepath.Join(dir, LockFile)
+	if err := os.WriteFile(path, []byte("pid=1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	// Of several runs finding the same stale lock, one takes it over
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var locks []*Lock
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			lock, err := AcquireLock(context.Background(), dir, 0, time.Hour, logger)
+			if err != nil {
+				if !errors.Is(err, ErrLocked) {
+					t.Errorf("AcquireLock error = %v", err)
+				}
+				return
+			}
+			mu.Lock()
+			locks = append(locks, lock)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	if len(locks) != 1 {
+		t.Fatalf("%d runs hold the lock, want 1", len(locks))
+	}
+	if !locks[0].held() {
+		t.Error("lock file is not the one of the holder")
+	}
+	if err := locks[0].Release(); err != nil {
+		t.Fatalf("Release error = %v", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("files left behind: %v", entries)
+	}
+}
@@ -0,0 +1,167 @@
+// internal/report/manifest.go
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Manifest describes a single report run. It is written as JSON next to the
+// report so that consumers can see what the run covered without parsing logs.
+type Manifest struct {
+	ReportPath string   `json:"reportPath"`       // the chunk index when the report is chunked
+	Chunks     []string `json:"chunks,omitempty"` // chunk files of a chunked report, in order
+	Export     string   `json:"export,omitempty"` // the report in a format other than CSV, see ReportFormats
+	// Companions are the other files and directories written with the
+	// report, such as rollups and owner reports, removed with it by
+	// RotateReports.
+	Companions   []string       `json:"companions,omitempty"`
+	GeneratedAt  time.Time      `json:"generatedAt"`
+	Applications int            `json:"applications"`
+	Processed    int            `json:"processed"` // applications fetched without error or skip
+	Rows         int            `json:"rows"`
+	Selection    string         `json:"reportSelection"`      // policy used to choose among candidate reports
+	AsOf         *time.Time     `json:"asOf,omitempty"`       // reports were the latest at this time instead of now
+	Suppressed   int            `json:"suppressed,omitempty"` // rows excluded by the suppression file
+	Errors       int            `json:"errors"`
+	ErrorsByKind map[string]int `json:"errorsByKind,omitempty"`
+	Skipped      map[string]int `json:"skipped,omitempty"` // skip reason -> application count
+	Transfer     Transfer       `json:"transfer"`
+	// Resources records the memory, goroutines, HTTP calls and CPU time the
+	// run used.
+	Resources *ResourceUsage `json:"resources,omitempty"`
+	// RiskScores ranks applications by weighted violation count, highest first.
+	RiskScores []ApplicationRisk `json:"riskScores,omitempty"`
+	// CountMismatches lists reports whose parsed violations disagree with the
+	// IQ Server summary counts (VALIDATE_COUNTS).
+	CountMismatches []CountMismatch `json:"countMismatches,omitempty"`
+	// Dispositions records per application why it has rows in the report or
+	// not, sorted by application.
+	Dispositions []AppDisposition `json:"applicationDispositions,omitempty"`
+	// UnlistedOrganizations are organizations whose applications could not be
+	// listed (APP_LIST_BY_ORG), so their applications are missing.
+	UnlistedOrganizations []string `json:"unlistedOrganizations,omitempty"`
+	// OrphanedOrganizations are organizations referenced by applications but
+	// missing from the organization list, sorted by ID.
+	OrphanedOrganizations []OrphanedOrganization `json:"orphanedOrganizations,omitempty"`
+	// Run identifies the run and who triggered it, when known.
+	Run *RunMetadata `json:"run,omitempty"`
+	// Shard is the part of the applications covered by a sharded run.
+	Shard *Shard `json:"shard,omitempty"`
+	// MergedFrom lists the reports combined into a merged report.
+	MergedFrom []string `json:"mergedFrom,omitempty"`
+}
+
+// RunMetadata identifies a run; the same values are sent to IQ Server as
+// request headers.
+type RunMetadata struct {
+	RunID       string `json:"runId,omitempty"`
+	TriggeredBy string `json:"triggeredBy,omitempty"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+// OrphanedOrganization is an organization referenced by applications but
+// missing from the organization list, e.g. because it was deleted. Name is
+// set when the organization could still be looked up by ID; otherwise the
+// applications report the ID as their organization.
+type OrphanedOrganization struct {
+	ID           string   `json:"id"`
+	Name         string   `json:"name,omitempty"`
+	Applications []string `json:"applications"` // public IDs, sorted
+}
+
+// ResourceUsage records the resources used by a run, for capacity planning.
+// Peaks are sampled while the run is in progress.
+type ResourceUsage struct {
+	PeakMemoryBytes uint64  `json:"peakMemoryBytes"` // memory held by the Go runtime
+	PeakGoroutines  int     `json:"peakGoroutines"`
+	HTTPCalls       int     `json:"httpCalls"`
+	CPUSeconds      float64 `json:"cpuSeconds"` // user and system time of the process
+}
+
+// Transfer records the bytes downloaded from IQ Server during a run.
+type Transfer struct {
+	TotalBytes    int64            `json:"totalBytes"`
+	ByEndpoint    map[string]int64 `json:"byEndpoint"`
+	ByApplication map[string]int64 `json:"byApplication"`
+}
+
+// ManifestPath returns the manifest location for the report at reportPath:
+// the report path with its extension replaced by ".manifest.json".
+func ManifestPath(reportPath string) string {
+	return strings.TrimSuffix(reportPath, filepath.Ext(reportPath)) + ".manifest.json"
+}
+
+// WriteManifest writes m as indented JSON to destPath, atomically.
+func WriteManifest(ctx context.Context, destPath string, m Manifest, logger zerolog.Logger) error {
+	_, err := writeFileAtomic(ctx, destPath, logger, func(w io.Writer) error {
+		return WriteManifestTo(w, m)
+	})
+	return err
+}
+
+// WriteManifestTo writes m as indented JSON to w.
+func WriteManifestTo(w io.Writer, m Manifest) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(m); err != nil {
+		return fmt.Errorf("encode manifest: %w", err)
+	}
+	return nil
+}
+
+// ReadManifest reads a manifest written by WriteManifest.
+func ReadManifest(path string) (*Manifest, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read manifest: %w", err)
+	}
+	var m Manifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("decode manifest %s: %w", path, err)
+	}
+	return &m, nil
+}
+
+// LatestManifest returns the most recently generated manifest in dir, or nil
+// when dir contains none. Unreadable manifests are ignored.
+func LatestManifest(dir string) (*Manifest, error) {
+	return latestManifest(dir, func(*Manifest) bool { return true })
+}
+
+// LatestShardManifest is like LatestManifest, but only considers manifests
+// of the given shard; a nil shard selects the manifests of unsharded runs.
+func LatestShardManifest(dir string, shard *Shard) (*Manifest, error) {
+	return latestManifest(dir, func(m *Manifest) bool {
+		if m.Shard == nil || shard == nil {
+			return m.Shard == shard
+		}
+		return *m.Shard == *shard
+	})
+}
+
+func latestManifest(dir string, match func(*Manifest) bool) (*Manifest, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.manifest.json"))
+	if err != nil {
+		return nil, fmt.Errorf("list manifests: %w", err)
+	}
+	var latest *Manifest
+	for _, p := range paths {
+		m, err := ReadManifest(p)
+		if err != nil || !match(m) {
+			continue
+		}
+		if latest == nil || m.GeneratedAt.After(latest.GeneratedAt) {
+			latest = m
+		}
+	}
+	return latest, nil
+}
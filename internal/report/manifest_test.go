@@ -0,0 +1,103 @@
+// internal/report/manifest_test.go
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestManifestPath(t *testing.T) {
+	if got := ManifestPath(filepath.Join("out", "2024-01-01.csv")); got != filepath.Join("out", "2024-01-01.manifest.json") {
+		t.Errorf("ManifestPath = %q", got)
+	}
+}
+
+func TestWriteManifest_RoundTrip(t *testing.T) {
+	dest := filepath.Join(t.TempDir(), "run.manifest.json")
+	m := Manifest{
+		ReportPath:   "run.csv",
+		GeneratedAt:  time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC),
+		Applications: 2,
+		Rows:         5,
+		Transfer: Transfer{
+			TotalBytes:    300,
+			ByEndpoint:    map[string]int64{"applications": 100, "applications/{publicId}/reports/{reportId}/policy": 200},
+			ByApplication: map[string]int64{"app-1": 200},
+		},
+	}
+
+	if err := WriteManifest(context.Background(), dest, m, zerolog.New(io.Discard)); err != nil {
+		t.Fatalf("WriteManifest error = %v", err)
+	}
+
+	b, err := os.ReadFile(dest)
+	if err != nil {
+		t.Fatalf("read manifest: %v", err)
+	}
+	var got Manifest
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("decode manifest: %v", err)
+	}
+	if got.Rows != 5 || got.Transfer.TotalBytes != 300 || got.Transfer.ByApplication["app-1"] != 200 {
+		t.Errorf("unexpected manifest: %#v", got)
+	}
+}
+
+func TestLatestManifest_PicksNewest(t *testing.T) {
+	dir := t.TempDir()
+	logger := zerolog.New(io.Discard)
+	older := Manifest{ReportPath: "a.csv", GeneratedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Rows: 1}
+	newer := Manifest{ReportPath: "b.csv", GeneratedAt: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), Rows: 2}
+	if err := WriteManifest(context.Background(), filepath.Join(dir, "b.manifest.json"), newer, logger); err != nil {
+		t.Fatalf("WriteManifest: %v", err)
+	}
+	if err := WriteManifest(context.Background(), filepath.Join(dir, "a.manifest.json"), older, logger); err != nil {
+		t.Fatalf("WriteManifest: %v", err)
+	}
+
+	got, err := LatestManifest(dir)
+	if err != nil {
+		t.Fatalf("LatestManifest: %v", err)
+	}
+	if got == nil || got.ReportPath != "b.csv" {
+		t.Errorf("LatestManifest = %#v", got)
+	}
+
+	none, err := LatestManifest(t.TempDir())
+	if err != nil || none != nil {
+		t.Errorf("expected no manifest, got %#v, %v", none, err)
+	}
+}
+
+func TestLatestShardManifest(t *testing.T) {
+	dir := t.TempDir()
+	logger := zerolog.New(io.Discard)
+	shard := Shard{Index: 1, Total: 2}
+	manifests := map[string]Manifest{
+		"a.manifest.json":              {ReportPath: "a.csv", GeneratedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		"b.shard-1-of-2.manifest.json": {ReportPath: "b.csv", GeneratedAt: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), Shard: &shard},
+		"c.shard-0-of-2.manifest.json": {ReportPath: "c.csv", GeneratedAt: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), Shard: &Shard{Index: 0, Total: 2}},
+	}
+	for name, m := range manifests {
+		if err := WriteManifest(context.Background(), filepath.Join(dir, name), m, logger); err != nil {
+			t.Fatalf("WriteManifest: %v", err)
+		}
+	}
+
+	if got, err := LatestShardManifest(dir, &Shard{Index: 1, Total: 2}); err != nil || got == nil || got.ReportPath != "b.csv" {
+		t.Errorf("LatestShardManifest(1-of-2) = %#v, %v", got, err)
+	}
+	if got, err := LatestShardManifest(dir, nil); err != nil || got == nil || got.ReportPath != "a.csv" {
+		t.Errorf("LatestShardManifest(nil) = %#v, %v", got, err)
+	}
+	if got := ShardPath("out/report.csv", shard); got != "out/report.shard-1-of-2.csv" {
+		t.Errorf("ShardPath = %q", got)
+	}
+}
@@ -0,0 +1,301 @@
+// internal/report/merge.go
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// MergeStats summarizes a merge of partial reports.
+type MergeStats struct {
+	Inputs     int
+	Rows       int // rows of the merged report
+	Duplicates int // rows dropped because a later input had them too
+	Manifests  int // inputs whose manifest was found and merged
+}
+
+// MergeReports combines the CSV reports at inputs, e.g. the outputs of the
+// shards of a run or of a run and its resumption, into one report at
+// destPath with its manifest. Inputs are reports written by WriteCSV or the
+// chunk indexes written by WriteCSVChunks, and must share their columns.
+//
+// Rows are identified by Row ID, stage and CVE. When a row appears in
+// several inputs, the cells of the last input win while the row keeps the
+// position of its first occurrence; rows are then renumbered from 1 when
+// the reports have the "No." column. The manifests of the inputs, where
+// present, are merged with MergeManifests.
+func MergeReports(ctx context.Context, destPath string, inputs []string, logger zerolog.Logger) (MergeStats, error) {
+	stats := MergeStats{Inputs: len(inputs)}
+	if len(inputs) == 0 {
+		return stats, fmt.Errorf("no reports to merge")
+	}
+
+	var header []string
+	var bom bool
+	var records [][]string
+	byKey := make(map[string]int) // row key -> index in records
+	var manifests []*Manifest
+	for _, input := range inputs {
+		files, err := reportFiles(input)
+		if err != nil {
+			return stats, err
+		}
+		for _, file := range files {
+			h, rows, hasBOM, err := readReportCSV(file)
+			if err != nil {
+				return stats, err
+			}
+			if header == nil {
+				header, bom = h, hasBOM
+			} else if !slices.Equal(header, h) {
+				return stats, fmt.Errorf("%s: columns differ from %s", file, inputs[0])
+			}
+			key, err := rowKey(header)
+			if err != nil {
+				return stats, fmt.Errorf("%s: %w", file, err)
+			}
+			for _, rec := range rows {
+				k := key(rec)
+				if i, ok := byKey[k]; ok {
+					records[i] = rec
+					stats.Duplicates++
+					continue
+				}
+				byKey[k] = len(records)
+				records = append(records, rec)
+			}
+		}
+
+		m, err := ReadManifest(inputManifestPath(input))
+		if errors.Is(err, os.ErrNotExist) {
+			logger.Warn().Str("report", input).Msg("No manifest for report; merged manifest will not cover it")
+			continue
+		}
+		if err != nil {
+			return stats, err
+		}
+		manifests = append(manifests, m)
+	}
+	if header[0] == "No." {
+		for i, rec := range records {
+			rec[0] = strconv.Itoa(i + 1)
+		}
+	}
+	stats.Rows, stats.Manifests = len(records), len(manifests)
+
+	destPath, err := writeFileAtomic(ctx, destPath, logger, func(f io.Writer) error {
+		if bom {
+			if _, err := io.WriteString(f, utf8BOM); err != nil {
+				return fmt.Errorf("write byte order mark: %w", err)
+			}
+		}
+		w := csv.NewWriter(f)
+		if err := w.Write(header); err != nil {
+			return fmt.Errorf("write header: %w", err)
+		}
+		if err := w.WriteAll(records); err != nil {
+			return fmt.Errorf("write rows: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return stats, err
+	}
+
+	m := MergeManifests(manifests)
+	m.ReportPath, m.Rows, m.MergedFrom = destPath, len(records), inputs
+	if err := WriteManifest(ctx, ManifestPath(destPath), m, logger); err != nil {
+		return stats, err
+	}
+	logger.Debug().Int("rows", stats.Rows).Int("duplicates", stats.Duplicates).Msg("reports merged")
+	return stats, nil
+}
+
+// MergeManifests combines the manifests of partial runs: counts, error and
+// skip tallies, transfer totals, HTTP calls and CPU time are summed, the
+// highest resource peaks and the latest generation time are kept and risk
+// scores are ranked again. Applications processed by more
+// than one run are counted once per run, and take their risk score and
+// disposition from the last. The report path and rows are left to the
+// caller.
+func MergeManifests(ms []*Manifest) Manifest {
+	var out Manifest
+	risks := make(map[string]float64)
+	dispositions := make(map[string]AppDisposition)
+	unlisted := make(map[string]bool)
+	orphaned := make(map[string]*OrphanedOrganization)
+	for i, m := range ms {
+		if m.GeneratedAt.After(out.GeneratedAt) {
+			out.GeneratedAt = m.GeneratedAt
+		}
+		switch {
+		case i == 0:
+			out.Selection = m.Selection
+		case out.Selection != m.Selection:
+			out.Selection = "mixed"
+		}
+		if i == 0 {
+			out.AsOf = m.AsOf
+		} else if out.AsOf != nil && (m.AsOf == nil || !m.AsOf.Equal(*out.AsOf)) {
+			out.AsOf = nil // runs as of different times
+		}
+		out.Applications += m.Applications
+		out.Processed += m.Processed
+		out.Suppressed += m.Suppressed
+		out.Errors += m.Errors
+		out.ErrorsByKind = addCounts(out.ErrorsByKind, m.ErrorsByKind)
+		out.Skipped = addCounts(out.Skipped, m.Skipped)
+		out.Transfer.TotalBytes += m.Transfer.TotalBytes
+		if r := m.Resources; r != nil {
+			if out.Resources == nil {
+				out.Resources = &ResourceUsage{}
+			}
+			out.Resources.PeakMemoryBytes = max(out.Resources.PeakMemoryBytes, r.PeakMemoryBytes)
+			out.Resources.PeakGoroutines = max(out.Resources.PeakGoroutines, r.PeakGoroutines)
+			out.Resources.HTTPCalls += r.HTTPCalls
+			out.Resources.CPUSeconds += r.CPUSeconds
+		}
+		out.Transfer.ByEndpoint = addCounts(out.Transfer.ByEndpoint, m.Transfer.ByEndpoint)
+		out.Transfer.ByApplication = addCounts(out.Transfer.ByApplication, m.Transfer.ByApplication)
+		for _, r := range m.RiskScores {
+			risks[r.Application] = r.Score // a later run supersedes an earlier one
+		}
+		out.CountMismatches = append(out.CountMismatches, m.CountMismatches...)
+		for _, d := range m.Dispositions {
+			dispositions[d.Application] = d
+		}
+		for _, org := range m.UnlistedOrganizations {
+			unlisted[org] = true
+		}
+		for _, org := range m.OrphanedOrganizations {
+			o, ok := orphaned[org.ID]
+			if !ok {
+				o = &OrphanedOrganization{ID: org.ID}
+				orphaned[org.ID] = o
+			}
+			if org.Name != "" {
+				o.Name = org.Name
+			}
+			o.Applications = append(o.Applications, org.Applications...)
+		}
+	}
+	for app, score := range risks {
+		out.RiskScores = append(out.RiskScores, ApplicationRisk{Application: app, Score: score})
+	}
+	sort.Slice(out.RiskScores, func(i, j int) bool {
+		if out.RiskScores[i].Score != out.RiskScores[j].Score {
+			return out.RiskScores[i].Score > out.RiskScores[j].Score
+		}
+		return out.RiskScores[i].Application < out.RiskScores[j].Application
+	})
+	for _, d := range dispositions {
+		out.Dispositions = append(out.Dispositions, d)
+	}
+	sort.Slice(out.Dispositions, func(i, j int) bool { return out.Dispositions[i].Application < out.Dispositions[j].Application })
+	for org := range unlisted {
+		out.UnlistedOrganizations = append(out.UnlistedOrganizations, org)
+	}
+	slices.Sort(out.UnlistedOrganizations)
+	for _, o := range orphaned {
+		slices.Sort(o.Applications)
+		o.Applications = slices.Compact(o.Applications)
+		out.OrphanedOrganizations = append(out.OrphanedOrganizations, *o)
+	}
+	sort.Slice(out.OrphanedOrganizations, func(i, j int) bool { return out.OrphanedOrganizations[i].ID < out.OrphanedOrganizations[j].ID })
+	return out
+}
+
+// addCounts adds the counts of src to dst, allocating dst when needed.
+func addCounts[V int | int64](dst, src map[string]V) map[string]V {
+	if len(src) > 0 && dst == nil {
+		dst = make(map[string]V, len(src))
+	}
+	for k, v := range src {
+		dst[k] += v
+	}
+	return dst
+}
+
+// reportFiles returns the CSV files holding the rows of the report at path:
+// the chunks listed by a chunk index, or the report itself.
+func reportFiles(path string) ([]string, error) {
+	if !strings.HasSuffix(path, ".index.csv") {
+		return []string{path}, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("read chunk index: %w", err)
+	}
+	defer f.Close()
+	index, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("read chunk index %s: %w", path, err)
+	}
+	var files []string
+	for _, rec := range index[min(1, len(index)):] {
+		files = append(files, filepath.Join(filepath.Dir(path), rec[0]))
+	}
+	return files, nil
+}
+
+// inputManifestPath returns the manifest location of a report or chunk
+// index.
+func inputManifestPath(path string) string {
+	if base, ok := strings.CutSuffix(path, ".index.csv"); ok {
+		return base + ".manifest.json"
+	}
+	return ManifestPath(path)
+}
+
+// readReportCSV reads the header and rows of a CSV report, and whether it
+// starts with a UTF-8 byte order mark (see WithUTF8BOM).
+func readReportCSV(path string) (header []string, rows [][]string, bom bool, err error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("read report: %w", err)
+	}
+	b, bom = bytes.CutPrefix(b, []byte(utf8BOM))
+	records, err := csv.NewReader(bytes.NewReader(b)).ReadAll()
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("read report %s: %w", path, err)
+	}
+	if len(records) == 0 {
+		return nil, nil, false, fmt.Errorf("read report %s: no header", path)
+	}
+	return records[0], records[1:], bom, nil
+}
+
+// rowKey returns the identity of a report row by the given header: its
+// Row ID, stage and CVE, so that rows split per CVE stay distinct. Reports
+// without the optional Stage column are keyed by an empty stage, and those
+// without the Row ID column by the hash RowID would write.
+func rowKey(header []string) (func([]string) string, error) {
+	rowID, err := rowIDReader(header)
+	if err != nil {
+		return nil, err
+	}
+	cve := slices.Index(header, "CVE")
+	if cve < 0 {
+		return nil, fmt.Errorf("no %q column", "CVE")
+	}
+	stage := slices.Index(header, ColumnStage)
+	return func(rec []string) string {
+		key := rowID(rec) + "\x1f"
+		if stage >= 0 {
+			key += rec[stage]
+		}
+		return key + "\x1f" + rec[cve]
+	}, nil
+}
@@ -0,0 +1,99 @@
+// internal/report/merge_test.go
+package report
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestMergeReports(t *testing.T) {
+	dir := t.TempDir()
+	logger := zerolog.New(io.Discard)
+	rows := goldenRows()
+
+	// Two shards overlapping in one row, whose action changed in the second
+	first, second := filepath.Join(dir, "a.csv"), filepath.Join(dir, "b.csv")
+	if _, err := WriteCSV(context.Background(), first, rows[:2], logger); err != nil {
+		t.Fatal(err)
+	}
+	updated := rows[1]
+	updated.PolicyAction = "Block"
+	if _, err := WriteCSVChunks(context.Background(), second, []Row{updated, rows[2], rows[3]}, 2, logger); err != nil {
+		t.Fatal(err)
+	}
+	for path, m := range map[string]Manifest{
+		first:  {GeneratedAt: goldenTime, Applications: 1, Processed: 1, Selection: "first", Errors: 1, ErrorsByKind: map[string]int{"timeout": 1}, RiskScores: []ApplicationRisk{{Application: "web-app", Score: 2}}},
+		second: {GeneratedAt: goldenTime.Add(time.Hour), Applications: 2, Processed: 2, Selection: "first", Skipped: map[string]int{"no_reports": 1}, RiskScores: []ApplicationRisk{{Application: "batch-jobs", Score: 5}}},
+	} {
+		if err := WriteManifest(context.Background(), ManifestPath(path), m, logger); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dest := filepath.Join(dir, "merged.csv")
+	stats, err := MergeReports(context.Background(), dest, []string{first, IndexPath(second)}, logger)
+	if err != nil {
+		t.Fatalf("MergeReports: %v", err)
+	}
+	if stats.Rows != 4 || stats.Duplicates != 1 || stats.Manifests != 2 {
+		t.Errorf("stats = %+v", stats)
+	}
+
+	f, err := os.Open(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 5 {
+		t.Fatalf("merged report has %d records, want header and 4 rows", len(records))
+	}
+	for i, rec := range records[1:] {
+		if want := []string{"1", "2", "3", "4"}[i]; rec[0] != want {
+			t.Errorf("row %d numbered %q", i, rec[0])
+		}
+	}
+	if action := records[2][slices.Index(records[0], "Policy/Action")]; action != "Block" {
+		t.Errorf("duplicate row action = %q, want the later input's", action)
+	}
+
+	m, err := ReadManifest(ManifestPath(dest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Applications != 3 || m.Processed != 3 || m.Rows != 4 || m.Errors != 1 || m.Skipped["no_reports"] != 1 {
+		t.Errorf("merged manifest = %+v", m)
+	}
+	if !m.GeneratedAt.Equal(goldenTime.Add(time.Hour)) || m.Selection != "first" || len(m.MergedFrom) != 2 {
+		t.Errorf("merged manifest = %+v", m)
+	}
+	if len(m.RiskScores) != 2 || m.RiskScores[0].Application != "batch-jobs" {
+		t.Errorf("merged risk scores = %v", m.RiskScores)
+	}
+}
+
+func TestMergeReports_ColumnMismatch(t *testing.T) {
+	dir := t.TempDir()
+	logger := zerolog.New(io.Discard)
+	first, second := filepath.Join(dir, "a.csv"), filepath.Join(dir, "b.csv")
+	if _, err := WriteCSV(context.Background(), first, goldenRows(), logger); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := WriteCSV(context.Background(), second, goldenRows(), logger, WithOptionalColumns("Hash")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := MergeReports(context.Background(), filepath.Join(dir, "merged.csv"), []string{first, second}, logger); err == nil {
+		t.Error("expected reports with different columns to be rejected")
+	}
+}
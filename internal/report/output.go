@@ -0,0 +1,37 @@
+// internal/report/output.go
+package report
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+
+	"github.com/rs/zerolog"
+)
+
+// OutputFS is a writable file system for writers producing several files,
+// e.g. an archive or an object store. Names are slash-separated and
+// relative to its root.
+type OutputFS interface {
+	// WriteFile creates or replaces the file name with what write writes.
+	// When write fails, the file must not be left behind under name.
+	WriteFile(name string, write func(w io.Writer) error) error
+}
+
+// DirFS returns the OutputFS of the files below dir, written atomically
+// like the files of the path-based writers; once ctx is done, files are no
+// longer written.
+func DirFS(ctx context.Context, dir string, logger zerolog.Logger) OutputFS {
+	return dirFS{ctx: ctx, dir: dir, logger: logger}
+}
+
+type dirFS struct {
+	ctx    context.Context
+	dir    string
+	logger zerolog.Logger
+}
+
+func (d dirFS) WriteFile(name string, write func(w io.Writer) error) error {
+	_, err := writeFileAtomic(d.ctx, filepath.Join(d.dir, filepath.FromSlash(name)), d.logger, write)
+	return err
+}
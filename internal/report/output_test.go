@@ -0,0 +1,76 @@
+// internal/report/output_test.go
+package report
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+// zipFS is an OutputFS writing into a zip archive.
+type zipFS struct{ zw *zip.Writer }
+
+func (z zipFS) WriteFile(name string, write func(w io.Writer) error) error {
+	w, err := z.zw.Create(name)
+	if err != nil {
+		return err
+	}
+	return write(w)
+}
+
+func TestWriteCSVTo_MatchesWriteCSV(t *testing.T) {
+	dest := filepath.Join(t.TempDir(), "report.csv")
+	opts := []CSVOption{WithEmptyValue("N/A"), WithOptionalColumns(OptionalColumns()...)}
+	if _, err := WriteCSV(context.Background(), dest, goldenRows(), zerolog.New(io.Discard), opts...); err != nil {
+		t.Fatalf("WriteCSV: %v", err)
+	}
+	want, err := os.ReadFile(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCSVTo(&buf, goldenRows(), opts...); err != nil {
+		t.Fatalf("WriteCSVTo: %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("WriteCSVTo wrote\n%s\nwant\n%s", buf.Bytes(), want)
+	}
+
+	if err := WriteCSVTo(io.Discard, goldenRows(), WithOptionalColumns("Nope")); err == nil {
+		t.Error("WriteCSVTo accepted an unknown optional column")
+	}
+}
+
+func TestWriteCSVChunksFS_Zip(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	names, err := WriteCSVChunksFS(zipFS{zw}, "out/report.csv", goldenRows(), 3)
+	if err != nil {
+		t.Fatalf("WriteCSVChunksFS: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 2 || names[0] != "out/report-001.csv" || names[1] != "out/report-002.csv" {
+		t.Errorf("chunk names = %v", names)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var files []string
+	for _, f := range zr.File {
+		files = append(files, f.Name)
+	}
+	if len(files) != 3 || files[2] != "out/report.index.csv" {
+		t.Errorf("archive holds %v, want two chunks and the index", files)
+	}
+}
@@ -0,0 +1,118 @@
+// internal/report/owners.go
+package report
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// OwnerReport holds the rows of the applications one owner is responsible
+// for.
+type OwnerReport struct {
+	Email        string // lower-cased
+	Rows         []Row
+	Applications int
+}
+
+// SplitByOwner groups rows by the addresses in their OwnerEmail, so that a
+// row of an application with several owners goes to each of them. Reports
+// are sorted by email. unowned counts the rows without any owner address.
+func SplitByOwner(rows []Row) (reports []OwnerReport, unowned int) {
+	byOwner := make(map[string][]Row)
+	for _, r := range rows {
+		emails := ownerEmails(r.OwnerEmail)
+		if len(emails) == 0 {
+			unowned++
+			continue
+		}
+		for _, e := range emails {
+			byOwner[e] = append(byOwner[e], r)
+		}
+	}
+	for email, ownerRows := range byOwner {
+		apps := make(map[string]bool)
+		for _, r := range ownerRows {
+			apps[r.Application] = true
+		}
+		reports = append(reports, OwnerReport{Email: email, Rows: ownerRows, Applications: len(apps)})
+	}
+	sort.Slice(reports, func(i, j int) bool { return reports[i].Email < reports[j].Email })
+	return reports, unowned
+}
+
+// ownerEmails splits an OwnerEmail cell into distinct, lower-cased
+// addresses.
+func ownerEmails(cell string) []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, e := range strings.Split(cell, ",") {
+		e = strings.ToLower(strings.TrimSpace(e))
+		if e == "" || seen[e] {
+			continue
+		}
+		seen[e] = true
+		out = append(out, e)
+	}
+	return out
+}
+
+// OwnersPath returns the owner index location for the report at
+// reportPath: the report path with its extension replaced by ".owners.csv".
+func OwnersPath(reportPath string) string {
+	return strings.TrimSuffix(reportPath, filepath.Ext(reportPath)) + ".owners.csv"
+}
+
+// OwnerReportPath returns the location of the personal report of the owner
+// with the given email: a file named after the email in the "<report>.owners"
+// directory next to the report.
+func OwnerReportPath(reportPath, email string) string {
+	dir := strings.TrimSuffix(reportPath, filepath.Ext(reportPath)) + ".owners"
+	name := strings.NewReplacer("/", "-", `\`, "-", ":", "-").Replace(email)
+	if name == "" || name == "." || name == ".." {
+		name = "_"
+	}
+	return filepath.Join(dir, name+".csv")
+}
+
+// WriteOwnersCSV writes the owner index to destPath, atomically: one line
+// per owner report with the owner's email, counts and the path of the
+// personal report, for mailers or uploaders to pick up. The header is
+// written even when there are no owners.
+func WriteOwnersCSV(ctx context.Context, destPath string, reports []OwnerReport, reportPath string, logger zerolog.Logger) error {
+	_, err := writeFileAtomic(ctx, destPath, logger, func(f io.Writer) error {
+		return WriteOwnersCSVTo(f, reports, reportPath)
+	})
+	return err
+}
+
+// WriteOwnersCSVTo writes the owner index as CSV to f.
+func WriteOwnersCSVTo(f io.Writer, reports []OwnerReport, reportPath string) error {
+	w := csv.NewWriter(f)
+	if err := w.Write([]string{"Owner Email", "Applications", "Rows", "File"}); err != nil {
+		return fmt.Errorf("write header: %w", err)
+	}
+	for i, o := range reports {
+		record := []string{
+			o.Email,
+			strconv.Itoa(o.Applications),
+			strconv.Itoa(len(o.Rows)),
+			OwnerReportPath(reportPath, o.Email),
+		}
+		if err := w.Write(record); err != nil {
+			return fmt.Errorf("write row %d: %w", i+1, err)
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("flush csv: %w", err)
+	}
+	return nil
+}
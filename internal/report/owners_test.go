@@ -0,0 +1,70 @@
+// internal/report/owners_test.go
+package report
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestSplitByOwner(t *testing.T) {
+	rows := []Row{
+		{Application: "a", Component: "lib1", OwnerEmail: "jane@example.com, Bob@example.com"},
+		{Application: "a", Component: "lib2", OwnerEmail: "jane@example.com, bob@example.com"},
+		{Application: "b", Component: "lib3", OwnerEmail: "JANE@example.com"},
+		{Application: "c", Component: "lib4"},
+	}
+
+	owners, unowned := SplitByOwner(rows)
+	if unowned != 1 {
+		t.Errorf("unowned = %d, want 1", unowned)
+	}
+	if len(owners) != 2 {
+		t.Fatalf("got %d owners, want 2: %+v", len(owners), owners)
+	}
+	if owners[0].Email != "bob@example.com" || len(owners[0].Rows) != 2 || owners[0].Applications != 1 {
+		t.Errorf("first owner = %+v", owners[0])
+	}
+	if owners[1].Email != "jane@example.com" || len(owners[1].Rows) != 3 || owners[1].Applications != 2 {
+		t.Errorf("second owner = %+v", owners[1])
+	}
+}
+
+func TestWriteOwnersCSV(t *testing.T) {
+	reportPath := filepath.Join(t.TempDir(), "report.csv")
+	dest := OwnersPath(reportPath)
+	if filepath.Base(dest) != "report.owners.csv" {
+		t.Errorf("OwnersPath = %q", dest)
+	}
+	if got := OwnerReportPath(reportPath, "a/b@example.com"); filepath.Base(got) != "a-b@example.com.csv" || filepath.Base(filepath.Dir(got)) != "report.owners" {
+		t.Errorf("OwnerReportPath = %q", got)
+	}
+
+	owners := []OwnerReport{{Email: "jane@example.com", Rows: []Row{{Application: "a"}, {Application: "b"}}, Applications: 2}}
+	if err := WriteOwnersCSV(context.Background(), dest, owners, reportPath, zerolog.New(io.Discard)); err != nil {
+		t.Fatalf("WriteOwnersCSV: %v", err)
+	}
+	f, err := os.Open(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close() //nolint:errcheck
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want header and 1 row", len(records))
+	}
+	want := []string{"jane@example.com", "2", "2", OwnerReportPath(reportPath, "jane@example.com")}
+	for i, v := range want {
+		if records[1][i] != v {
+			t.Errorf("column %s = %q, want %q", records[0][i], records[1][i], v)
+		}
+	}
+}
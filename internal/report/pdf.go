@@ -0,0 +1,36 @@
+// internal/report/pdf.go
+package report
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// PDFDir is the subdirectory of the output directory that archives the PDF
+// reports rendered by IQ Server.
+const PDFDir = "pdf"
+
+// PDFPath returns the archive location of the PDF of a report:
+// outputDir/pdf/<application>_<stage>_<reportID>.pdf. Report IDs are unique,
+// so a report already archived by an earlier run has the same path.
+func PDFPath(outputDir, application, stage, reportID string) string {
+	return filepath.Join(outputDir, PDFDir, artifactName(application, stage, reportID)+".pdf")
+}
+
+// artifactName names a per-report artifact <application>_<stage>_<reportID>,
+// with path separators in the parts replaced.
+func artifactName(application, stage, reportID string) string {
+	clean := strings.NewReplacer("/", "-", `\`, "-", ":", "-").Replace
+	return clean(application) + "_" + clean(stage) + "_" + clean(reportID)
+}
+
+// WritePDF writes the PDF produced by download to destPath, atomically, so
+// that an interrupted download never leaves a truncated file behind.
+func WritePDF(ctx context.Context, destPath string, logger zerolog.Logger, download func(w io.Writer) error) error {
+	_, err := writeFileAtomic(ctx, destPath, logger, download)
+	return err
+}
@@ -0,0 +1,46 @@
+// internal/report/pdf_test.go
+package report
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestPDFPath(t *testing.T) {
+	got := PDFPath("out", "my/app", "build", "rpt-1")
+	if want := filepath.Join("out", "pdf", "my-app_build_rpt-1.pdf"); got != want {
+		t.Errorf("PDFPath = %q, want %q", got, want)
+	}
+}
+
+func TestWritePDF_FailedDownloadLeavesNoFile(t *testing.T) {
+	dest := PDFPath(t.TempDir(), "app", "build", "rpt-1")
+	logger := zerolog.New(io.Discard)
+
+	err := WritePDF(context.Background(), dest, logger, func(w io.Writer) error {
+		_, _ = w.Write([]byte("%PDF-partial"))
+		return errors.New("connection reset")
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if _, err := os.Stat(dest); !os.IsNotExist(err) {
+		t.Errorf("partial PDF left behind: %v", err)
+	}
+
+	if err := WritePDF(context.Background(), dest, logger, func(w io.Writer) error {
+		_, err := w.Write([]byte("%PDF-1.4"))
+		return err
+	}); err != nil {
+		t.Fatalf("WritePDF: %v", err)
+	}
+	if b, _ := os.ReadFile(dest); string(b) != "%PDF-1.4" {
+		t.Errorf("content = %q", b)
+	}
+}
@@ -0,0 +1,39 @@
+// internal/report/raw.go
+package report
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// RawDir is the subdirectory of the output directory that archives the raw
+// policy report responses of IQ Server.
+const RawDir = "raw"
+
+// RawPath returns the archive location of the raw policy report response of
+// a report: outputDir/raw/<application>_<stage>_<reportID>.json.gz.
+func RawPath(outputDir, application, stage, reportID string) string {
+	return filepath.Join(outputDir, RawDir, artifactName(application, stage, reportID)+".json.gz")
+}
+
+// WriteRawJSON writes body gzip-compressed to destPath, atomically.
+func WriteRawJSON(ctx context.Context, destPath string, body []byte, logger zerolog.Logger) error {
+	_, err := writeFileAtomic(ctx, destPath, logger, func(w io.Writer) error {
+		zw := gzip.NewWriter(w)
+		zw.Name = strings.TrimSuffix(filepath.Base(destPath), ".gz")
+		if _, err := zw.Write(body); err != nil {
+			return fmt.Errorf("compress raw response: %w", err)
+		}
+		if err := zw.Close(); err != nil {
+			return fmt.Errorf("compress raw response: %w", err)
+		}
+		return nil
+	})
+	return err
+}
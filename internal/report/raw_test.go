@@ -0,0 +1,39 @@
+// internal/report/raw_test.go
+package report
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestWriteRawJSON(t *testing.T) {
+	dest := RawPath(t.TempDir(), "app", "build", "rpt-1")
+	if filepath.Base(dest) != "app_build_rpt-1.json.gz" {
+		t.Errorf("RawPath = %q", dest)
+	}
+
+	body := []byte(`{"components": []}`)
+	if err := WriteRawJSON(context.Background(), dest, body, zerolog.New(io.Discard)); err != nil {
+		t.Fatalf("WriteRawJSON: %v", err)
+	}
+
+	f, err := os.Open(dest)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("gzip reader: %v", err)
+	}
+	got, err := io.ReadAll(zr)
+	if err != nil || string(got) != string(body) {
+		t.Errorf("decompressed = %q, %v", got, err)
+	}
+}
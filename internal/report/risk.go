@@ -0,0 +1,96 @@
+// internal/report/risk.go
+package report
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Threat bands as used by IQ Server for policy threat levels.
+const (
+	BandCritical = "critical" // 8-10
+	BandSevere   = "severe"   // 4-7
+	BandModerate = "moderate" // 2-3
+	BandLow      = "low"      // 1
+	BandNone     = "none"     // 0
+)
+
+// ThreatBand returns the IQ Server threat band for a policy threat level.
+func ThreatBand(threat int) string {
+	switch {
+	case threat >= 8:
+		return BandCritical
+	case threat >= 4:
+		return BandSevere
+	case threat >= 2:
+		return BandModerate
+	case threat == 1:
+		return BandLow
+	default:
+		return BandNone
+	}
+}
+
+// DefaultRiskWeights are the per-band weights used for risk scores when no
+// weights are configured.
+var DefaultRiskWeights = map[string]float64{
+	BandCritical: 10,
+	BandSevere:   5,
+	BandModerate: 2,
+	BandLow:      1,
+}
+
+// ApplicationRisk is the risk score of a single application.
+type ApplicationRisk struct {
+	Application string  `json:"application"`
+	Score       float64 `json:"score"`
+}
+
+// RiskScores computes a risk score per application as the weighted sum of
+// its violation rows by threat band. Bands without a weight count as zero.
+// The result is sorted by score, highest risk first.
+func RiskScores(rows []Row, weights map[string]float64) []ApplicationRisk {
+	scores := make(map[string]float64)
+	for _, r := range rows {
+		scores[r.Application] += weights[ThreatBand(r.Threat)]
+	}
+	out := make([]ApplicationRisk, 0, len(scores))
+	for app, score := range scores {
+		out = append(out, ApplicationRisk{Application: app, Score: score})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Score != out[j].Score {
+			return out[i].Score > out[j].Score
+		}
+		return out[i].Application < out[j].Application
+	})
+	return out
+}
+
+// Threat formats for the Threat column, see FormatThreat.
+const (
+	ThreatFormatNumber  = "number"  // 7
+	ThreatFormatBand    = "band"    // Severe
+	ThreatFormatLabeled = "labeled" // Severe (7)
+)
+
+// ThreatFormats returns the supported threat formats.
+func ThreatFormats() []string {
+	return []string{ThreatFormatNumber, ThreatFormatBand, ThreatFormatLabeled}
+}
+
+// FormatThreat formats a policy threat level. Unknown formats write the
+// number.
+func FormatThreat(threat int, format string) string {
+	band := ThreatBand(threat)
+	band = strings.ToUpper(band[:1]) + band[1:]
+	switch format {
+	case ThreatFormatBand:
+		return band
+	case ThreatFormatLabeled:
+		return band + " (" + strconv.Itoa(threat) + ")"
+	default:
+		return strconv.Itoa(threat)
+	}
+}
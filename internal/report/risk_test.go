@@ -0,0 +1,57 @@
+// internal/report/risk_test.go
+package report
+
+import "testing"
+
+func TestThreatBand(t *testing.T) {
+	cases := map[int]string{0: BandNone, 1: BandLow, 2: BandModerate, 3: BandModerate, 4: BandSevere, 7: BandSevere, 8: BandCritical, 10: BandCritical}
+	for threat, want := range cases {
+		if got := ThreatBand(threat); got != want {
+			t.Errorf("ThreatBand(%d) = %q, want %q", threat, got, want)
+		}
+	}
+}
+
+func TestFormatThreat(t *testing.T) {
+	cases := []struct {
+		threat int
+		format string
+		want   string
+	}{
+		{7, ThreatFormatNumber, "7"},
+		{7, "", "7"},
+		{7, ThreatFormatBand, "Severe"},
+		{7, ThreatFormatLabeled, "Severe (7)"},
+		{10, ThreatFormatLabeled, "Critical (10)"},
+		{0, ThreatFormatBand, "None"},
+	}
+	for _, c := range cases {
+		if got := FormatThreat(c.threat, c.format); got != c.want {
+			t.Errorf("FormatThreat(%d, %q) = %q, want %q", c.threat, c.format, got, c.want)
+		}
+	}
+}
+
+func TestRiskScores_WeightedAndSorted(t *testing.T) {
+	rows := []Row{
+		{Application: "app-a", Threat: 9},
+		{Application: "app-a", Threat: 5},
+		{Application: "app-b", Threat: 9},
+		{Application: "app-b", Threat: 9},
+		{Application: "app-c", Threat: 0},
+	}
+
+	got := RiskScores(rows, DefaultRiskWeights)
+	if len(got) != 3 {
+		t.Fatalf("expected 3 applications, got %#v", got)
+	}
+	if got[0].Application != "app-b" || got[0].Score != 20 {
+		t.Errorf("top risk = %#v, want app-b with 20", got[0])
+	}
+	if got[1].Application != "app-a" || got[1].Score != 15 {
+		t.Errorf("second risk = %#v, want app-a with 15", got[1])
+	}
+	if got[2].Score != 0 {
+		t.Errorf("app-c score = %v, want 0", got[2].Score)
+	}
+}
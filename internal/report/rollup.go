@@ -0,0 +1,239 @@
+// internal/report/rollup.go
+package report
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// ApplicationScan describes the exported reports of one application. Scans
+// are recorded for every processed application, including applications
+// without violations.
+type ApplicationScan struct {
+	Application  string
+	Organization string
+	Stages       []string
+	ScanDate     time.Time // latest evaluation date of the exported reports; zero when unknown
+}
+
+// ApplicationSummary is one row of the application rollup: violation counts
+// per threat band and the risk score of an application.
+type ApplicationSummary struct {
+	Application  string
+	Organization string
+	Stages       []string
+	ScanDate     time.Time
+	Critical     int
+	Severe       int
+	Moderate     int
+	Low          int
+	RiskScore    float64
+}
+
+// SummarizeApplications rolls rows up per application. Every scan yields a
+// summary, so clean applications are listed with zero counts. The result is
+// sorted by risk score, highest first.
+func SummarizeApplications(scans []ApplicationScan, rows []Row, weights map[string]float64) []ApplicationSummary {
+	byApp := make(map[string]*ApplicationSummary, len(scans))
+	for _, sc := range scans {
+		byApp[sc.Application] = &ApplicationSummary{
+			Application:  sc.Application,
+			Organization: sc.Organization,
+			Stages:       sc.Stages,
+			ScanDate:     sc.ScanDate,
+		}
+	}
+
+	for _, r := range rows {
+		sum, ok := byApp[r.Application]
+		if !ok {
+			sum = &ApplicationSummary{Application: r.Application, Organization: r.Organization}
+			byApp[r.Application] = sum
+		}
+		band := ThreatBand(r.Threat)
+		switch band {
+		case BandCritical:
+			sum.Critical++
+		case BandSevere:
+			sum.Severe++
+		case BandModerate:
+			sum.Moderate++
+		case BandLow:
+			sum.Low++
+		}
+		sum.RiskScore += weights[band]
+	}
+
+	out := make([]ApplicationSummary, 0, len(byApp))
+	for _, sum := range byApp {
+		out = append(out, *sum)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].RiskScore != out[j].RiskScore {
+			return out[i].RiskScore > out[j].RiskScore
+		}
+		return out[i].Application < out[j].Application
+	})
+	return out
+}
+
+// ApplicationsPath returns the application rollup location for the report
+// at reportPath: the report path with its extension replaced by
+// ".applications.csv".
+func ApplicationsPath(reportPath string) string {
+	return strings.TrimSuffix(reportPath, filepath.Ext(reportPath)) + ".applications.csv"
+}
+
+// WriteApplicationsCSV writes the application rollup to destPath, atomically.
+func WriteApplicationsCSV(ctx context.Context, destPath string, summaries []ApplicationSummary, logger zerolog.Logger) error {
+	_, err := writeFileAtomic(ctx, destPath, logger, func(f io.Writer) error {
+		return WriteApplicationsCSVTo(f, summaries)
+	})
+	return err
+}
+
+// WriteApplicationsCSVTo writes the application rollup as CSV to f.
+func WriteApplicationsCSVTo(f io.Writer, summaries []ApplicationSummary) error {
+	w := csv.NewWriter(f)
+	header := []string{"Application", "Organization", "Stage", "Latest Scan", "Critical", "Severe", "Moderate", "Low", "Risk Score"}
+	if err := w.Write(header); err != nil {
+		return fmt.Errorf("write header: %w", err)
+	}
+	for i, s := range summaries {
+		scanDate := ""
+		if !s.ScanDate.IsZero() {
+			scanDate = s.ScanDate.UTC().Format(time.DateOnly)
+		}
+		record := []string{
+			s.Application,
+			s.Organization,
+			strings.Join(s.Stages, "+"),
+			scanDate,
+			strconv.Itoa(s.Critical),
+			strconv.Itoa(s.Severe),
+			strconv.Itoa(s.Moderate),
+			strconv.Itoa(s.Low),
+			strconv.FormatFloat(s.RiskScore, 'f', -1, 64),
+		}
+		if err := w.Write(record); err != nil {
+			return fmt.Errorf("write row %d: %w", i+1, err)
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("flush csv: %w", err)
+	}
+	return nil
+}
+
+// OrganizationSummary is one row of the organization rollup, aggregating
+// the application summaries of an organization.
+type OrganizationSummary struct {
+	Organization     string
+	Applications     int
+	Critical         int
+	Severe           int
+	Moderate         int
+	Low              int
+	AverageRiskScore float64
+	WorstApplication string // application with the highest risk score
+	WorstRiskScore   float64
+}
+
+// Violations returns the number of violations with a threat level of 1 or
+// more.
+func (o OrganizationSummary) Violations() int {
+	return o.Critical + o.Severe + o.Moderate + o.Low
+}
+
+// SummarizeOrganizations aggregates application summaries per organization,
+// sorted by average risk score, highest first.
+func SummarizeOrganizations(apps []ApplicationSummary) []OrganizationSummary {
+	byOrg := make(map[string]*OrganizationSummary)
+	totals := make(map[string]float64)
+	for _, a := range apps {
+		org, ok := byOrg[a.Organization]
+		if !ok {
+			org = &OrganizationSummary{Organization: a.Organization}
+			byOrg[a.Organization] = org
+		}
+		org.Applications++
+		org.Critical += a.Critical
+		org.Severe += a.Severe
+		org.Moderate += a.Moderate
+		org.Low += a.Low
+		totals[a.Organization] += a.RiskScore
+		if org.WorstApplication == "" || a.RiskScore > org.WorstRiskScore ||
+			(a.RiskScore == org.WorstRiskScore && a.Application < org.WorstApplication) {
+			org.WorstApplication, org.WorstRiskScore = a.Application, a.RiskScore
+		}
+	}
+
+	out := make([]OrganizationSummary, 0, len(byOrg))
+	for name, org := range byOrg {
+		org.AverageRiskScore = totals[name] / float64(org.Applications)
+		out = append(out, *org)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].AverageRiskScore != out[j].AverageRiskScore {
+			return out[i].AverageRiskScore > out[j].AverageRiskScore
+		}
+		return out[i].Organization < out[j].Organization
+	})
+	return out
+}
+
+// OrganizationsPath returns the organization rollup location for the report
+// at reportPath: the report path with its extension replaced by
+// ".organizations.csv".
+func OrganizationsPath(reportPath string) string {
+	return strings.TrimSuffix(reportPath, filepath.Ext(reportPath)) + ".organizations.csv"
+}
+
+// WriteOrganizationsCSV writes the organization rollup to destPath, atomically.
+func WriteOrganizationsCSV(ctx context.Context, destPath string, summaries []OrganizationSummary, logger zerolog.Logger) error {
+	_, err := writeFileAtomic(ctx, destPath, logger, func(f io.Writer) error {
+		return WriteOrganizationsCSVTo(f, summaries)
+	})
+	return err
+}
+
+// WriteOrganizationsCSVTo writes the organization rollup as CSV to f.
+func WriteOrganizationsCSVTo(f io.Writer, summaries []OrganizationSummary) error {
+	w := csv.NewWriter(f)
+	header := []string{"Organization", "Applications", "Critical", "Severe", "Moderate", "Low", "Total Violations", "Average Risk Score", "Worst Application", "Worst Risk Score"}
+	if err := w.Write(header); err != nil {
+		return fmt.Errorf("write header: %w", err)
+	}
+	for i, o := range summaries {
+		record := []string{
+			o.Organization,
+			strconv.Itoa(o.Applications),
+			strconv.Itoa(o.Critical),
+			strconv.Itoa(o.Severe),
+			strconv.Itoa(o.Moderate),
+			strconv.Itoa(o.Low),
+			strconv.Itoa(o.Violations()),
+			strconv.FormatFloat(o.AverageRiskScore, 'f', 2, 64),
+			o.WorstApplication,
+			strconv.FormatFloat(o.WorstRiskScore, 'f', -1, 64),
+		}
+		if err := w.Write(record); err != nil {
+			return fmt.Errorf("write row %d: %w", i+1, err)
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("flush csv: %w", err)
+	}
+	return nil
+}
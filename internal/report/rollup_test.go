@@ -0,0 +1,116 @@
+// internal/report/rollup_test.go
+package report
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestSummarizeApplications(t *testing.T) {
+	scanned := time.Date(2025, 1, 15, 9, 0, 0, 0, time.UTC)
+	scans := []ApplicationScan{
+		{Application: "app-a", Organization: "org", Stages: []string{"build"}, ScanDate: scanned},
+		{Application: "app-clean", Organization: "org", Stages: []string{"build"}},
+	}
+	rows := []Row{
+		{Application: "app-a", Threat: 9},
+		{Application: "app-a", Threat: 8},
+		{Application: "app-a", Threat: 5},
+		{Application: "app-a", Threat: 2},
+		{Application: "app-a", Threat: 1},
+		{Application: "app-a", Threat: 0},
+	}
+
+	got := SummarizeApplications(scans, rows, DefaultRiskWeights)
+	if len(got) != 2 {
+		t.Fatalf("got %d summaries, want 2", len(got))
+	}
+	a := got[0]
+	if a.Application != "app-a" || a.Critical != 2 || a.Severe != 1 || a.Moderate != 1 || a.Low != 1 || a.RiskScore != 28 {
+		t.Errorf("unexpected summary: %+v", a)
+	}
+	if !a.ScanDate.Equal(scanned) {
+		t.Errorf("ScanDate = %v", a.ScanDate)
+	}
+	if got[1].Application != "app-clean" || got[1].Critical+got[1].Severe+got[1].Moderate+got[1].Low != 0 {
+		t.Errorf("clean application summary = %+v", got[1])
+	}
+}
+
+func TestWriteApplicationsCSV(t *testing.T) {
+	dest := ApplicationsPath(filepath.Join(t.TempDir(), "report.csv"))
+	if filepath.Base(dest) != "report.applications.csv" {
+		t.Errorf("ApplicationsPath = %q", dest)
+	}
+
+	summaries := []ApplicationSummary{{
+		Application: "app-a", Organization: "org", Stages: []string{"build", "operate"},
+		ScanDate: time.Date(2025, 1, 15, 9, 0, 0, 0, time.UTC), Critical: 2, Severe: 1, RiskScore: 25,
+	}}
+	if err := WriteApplicationsCSV(context.Background(), dest, summaries, zerolog.New(io.Discard)); err != nil {
+		t.Fatalf("WriteApplicationsCSV: %v", err)
+	}
+
+	f, err := os.Open(dest)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatalf("read csv: %v", err)
+	}
+	want := []string{"app-a", "org", "build+operate", "2025-01-15", "2", "1", "0", "0", "25"}
+	if len(records) != 2 || len(records[1]) != len(want) {
+		t.Fatalf("unexpected records: %v", records)
+	}
+	for i := range want {
+		if records[1][i] != want[i] {
+			t.Errorf("column %d = %q, want %q", i, records[1][i], want[i])
+		}
+	}
+}
+
+func TestSummarizeOrganizations(t *testing.T) {
+	apps := []ApplicationSummary{
+		{Application: "a1", Organization: "org-1", Critical: 2, Severe: 1, RiskScore: 25},
+		{Application: "a2", Organization: "org-1", Low: 3, RiskScore: 3},
+		{Application: "a3", Organization: "org-1", RiskScore: 0},
+		{Application: "b1", Organization: "org-2", Critical: 1, RiskScore: 10},
+	}
+
+	got := SummarizeOrganizations(apps)
+	if len(got) != 2 {
+		t.Fatalf("got %d summaries, want 2", len(got))
+	}
+	o := got[1]
+	if o.Organization != "org-1" || o.Applications != 3 || o.Critical != 2 || o.Low != 3 || o.Violations() != 6 {
+		t.Errorf("unexpected summary: %+v", o)
+	}
+	if o.AverageRiskScore < 9.33 || o.AverageRiskScore > 9.34 || o.WorstApplication != "a1" || o.WorstRiskScore != 25 {
+		t.Errorf("unexpected scores: %+v", o)
+	}
+	if got[0].Organization != "org-2" {
+		t.Errorf("organizations not sorted by average risk: %+v", got)
+	}
+
+	dest := OrganizationsPath(filepath.Join(t.TempDir(), "report.csv"))
+	if err := WriteOrganizationsCSV(context.Background(), dest, got, zerolog.New(io.Discard)); err != nil {
+		t.Fatalf("WriteOrganizationsCSV: %v", err)
+	}
+	b, err := os.ReadFile(dest)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if want := "org-1,3,2,1,0,3,6,9.33,a1,25\n"; !strings.Contains(string(b), want) {
+		t.Errorf("missing row %q in:\n%s", want, b)
+	}
+}
@@ -0,0 +1,134 @@
+// internal/report/rotate.go
+package report
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
+
+	"github.com/rs/zerolog"
+)
+
+// LatestName is the name of the stable path to the newest report in the
+// output directory, see UpdateLatest.
+const LatestName = "latest.csv"
+
+// UpdateLatest points LatestName next to the report at reportPath to that
+// report: a relative symbolic link, or a copy on Windows and wherever links
+// cannot be created. The link is replaced atomically, so consumers always
+// find a complete report there. For a chunked report, reportPath is its
+// chunk index.
+func UpdateLatest(ctx context.Context, reportPath string, logger zerolog.Logger) error {
+	dir := filepath.Dir(reportPath)
+	dest := filepath.Join(dir, LatestName)
+	if runtime.GOOS != "windows" {
+		tmp := filepath.Join(dir, tempPrefix+runTag+"-"+LatestName)
+		_ = os.Remove(tmp)
+		err := os.Symlink(filepath.Base(reportPath), tmp)
+		if err == nil {
+			if err = os.Rename(tmp, dest); err == nil {
+				logger.Debug().Str("path", dest).Str("target", reportPath).Msg("latest link updated")
+				return nil
+			}
+			_ = os.Remove(tmp)
+		}
+		logger.Debug().Err(err).Msg("cannot link latest report, copying it")
+	}
+
+	src, err := os.Open(reportPath)
+	if err != nil {
+		return fmt.Errorf("open report: %w", err)
+	}
+	defer src.Close()
+	_, err = writeFileAtomic(ctx, dest, logger, func(w io.Writer) error {
+		if _, err := io.Copy(w, src); err != nil {
+			return fmt.Errorf("copy report: %w", err)
+		}
+		return nil
+	})
+	return err
+}
+
+// RotateReports removes the runs in dir beyond the keep newest, each with
+// the manifest and the files it records: report, chunks, export and
+// companion files such as rollups and owner reports. Runs are told apart by
+// their manifests, and the runs of each shard are rotated separately. The
+// report LatestName points to is kept. It returns the removed paths.
+func RotateReports(dir string, keep int, logger zerolog.Logger) ([]string, error) {
+	if keep <= 0 {
+		return nil, fmt.Errorf("number of reports to keep must be positive, got %d", keep)
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.manifest.json"))
+	if err != nil {
+		return nil, fmt.Errorf("list manifests: %w", err)
+	}
+	// Report the latest link points to, if it is a link
+	var latest string
+	if target, err := os.Readlink(filepath.Join(dir, LatestName)); err == nil {
+		latest = filepath.Join(dir, filepath.Base(target))
+	}
+
+	type run struct {
+		manifest string
+		m        *Manifest
+	}
+	byShard := make(map[string][]run)
+	for _, p := range paths {
+		if fi, err := os.Lstat(p); err != nil || !fi.Mode().IsRegular() {
+			continue
+		}
+		m, err := ReadManifest(p)
+		if err != nil {
+			logger.Warn().Err(err).Msg("Skipping unreadable manifest")
+			continue
+		}
+		var shard string
+		if m.Shard != nil {
+			shard = m.Shard.String()
+		}
+		byShard[shard] = append(byShard[shard], run{manifest: p, m: m})
+	}
+
+	var removed []string
+	for _, runs := range byShard {
+		sort.Slice(runs, func(i, j int) bool { return runs[i].m.GeneratedAt.After(runs[j].m.GeneratedAt) })
+		for _, r := range runs[min(keep, len(runs)):] {
+			files := runFiles(dir, r.manifest, r.m)
+			if slices.Contains(files, latest) {
+				continue
+			}
+			for _, f := range files {
+				if err := os.RemoveAll(f); err != nil {
+					return removed, fmt.Errorf("remove %s: %w", f, err)
+				}
+				removed = append(removed, f)
+			}
+			logger.Debug().Str("report", r.m.ReportPath).Time("generatedAt", r.m.GeneratedAt).Int("files", len(files)).Msg("rotated out old run")
+		}
+	}
+	sort.Strings(removed)
+	return removed, nil
+}
+
+// runFiles lists the existing files in dir of the run with the manifest at
+// manifestPath: the manifest and the report, chunks, export and companions
+// it records. Recorded paths are looked up by name in dir, so that runs
+// stay rotatable when the output directory is moved.
+func runFiles(dir, manifestPath string, m *Manifest) []string {
+	files := []string{manifestPath}
+	for _, p := range slices.Concat([]string{m.ReportPath, m.Export}, m.Chunks, m.Companions) {
+		if p == "" {
+			continue
+		}
+		f := filepath.Join(dir, filepath.Base(p))
+		if _, err := os.Lstat(f); err == nil && !slices.Contains(files, f) {
+			files = append(files, f)
+		}
+	}
+	return files
+}
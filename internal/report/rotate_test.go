@@ -0,0 +1,101 @@
+// internal/report/rotate_test.go
+package report
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestUpdateLatest(t *testing.T) {
+	dir := t.TempDir()
+	logger := zerolog.New(io.Discard)
+	for _, name := range []string{"a.csv", "b.csv"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := UpdateLatest(context.Background(), filepath.Join(dir, name), logger); err != nil {
+			t.Fatalf("UpdateLatest(context.Background(), %s): %v", name, err)
+		}
+		got, err := os.ReadFile(filepath.Join(dir, LatestName))
+		if err != nil || string(got) != name {
+			t.Errorf("latest after %s = %q, %v", name, got, err)
+		}
+	}
+	if runtime.GOOS != "windows" {
+		if target, err := os.Readlink(filepath.Join(dir, LatestName)); err != nil || target != "b.csv" {
+			t.Errorf("latest link = %q, %v; want relative link to b.csv", target, err)
+		}
+	}
+}
+
+func TestRotateReports(t *testing.T) {
+	dir := t.TempDir()
+	logger := zerolog.New(io.Discard)
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	touch := func(files ...string) {
+		t.Helper()
+		for _, f := range files {
+			if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, f)), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, f), nil, 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	writeRun := func(base string, n int, m Manifest, files ...string) {
+		t.Helper()
+		if m.ReportPath == "" {
+			m.ReportPath = base + ".csv"
+		}
+		touch(append(append([]string{m.ReportPath}, m.Chunks...), files...)...)
+		m.GeneratedAt = start.Add(time.Duration(n) * time.Hour)
+		if err := WriteManifest(context.Background(), filepath.Join(dir, base+".manifest.json"), m, logger); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeRun("r1", 1, Manifest{Companions: []string{"r1.applications.csv", "r1.owners"}}, "r1.applications.csv", "r1.owners/a@example.com.csv")
+	writeRun("r2", 2, Manifest{ReportPath: "r2.index.csv", Chunks: []string{"r2-001.csv", "r2-002.csv"}})
+	writeRun("r3", 3, Manifest{})
+	writeRun("r4", 4, Manifest{})
+	writeRun("s1.shard-0-of-2", 1, Manifest{Shard: &Shard{Index: 0, Total: 2}})
+	// A shard named after r1 and a file of r1's name not recorded by its
+	// manifest, such as an alternate name of another run's report
+	writeRun("r1.shard-1-of-2", 1, Manifest{Shard: &Shard{Index: 1, Total: 2}})
+	touch("notes.txt", "r1.20250301-000000.csv")
+	if runtime.GOOS != "windows" {
+		// latest.csv still points at an older run
+		if err := UpdateLatest(context.Background(), filepath.Join(dir, "r2.index.csv"), logger); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	removed, err := RotateReports(dir, 2, logger)
+	if err != nil {
+		t.Fatalf("RotateReports: %v", err)
+	}
+	for i, p := range removed {
+		removed[i], _ = filepath.Rel(dir, p)
+	}
+	want := []string{"r1.applications.csv", "r1.csv", "r1.manifest.json", "r1.owners"}
+	if runtime.GOOS == "windows" {
+		want = append([]string{"r2-001.csv", "r2-002.csv", "r2.index.csv", "r2.manifest.json"}, want...)
+		slices.Sort(want)
+	}
+	if !slices.Equal(removed, want) {
+		t.Errorf("removed %v, want %v", removed, want)
+	}
+	for _, keep := range []string{"r3.csv", "r4.manifest.json", "s1.shard-0-of-2.csv", "r1.shard-1-of-2.csv", "r1.shard-1-of-2.manifest.json", "r1.20250301-000000.csv", "notes.txt"} {
+		if _, err := os.Stat(filepath.Join(dir, keep)); err != nil {
+			t.Errorf("%s removed: %v", keep, err)
+		}
+	}
+}
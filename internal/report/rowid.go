@@ -0,0 +1,56 @@
+// internal/report/rowid.go
+package report
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// RowID returns a deterministic identity of the violation behind the row
+// that is stable across runs, for joining reports when diffing,
+// deduplicating tickets or baselining. It is the IQ policy violation ID when
+// known and otherwise a hash of application, policy, component, constraint
+// and condition. Rows split per CVE share the RowID of their violation.
+func (r Row) RowID() string {
+	if r.ViolationID != "" {
+		return r.ViolationID
+	}
+	return hashRowID(r.Application, r.Policy, r.Component, r.ConstraintName, r.Condition)
+}
+
+// hashRowID returns the RowID of a violation without a policy violation ID
+// from its application, policy, component, constraint and condition.
+func hashRowID(fields ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(fields, "\x1f")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// rowIDColumns are the columns RowID hashes, in order.
+var rowIDColumns = []string{"Application", "Policy", "Component", "Constraint Name", "Condition"}
+
+// rowIDReader returns a function reading the Row ID of report records by
+// the given header: the Row ID column when the report has one, and
+// otherwise the hash of the application, policy, component, constraint and
+// condition columns, which is the Row ID of violations without a policy
+// violation ID.
+func rowIDReader(header []string) (func([]string) string, error) {
+	if i := slices.Index(header, ColumnRowID); i >= 0 {
+		return func(rec []string) string { return rec[i] }, nil
+	}
+	cols := make([]int, len(rowIDColumns))
+	for j, name := range rowIDColumns {
+		if cols[j] = slices.Index(header, name); cols[j] < 0 {
+			return nil, fmt.Errorf("no %q or %q column", ColumnRowID, name)
+		}
+	}
+	return func(rec []string) string {
+		fields := make([]string, len(cols))
+		for j, c := range cols {
+			fields[j] = rec[c]
+		}
+		return hashRowID(fields...)
+	}, nil
+}
@@ -0,0 +1,60 @@
+// internal/report/rowid_test.go
+package report
+
+import "testing"
+
+func TestRow_RowID(t *testing.T) {
+	r := Row{Application: "app", Policy: "Security-High", Component: "lib:1.0", ConstraintName: "CVEs", Condition: "CVE Count >= 1"}
+
+	id := r.RowID()
+	if len(id) != 16 {
+		t.Errorf("RowID() = %q, want 16 hex characters", id)
+	}
+	if again := r.RowID(); again != id {
+		t.Errorf("RowID() not deterministic: %q vs %q", again, id)
+	}
+
+	// Fields outside the identity do not change it
+	r2 := r
+	r2.Threat, r2.Waived, r2.Stage = 9, true, "operate"
+	if r2.RowID() != id {
+		t.Error("RowID() changed with non-identity fields")
+	}
+
+	r2.Component = "lib:1.1"
+	if r2.RowID() == id {
+		t.Error("RowID() unchanged for a different component")
+	}
+
+	r.ViolationID = "pv-123"
+	if got := r.RowID(); got != "pv-123" {
+		t.Errorf("RowID() = %q, want the policy violation ID", got)
+	}
+}
+
+func TestRowIDReader(t *testing.T) {
+	r := Row{Application: "app", Policy: "Security-High", Component: "lib:1.0", ConstraintName: "CVEs", Condition: "CVE Count >= 1"}
+
+	// Without the Row ID column the hash is computed from the report cells
+	table := Table([]Row{r})
+	rowID, err := rowIDReader(table[0])
+	if err != nil {
+		t.Fatalf("rowIDReader: %v", err)
+	}
+	if got := rowID(table[1]); got != r.RowID() {
+		t.Errorf("hashed Row ID = %q, want %q", got, r.RowID())
+	}
+
+	r.ViolationID = "pv-123"
+	table = Table([]Row{r}, WithOptionalColumns(ColumnRowID))
+	if rowID, err = rowIDReader(table[0]); err != nil {
+		t.Fatalf("rowIDReader: %v", err)
+	}
+	if got := rowID(table[1]); got != "pv-123" {
+		t.Errorf("Row ID = %q, want the column's cell", got)
+	}
+
+	if _, err := rowIDReader([]string{"Application", "Policy"}); err == nil {
+		t.Error("expected error for a header without Row ID or its hashed columns")
+	}
+}
@@ -0,0 +1,28 @@
+// internal/report/shard.go
+package report
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Shard identifies the part of the applications a sharded run processes:
+// shard Index (from zero) of Total.
+type Shard struct {
+	Index int `json:"index"`
+	Total int `json:"total"`
+}
+
+// String returns the shard as "<index>-of-<total>".
+func (s Shard) String() string {
+	return fmt.Sprintf("%d-of-%d", s.Index, s.Total)
+}
+
+// ShardPath returns the output location of shard s for the report at
+// reportPath: ".shard-<index>-of-<total>" is inserted before the extension,
+// so that the outputs of parallel shards sharing a directory never collide.
+func ShardPath(reportPath string, s Shard) string {
+	ext := filepath.Ext(reportPath)
+	return strings.TrimSuffix(reportPath, ext) + ".shard-" + s.String() + ext
+}
@@ -0,0 +1,127 @@
+// internal/report/sla.go
+package report
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// SLABreach is one row of the SLA breach report: an open violation older
+// than the SLA of its threat band.
+type SLABreach struct {
+	Application  string
+	Organization string
+	Policy       string
+	Component    string
+	Threat       int
+	Band         string
+	OpenTime     time.Time
+	AgeDays      int
+	SLADays      int
+	RowID        string
+}
+
+// OverdueDays returns the number of days the violation is past its SLA.
+func (b SLABreach) OverdueDays() int {
+	return b.AgeDays - b.SLADays
+}
+
+// SLABreaches lists the violations whose age at now exceeds the SLA in days
+// of their threat band. Bands without an SLA, waived rows and rows without
+// an open time are skipped; rows of the same violation (see Row.RowID) are
+// listed once. The result is sorted by overdue days, most overdue first.
+func SLABreaches(rows []Row, slaDays map[string]int, now time.Time) []SLABreach {
+	var out []SLABreach
+	seen := make(map[string]bool)
+	for _, r := range rows {
+		band := ThreatBand(r.Threat)
+		sla, ok := slaDays[band]
+		if !ok || r.Waived || r.OpenTime.IsZero() {
+			continue
+		}
+		age := int(now.Sub(r.OpenTime).Hours() / 24)
+		if age <= sla {
+			continue
+		}
+		key := r.Application + "\x1f" + r.RowID()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, SLABreach{
+			Application:  r.Application,
+			Organization: r.Organization,
+			Policy:       r.Policy,
+			Component:    r.Component,
+			Threat:       r.Threat,
+			Band:         band,
+			OpenTime:     r.OpenTime,
+			AgeDays:      age,
+			SLADays:      sla,
+			RowID:        r.RowID(),
+		})
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].OverdueDays() != out[j].OverdueDays() {
+			return out[i].OverdueDays() > out[j].OverdueDays()
+		}
+		return out[i].Application < out[j].Application
+	})
+	return out
+}
+
+// SLAPath returns the SLA breach report location for the report at
+// reportPath: the report path with its extension replaced by ".sla.csv".
+func SLAPath(reportPath string) string {
+	return strings.TrimSuffix(reportPath, filepath.Ext(reportPath)) + ".sla.csv"
+}
+
+// WriteSLACSV writes the SLA breach report to destPath, atomically. The
+// header is written even when there are no breaches.
+func WriteSLACSV(ctx context.Context, destPath string, breaches []SLABreach, logger zerolog.Logger) error {
+	_, err := writeFileAtomic(ctx, destPath, logger, func(f io.Writer) error {
+		return WriteSLACSVTo(f, breaches)
+	})
+	return err
+}
+
+// WriteSLACSVTo writes the SLA breach report as CSV to f.
+func WriteSLACSVTo(f io.Writer, breaches []SLABreach) error {
+	w := csv.NewWriter(f)
+	header := []string{"Application", "Organization", "Policy", "Component", "Threat", "Threat Band", "Open Since", "Age (days)", "SLA (days)", "Overdue (days)", "Row ID"}
+	if err := w.Write(header); err != nil {
+		return fmt.Errorf("write header: %w", err)
+	}
+	for i, b := range breaches {
+		record := []string{
+			b.Application,
+			b.Organization,
+			b.Policy,
+			b.Component,
+			strconv.Itoa(b.Threat),
+			b.Band,
+			b.OpenTime.UTC().Format(time.DateOnly),
+			strconv.Itoa(b.AgeDays),
+			strconv.Itoa(b.SLADays),
+			strconv.Itoa(b.OverdueDays()),
+			b.RowID,
+		}
+		if err := w.Write(record); err != nil {
+			return fmt.Errorf("write row %d: %w", i+1, err)
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("flush csv: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+// internal/report/sla_test.go
+package report
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestSLABreaches(t *testing.T) {
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	daysAgo := func(d int) time.Time { return now.AddDate(0, 0, -d) }
+	rows := []Row{
+		{Application: "a", ViolationID: "v1", Threat: 9, OpenTime: daysAgo(10)},
+		{Application: "a", ViolationID: "v1", Threat: 9, OpenTime: daysAgo(10), ConstraintName: "other"},
+		{Application: "a", ViolationID: "v2", Threat: 9, OpenTime: daysAgo(5)},
+		{Application: "b", ViolationID: "v3", Threat: 5, OpenTime: daysAgo(90)},
+		{Application: "b", ViolationID: "v4", Threat: 5, OpenTime: daysAgo(90), Waived: true},
+		{Application: "b", ViolationID: "v5", Threat: 5},
+		{Application: "c", ViolationID: "v6", Threat: 2, OpenTime: daysAgo(400)},
+	}
+
+	got := SLABreaches(rows, map[string]int{BandCritical: 7, BandSevere: 30}, now)
+	if len(got) != 2 {
+		t.Fatalf("got %d breaches, want 2: %+v", len(got), got)
+	}
+	if got[0].RowID != "v3" || got[0].AgeDays != 90 || got[0].OverdueDays() != 60 || got[0].Band != BandSevere {
+		t.Errorf("first breach = %+v", got[0])
+	}
+	if got[1].RowID != "v1" || got[1].AgeDays != 10 || got[1].SLADays != 7 {
+		t.Errorf("second breach = %+v", got[1])
+	}
+}
+
+func TestWriteSLACSV(t *testing.T) {
+	dest := SLAPath(filepath.Join(t.TempDir(), "report.csv"))
+	if filepath.Base(dest) != "report.sla.csv" {
+		t.Errorf("SLAPath = %q", dest)
+	}
+
+	breaches := []SLABreach{{
+		Application: "a", Organization: "org", Policy: "Security-High", Component: "lib 1.0",
+		Threat: 9, Band: BandCritical, OpenTime: time.Date(2025, 1, 15, 9, 0, 0, 0, time.UTC),
+		AgeDays: 45, SLADays: 7, RowID: "v1",
+	}}
+	if err := WriteSLACSV(context.Background(), dest, breaches, zerolog.New(io.Discard)); err != nil {
+		t.Fatalf("WriteSLACSV: %v", err)
+	}
+
+	f, err := os.Open(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close() //nolint:errcheck
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2", len(records))
+	}
+	if got := records[1]; got[6] != "2025-01-15" || got[7] != "45" || got[9] != "38" || got[10] != "v1" {
+		t.Errorf("unexpected record: %v", got)
+	}
+}
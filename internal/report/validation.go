@@ -0,0 +1,50 @@
+// internal/report/validation.go
+package report
+
+// ViolationCounts are the number of distinct, unwaived policy violations per
+// threat band, as summarized by IQ Server for a report.
+type ViolationCounts struct {
+	Critical int `json:"critical"`
+	Severe   int `json:"severe"`
+	Moderate int `json:"moderate"`
+}
+
+// CountViolations counts the distinct unwaived violations of rows per threat
+// band. A violation spans one row per constraint; rows are grouped by
+// ViolationID, or by component and policy when it is missing.
+func CountViolations(rows []Row) ViolationCounts {
+	seen := make(map[string]bool, len(rows))
+	var c ViolationCounts
+	for _, r := range rows {
+		if r.Waived {
+			continue
+		}
+		key := r.ViolationID
+		if key == "" {
+			key = r.Application + "\x00" + r.Component + "\x00" + r.Policy
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		switch ThreatBand(r.Threat) {
+		case BandCritical:
+			c.Critical++
+		case BandSevere:
+			c.Severe++
+		case BandModerate:
+			c.Moderate++
+		}
+	}
+	return c
+}
+
+// CountMismatch records a report whose parsed violations disagree with the
+// counts IQ Server reports for it.
+type CountMismatch struct {
+	Application string          `json:"application"`
+	Stage       string          `json:"stage"`
+	ReportID    string          `json:"reportId"`
+	Expected    ViolationCounts `json:"expected"` // from the IQ Server report summary
+	Parsed      ViolationCounts `json:"parsed"`
+}
@@ -0,0 +1,20 @@
+// internal/report/validation_test.go
+package report
+
+import "testing"
+
+func TestCountViolations(t *testing.T) {
+	rows := []Row{
+		{ViolationID: "v1", Threat: 9, ConstraintName: "a"},
+		{ViolationID: "v1", Threat: 9, ConstraintName: "b"}, // same violation, second constraint
+		{ViolationID: "v2", Threat: 5},
+		{ViolationID: "v3", Threat: 9, Waived: true},
+		{Component: "c", Policy: "p", Threat: 2},
+		{Component: "c", Policy: "p", Threat: 2},
+		{ViolationID: "v4", Threat: 1},
+	}
+	want := ViolationCounts{Critical: 1, Severe: 1, Moderate: 1}
+	if got := CountViolations(rows); got != want {
+		t.Errorf("CountViolations = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,125 @@
+// internal/report/vulnerabilities.go
+package report
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// VulnerabilitySummary is one row of the vulnerability view: a CVE with the
+// applications and components it affects, for patching campaigns run per
+// CVE rather than per application.
+type VulnerabilitySummary struct {
+	CVE          string
+	Applications []string // sorted
+	Components   []string // sorted
+	Violations   int      // rows of distinct violations referencing the CVE
+	MaxThreat    int
+	KEVListed    bool
+}
+
+// SummarizeVulnerabilities groups rows by CVE; rows referencing several
+// CVEs count for each. Waived rows and rows without a CVE are skipped, and
+// rows of the same violation (see Row.RowID) are counted once. The result is
+// sorted by the number of affected applications, then by threat, highest
+// first.
+func SummarizeVulnerabilities(rows []Row) []VulnerabilitySummary {
+	byCVE := make(map[string]*VulnerabilitySummary)
+	seen := make(map[string]bool)
+	for _, r := range rows {
+		if r.Waived {
+			continue
+		}
+		for _, id := range SplitCVEs(r.CVE) {
+			key := id + "\x1f" + r.Application + "\x1f" + r.RowID()
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			sum, ok := byCVE[id]
+			if !ok {
+				sum = &VulnerabilitySummary{CVE: id}
+				byCVE[id] = sum
+			}
+			if !slices.Contains(sum.Applications, r.Application) {
+				sum.Applications = append(sum.Applications, r.Application)
+			}
+			if !slices.Contains(sum.Components, r.Component) {
+				sum.Components = append(sum.Components, r.Component)
+			}
+			sum.Violations++
+			sum.MaxThreat = max(sum.MaxThreat, r.Threat)
+			sum.KEVListed = sum.KEVListed || r.KEVListed
+		}
+	}
+
+	out := make([]VulnerabilitySummary, 0, len(byCVE))
+	for _, sum := range byCVE {
+		slices.Sort(sum.Applications)
+		slices.Sort(sum.Components)
+		out = append(out, *sum)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if len(out[i].Applications) != len(out[j].Applications) {
+			return len(out[i].Applications) > len(out[j].Applications)
+		}
+		if out[i].MaxThreat != out[j].MaxThreat {
+			return out[i].MaxThreat > out[j].MaxThreat
+		}
+		return out[i].CVE < out[j].CVE
+	})
+	return out
+}
+
+// VulnerabilitiesPath returns the vulnerability view location for the
+// report at reportPath: the report path with its extension replaced by
+// ".vulnerabilities.csv".
+func VulnerabilitiesPath(reportPath string) string {
+	return strings.TrimSuffix(reportPath, filepath.Ext(reportPath)) + ".vulnerabilities.csv"
+}
+
+// WriteVulnerabilitiesCSV writes the vulnerability view to destPath,
+// atomically.
+func WriteVulnerabilitiesCSV(ctx context.Context, destPath string, summaries []VulnerabilitySummary, logger zerolog.Logger) error {
+	_, err := writeFileAtomic(ctx, destPath, logger, func(f io.Writer) error {
+		return WriteVulnerabilitiesCSVTo(f, summaries)
+	})
+	return err
+}
+
+// WriteVulnerabilitiesCSVTo writes the vulnerability view as CSV to f.
+func WriteVulnerabilitiesCSVTo(f io.Writer, summaries []VulnerabilitySummary) error {
+	w := csv.NewWriter(f)
+	header := []string{"CVE", "Affected Applications", "Applications", "Components", "Violations", "Max Threat", "KEV Listed"}
+	if err := w.Write(header); err != nil {
+		return fmt.Errorf("write header: %w", err)
+	}
+	for i, v := range summaries {
+		record := []string{
+			v.CVE,
+			strconv.Itoa(len(v.Applications)),
+			strings.Join(v.Applications, ", "),
+			strings.Join(v.Components, ", "),
+			strconv.Itoa(v.Violations),
+			strconv.Itoa(v.MaxThreat),
+			strconv.FormatBool(v.KEVListed),
+		}
+		if err := w.Write(record); err != nil {
+			return fmt.Errorf("write row %d: %w", i+1, err)
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("flush csv: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+// internal/report/xlsx.go
+package report
+
+import (
+	"archive/zip"
+	"bufio"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/rs/zerolog"
+)
+
+// XLSXPath returns the workbook location for the report at reportPath: the
+// report path with its extension replaced by ".xlsx".
+func XLSXPath(reportPath string) string {
+	return strings.TrimSuffix(reportPath, filepath.Ext(reportPath)) + ".xlsx"
+}
+
+// maxXLSXCell is the most characters Excel holds in a cell; longer cells,
+// e.g. condition text of components with many vulnerabilities, are
+// truncated.
+const maxXLSXCell = 32767
+
+// WriteXLSX writes the given rows into an Excel workbook at destPath,
+// atomically, with the columns of WriteCSV. The header row is bold and
+// frozen, and has auto-filters. Cells are written as text, so formula
+// escaping and byte order marks do not apply; the No. and Threat columns are
+// numbers where they hold one. It returns the path written, like WriteCSV.
+func WriteXLSX(ctx context.Context, destPath string, rows []Row, logger zerolog.Logger, opts ...CSVOption) (string, error) {
+	layout, err := newCSVLayout(opts)
+	if err != nil {
+		return "", err
+	}
+	return writeFileAtomic(ctx, destPath, logger, func(f io.Writer) error {
+		if err := writeXLSX(f, rows, layout); err != nil {
+			return err
+		}
+		logger.Debug().Int("rows", len(rows)).Msg("xlsx rows encoded")
+		return nil
+	})
+}
+
+// WriteXLSXTo writes rows as an Excel workbook to f with the columns of
+// WriteXLSX.
+func WriteXLSXTo(f io.Writer, rows []Row, opts ...CSVOption) error {
+	layout, err := newCSVLayout(opts)
+	if err != nil {
+		return err
+	}
+	return writeXLSX(f, rows, layout)
+}
+
+// xlsxStatic are the fixed parts of the workbook, by path in the archive.
+var xlsxStatic = []struct{ name, content string }{
+	{"[Content_Types].xml", xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`},
+	{"xl/_rels/workbook.xml.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+		`</Relationships>`},
+	// Style 1 is the bold header font
+	{"xl/styles.xml", xml.Header + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+		`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+		`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+		`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+		`<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/><xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/></cellXfs>` +
+		`</styleSheet>`},
+}
+
+// writeXLSX writes the header and rows as a single-sheet workbook to f.
+func writeXLSX(f io.Writer, rows []Row, layout *csvLayout) error {
+	zw := zip.NewWriter(f)
+	// A fixed modification time keeps the output of equal rows identical
+	modified := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	create := func(name string) (io.Writer, error) {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: modified})
+		if err != nil {
+			return nil, fmt.Errorf("create %s: %w", name, err)
+		}
+		return w, nil
+	}
+	for _, part := range xlsxStatic {
+		w, err := create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, part.content); err != nil {
+			return fmt.Errorf("write %s: %w", part.name, err)
+		}
+	}
+
+	lastColumn := xlsxColumn(len(layout.headers) - 1)
+	w, err := create("xl/workbook.xml")
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, xml.Header+`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">`+
+		`<sheets><sheet name="Violations" sheetId="1" r:id="rId1"/></sheets>`+
+		`<definedNames><definedName name="_xlnm._FilterDatabase" localSheetId="0" hidden="1">Violations!$A$1:$`+
+		lastColumn+`$`+strconv.Itoa(len(rows)+1)+`</definedName></definedNames>`+
+		`</workbook>`); err != nil {
+		return fmt.Errorf("write workbook: %w", err)
+	}
+
+	sw, err := create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(sw)
+	bw.WriteString(xml.Header + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	bw.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`)
+	bw.WriteString(`<sheetData>`)
+
+	numeric := make([]bool, len(layout.headers))
+	for i, h := range layout.headers {
+		numeric[i] = h == "No." || h == "Threat"
+	}
+	writeRow := func(n int, cells []string, style int) {
+		fmt.Fprintf(bw, `<row r="%d">`, n)
+		for j, cell := range cells {
+			ref := xlsxColumn(j) + strconv.Itoa(n)
+			if style == 0 && numeric[j] {
+				if _, err := strconv.Atoi(cell); err == nil {
+					fmt.Fprintf(bw, `<c r="%s"><v>%s</v></c>`, ref, cell)
+					continue
+				}
+			}
+			if style != 0 {
+				fmt.Fprintf(bw, `<c r="%s" s="%d" t="inlineStr"><is><t xml:space="preserve">`, ref, style)
+			} else {
+				fmt.Fprintf(bw, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">`, ref)
+			}
+			_ = xml.EscapeText(bw, []byte(truncateCell(cell)))
+			bw.WriteString(`</t></is></c>`)
+		}
+		bw.WriteString(`</row>`)
+	}
+
+	writeRow(1, layout.headers, 1)
+	for i, r := range rows {
+		rec := layout.record(i, r)
+		for j, cell := range rec {
+			if cell == "" {
+				rec[j] = layout.placeholders[j]
+			}
+		}
+		writeRow(i+2, rec, 0)
+	}
+	bw.WriteString(`</sheetData>`)
+	fmt.Fprintf(bw, `<autoFilter ref="A1:%s%d"/>`, lastColumn, len(rows)+1)
+	bw.WriteString(`</worksheet>`)
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("write sheet: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("close workbook: %w", err)
+	}
+	return nil
+}
+
+// truncateCell returns cell as valid UTF-8 of at most maxXLSXCell
+// characters.
+func truncateCell(cell string) string {
+	cell = strings.ToValidUTF8(cell, "\uFFFD")
+	if utf8.RuneCountInString(cell) <= maxXLSXCell {
+		return cell
+	}
+	return string([]rune(cell)[:maxXLSXCell])
+}
+
+// xlsxColumn returns the column letters of the zero-based column index i,
+// e.g. A, Z, AA.
+func xlsxColumn(i int) string {
+	var b []byte
+	for i++; i > 0; i = (i - 1) / 26 {
+		b = append([]byte{byte('A' + (i-1)%26)}, b...)
+	}
+	return string(b)
+}
@@ -0,0 +1,93 @@
+// internal/report/xlsx_test.go
+package report
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+// readZipFile returns the content of name in the archive at path.
+func readZipFile(t *testing.T, path, name string) string {
+	t.Helper()
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatalf("open workbook: %v", err)
+	}
+	defer zr.Close()
+	f, err := zr.Open(name)
+	if err != nil {
+		t.Fatalf("open %s: %v", name, err)
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatalf("read %s: %v", name, err)
+	}
+	return string(data)
+}
+
+func TestWriteXLSX(t *testing.T) {
+	dest := XLSXPath(filepath.Join(t.TempDir(), "report.csv"))
+	if filepath.Ext(dest) != ".xlsx" {
+		t.Fatalf("XLSXPath = %q", dest)
+	}
+	rows := []Row{
+		{Application: "web-app", Organization: "payments", Component: "a, b", Threat: 9, Condition: "Severity >= 7, <script>"},
+		{Application: "=cmd", Organization: "payments", Threat: 3},
+	}
+	if _, err := WriteXLSX(context.Background(), dest, rows, zerolog.New(io.Discard), WithEmptyValue("-"), WithFormulaEscaping(true)); err != nil {
+		t.Fatalf("WriteXLSX: %v", err)
+	}
+
+	lastColumn := xlsxColumn(len(CSVColumns()) - 1)
+	sheet := readZipFile(t, dest, "xl/worksheets/sheet1.xml")
+	if err := xml.Unmarshal([]byte(sheet), new(struct{})); err != nil {
+		t.Fatalf("sheet is not well-formed XML: %v", err)
+	}
+	for _, want := range []string{
+		`<pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/>`,
+		`<autoFilter ref="A1:` + lastColumn + `3"/>`,
+		`<c r="A1" s="1" t="inlineStr"><is><t xml:space="preserve">No.</t></is></c>`,
+		`<c r="G2"><v>9</v></c>`, // Threat as a number
+		`<t xml:space="preserve">a, b</t>`,
+		`<t xml:space="preserve">Severity &gt;= 7, &lt;script&gt;</t>`,
+		`<t xml:space="preserve">=cmd</t>`, // not escaped: inline strings are not evaluated
+		`<c r="C3" t="inlineStr"><is><t xml:space="preserve">payments</t></is></c>`,
+		`<c r="D3" t="inlineStr"><is><t xml:space="preserve">-</t></is></c>`, // placeholder
+	} {
+		if !strings.Contains(sheet, want) {
+			t.Errorf("sheet lacks %s", want)
+		}
+	}
+	if wb := readZipFile(t, dest, "xl/workbook.xml"); !strings.Contains(wb, "Violations!$A$1:$"+lastColumn+"$3") {
+		t.Errorf("workbook lacks filter range: %s", wb)
+	}
+
+	// Equal rows give identical workbooks
+	var a, b bytes.Buffer
+	if err := WriteXLSXTo(&a, rows); err != nil {
+		t.Fatalf("WriteXLSXTo: %v", err)
+	}
+	if err := WriteXLSXTo(&b, rows); err != nil {
+		t.Fatalf("WriteXLSXTo: %v", err)
+	}
+	if !bytes.Equal(a.Bytes(), b.Bytes()) {
+		t.Error("workbooks of equal rows differ")
+	}
+}
+
+func TestXLSXColumn(t *testing.T) {
+	for i, want := range map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"} {
+		if got := xlsxColumn(i); got != want {
+			t.Errorf("xlsxColumn(%d) = %q, want %q", i, got, want)
+		}
+	}
+}
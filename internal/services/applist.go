@@ -0,0 +1,41 @@
+// internal/services/applist.go
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/client"
+)
+
+// LoadApplicationList reads an application list snapshot previously written
+// by SaveApplicationList (or by "iqfetch list --json apps").
+func LoadApplicationList(path string) ([]client.Application, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read application list: %w", err)
+	}
+	var apps []client.Application
+	if err := json.Unmarshal(b, &apps); err != nil {
+		return nil, fmt.Errorf("decode application list %s: %w", path, err)
+	}
+	return apps, nil
+}
+
+// SaveApplicationList writes apps as a JSON snapshot to path so that later
+// runs can be pinned to exactly the same application set.
+func SaveApplicationList(path string, apps []client.Application) error {
+	b, err := json.MarshalIndent(apps, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode application list: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("prepare application list dir: %w", err)
+	}
+	if err := os.WriteFile(path, append(b, '\n'), 0o644); err != nil {
+		return fmt.Errorf("write application list: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+// internal/services/applist_test.go
+package services
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/client"
+)
+
+func TestApplicationList_SaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshots", "apps.json")
+	apps := []client.Application{
+		{ID: "aid-1", PublicID: "apid-1", OrganizationID: "org-1"},
+		{ID: "aid-2", PublicID: "apid-2", OrganizationID: "org-2", ApplicationTags: []client.ApplicationTag{{TagID: "tag-1"}}},
+	}
+
+	if err := SaveApplicationList(path, apps); err != nil {
+		t.Fatalf("SaveApplicationList: %v", err)
+	}
+	got, err := LoadApplicationList(path)
+	if err != nil {
+		t.Fatalf("LoadApplicationList: %v", err)
+	}
+	if len(got) != 2 || !reflect.DeepEqual(got[1], apps[1]) {
+		t.Errorf("unexpected list: %#v", got)
+	}
+}
+
+func TestLoadApplicationList_Missing(t *testing.T) {
+	if _, err := LoadApplicationList(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Fatal("expected error for missing snapshot")
+	}
+}
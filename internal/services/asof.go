@@ -0,0 +1,52 @@
+// internal/services/asof.go
+package services
+
+import (
+	"time"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/client"
+)
+
+// SetAsOf makes runs export, instead of the latest reports, the reports
+// that were latest at t: per stage the newest report of the report history
+// evaluated at or before t, e.g. to reproduce the state presented in a past
+// audit. Waivers and owners still reflect the current state. The zero time
+// exports the latest reports.
+func (s *IQReportService) SetAsOf(t time.Time) {
+	s.asOf = t
+}
+
+// reportsAsOf returns per stage the newest report of history evaluated at or
+// before asOf, in the order stages first appear in history. Reports with an
+// unparsable evaluation date are left out.
+func reportsAsOf(history []client.HistoricalReport, asOf time.Time) []client.ReportInfo {
+	var stages []string
+	newest := make(map[string]client.ReportInfo)
+	for _, h := range history {
+		t := evaluationTime(h.ReportInfo)
+		if t.IsZero() || t.After(asOf) {
+			continue
+		}
+		best, ok := newest[h.Stage]
+		if !ok {
+			stages = append(stages, h.Stage)
+		}
+		if !ok || t.After(evaluationTime(best)) {
+			newest[h.Stage] = h.ReportInfo
+		}
+	}
+	infos := make([]client.ReportInfo, 0, len(stages))
+	for _, stage := range stages {
+		infos = append(infos, newest[stage])
+	}
+	return infos
+}
+
+// asOfTime returns the time set with SetAsOf for the manifest, or nil.
+func (s *IQReportService) asOfTime() *time.Time {
+	if s.asOf.IsZero() {
+		return nil
+	}
+	t := s.asOf.UTC()
+	return &t
+}
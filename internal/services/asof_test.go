@@ -0,0 +1,97 @@
+// internal/services/asof_test.go
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/client"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/config"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/report"
+)
+
+func TestReportsAsOf(t *testing.T) {
+	history := []client.HistoricalReport{
+		{ReportInfo: client.ReportInfo{Stage: "build", EvaluationDate: "2024-07-02T10:00:00.000+0000", ReportHTMLURL: "http://iq/report/build-after"}},
+		{ReportInfo: client.ReportInfo{Stage: "release", EvaluationDate: "2024-06-01T10:00:00.000+0000", ReportHTMLURL: "http://iq/report/release"}},
+		{ReportInfo: client.ReportInfo{Stage: "build", EvaluationDate: "2024-06-30T09:00:00.000+0000", ReportHTMLURL: "http://iq/report/build-on"}},
+		{ReportInfo: client.ReportInfo{Stage: "build", EvaluationDate: "2024-05-15T10:00:00.000+0000", ReportHTMLURL: "http://iq/report/build-before"}},
+		{ReportInfo: client.ReportInfo{Stage: "operate", EvaluationDate: "2024-08-01T10:00:00.000+0000", ReportHTMLURL: "http://iq/report/operate"}},
+		{ReportInfo: client.ReportInfo{Stage: "source", EvaluationDate: "garbage", ReportHTMLURL: "http://iq/report/source"}},
+	}
+	asOf := time.Date(2024, 6, 30, 23, 59, 59, 0, time.UTC)
+
+	got := reportsAsOf(history, asOf)
+	var ids []string
+	for _, info := range got {
+		id, _ := info.ReportID()
+		ids = append(ids, id)
+	}
+	if want := "release,build-on"; strings.Join(ids, ",") != want {
+		t.Errorf("reports = %s, want %s", strings.Join(ids, ","), want)
+	}
+}
+
+func TestGenerateLatestPolicyReport_AsOf(t *testing.T) {
+	var latestRequested bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v2/applications":
+			_, _ = w.Write([]byte(`{"applications":[{"id":"aid-1","publicId":"app-1","organizationId":"org-1"}]}`))
+		case "/api/v2/organizations":
+			_, _ = w.Write([]byte(`{"organizations":[{"id":"org-1","name":"Org"}]}`))
+		case "/api/v2/reports/applications/aid-1":
+			latestRequested = true
+			_, _ = w.Write([]byte(`[]`))
+		case "/api/v2/reports/applications/aid-1/history":
+			_ = json.NewEncoder(w).Encode(map[string]any{"reports": []map[string]any{
+				{"stage": "build", "evaluationDate": "2024-07-05T10:00:00.000+0000", "reportHtmlUrl": "https://stub/report/r-new"},
+				{"stage": "build", "evaluationDate": "2024-06-20T10:00:00.000+0000", "reportHtmlUrl": "https://stub/report/r-old"},
+			}})
+		case "/api/v2/applications/app-1/reports/r-old/policy":
+			_, _ = w.Write([]byte(`{"components":[{"displayName":"old-lib","componentIdentifier":{"format":"maven"},"violations":[{"policyName":"Security-High","policyThreatLevel":9,"constraints":[{"constraintName":"High risk"}]}]}]}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	baseURL := srv.URL + "/api/v2"
+	iqClient, err := client.NewClient(baseURL, "u", "p", testLogger())
+	if err != nil {
+		t.Fatalf("client init: %v", err)
+	}
+	svc := NewIQReportService(&config.Config{IQServerURL: baseURL, OutputDir: t.TempDir()}, iqClient, testLogger())
+	asOf := time.Date(2024, 6, 30, 23, 59, 59, 0, time.UTC)
+	svc.SetAsOf(asOf)
+
+	path, err := svc.GenerateLatestPolicyReport(rCtx(t), "report.csv")
+	if err != nil {
+		t.Fatalf("GenerateLatestPolicyReport: %v", err)
+	}
+	if latestRequested {
+		t.Error("latest report infos requested despite SetAsOf")
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "old-lib") {
+		t.Errorf("report does not hold the violations of the report as of %s:\n%s", asOf, b)
+	}
+	m, err := report.ReadManifest(report.ManifestPath(filepath.Join(filepath.Dir(path), "report.csv")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.AsOf == nil || !m.AsOf.Equal(asOf) {
+		t.Errorf("manifest asOf = %v, want %s", m.AsOf, asOf)
+	}
+}
@@ -0,0 +1,164 @@
+// internal/services/blackout.go
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrBlackout is returned when a run is not started because it falls into
+// a blackout window and BLACKOUT_ACTION is "skip".
+var ErrBlackout = errors.New("blackout window")
+
+// maxBlackout bounds the duration of a blackout window.
+const maxBlackout = 7 * 24 * time.Hour
+
+// BlackoutWindow is a recurring period during which runs should not hit IQ
+// Server, e.g. while it is backed up.
+type BlackoutWindow struct {
+	spec     string
+	fields   [5]cronField // minute, hour, day of month, month, day of week
+	duration time.Duration
+}
+
+// String returns the window as configured.
+func (w BlackoutWindow) String() string { return w.spec }
+
+// cronField is the set of values a cron field matches; all is set for "*".
+type cronField struct {
+	values map[int]bool
+	all    bool
+}
+
+// cronRanges are the value ranges of the five cron fields. Day of week 7 is
+// Sunday, like 0.
+var cronRanges = [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+
+// ParseBlackoutWindows parses blackout windows given as a cron expression
+// for their start followed by their duration, e.g. "0 2 * * * 1h" for 02:00
+// to 03:00 every day or "30 22 * * 6 90m" for Saturdays from 22:30. Cron
+// expressions have the five standard fields (minute, hour, day of month,
+// month, day of week) with "*", values, ranges, lists and steps, and are
+// evaluated in local time.
+func ParseBlackoutWindows(specs []string) ([]BlackoutWindow, error) {
+	var windows []BlackoutWindow
+	for _, spec := range specs {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+		w, err := parseBlackoutWindow(spec)
+		if err != nil {
+			return nil, fmt.Errorf("blackout window %q: %w", spec, err)
+		}
+		windows = append(windows, w)
+	}
+	return windows, nil
+}
+
+func parseBlackoutWindow(spec string) (BlackoutWindow, error) {
+	parts := strings.Fields(spec)
+	if len(parts) != 6 {
+		return BlackoutWindow{}, fmt.Errorf("expected five cron fields and a duration, got %d fields", len(parts))
+	}
+	w := BlackoutWindow{spec: spec}
+	for i, part := range parts[:5] {
+		f, err := parseCronField(part, cronRanges[i][0], cronRanges[i][1])
+		if err != nil {
+			return BlackoutWindow{}, err
+		}
+		w.fields[i] = f
+	}
+	if w.fields[4].values[7] {
+		w.fields[4].values[0] = true
+	}
+	d, err := time.ParseDuration(parts[5])
+	if err != nil {
+		return BlackoutWindow{}, fmt.Errorf("invalid duration: %w", err)
+	}
+	if d < time.Minute || d > maxBlackout {
+		return BlackoutWindow{}, fmt.Errorf("duration %s is not between 1m and %s", d, maxBlackout)
+	}
+	w.duration = d
+	return w, nil
+}
+
+// parseCronField parses one cron field with values from lo to hi.
+func parseCronField(field string, lo, hi int) (cronField, error) {
+	if field == "*" {
+		return cronField{all: true}, nil
+	}
+	f := cronField{values: make(map[int]bool)}
+	for _, item := range strings.Split(field, ",") {
+		rng, stepText, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepText); err != nil || step < 1 {
+				return cronField{}, fmt.Errorf("invalid step in %q", item)
+			}
+		}
+		from, to := lo, hi
+		if rng != "*" {
+			a, b, isRange := strings.Cut(rng, "-")
+			var err error
+			if from, err = strconv.Atoi(a); err != nil {
+				return cronField{}, fmt.Errorf("invalid value in %q", item)
+			}
+			to = from
+			if isRange {
+				if to, err = strconv.Atoi(b); err != nil {
+					return cronField{}, fmt.Errorf("invalid range in %q", item)
+				}
+			} else if hasStep {
+				to = hi
+			}
+		}
+		if from < lo || to > hi || from > to {
+			return cronField{}, fmt.Errorf("%q is outside %d-%d", item, lo, hi)
+		}
+		for v := from; v <= to; v += step {
+			f.values[v] = true
+		}
+	}
+	return f, nil
+}
+
+func (f cronField) match(v int) bool { return f.all || f.values[v] }
+
+// starts reports whether the window starts at the minute of t. Like cron,
+// a time matches when either day field matches if both are restricted.
+func (w BlackoutWindow) starts(t time.Time) bool {
+	minute, hour, dom, month, dow := w.fields[0], w.fields[1], w.fields[2], w.fields[3], w.fields[4]
+	if !minute.match(t.Minute()) || !hour.match(t.Hour()) || !month.match(int(t.Month())) {
+		return false
+	}
+	if !dom.all && !dow.all {
+		return dom.match(t.Day()) || dow.match(int(t.Weekday()))
+	}
+	return dom.match(t.Day()) && dow.match(int(t.Weekday()))
+}
+
+// Active reports whether t falls into the window, and when the window ends.
+func (w BlackoutWindow) Active(t time.Time) (time.Time, bool) {
+	for start := t.Truncate(time.Minute); t.Sub(start) < w.duration; start = start.Add(-time.Minute) {
+		if w.starts(start) {
+			return start.Add(w.duration), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// ActiveBlackout returns the first of windows that t falls into and when it
+// ends.
+func ActiveBlackout(windows []BlackoutWindow, t time.Time) (BlackoutWindow, time.Time, bool) {
+	for _, w := range windows {
+		if end, ok := w.Active(t); ok {
+			return w, end, true
+		}
+	}
+	return BlackoutWindow{}, time.Time{}, false
+}
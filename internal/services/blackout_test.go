@@ -0,0 +1,59 @@
+// internal/services/blackout_test.go
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseBlackoutWindows_Invalid(t *testing.T) {
+	for _, spec := range []string{
+		"0 2 * * *",          // no duration
+		"0 2 * * * 1h extra", // too many fields
+		"60 2 * * * 1h",      // minute out of range
+		"0 2-1 * * * 1h",     // reversed range
+		"*/0 2 * * * 1h",     // zero step
+		"0 2 * * * soon",     // invalid duration
+		"0 2 * * * 30s",      // shorter than a minute
+		"0 2 * * * 200h",     // longer than a week
+	} {
+		if _, err := ParseBlackoutWindows([]string{spec}); err == nil {
+			t.Errorf("ParseBlackoutWindows(%q) succeeded", spec)
+		}
+	}
+}
+
+func TestBlackoutWindow_Active(t *testing.T) {
+	windows, err := ParseBlackoutWindows([]string{"0 2 * * * 1h", " ", "30 22 * * 6,7 90m", "*/15 8-9 1 1 * 5m"})
+	if err != nil {
+		t.Fatalf("ParseBlackoutWindows: %v", err)
+	}
+	if len(windows) != 3 {
+		t.Fatalf("got %d windows, want 3", len(windows))
+	}
+
+	at := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2025, month, day, hour, minute, 30, 0, time.UTC) // 2025-03-01 is a Saturday
+	}
+	tests := []struct {
+		t      time.Time
+		window string
+		end    time.Time
+	}{
+		{at(3, 1, 1, 59), "", time.Time{}},
+		{at(3, 1, 2, 0), "0 2 * * * 1h", at(3, 1, 3, 0).Truncate(time.Minute)},
+		{at(3, 1, 2, 59), "0 2 * * * 1h", at(3, 1, 3, 0).Truncate(time.Minute)},
+		{at(3, 1, 3, 0), "", time.Time{}},
+		{at(3, 1, 23, 45), "30 22 * * 6,7 90m", at(3, 2, 0, 0).Truncate(time.Minute)},
+		{at(3, 2, 23, 0), "30 22 * * 6,7 90m", at(3, 3, 0, 0).Truncate(time.Minute)}, // Sunday as 7
+		{at(3, 3, 23, 0), "", time.Time{}},
+		{at(1, 1, 9, 47), "*/15 8-9 1 1 * 5m", at(1, 1, 9, 50).Truncate(time.Minute)},
+		{at(1, 1, 9, 52), "", time.Time{}},
+	}
+	for _, tt := range tests {
+		w, end, ok := ActiveBlackout(windows, tt.t)
+		if ok != (tt.window != "") || w.String() != tt.window || !end.Equal(tt.end) {
+			t.Errorf("ActiveBlackout(%s) = %q until %s, %v; want %q until %s", tt.t, w, end, ok, tt.window, tt.end)
+		}
+	}
+}
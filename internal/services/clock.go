@@ -0,0 +1,38 @@
+// internal/services/clock.go
+package services
+
+import "time"
+
+// Clock tells the time of a run. The service reads it once at the start of
+// a run, so that the manifest, date placeholders, suppression expiry and SLA
+// ages all use the same instant even when a run crosses midnight.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the wall clock, used unless SetClock is called.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// FixedClock is a Clock frozen at a point in time, for tests and for
+// reproducing a past run.
+type FixedClock time.Time
+
+// Now returns the frozen time.
+func (c FixedClock) Now() time.Time { return time.Time(c) }
+
+// SetClock makes the service read the time from c.
+func (s *IQReportService) SetClock(c Clock) {
+	s.clock = c
+}
+
+// now returns the current time of the service's clock.
+func (s *IQReportService) now() time.Time {
+	if s.clock == nil {
+		return SystemClock.Now()
+	}
+	return s.clock.Now()
+}
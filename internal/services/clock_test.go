@@ -0,0 +1,67 @@
+// internal/services/clock_test.go
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/client"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/config"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/report"
+)
+
+func TestGenerateLatestPolicyReport_FixedClock(t *testing.T) {
+	server := faultServer(t, 2)
+	iqClient, err := client.NewClient(server.URL, "u", "p", testLogger())
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	// Just before midnight west of UTC: the manifest is stamped in UTC (the
+	// next day) while the date placeholder uses the clock's local date
+	zone := time.FixedZone("UTC-3", -3*60*60)
+	frozen := time.Date(2025, 3, 8, 23, 59, 59, 0, zone)
+
+	tmpDir := t.TempDir()
+	svc := NewIQReportService(&config.Config{OutputDir: tmpDir, OutputLayout: "{{date}}/{{app}}.csv"}, iqClient, testLogger())
+	svc.SetClock(FixedClock(frozen))
+	var events bytes.Buffer
+	svc.SetProgressWriter(&events)
+
+	path, err := svc.GenerateLatestPolicyReport(rCtx(t), "report.csv")
+	if err != nil {
+		t.Fatalf("GenerateLatestPolicyReport: %v", err)
+	}
+
+	b, err := os.ReadFile(report.ManifestPath(path))
+	if err != nil {
+		t.Fatalf("read manifest: %v", err)
+	}
+	var manifest report.Manifest
+	if err := json.Unmarshal(b, &manifest); err != nil {
+		t.Fatalf("decode manifest: %v", err)
+	}
+	if !manifest.GeneratedAt.Equal(frozen) || manifest.GeneratedAt.Location() != time.UTC {
+		t.Errorf("GeneratedAt = %v, want %v in UTC", manifest.GeneratedAt, frozen)
+	}
+
+	// The date placeholder uses the clock's date
+	if _, err := os.Stat(filepath.Join(tmpDir, "2025-03-08", "app-0.csv")); err != nil {
+		t.Errorf("per-application report not written under the frozen date: %v", err)
+	}
+
+	dec := json.NewDecoder(&events)
+	for dec.More() {
+		var ev ProgressEvent
+		if err := dec.Decode(&ev); err != nil {
+			t.Fatalf("decode event: %v", err)
+		}
+		if !ev.Time.Equal(frozen) {
+			t.Errorf("%s event time = %v, want %v", ev.Event, ev.Time, frozen)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+// internal/services/cputime_other.go
+
+//go:build !unix && !windows
+
+package services
+
+import (
+	"errors"
+	"time"
+)
+
+// processCPUTime is not supported on this platform.
+func processCPUTime() (time.Duration, error) {
+	return 0, errors.ErrUnsupported
+}
@@ -0,0 +1,46 @@
+// internal/services/deadline.go
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrPhaseTimeout reports that a phase of the run exceeded its deadline.
+var ErrPhaseTimeout = errors.New("phase deadline exceeded")
+
+// runWithDeadline runs fn and waits at most d for it to return; zero waits
+// indefinitely. When the deadline expires, the context passed to fn is
+// cancelled and the run no longer waits for it: the error wraps
+// ErrPhaseTimeout. fn may still be running then, e.g. blocked in a file
+// write that cannot be interrupted, so it must only change state through
+// its result, which is dropped, and stop writing outputs once its context
+// is done.
+func runWithDeadline[T any](ctx context.Context, phase string, d time.Duration, fn func(ctx context.Context) (T, error)) (T, error) {
+	if d <= 0 {
+		return fn(ctx)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	type result struct {
+		v   T
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		v, err := fn(ctx)
+		done <- result{v, err}
+	}()
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case r := <-done:
+		return r.v, r.err
+	case <-timer.C:
+		var zero T
+		return zero, fmt.Errorf("%s phase did not finish within %s: %w", phase, d, ErrPhaseTimeout)
+	}
+}
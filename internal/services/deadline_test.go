@@ -0,0 +1,130 @@
+// internal/services/deadline_test.go
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/client"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/config"
+)
+
+func TestRunWithDeadline(t *testing.T) {
+	boom := errors.New("boom")
+	fail := func(context.Context) (int, error) { return 0, boom }
+	if _, err := runWithDeadline(context.Background(), "aggregate", time.Second, fail); err != boom {
+		t.Errorf("fast phase: err = %v, want %v", err, boom)
+	}
+	succeed := func(context.Context) (int, error) { return 42, nil }
+	if v, err := runWithDeadline(context.Background(), "aggregate", 0, succeed); err != nil || v != 42 {
+		t.Errorf("no deadline: v, err = %d, %v", v, err)
+	}
+
+	release := make(chan struct{})
+	defer close(release)
+	cancelled := make(chan struct{})
+	start := time.Now()
+	_, err := runWithDeadline(context.Background(), "aggregate", 20*time.Millisecond, func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		close(cancelled)
+		<-release // a stuck writer
+		return 0, nil
+	})
+	if !errors.Is(err, ErrPhaseTimeout) {
+		t.Fatalf("stuck phase: err = %v, want ErrPhaseTimeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("stuck phase returned after %s", elapsed)
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Error("phase context not cancelled at the deadline")
+	}
+}
+
+func TestGenerateLatestPolicyReport_AggregationTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v2/applications":
+			_, _ = w.Write([]byte(`{"applications": [{"id": "aid-1", "publicId": "app-1"}]}`))
+		case "/api/v2/organizations":
+			_, _ = w.Write([]byte(`{"organizations": []}`))
+		case "/api/v2/reports/applications/aid-1":
+			_, _ = w.Write([]byte(`[{"stage": "build", "reportHtmlUrl": "https://stub/report/rpt-1"}]`))
+		case "/api/v2/applications/app-1/reports/rpt-1/policy":
+			_, _ = w.Write([]byte(`{"components": [
+				{"displayName": "a", "violations": [{"policyName": "P", "policyThreatLevel": 9, "constraints": [{"constraintName": "C"}]}]}
+			]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	iqClient, _ := client.NewClient(server.URL, "u", "p", testLogger())
+	opts := OptionsFromConfig(&config.Config{OutputDir: dir})
+	opts.AggregationTimeout = time.Microsecond
+	svc := NewIQReportServiceWithOptions(opts, client.NewPool(iqClient), testLogger())
+
+	if _, err := svc.GenerateLatestPolicyReport(rCtx(t), "report.csv"); !errors.Is(err, ErrPhaseTimeout) {
+		t.Fatalf("GenerateLatestPolicyReport error = %v, want ErrPhaseTimeout", err)
+	}
+	if last := svc.LastRun(); last.Status != "failed" {
+		t.Errorf("last run status = %q, want failed", last.Status)
+	}
+
+	// The abandoned phase stops writing: nothing of the failed run is
+	// published, and its temporary files are removed
+	time.Sleep(300 * time.Millisecond)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		t.Errorf("file %s written after the aggregation deadline", e.Name())
+	}
+}
+
+func TestGenerateLatestPolicyReport_FetchDeadlinePropagates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v2/applications":
+			_, _ = w.Write([]byte(`{"applications": [{"id": "aid-1", "publicId": "apid-1", "organizationId": "org-1"}]}`))
+		case "/api/v2/organizations":
+			_, _ = w.Write([]byte(`{"organizations": [{"id": "org-1", "name": "personal"}]}`))
+		default:
+			<-r.Context().Done() // report endpoints hang until the client gives up
+		}
+	}))
+	defer server.Close()
+
+	iqClient, err := client.NewClient(server.URL, "u", "p", testLogger())
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	svc := NewIQReportService(&config.Config{OutputDir: t.TempDir()}, iqClient, testLogger())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := svc.GenerateLatestPolicyReport(ctx, "report.csv"); err == nil {
+		t.Fatal("expected error when the fetch deadline expires")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("run returned %s after the fetch deadline", elapsed)
+	}
+
+	phases := svc.LastRun().PhasesMS
+	if _, ok := phases["list"]; !ok {
+		t.Errorf("phases = %v, want list phase recorded", phases)
+	}
+}
@@ -0,0 +1,70 @@
+// internal/services/dedupe.go
+package services
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/report"
+)
+
+// violationFetches memoizes policy violation fetches within a run, keyed on
+// application public ID and report ID, so that a report referenced several
+// times (e.g. by more than one stage) is downloaded once. Concurrent callers
+// for the same key wait for the in-flight fetch. Failed fetches are not
+// remembered: waiting callers and later callers fetch again, so that a
+// transient error does not fail every reference to the report.
+type violationFetches struct {
+	mu     sync.Mutex
+	calls  map[string]*violationFetch
+	reused atomic.Int64
+}
+
+type violationFetch struct {
+	done chan struct{}
+	rows []report.Row
+	err  error
+}
+
+func newViolationFetches() *violationFetches {
+	return &violationFetches{calls: make(map[string]*violationFetch)}
+}
+
+// get returns the rows for publicID and reportID, calling fetch unless an
+// earlier or in-flight call for the same key succeeded. The returned slice
+// is a copy that the caller may modify.
+func (v *violationFetches) get(publicID, reportID string, fetch func() ([]report.Row, error)) ([]report.Row, error) {
+	key := publicID + "/" + reportID
+	for {
+		v.mu.Lock()
+		call, ok := v.calls[key]
+		if !ok {
+			call = &violationFetch{done: make(chan struct{})}
+			v.calls[key] = call
+			v.mu.Unlock()
+
+			call.rows, call.err = fetch()
+			if call.err != nil {
+				v.mu.Lock()
+				delete(v.calls, key)
+				v.mu.Unlock()
+			}
+			close(call.done)
+			return slices.Clone(call.rows), call.err
+		}
+		v.mu.Unlock()
+
+		<-call.done
+		if call.err == nil {
+			v.reused.Add(1)
+			return slices.Clone(call.rows), nil
+		}
+		// The shared fetch failed; try again.
+	}
+}
+
+// Reused returns the number of fetches served from memory.
+func (v *violationFetches) Reused() int64 {
+	return v.reused.Load()
+}
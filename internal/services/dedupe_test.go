@@ -0,0 +1,73 @@
+// internal/services/dedupe_test.go
+package services
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/report"
+)
+
+func TestViolationFetches_Deduplicates(t *testing.T) {
+	fetches := newViolationFetches()
+	var calls atomic.Int32
+	release := make(chan struct{})
+	fetch := func() ([]report.Row, error) {
+		calls.Add(1)
+		<-release
+		return []report.Row{{Application: "app-a"}}, nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([][]report.Row, 5)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _ = fetches.get("app-a", "r1", fetch)
+		}()
+	}
+	close(release)
+	wg.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("fetch called %d times, want 1", got)
+	}
+	if fetches.Reused() != 4 {
+		t.Errorf("Reused() = %d, want 4", fetches.Reused())
+	}
+	// Callers get independent copies
+	results[0][0].Stage = "build"
+	if results[1][0].Stage != "" {
+		t.Error("rows are shared between callers")
+	}
+
+	// A different report ID is fetched separately
+	release = make(chan struct{})
+	close(release)
+	if _, err := fetches.get("app-a", "r2", fetch); err != nil || calls.Load() != 2 {
+		t.Errorf("err=%v calls=%d, want separate fetch", err, calls.Load())
+	}
+}
+
+func TestViolationFetches_RetriesAfterError(t *testing.T) {
+	fetches := newViolationFetches()
+	calls := 0
+	fetch := func() ([]report.Row, error) {
+		calls++
+		if calls == 1 {
+			return nil, errors.New("timeout")
+		}
+		return []report.Row{{Application: "app-a"}}, nil
+	}
+
+	if _, err := fetches.get("app-a", "r1", fetch); err == nil {
+		t.Fatal("expected error from first fetch")
+	}
+	rows, err := fetches.get("app-a", "r1", fetch)
+	if err != nil || len(rows) != 1 || calls != 2 {
+		t.Errorf("rows=%v err=%v calls=%d, want refetch after error", rows, err, calls)
+	}
+}
@@ -0,0 +1,46 @@
+// internal/services/diskspace.go
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/config"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/report"
+)
+
+// ErrInsufficientDiskSpace is returned before fetching when the output
+// directory lacks the space the run is estimated to need and
+// DISK_SPACE_CHECK is "fail".
+var ErrInsufficientDiskSpace = errors.New("insufficient disk space")
+
+// checkDiskSpace compares the free space of the output directory with the
+// estimated output of a run over apps applications, so that a run fails
+// early instead of while writing to a full share. A free space that cannot
+// be determined is logged and does not fail the run.
+func (s *IQReportService) checkDiskSpace(apps int) error {
+	if s.opts.DiskSpaceCheck == config.DiskSpaceOff {
+		return nil
+	}
+	previous, err := report.LatestShardManifest(s.opts.OutputDir, s.shard())
+	if err != nil {
+		s.logger.Warn().Err(err).Msg("Could not read previous run manifest")
+	}
+	need := report.EstimateOutputBytes(previous, apps)
+	free, err := report.FreeSpace(s.opts.OutputDir)
+	if err != nil {
+		s.logger.Warn().Err(err).Msg("Could not determine free disk space; skipping disk space check")
+		return nil
+	}
+	s.logger.Debug().Uint64("free", free).Uint64("estimated", need).Msg("Checked disk space of output directory")
+	if free >= need {
+		return nil
+	}
+	err = fmt.Errorf("%w: %s free in output directory %s, the run needs about %s (set DISK_SPACE_CHECK=warn to proceed anyway)",
+		ErrInsufficientDiskSpace, report.FormatBytes(free), s.opts.OutputDir, report.FormatBytes(need))
+	if s.opts.DiskSpaceCheck == config.DiskSpaceWarn {
+		s.logger.Warn().Err(err).Msg("Output directory may run out of space")
+		return nil
+	}
+	return err
+}
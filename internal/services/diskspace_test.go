@@ -0,0 +1,56 @@
+// internal/services/diskspace_test.go
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/client"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/config"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/report"
+)
+
+func TestGenerateLatestPolicyReport_DiskSpaceCheck(t *testing.T) {
+	server := faultServer(t, 2)
+	iqClient, err := client.NewClient(server.URL, "u", "p", testLogger())
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	// A previous report far larger than any disk makes the estimate exceed
+	// the free space; the file is sparse, so it takes no space itself
+	tmpDir := t.TempDir()
+	previous := filepath.Join(tmpDir, "previous.csv")
+	f, err := os.Create(previous)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Truncate(1 << 42); err != nil {
+		f.Close()
+		t.Skipf("sparse file not supported: %v", err)
+	}
+	f.Close()
+	m := report.Manifest{ReportPath: previous, GeneratedAt: time.Now().UTC(), Applications: 1}
+	if err := report.WriteManifest(context.Background(), report.ManifestPath(previous), m, testLogger()); err != nil {
+		t.Fatal(err)
+	}
+
+	svc := NewIQReportService(&config.Config{OutputDir: tmpDir}, iqClient, testLogger())
+	if _, err := svc.GenerateLatestPolicyReport(rCtx(t), "report.csv"); !errors.Is(err, ErrInsufficientDiskSpace) {
+		t.Fatalf("expected insufficient disk space, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "report.csv")); !os.IsNotExist(err) {
+		t.Errorf("report written despite failed disk space check: %v", err)
+	}
+
+	for _, check := range []string{config.DiskSpaceWarn, config.DiskSpaceOff} {
+		svc := NewIQReportService(&config.Config{OutputDir: tmpDir, DiskSpaceCheck: check}, iqClient, testLogger())
+		if _, err := svc.GenerateLatestPolicyReport(rCtx(t), "report-"+check+".csv"); err != nil {
+			t.Errorf("DISK_SPACE_CHECK=%s: %v", check, err)
+		}
+	}
+}
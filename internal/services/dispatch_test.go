@@ -0,0 +1,74 @@
+// internal/services/dispatch_test.go
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/client"
+)
+
+func TestInterleaveByOrganization(t *testing.T) {
+	apps := []client.Application{
+		{PublicID: "big-1", OrganizationID: "big"},
+		{PublicID: "big-2", OrganizationID: "big"},
+		{PublicID: "big-3", OrganizationID: "big"},
+		{PublicID: "small-1", OrganizationID: "small"},
+		{PublicID: "big-4", OrganizationID: "big"},
+		{PublicID: "other-1", OrganizationID: "other"},
+		{PublicID: "small-2", OrganizationID: "small"},
+	}
+	var got []string
+	for _, app := range interleaveByOrganization(apps) {
+		got = append(got, app.PublicID)
+	}
+	want := []string{"big-1", "small-1", "other-1", "big-2", "small-2", "big-3", "big-4"}
+	if !slices.Equal(got, want) {
+		t.Errorf("interleaveByOrganization = %v, want %v", got, want)
+	}
+}
+
+func TestFetchApplications_FairAcrossOrganizations(t *testing.T) {
+	var mu sync.Mutex
+	var order []string // applications in the order their reports were requested
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := strings.CutPrefix(r.URL.Path, "/api/v2/reports/applications/"); ok {
+			mu.Lock()
+			order = append(order, id)
+			mu.Unlock()
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+	iqClient, err := client.NewClient(server.URL, "u", "p", testLogger())
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	// A large organization listed first and a small one listed last
+	var apps []client.Application
+	for i := range 6 {
+		id := fmt.Sprintf("big-%d", i)
+		apps = append(apps, client.Application{ID: id, PublicID: id, OrganizationID: "org-big"})
+	}
+	apps = append(apps, client.Application{ID: "small-0", PublicID: "small-0", OrganizationID: "org-small"})
+
+	// With one worker, applications are processed in dispatch order
+	svc := NewIQReportServiceWithOptions(ServiceOptions{Concurrency: 1}, client.NewPool(iqClient), testLogger())
+	results := 0
+	for range svc.fetchApplications(rCtx(t), apps, nil, nil, nil, newViolationFetches()) {
+		results++
+	}
+	if results != len(apps) {
+		t.Fatalf("got %d results, want %d", results, len(apps))
+	}
+	if want := []string{"big-0", "small-0", "big-1"}; !slices.Equal(order[:3], want) {
+		t.Errorf("dispatch order = %v, want it to start with %v", order, want)
+	}
+}
@@ -0,0 +1,50 @@
+// internal/services/disposition.go
+package services
+
+import (
+	"sort"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/report"
+)
+
+// newDisposition returns the disposition of an application result. The
+// disposition of an application with fetched rows depends on the filters
+// and is left empty for finishDispositions.
+func newDisposition(res AppReportResult) report.AppDisposition {
+	d := report.AppDisposition{Application: res.Application, Organization: res.Organization, FetchedRows: len(res.Rows)}
+	switch {
+	case res.Skipped == SkipRemoved:
+		d.Disposition = report.DispositionRemoved
+	case res.Err != nil:
+		d.Disposition, d.Error = report.DispositionFailed, res.Err.Error()
+	case len(res.Scan.Stages) == 0:
+		d.Disposition = report.DispositionNoReport
+	case len(res.Rows) == 0:
+		d.Disposition = report.DispositionEmpty
+	}
+	if !res.Scan.ScanDate.IsZero() {
+		t := res.Scan.ScanDate.UTC()
+		d.ScanDate = &t
+	}
+	return d
+}
+
+// finishDispositions counts the report rows of each application and sets
+// the remaining dispositions: exported when rows are left, filtered when
+// filters and suppressions removed them all. ds is sorted by application.
+func finishDispositions(ds []report.AppDisposition, rows []report.Row) {
+	counts := make(map[string]int)
+	for _, r := range rows {
+		counts[r.Application]++
+	}
+	for i := range ds {
+		ds[i].Rows = counts[ds[i].Application]
+		if ds[i].Disposition == "" {
+			ds[i].Disposition = report.DispositionFiltered
+			if ds[i].Rows > 0 {
+				ds[i].Disposition = report.DispositionExported
+			}
+		}
+	}
+	sort.Slice(ds, func(i, j int) bool { return ds[i].Application < ds[j].Application })
+}
@@ -0,0 +1,96 @@
+// internal/services/disposition_test.go
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/client"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/config"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/report"
+)
+
+func TestGenerateLatestPolicyReport_Dispositions(t *testing.T) {
+	const violation = `{"components": [{"displayName": "lib 1.0", "componentIdentifier": {"format": "maven"}, "violations": [{"policyName": "Security-High", "policyThreatLevel": 9, "constraints": [{"constraintName": "CVSS >= 7", "conditions": [{"conditionSummary": "CVE-2024-0001"}]}]}]}]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/api/v2/applications":
+			_, _ = w.Write([]byte(`{"applications": [
+				{"id": "aid-exported", "publicId": "exported", "organizationId": "org-1"},
+				{"id": "aid-filtered", "publicId": "filtered", "organizationId": "org-1"},
+				{"id": "aid-noreport", "publicId": "noreport", "organizationId": "org-1"},
+				{"id": "aid-empty", "publicId": "empty", "organizationId": "org-1"},
+				{"id": "aid-failing", "publicId": "failing", "organizationId": "org-1"}]}`))
+		case r.URL.Path == "/api/v2/organizations":
+			_, _ = w.Write([]byte(`{"organizations": [{"id": "org-1", "name": "Org"}]}`))
+		case r.URL.Path == "/api/v2/reports/applications/aid-noreport":
+			_, _ = w.Write([]byte(`[]`))
+		case r.URL.Path == "/api/v2/reports/applications/aid-failing":
+			http.Error(w, "boom", http.StatusInternalServerError)
+		case strings.HasPrefix(r.URL.Path, "/api/v2/reports/applications/"):
+			id := strings.TrimPrefix(r.URL.Path, "/api/v2/reports/applications/aid-")
+			_, _ = w.Write([]byte(`[{"stage": "build", "evaluationDate": "2025-03-01T10:00:00.000+0000", "reportDataUrl": "api/v2/applications/` + id + `/reports/rpt-1"}]`))
+		case r.URL.Path == "/api/v2/applications/empty/reports/rpt-1/policy":
+			_, _ = w.Write([]byte(`{"components": []}`))
+		case strings.HasSuffix(r.URL.Path, "/policy"):
+			_, _ = w.Write([]byte(violation))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	iqClient, err := client.NewClient(server.URL, "u", "p", testLogger())
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	svc := NewIQReportService(&config.Config{OutputDir: t.TempDir(), Filter: `Application != "filtered"`}, iqClient, testLogger())
+	path, err := svc.GenerateLatestPolicyReport(rCtx(t), "report.csv")
+	if err == nil || path == "" {
+		t.Fatalf("GenerateLatestPolicyReport = %q, %v; want the report path and an error", path, err)
+	}
+
+	b, err := os.ReadFile(report.ManifestPath(path))
+	if err != nil {
+		t.Fatalf("read manifest: %v", err)
+	}
+	var manifest report.Manifest
+	if err := json.Unmarshal(b, &manifest); err != nil {
+		t.Fatalf("decode manifest: %v", err)
+	}
+	want := map[string]struct {
+		disposition  string
+		fetched, out int
+	}{
+		"empty":    {report.DispositionEmpty, 0, 0},
+		"exported": {report.DispositionExported, 1, 1},
+		"failing":  {report.DispositionFailed, 0, 0},
+		"filtered": {report.DispositionFiltered, 1, 0},
+		"noreport": {report.DispositionNoReport, 0, 0},
+	}
+	if len(manifest.Dispositions) != len(want) {
+		t.Fatalf("dispositions = %+v", manifest.Dispositions)
+	}
+	for i, d := range manifest.Dispositions {
+		if i > 0 && manifest.Dispositions[i-1].Application > d.Application {
+			t.Errorf("dispositions not sorted by application: %+v", manifest.Dispositions)
+		}
+		w := want[d.Application]
+		if d.Disposition != w.disposition || d.FetchedRows != w.fetched || d.Rows != w.out || d.Organization != "Org" {
+			t.Errorf("disposition of %s = %+v, want %+v", d.Application, d, w)
+		}
+		if (d.Error != "") != (d.Disposition == report.DispositionFailed) {
+			t.Errorf("disposition of %s has error %q", d.Application, d.Error)
+		}
+	}
+
+	stats := svc.LastRun()
+	if stats.Dispositions[report.DispositionExported] != 1 || stats.Dispositions[report.DispositionFailed] != 1 {
+		t.Errorf("run dispositions = %v", stats.Dispositions)
+	}
+}
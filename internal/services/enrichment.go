@@ -0,0 +1,161 @@
+// internal/services/enrichment.go
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/client"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/report"
+)
+
+// OwnerSnapshotName is the file name of the owner snapshot in the
+// enrichment directory.
+const OwnerSnapshotName = "owners.json"
+
+// OwnerSnapshot is the owner mapping of all applications at one time, read
+// instead of fetching owners when ServiceOptions.EnrichmentDir is set.
+type OwnerSnapshot struct {
+	GeneratedAt time.Time `json:"generatedAt"`
+	Role        string    `json:"role"`
+	// Applications maps public IDs to owners; applications without
+	// owners have empty entries.
+	Applications map[string]ApplicationOwners `json:"applications"`
+}
+
+// ApplicationOwners are the owners of an application as written to the
+// OwnerName and OwnerEmail columns.
+type ApplicationOwners struct {
+	Names  string `json:"names,omitempty"`
+	Emails string `json:"emails,omitempty"`
+}
+
+// ReadOwnerSnapshot reads the owner snapshot of the enrichment directory
+// dir.
+func ReadOwnerSnapshot(dir string) (*OwnerSnapshot, error) {
+	data, err := os.ReadFile(filepath.Join(dir, OwnerSnapshotName))
+	if err != nil {
+		return nil, fmt.Errorf("read owner snapshot: %w", err)
+	}
+	var snap OwnerSnapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("parse owner snapshot: %w", err)
+	}
+	if snap.Applications == nil {
+		snap.Applications = make(map[string]ApplicationOwners)
+	}
+	return &snap, nil
+}
+
+// BuildEnrichmentSnapshots fetches the enrichment data from IQ Server and
+// writes it to the enrichment directory dir, to be copied to deployments
+// that read it with ServiceOptions.EnrichmentDir. With publicIDs, only
+// those applications are updated in the existing snapshots; otherwise all
+// are, and applications no longer listed are removed. Applications that
+// fail keep their previous entries, and are returned as errors together.
+// It returns the paths written.
+func (s *IQReportService) BuildEnrichmentSnapshots(ctx context.Context, dir string, publicIDs []string) ([]string, error) {
+	apps, err := s.applications(ctx)
+	var errs []error
+	var partial *PartialListingError
+	if errors.As(err, &partial) {
+		errs = append(errs, err)
+	} else if err != nil {
+		return nil, err
+	}
+
+	snap, err := ReadOwnerSnapshot(dir)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		snap = &OwnerSnapshot{Applications: make(map[string]ApplicationOwners)}
+	case err != nil && len(publicIDs) > 0:
+		return nil, err
+	case err != nil:
+		s.logger.Warn().Err(err).Msg("Replacing unreadable owner snapshot")
+		snap = &OwnerSnapshot{Applications: make(map[string]ApplicationOwners)}
+	}
+	if len(publicIDs) > 0 {
+		byPublicID := make(map[string]client.Application, len(apps))
+		for _, app := range apps {
+			byPublicID[app.PublicID] = app
+		}
+		selected := make([]client.Application, 0, len(publicIDs))
+		for _, id := range publicIDs {
+			app, ok := byPublicID[id]
+			if !ok {
+				return nil, fmt.Errorf("application %q not found", id)
+			}
+			selected = append(selected, app)
+		}
+		apps = selected
+	} else if partial == nil {
+		// Drop the applications removed since the previous snapshot
+		listed := make(map[string]bool, len(apps))
+		for _, app := range apps {
+			listed[app.PublicID] = true
+		}
+		for id := range snap.Applications {
+			if !listed[id] {
+				delete(snap.Applications, id)
+			}
+		}
+	}
+
+	owners, err := s.liveOwnerLookup(ctx)
+	if err != nil {
+		return nil, err
+	}
+	snap.GeneratedAt, snap.Role = s.now(), s.opts.OwnerRole
+	if snap.Role == "" {
+		snap.Role = DefaultOwnerRole
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	jobs := make(chan client.Application)
+	for range min(s.opts.Concurrency, len(apps)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for app := range jobs {
+				names, emails, err := s.fetchOwners(ctx, owners, app)
+				mu.Lock()
+				if err != nil {
+					errs = append(errs, fmt.Errorf("app %s: get owners: %w", app.PublicID, err))
+				} else {
+					snap.Applications[app.PublicID] = ApplicationOwners{Names: names, Emails: emails}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, app := range apps {
+		if ctx.Err() != nil {
+			break
+		}
+		jobs <- app
+	}
+	close(jobs)
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	fsys := report.DirFS(ctx, dir, s.logger)
+	if err := fsys.WriteFile(OwnerSnapshotName, func(w io.Writer) error {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(snap)
+	}); err != nil {
+		return nil, fmt.Errorf("write owner snapshot: %w", err)
+	}
+	s.logger.Info().Int("applications", len(snap.Applications)).Int("failed", len(errs)).Msg("Owner snapshot written")
+	return []string{filepath.Join(dir, OwnerSnapshotName)}, errors.Join(errs...)
+}
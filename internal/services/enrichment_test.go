@@ -0,0 +1,80 @@
+// internal/services/enrichment_test.go
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/client"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/config"
+)
+
+func TestBuildEnrichmentSnapshots(t *testing.T) {
+	var ownerCalls atomic.Int32
+	var failApp2 atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v2/applications":
+			_, _ = w.Write([]byte(`{"applications": [{"id": "aid-1", "publicId": "apid-1", "organizationId": "org-1"}, {"id": "aid-2", "publicId": "apid-2", "organizationId": "org-1"}]}`))
+		case "/api/v2/roles":
+			ownerCalls.Add(1)
+			_, _ = w.Write([]byte(`{"roles": [{"id": "r-owner", "name": "Owner"}]}`))
+		case "/api/v2/roleMemberships/application/aid-1":
+			ownerCalls.Add(1)
+			_, _ = w.Write([]byte(`{"memberMappings": [{"roleId": "r-owner", "members": [{"type": "USER", "userOrGroupName": "jdoe"}]}]}`))
+		case "/api/v2/roleMemberships/application/aid-2":
+			ownerCalls.Add(1)
+			if failApp2.Load() {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			_, _ = w.Write([]byte(`{"memberMappings": [{"roleId": "r-owner", "members": [{"type": "GROUP", "userOrGroupName": "team-b"}]}]}`))
+		case "/api/v2/users/jdoe":
+			_, _ = w.Write([]byte(`{"username": "jdoe", "firstName": "Jane", "lastName": "Doe", "email": "jane@example.com"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	iqClient, err := client.NewClient(server.URL, "u", "p", testLogger())
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	dir := t.TempDir()
+	svc := NewIQReportService(&config.Config{OutputDir: t.TempDir()}, iqClient, testLogger())
+	if _, err := svc.BuildEnrichmentSnapshots(rCtx(t), dir, nil); err != nil {
+		t.Fatalf("BuildEnrichmentSnapshots: %v", err)
+	}
+
+	// A failed update keeps the previous entry
+	failApp2.Store(true)
+	if _, err := svc.BuildEnrichmentSnapshots(rCtx(t), dir, []string{"apid-2"}); err == nil {
+		t.Error("expected an error for the failed application")
+	}
+	snap, err := ReadOwnerSnapshot(dir)
+	if err != nil {
+		t.Fatalf("ReadOwnerSnapshot: %v", err)
+	}
+	if got := snap.Applications["apid-1"]; got != (ApplicationOwners{Names: "Jane Doe", Emails: "jane@example.com"}) {
+		t.Errorf("apid-1 owners = %+v", got)
+	}
+	if got := snap.Applications["apid-2"]; got.Names != "team-b" {
+		t.Errorf("apid-2 owners = %+v, want the previous entry", got)
+	}
+
+	// Offline runs read the snapshot without asking IQ Server
+	ownerCalls.Store(0)
+	svc = NewIQReportService(&config.Config{OutputDir: t.TempDir(), EnrichmentDir: dir}, iqClient, testLogger())
+	l := svc.newOwnerLookup(rCtx(t))
+	names, emails := svc.applicationOwners(rCtx(t), l, client.Application{ID: "aid-1", PublicID: "apid-1"})
+	if names != "Jane Doe" || emails != "jane@example.com" {
+		t.Errorf("owners = %q, %q", names, emails)
+	}
+	if n := ownerCalls.Load(); n != 0 {
+		t.Errorf("%d owner requests with ENRICHMENT_DIR, want none", n)
+	}
+}
@@ -0,0 +1,136 @@
+// internal/services/faults.go
+package services
+
+import (
+	"bytes"
+	"hash/fnv"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Fault kinds injected by faultInjector.
+const (
+	faultNone      = ""
+	faultError     = "error"     // 503 Service Unavailable
+	faultDelay     = "delay"     // response delayed by faultConfig.Delay
+	faultMalformed = "malformed" // 200 OK with truncated JSON
+)
+
+// faultConfig sets the share of matching requests, from 0 to 1, that fail,
+// are delayed or return a malformed payload. Decisions are derived from Seed
+// and the request path, so a run injects the same faults regardless of the
+// order in which applications are processed.
+type faultConfig struct {
+	Seed          uint64
+	ErrorRate     float64
+	DelayRate     float64
+	Delay         time.Duration
+	MalformedRate float64
+	// Match selects the requests faults apply to; nil matches the
+	// per-application endpoints (report infos and policy violations), so
+	// that listing applications and organizations is never affected.
+	Match func(*http.Request) bool
+}
+
+// faultInjector is an http.RoundTripper that injects faults into requests
+// to IQ Server, for tests of the aggregation and partial-failure paths. It
+// is installed with client.WithTransport and is never used outside tests.
+type faultInjector struct {
+	cfg  faultConfig
+	next http.RoundTripper
+
+	mu       sync.Mutex
+	injected map[string]int // fault kind -> number of requests
+}
+
+func newFaultInjector(cfg faultConfig, next http.RoundTripper) *faultInjector {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if cfg.Match == nil {
+		cfg.Match = isApplicationRequest
+	}
+	return &faultInjector{cfg: cfg, next: next, injected: make(map[string]int)}
+}
+
+// isApplicationRequest reports whether req fetches the reports of a single
+// application.
+func isApplicationRequest(req *http.Request) bool {
+	path := req.URL.Path
+	return strings.Contains(path, "/reports/applications/") || strings.HasSuffix(path, "/policy")
+}
+
+// fault returns the fault to inject into req.
+func (f *faultInjector) fault(req *http.Request) string {
+	if !f.cfg.Match(req) {
+		return faultNone
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(strconv.FormatUint(f.cfg.Seed, 10) + " " + req.Method + " " + req.URL.Path))
+	p := float64(h.Sum64()%1_000_000) / 1_000_000
+	switch {
+	case p < f.cfg.ErrorRate:
+		return faultError
+	case p < f.cfg.ErrorRate+f.cfg.MalformedRate:
+		return faultMalformed
+	case p < f.cfg.ErrorRate+f.cfg.MalformedRate+f.cfg.DelayRate:
+		return faultDelay
+	default:
+		return faultNone
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (f *faultInjector) RoundTrip(req *http.Request) (*http.Response, error) {
+	kind := f.fault(req)
+	if kind != faultNone {
+		f.mu.Lock()
+		f.injected[kind]++
+		f.mu.Unlock()
+	}
+
+	switch kind {
+	case faultError:
+		return fakeResponse(req, http.StatusServiceUnavailable, "text/plain", "injected fault"), nil
+	case faultMalformed:
+		return fakeResponse(req, http.StatusOK, "application/json", `{"components": [{"displayName": `), nil
+	case faultDelay:
+		t := time.NewTimer(f.cfg.Delay)
+		defer t.Stop()
+		select {
+		case <-t.C:
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+	return f.next.RoundTrip(req)
+}
+
+// Injected returns the number of requests that received each kind of fault.
+func (f *faultInjector) Injected() map[string]int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := make(map[string]int, len(f.injected))
+	for k, v := range f.injected {
+		out[k] = v
+	}
+	return out
+}
+
+func fakeResponse(req *http.Request, status int, contentType, body string) *http.Response {
+	return &http.Response{
+		Status:        strconv.Itoa(status) + " " + http.StatusText(status),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{contentType}},
+		Body:          io.NopCloser(bytes.NewReader([]byte(body))),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
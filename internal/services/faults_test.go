@@ -0,0 +1,151 @@
+// internal/services/faults_test.go
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/client"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/config"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/report"
+)
+
+// faultServer serves apps applications with one violation each.
+func faultServer(t *testing.T, apps int) *httptest.Server {
+	t.Helper()
+	var appList strings.Builder
+	appList.WriteString(`{"applications": [`)
+	for i := range apps {
+		if i > 0 {
+			appList.WriteString(",")
+		}
+		fmt.Fprintf(&appList, `{"id": "aid-%d", "publicId": "app-%d", "organizationId": "org-1"}`, i, i)
+	}
+	appList.WriteString(`]}`)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/api/v2/applications":
+			_, _ = w.Write([]byte(appList.String()))
+		case r.URL.Path == "/api/v2/organizations":
+			_, _ = w.Write([]byte(`{"organizations": [{"id": "org-1", "name": "Org"}]}`))
+		case strings.HasPrefix(r.URL.Path, "/api/v2/reports/applications/"):
+			_, _ = w.Write([]byte(`[{"stage": "build", "reportDataUrl": "api/v2/applications/x/reports/rpt-1"}]`))
+		case strings.HasSuffix(r.URL.Path, "/policy"):
+			_, _ = w.Write([]byte(`{"components": [{"displayName": "lib 1.0", "componentIdentifier": {"format": "maven"}, "violations": [{"policyName": "Security-High", "policyThreatLevel": 9, "constraints": [{"constraintName": "CVSS >= 7", "conditions": [{"conditionSummary": "CVE-2024-0001"}]}]}]}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestFaultInjector_Deterministic(t *testing.T) {
+	cfg := faultConfig{Seed: 7, ErrorRate: 0.3, MalformedRate: 0.2, DelayRate: 0.2}
+	a, b := newFaultInjector(cfg, nil), newFaultInjector(cfg, nil)
+	counts := make(map[string]int)
+	for i := range 200 {
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v2/applications/app-%d/reports/rpt-1/policy", i), nil)
+		kind := a.fault(req)
+		if kind != b.fault(req) {
+			t.Fatalf("fault for %s differs between injectors with the same seed", req.URL.Path)
+		}
+		counts[kind]++
+	}
+	if counts[faultError] == 0 || counts[faultMalformed] == 0 || counts[faultDelay] == 0 || counts[faultNone] == 0 {
+		t.Errorf("expected every fault kind, got %v", counts)
+	}
+
+	list := httptest.NewRequest(http.MethodGet, "/api/v2/applications", nil)
+	if kind := newFaultInjector(faultConfig{ErrorRate: 1}, nil).fault(list); kind != faultNone {
+		t.Errorf("listing applications got fault %q", kind)
+	}
+}
+
+func TestGenerateLatestPolicyReport_InjectedFaults(t *testing.T) {
+	const apps = 40
+	server := faultServer(t, apps)
+	faults := newFaultInjector(faultConfig{
+		Seed:          1,
+		ErrorRate:     0.15,
+		MalformedRate: 0.1,
+		DelayRate:     0.2,
+		Delay:         20 * time.Millisecond,
+	}, nil)
+	iqClient, err := client.NewClient(server.URL, "u", "p", testLogger(), client.WithTransport(faults))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	tmpDir := t.TempDir()
+	svc := NewIQReportService(&config.Config{OutputDir: tmpDir, RollupReports: true}, iqClient, testLogger())
+	// A partial run publishes the report and reports the failures
+	path, err := svc.GenerateLatestPolicyReport(rCtx(t), "report.csv")
+	if err == nil || path == "" {
+		t.Fatalf("GenerateLatestPolicyReport = %q, %v; want the report path and an error", path, err)
+	}
+	if !errors.Is(err, client.ErrServer) || !errors.Is(err, client.ErrParse) {
+		t.Errorf("expected server and parse errors, got %v", err)
+	}
+
+	// Every failed application got exactly one failing response: requests
+	// after the first failure of an application are not made
+	injected := faults.Injected()
+	failed := injected[faultError] + injected[faultMalformed]
+	if injected[faultError] == 0 || injected[faultMalformed] == 0 || injected[faultDelay] == 0 {
+		t.Fatalf("expected every fault kind to be injected, got %v", injected)
+	}
+	stats := svc.LastRun()
+	if stats.Failed != failed || stats.Processed != apps-failed || stats.Rows != apps-failed {
+		t.Errorf("stats = %+v, injected = %v", stats, injected)
+	}
+
+	b, err := os.ReadFile(report.ManifestPath(path))
+	if err != nil {
+		t.Fatalf("read manifest: %v", err)
+	}
+	var manifest report.Manifest
+	if err := json.Unmarshal(b, &manifest); err != nil {
+		t.Fatalf("decode manifest: %v", err)
+	}
+	if manifest.Errors != failed || manifest.ErrorsByKind["server"] != injected[faultError] || manifest.ErrorsByKind["parse"] != injected[faultMalformed] {
+		t.Errorf("manifest errors = %d %v, injected = %v", manifest.Errors, manifest.ErrorsByKind, injected)
+	}
+	if manifest.Rows != apps-failed {
+		t.Errorf("manifest rows = %d, want %d", manifest.Rows, apps-failed)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "report.applications.csv")); err != nil {
+		t.Errorf("application rollup not written: %v", err)
+	}
+}
+
+func TestGenerateLatestPolicyReport_InjectedDelaysPastDeadline(t *testing.T) {
+	server := faultServer(t, 5)
+	faults := newFaultInjector(faultConfig{DelayRate: 1, Delay: time.Minute}, nil)
+	iqClient, err := client.NewClient(server.URL, "u", "p", testLogger(), client.WithTransport(faults))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	tmpDir := t.TempDir()
+	svc := NewIQReportService(&config.Config{OutputDir: tmpDir}, iqClient, testLogger())
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if _, err := svc.GenerateLatestPolicyReport(ctx, "report.csv"); err == nil {
+		t.Fatal("expected the run to fail when applications cannot be fetched before the deadline")
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "report.csv")); !os.IsNotExist(err) {
+		t.Errorf("expected no report to be published, stat error = %v", err)
+	}
+}
@@ -0,0 +1,33 @@
+// internal/services/filter.go
+package services
+
+import (
+	"slices"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/report"
+)
+
+// filterRows returns the rows that pass the configured row filters and expr,
+// the compiled FILTER expression (nil keeps all rows). The input slice is
+// not modified.
+func (s *IQReportService) filterRows(rows []report.Row, expr *report.Filter) []report.Row {
+	categories := s.opts.ThreatCategories
+	if len(categories) == 0 && !s.opts.ExcludeProprietary && expr == nil {
+		return rows
+	}
+
+	out := make([]report.Row, 0, len(rows))
+	for _, r := range rows {
+		if len(categories) > 0 && !slices.Contains(categories, r.Category) {
+			continue
+		}
+		if s.opts.ExcludeProprietary && r.Proprietary {
+			continue
+		}
+		if !expr.Match(r) {
+			continue
+		}
+		out = append(out, r)
+	}
+	return out
+}
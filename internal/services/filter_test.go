@@ -0,0 +1,67 @@
+// internal/services/filter_test.go
+package services
+
+import (
+	"testing"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/config"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/report"
+)
+
+func TestFilterRows_ByThreatCategory(t *testing.T) {
+	rows := []report.Row{
+		{Application: "a", Category: "security"},
+		{Application: "b", Category: "license"},
+		{Application: "c", Category: "quality"},
+	}
+
+	svc := NewIQReportService(&config.Config{}, nil, testLogger())
+	if got := svc.filterRows(rows, nil); len(got) != 3 {
+		t.Errorf("no filter: got %d rows, want 3", len(got))
+	}
+
+	svc = NewIQReportService(&config.Config{ThreatCategories: []string{"security", "license"}}, nil, testLogger())
+	got := svc.filterRows(rows, nil)
+	if len(got) != 2 || got[0].Application != "a" || got[1].Application != "b" {
+		t.Errorf("unexpected rows: %#v", got)
+	}
+}
+
+func TestFilterRows_ExcludeProprietary(t *testing.T) {
+	rows := []report.Row{
+		{Application: "a", Category: "security"},
+		{Application: "b", Category: "security", Proprietary: true},
+		{Application: "c", Category: "license"},
+	}
+
+	svc := NewIQReportService(&config.Config{ExcludeProprietary: true}, nil, testLogger())
+	got := svc.filterRows(rows, nil)
+	if len(got) != 2 || got[0].Application != "a" || got[1].Application != "c" {
+		t.Errorf("unexpected rows: %#v", got)
+	}
+
+	svc = NewIQReportService(&config.Config{ExcludeProprietary: true, ThreatCategories: []string{"security"}}, nil, testLogger())
+	got = svc.filterRows(rows, nil)
+	if len(got) != 1 || got[0].Application != "a" {
+		t.Errorf("combined filters: unexpected rows: %#v", got)
+	}
+}
+
+func TestFilterRows_Expression(t *testing.T) {
+	rows := []report.Row{
+		{Application: "a", Organization: "prod", Format: "maven", Threat: 9, Category: "security"},
+		{Application: "b", Organization: "sandbox", Format: "maven", Threat: 9, Category: "security"},
+		{Application: "c", Organization: "prod", Format: "npm", Threat: 9, Category: "security"},
+		{Application: "d", Organization: "prod", Format: "maven", Threat: 3, Category: "license"},
+	}
+	expr, err := report.ParseFilter(`Threat >= 7 && Format == "maven" && Organization != "sandbox"`)
+	if err != nil {
+		t.Fatalf("ParseFilter: %v", err)
+	}
+
+	svc := NewIQReportService(&config.Config{}, nil, testLogger())
+	got := svc.filterRows(rows, expr)
+	if len(got) != 1 || got[0].Application != "a" {
+		t.Errorf("unexpected rows: %#v", got)
+	}
+}
@@ -0,0 +1,89 @@
+// internal/services/history.go
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/client"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/report"
+)
+
+// ExportReportHistory writes the scan timeline (every report evaluation with
+// its date, stage and policy violation counts) of the applications with the
+// given public IDs to OutputDir/history/<application>.csv, or of all
+// applications when publicIDs is empty. Applications are exported one after
+// the other; a failed application does not stop the others. It returns the
+// paths written.
+func (s *IQReportService) ExportReportHistory(ctx context.Context, publicIDs []string) ([]string, error) {
+	apps, err := s.applications(ctx)
+	var errs []error
+	var partial *PartialListingError
+	if errors.As(err, &partial) {
+		errs = append(errs, err)
+	} else if err != nil {
+		return nil, err
+	}
+	if len(publicIDs) > 0 {
+		byPublicID := make(map[string]client.Application, len(apps))
+		for _, app := range apps {
+			byPublicID[app.PublicID] = app
+		}
+		selected := make([]client.Application, 0, len(publicIDs))
+		for _, id := range publicIDs {
+			app, ok := byPublicID[id]
+			if !ok {
+				return nil, fmt.Errorf("application %q not found", id)
+			}
+			selected = append(selected, app)
+		}
+		apps = selected
+	}
+
+	orgs, err := s.clients.Default().GetOrganizations(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get organizations: %w", err)
+	}
+	orgIDToName, _ := s.organizationNames(ctx, orgs, apps)
+
+	var paths []string
+	for _, app := range apps {
+		appCtx := client.WithApplication(ctx, app.PublicID)
+		history, err := s.clients.For(app.OrganizationID).GetReportHistory(appCtx, app.ID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("app %s: get report history: %w", app.PublicID, err))
+			continue
+		}
+
+		orgName, ok := orgIDToName[app.OrganizationID]
+		if !ok {
+			orgName = app.OrganizationID
+		}
+		entries := make([]report.HistoryEntry, 0, len(history))
+		for _, h := range history {
+			reportID, _ := h.ReportID() // informational; empty when the URLs are missing
+			entries = append(entries, report.HistoryEntry{
+				Application:        app.PublicID,
+				Organization:       orgName,
+				Stage:              h.Stage,
+				EvaluationDate:     h.EvaluationDate,
+				ReportID:           reportID,
+				Critical:           h.PolicyEvaluationResult.CriticalPolicyViolationCount,
+				Severe:             h.PolicyEvaluationResult.SeverePolicyViolationCount,
+				Moderate:           h.PolicyEvaluationResult.ModeratePolicyViolationCount,
+				AffectedComponents: h.PolicyEvaluationResult.AffectedComponentCount,
+				TotalComponents:    h.PolicyEvaluationResult.TotalComponentCount,
+			})
+		}
+
+		dest := report.HistoryPath(s.opts.OutputDir, app.PublicID)
+		if err := report.WriteHistoryCSV(ctx, dest, entries, s.logger); err != nil {
+			errs = append(errs, fmt.Errorf("app %s: write report history: %w", app.PublicID, err))
+			continue
+		}
+		s.logger.Info().Str("appPublicID", app.PublicID).Int("evaluations", len(entries)).Str("path", dest).Msg("Report history written")
+		paths = append(paths, dest)
+	}
+	return paths, errors.Join(errs...)
+}
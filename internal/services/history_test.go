@@ -0,0 +1,61 @@
+// internal/services/history_test.go
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/client"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/config"
+)
+
+func TestExportReportHistory(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v2/applications":
+			_, _ = w.Write([]byte(`{"applications": [{"id": "aid-1", "publicId": "apid-1", "organizationId": "org-1"}, {"id": "aid-2", "publicId": "apid-2", "organizationId": "org-1"}]}`))
+		case "/api/v2/organizations":
+			_, _ = w.Write([]byte(`{"organizations": [{"id": "org-1", "name": "Org One"}]}`))
+		case "/api/v2/reports/applications/aid-1/history":
+			_, _ = w.Write([]byte(`{"reports": [
+				{"stage": "build", "evaluationDate": "2025-01-15", "reportHtmlUrl": "https://stub/report/rpt-2", "policyEvaluationResult": {"criticalPolicyViolationCount": 2}},
+				{"stage": "build", "evaluationDate": "2025-01-01", "reportHtmlUrl": "https://stub/report/rpt-1"}
+			]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	iqClient, _ := client.NewClient(server.URL+"/api/v2", "u", "p", testLogger())
+	svc := NewIQReportService(&config.Config{OutputDir: t.TempDir()}, iqClient, testLogger())
+
+	paths, err := svc.ExportReportHistory(rCtx(t), []string{"apid-1"})
+	if err != nil {
+		t.Fatalf("ExportReportHistory: %v", err)
+	}
+	if len(paths) != 1 {
+		t.Fatalf("paths = %v", paths)
+	}
+	b, err := os.ReadFile(paths[0])
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if !strings.Contains(string(b), "apid-1,Org One,build,2025-01-15,rpt-2,2,") || strings.Count(string(b), "\n") != 3 {
+		t.Errorf("unexpected timeline:\n%s", b)
+	}
+
+	// All applications: apid-2 has no history endpoint and fails alone
+	paths, err = svc.ExportReportHistory(rCtx(t), nil)
+	if err == nil || !strings.Contains(err.Error(), "apid-2") || len(paths) != 1 {
+		t.Errorf("paths = %v, err = %v", paths, err)
+	}
+
+	if _, err := svc.ExportReportHistory(rCtx(t), []string{"missing"}); err == nil {
+		t.Error("expected error for unknown application")
+	}
+}
@@ -5,9 +5,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"os"
 	"path/filepath"
-	"strings"
+	"slices"
 	"sync"
+	"time"
 
 	"github.com/anmicius0/iqserver-report-fetch-go/internal/client"
 	"github.com/anmicius0/iqserver-report-fetch-go/internal/config"
@@ -21,9 +24,28 @@ import (
 // I/O and HTTP logic lives in the internal/report and internal/client
 // packages respectively.
 type IQReportService struct {
-	cfg    *config.Config
-	client *client.Client
-	logger zerolog.Logger
+	opts     ServiceOptions
+	clients  *client.Pool
+	sinks    []Sink
+	progress *progressWriter
+	clock    Clock
+	asOf     time.Time // see SetAsOf
+	logger   zerolog.Logger
+
+	mu      sync.Mutex
+	lastRun RunStats
+}
+
+// Sink receives the final report rows after the report has been written,
+// e.g. to open tickets in an external system.
+type Sink interface {
+	Name() string
+	Publish(ctx context.Context, rows []report.Row) error
+}
+
+// AddSink registers a sink that receives the rows of every generated report.
+func (s *IQReportService) AddSink(sink Sink) {
+	s.sinks = append(s.sinks, sink)
 }
 
 // AppReportResult holds the violation rows and any error encountered
@@ -32,181 +54,803 @@ type IQReportService struct {
 // any error that occurred while collecting them. Errors are returned to the
 // caller rather than being logged here.
 type AppReportResult struct {
+	// Application and Organization are the public ID and organization name
+	// of the application.
+	Application  string
+	Organization string
+
 	Rows []report.Row
 	Err  error
+	// Scan describes the exported reports of a processed application.
+	Scan report.ApplicationScan
+	// Skipped is set to the reason when the application was intentionally
+	// skipped rather than failed (see the Skip* constants).
+	Skipped string
+	// Mismatches lists reports failing the count validation (VALIDATE_COUNTS).
+	Mismatches []report.CountMismatch
 }
 
+// Reasons recorded in AppReportResult.Skipped and in the manifest.
+const (
+	// SkipRemoved marks applications or reports deleted between listing and fetching.
+	SkipRemoved = "removed"
+)
+
 // NewIQReportService constructs a new service.
 // NewIQReportService creates a new IQReportService configured with cfg and
 // a client used to talk to IQ Server.
 func NewIQReportService(cfg *config.Config, cl *client.Client, logger zerolog.Logger) *IQReportService {
-	return &IQReportService{cfg: cfg, client: cl, logger: logger}
+	return NewIQReportServiceWithPool(cfg, client.NewPool(cl), logger)
+}
+
+// NewIQReportServiceWithPool creates a new IQReportService that selects the
+// client per application organization from pool. The pool's default client
+// is used for instance-wide calls such as listing applications.
+func NewIQReportServiceWithPool(cfg *config.Config, pool *client.Pool, logger zerolog.Logger) *IQReportService {
+	return NewIQReportServiceWithOptions(OptionsFromConfig(cfg), pool, logger)
 }
 
 // GenerateLatestPolicyReport fetches latest policy violations for all applications
-// and writes a CSV to cfg.OutputDir/filename, returning the absolute file path.
-func (s *IQReportService) GenerateLatestPolicyReport(ctx context.Context, filename string) (string, error) {
+// and writes a CSV to OutputDir/filename, returning the absolute file path.
+func (s *IQReportService) GenerateLatestPolicyReport(ctx context.Context, filename string) (path string, err error) {
 	logger := s.logger.With().Str("filename", filename).Logger()
+	md, hasMetadata := client.MetadataFromContext(ctx)
+	if hasMetadata {
+		logger = md.Fields(logger.With()).Logger()
+	}
 
 	logger.Info().Msg("GenerateLatestPolicyReport invoked")
+	phaseStart := time.Now()
+	runTime := s.now()
+
+	// Summary for the run_finished progress event, filled in as the run proceeds
+	stats := &RunStats{}
+	resources := s.startResourceMonitor()
+	defer func(start time.Time) {
+		stats.Status, stats.ReportPath = "ok", path
+		stats.DurationMS = time.Since(start).Milliseconds()
+		usage := resources.usage()
+		stats.Resources = &usage
+		if err != nil {
+			stats.Status, stats.Error = "failed", err.Error()
+		}
+		s.progress.emit(ProgressEvent{Event: EventRunFinished, Stats: stats})
+		s.mu.Lock()
+		s.lastRun = *stats
+		s.mu.Unlock()
+	}(phaseStart)
+
+	// Phase boundaries are logged and recorded in the run stats
+	phaseDone := func(phase string) {
+		d := time.Since(phaseStart)
+		logger.Info().Str("phase", phase).Dur("duration", d).Msg("Phase completed")
+		if stats.PhasesMS == nil {
+			stats.PhasesMS = make(map[string]int64)
+		}
+		stats.PhasesMS[phase] = d.Milliseconds()
+		phaseStart = time.Now()
+	}
+
+	// Reject unknown columns before fetching anything
+	for _, column := range s.opts.CSVOptionalColumns {
+		if !slices.Contains(report.OptionalColumns(), column) {
+			return "", fmt.Errorf("CSV_OPTIONAL_COLUMNS: unknown column %q", column)
+		}
+	}
+	columns := report.CSVColumns(report.WithOptionalColumns(s.optionalColumns()...))
+	for _, key := range s.opts.AppTagColumns {
+		if slices.Contains(columns, key) {
+			return "", fmt.Errorf("APP_TAG_COLUMNS: %q duplicates a report column", key)
+		}
+		columns = append(columns, key)
+	}
+	for column := range s.opts.CSVEmptyValues {
+		if !slices.Contains(columns, column) {
+			return "", fmt.Errorf("CSV_EMPTY_VALUES: unknown column %q", column)
+		}
+	}
+
+	rowFilter, err := report.ParseFilter(s.opts.Filter)
+	if err != nil {
+		return "", fmt.Errorf("FILTER: %w", err)
+	}
+
+	if s.opts.OutputLayout != "" {
+		if err := report.ValidateLayout(s.opts.OutputLayout); err != nil {
+			return "", fmt.Errorf("OUTPUT_LAYOUT: %w", err)
+		}
+	}
+
+	// Load suppressions up front so that an invalid file fails the run early
+	var suppressions []Suppression
+	if s.opts.SuppressionsFile != "" {
+		var err error
+		suppressions, err = LoadSuppressions(s.opts.SuppressionsFile)
+		if err != nil {
+			return "", err
+		}
+		logger.Info().Str("file", s.opts.SuppressionsFile).Int("count", len(suppressions)).Msg("Loaded suppressions")
+	}
 
 	// =================================================================
 	// 1. APPLICATION AND ORGANIZATION FETCHING (Sequential Setup)
 	// =================================================================
 
-	// Fetch application list
-	apps, err := s.client.GetApplications(ctx)
-	if err != nil {
-		return "", fmt.Errorf("get applications: %w", err)
+	// Fetch application list, or use the pinned snapshot
+	apps, err := s.applications(ctx)
+	var partial *PartialListingError
+	if errors.As(err, &partial) {
+		// Process what could be listed; the run still reports the failure
+		logger.Warn().Strs("organizations", partial.Organizations()).Msg("Applications of some organizations could not be listed")
+	} else if err != nil {
+		return "", err
 	}
+	listErr := err
 	logger.Info().Int("count", len(apps)).Msg("Fetched applications")
+	apps = s.shardApplications(apps)
 
 	if len(apps) == 0 {
 		logger.Warn().Msg("Task finished: no applications found matching criteria")
 		return "", fmt.Errorf("no applications found")
 	}
+	stats.Applications = len(apps)
+	if err := s.checkDiskSpace(len(apps)); err != nil {
+		return "", err
+	}
+	s.progress.emit(ProgressEvent{Event: EventRunStarted, Stats: &RunStats{Applications: len(apps)}})
 
 	// Fetch organizations to create an ID-to-name map
-	orgs, err := s.client.GetOrganizations(ctx)
+	orgs, err := s.clients.Default().GetOrganizations(ctx)
 	if err != nil {
 		return "", fmt.Errorf("get organizations: %w", err)
 	}
-	orgIDToName := make(map[string]string)
-	for _, org := range orgs {
-		orgIDToName[org.ID] = org.Name
-	}
+	orgIDToName, orphanedOrgs := s.organizationNames(ctx, orgs, apps)
 	logger.Info().Int("count", len(orgIDToName)).Msg("Created organization ID-to-name map")
+	var tagNames map[string]string
+	if len(s.opts.AppTagColumns) > 0 {
+		tagNames = s.tagNames(ctx, orgs)
+		logger.Info().Int("count", len(tagNames)).Msg("Fetched application categories for tag columns")
+	}
+	var owners *ownerLookup
+	if s.needsOwners() {
+		owners = s.newOwnerLookup(ctx)
+	}
+	phaseDone("list")
 
 	// =================================================================
 	// 2. PROCESS APPLICATIONS CONCURRENTLY
 	// =================================================================
 
-	// Setup concurrency primitives: semaphore (max 10), channel for results, WaitGroup
-	sem := make(chan struct{}, 10) // Bounded semaphore: max 10 concurrent
-	resultsChan := make(chan AppReportResult, len(apps))
-	var wg sync.WaitGroup
+	fetches := newViolationFetches()
+	s.logger.Info().Int("appsToProcess", len(apps)).Int("maxConcurrent", s.opts.Concurrency).Msg("Starting concurrent report fetching for applications")
+	resultsChan := s.fetchApplications(ctx, apps, orgIDToName, tagNames, owners, fetches)
 
-	s.logger.Info().Int("appsToProcess", len(apps)).Int("maxConcurrent", 10).Msg("Starting concurrent report fetching for applications")
+	// Aggregate results
+	var allViolationRows []report.Row
+	var scans []report.ApplicationScan
+	var mismatches []report.CountMismatch
 
-	// Launch a goroutine for each application
-	for _, a := range apps {
-		wg.Add(1)
+	// Aggregate results and collect any errors, counted per error kind
+	var errs []error
+	errKinds := make(map[string]int)
+	skipped := make(map[string]int)
+	var dispositions []report.AppDisposition
+	processed, received := 0, 0
+	for res := range resultsChan {
+		received++
+		dispositions = append(dispositions, newDisposition(res))
+		if res.Skipped != "" {
+			skipped[res.Skipped]++
+			continue
+		}
+		if res.Err != nil {
+			errs = append(errs, res.Err)
+			errKinds[client.KindName(res.Err)]++
+			continue
+		}
+		processed++
+		mismatches = append(mismatches, res.Mismatches...)
+		allViolationRows = append(allViolationRows, res.Rows...)
+		scans = append(scans, res.Scan)
+	}
+	// Applications still in flight when the context ends send no result; a
+	// report missing them must not be published as complete
+	if received < len(apps) && ctx.Err() != nil {
+		return "", fmt.Errorf("fetch reports: %d of %d applications unfinished: %w", len(apps)-received, len(apps), ctx.Err())
+	}
+	for kind, n := range errKinds {
+		logger.Warn().Str("kind", kind).Int("count", n).Msg("Applications failed")
+	}
+	for reason, n := range skipped {
+		logger.Info().Str("reason", reason).Int("count", n).Msg("Applications skipped")
+	}
+	for _, m := range mismatches {
+		logger.Warn().
+			Str("application", m.Application).
+			Str("stage", m.Stage).
+			Str("reportId", m.ReportID).
+			Interface("expected", m.Expected).
+			Interface("parsed", m.Parsed).
+			Msg("Parsed violations differ from IQ Server summary counts")
+	}
+	if n := fetches.Reused(); n > 0 {
+		logger.Info().Int64("count", n).Msg("Reused policy violations of reports referenced more than once")
+	}
 
-		// Capture loop variable 'a' for use in the goroutine closure
-		app := a
+	stats.Processed, stats.Failed = processed, len(errs)
+	if listErr != nil {
+		errs = append(errs, listErr)
+	}
+	stats.TopErrors = topErrors(errs, 5)
+	for _, n := range skipped {
+		stats.Skipped += n
+	}
 
-		go func() {
-			defer wg.Done()
+	phaseDone("fetch")
 
-			// Acquire semaphore with context cancellation support
-			select {
-			case sem <- struct{}{}:
-				defer func() { <-sem }() // Release semaphore
-			case <-ctx.Done():
-				return
-			}
+	// =================================================================
+	// 3. AGGREGATION, CSV GENERATION AND FINAL PATH RETURN
+	// =================================================================
 
-			// Check for context cancellation/timeout early
-			if ctx.Err() != nil {
-				return
-			}
+	// Filtering and writing run under their own deadline, so that a stuck
+	// writer (e.g. on a slow network share) cannot hang the run. The phase
+	// works on its own copies and returns its results: once the deadline
+	// expired, it may still be running while the run returns.
+	agg, err := runWithDeadline(context.WithoutCancel(ctx), "aggregate", s.opts.AggregationTimeout, func(ctx context.Context) (aggregateResult, error) {
+		var res aggregateResult
+		allViolationRows := slices.Clone(allViolationRows)
+		dispositions := slices.Clone(dispositions)
+		fetchedRows := len(allViolationRows)
+		allViolationRows = s.filterRows(allViolationRows, rowFilter)
+		if filtered := fetchedRows - len(allViolationRows); filtered > 0 {
+			logger.Info().Int("filtered", filtered).Int("remaining", len(allViolationRows)).Msg("Rows removed by filters")
+		}
+		var suppressed int
+		allViolationRows, suppressed = suppressRows(allViolationRows, suppressions, runTime)
+		if suppressed > 0 {
+			logger.Info().Int("suppressed", suppressed).Int("remaining", len(allViolationRows)).Msg("Rows suppressed")
+		}
+		if s.opts.CVERows == config.CVERowsSplit {
+			allViolationRows = report.SplitCVERows(allViolationRows)
+		}
+		report.SortRows(allViolationRows)
+		finishDispositions(dispositions, allViolationRows)
+		res.dispositions = report.CountDispositions(dispositions)
+		for disposition, n := range res.dispositions {
+			logger.Info().Str("disposition", disposition).Int("count", n).Msg("Application dispositions")
+		}
 
-			appLogger := s.logger.With().Str("appPublicID", app.PublicID).Str("appInternalID", app.ID).Logger()
+		// =================================================================
+		// 3. CSV GENERATION AND FINAL PATH RETURN
+		// =================================================================
 
-			// 2a. Fetch latest report info
-			reportInfo, err := s.client.GetLatestReportInfo(ctx, app.ID)
+		// Refuse to publish a suspicious report (e.g. empty because of upstream issues)
+		previous, err := report.LatestShardManifest(s.opts.OutputDir, s.shard())
+		if err != nil {
+			logger.Warn().Err(err).Msg("Could not read previous run manifest")
+		}
+		if err := s.checkSanity(len(allViolationRows), len(apps), processed, previous); err != nil {
+			return res, err
+		}
+
+		target := filepath.Join(s.opts.OutputDir, filename)
+		s.logger.Info().Str("path", target).Int("totalRows", len(allViolationRows)).Msg("Writing CSV report")
+
+		csvOpts := s.csvOptions()
+		reportFile := target
+		var chunks []string
+		if s.opts.CSVChunkRows > 0 {
+			chunks, err = report.WriteCSVChunks(ctx, target, allViolationRows, s.opts.CSVChunkRows, s.logger, csvOpts...)
 			if err != nil {
-				// Return error to caller (collected by the aggregator)
-				select {
-				case resultsChan <- AppReportResult{Err: fmt.Errorf("app %s: %w", app.ID, err)}:
-				case <-ctx.Done():
-				}
-				return
+				return res, fmt.Errorf("write csv chunks: %w", err)
 			}
+			reportFile = report.IndexPath(target)
+			s.logger.Info().Int("chunks", len(chunks)).Int("chunkRows", s.opts.CSVChunkRows).Msg("Report split into chunks")
+		} else if reportFile, err = report.WriteCSV(ctx, target, allViolationRows, s.logger, csvOpts...); err != nil {
+			return res, fmt.Errorf("write csv: %w", err)
+		}
 
-			// Skip if no report available
-			if reportInfo == nil || strings.TrimSpace(reportInfo.ReportHTMLURL) == "" {
-				// No report found: return empty rows without error
-				select {
-				case resultsChan <- AppReportResult{Rows: nil}:
-				case <-ctx.Done():
-				}
-				return
-			}
+		s.logger.Info().Str("path", reportFile).Msg("Report written successfully")
 
-			// 2b. Extract report ID and validate
-			_, reportID, found := strings.Cut(reportInfo.ReportHTMLURL, "/report/")
-			if !found || reportID == "" {
-				select {
-				case resultsChan <- AppReportResult{Err: fmt.Errorf("app %s: malformed report URL: %s", app.ID, reportInfo.ReportHTMLURL)}:
-				case <-ctx.Done():
-				}
-				return
+		export, err := s.writeExport(ctx, target, allViolationRows, csvOpts)
+		if err != nil {
+			return res, fmt.Errorf("write %s report: %w", s.opts.ReportFormat, err)
+		}
+		if export != "" {
+			s.logger.Info().Str("path", export).Str("format", s.opts.ReportFormat).Msg("Report exported")
+		}
+
+		if s.opts.OutputLayout != "" {
+			n, err := s.writeSplitOutputs(ctx, target, scans, allViolationRows, runTime, csvOpts)
+			if err != nil {
+				return res, fmt.Errorf("write split outputs: %w", err)
+			}
+			s.logger.Info().Int("files", n).Str("layout", s.opts.OutputLayout).Msg("Per-application reports written")
+		}
+		var companions []string
+		if s.opts.OwnerReports {
+			if err := s.writeOwnerReports(ctx, target, allViolationRows, csvOpts); err != nil {
+				return res, fmt.Errorf("write owner reports: %w", err)
 			}
-			appLogger.Debug().Str("reportID", reportID).Str("stage", reportInfo.Stage).Msg("Parsed report ID")
+			companions = append(companions, report.OwnersPath(target), filepath.Dir(report.OwnerReportPath(target, "_")))
+		}
 
-			// 2c. Look up organization name
-			orgName, ok := orgIDToName[app.OrganizationID]
-			if !ok {
-				orgName = app.OrganizationID
-				// fallback to ID
-				appLogger.Debug().Str("orgID", app.OrganizationID).Msg("organization name not found, using ID as fallback")
+		weights := s.opts.RiskWeights
+		if len(weights) == 0 {
+			weights = report.DefaultRiskWeights
+		}
+		risks := report.RiskScores(allViolationRows, weights)
+		for i, r := range risks {
+			if i == 5 {
+				break
 			}
+			logger.Info().Str("application", r.Application).Float64("riskScore", r.Score).Int("rank", i+1).Msg("Application risk")
+		}
 
-			// 2d. Fetch policy violations (returns []report.Row)
-			clientRows, err := s.client.GetPolicyViolations(ctx, app.PublicID, reportID, orgName)
-			if err != nil {
-				select {
-				case resultsChan <- AppReportResult{Err: fmt.Errorf("app %s: get policy violations: %w", app.ID, err)}:
-				case <-ctx.Done():
-				}
-				return
+		if s.opts.RollupReports {
+			summaries := report.SummarizeApplications(scans, allViolationRows, weights)
+			if err := report.WriteApplicationsCSV(ctx, report.ApplicationsPath(target), summaries, s.logger); err != nil {
+				return res, fmt.Errorf("write application rollup: %w", err)
+			}
+			if err := report.WriteOrganizationsCSV(ctx, report.OrganizationsPath(target), report.SummarizeOrganizations(summaries), s.logger); err != nil {
+				return res, fmt.Errorf("write organization rollup: %w", err)
 			}
-			appLogger.Debug().Int("rowsCount", len(clientRows)).Msg("Fetched policy violations")
+			if err := report.WriteVulnerabilitiesCSV(ctx, report.VulnerabilitiesPath(target), report.SummarizeVulnerabilities(allViolationRows), s.logger); err != nil {
+				return res, fmt.Errorf("write vulnerability view: %w", err)
+			}
+			companions = append(companions, report.ApplicationsPath(target), report.OrganizationsPath(target), report.VulnerabilitiesPath(target))
+		}
+		if len(s.opts.SLADays) > 0 {
+			breaches := report.SLABreaches(allViolationRows, s.opts.SLADays, runTime)
+			if err := report.WriteSLACSV(ctx, report.SLAPath(target), breaches, s.logger); err != nil {
+				return res, fmt.Errorf("write sla report: %w", err)
+			}
+			companions = append(companions, report.SLAPath(target))
+			logger.Info().Int("breaches", len(breaches)).Msg("SLA breach report written")
+		}
+
+		transfer := s.clients.Transfer()
+		usage := resources.usage()
+		manifest := report.Manifest{
+			ReportPath:   reportFile,
+			Chunks:       chunks,
+			Export:       export,
+			Companions:   companions,
+			GeneratedAt:  runTime.UTC(),
+			Applications: len(apps),
+			Processed:    processed,
+			Rows:         len(allViolationRows),
+			Selection:    s.selectionPolicy(),
+			AsOf:         s.asOfTime(),
+			Suppressed:   suppressed,
+			Errors:       len(errs),
+			ErrorsByKind: errKinds,
+			Skipped:      skipped,
+			Transfer: report.Transfer{
+				TotalBytes:    transfer.TotalBytes,
+				ByEndpoint:    transfer.ByEndpoint,
+				ByApplication: transfer.ByApplication,
+			},
+			Resources:             &usage,
+			RiskScores:            risks,
+			CountMismatches:       mismatches,
+			Dispositions:          dispositions,
+			OrphanedOrganizations: orphanedOrgs,
+			Shard:                 s.shard(),
+		}
+		if partial != nil {
+			manifest.UnlistedOrganizations = partial.Organizations()
+		}
+		if hasMetadata {
+			manifest.Run = &report.RunMetadata{RunID: md.RunID, TriggeredBy: md.TriggeredBy, Reason: md.Reason}
+		}
+		if err := report.WriteManifest(ctx, report.ManifestPath(target), manifest, s.logger); err != nil {
+			return res, fmt.Errorf("write manifest: %w", err)
+		}
+		logger.Info().Int64("bytesDownloaded", transfer.TotalBytes).Msg("Transfer totals recorded in manifest")
+		logger.Info().Uint64("peakMemoryBytes", usage.PeakMemoryBytes).Int("peakGoroutines", usage.PeakGoroutines).
+			Int("httpCalls", usage.HTTPCalls).Float64("cpuSeconds", usage.CPUSeconds).Msg("Resource usage recorded in manifest")
+		res.rows, res.reportFile = allViolationRows, reportFile
+		return res, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	allViolationRows, reportFile := agg.rows, agg.reportFile
+	stats.Rows, stats.Dispositions = len(allViolationRows), agg.dispositions
+	s.maintainOutputs(context.WithoutCancel(ctx), logger, reportFile, len(errs) == 0)
+	phaseDone("aggregate")
+
+	// =================================================================
+	// 4. PUBLISH TO SINKS
+	// =================================================================
+
+	sinkErr := s.publish(ctx, allViolationRows)
+
+	if len(errs) > 0 {
+		return reportFile, fmt.Errorf("encountered errors while fetching reports: %w", errors.Join(append(errs, sinkErr)...))
+	}
+	if sinkErr != nil {
+		return reportFile, sinkErr
+	}
+
+	return reportFile, nil
+}
+
+// aggregateResult is the outcome of the aggregation phase of
+// GenerateLatestPolicyReport.
+type aggregateResult struct {
+	rows         []report.Row // filtered and sorted
+	reportFile   string
+	dispositions map[string]int // applications per disposition
+}
+
+// LastRun returns the summary of the most recent GenerateLatestPolicyReport
+// call, whether it succeeded or failed.
+func (s *IQReportService) LastRun() RunStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastRun
+}
+
+// publish hands rows to every registered sink. A failing sink does not stop
+// the others; all failures are returned joined.
+func (s *IQReportService) publish(ctx context.Context, rows []report.Row) error {
+	var errs []error
+	for _, sink := range s.sinks {
+		if err := sink.Publish(ctx, rows); err != nil {
+			s.logger.Error().Err(err).Str("sink", sink.Name()).Msg("Sink failed")
+			errs = append(errs, fmt.Errorf("sink %s: %w", sink.Name(), err))
+			continue
+		}
+		s.logger.Info().Str("sink", sink.Name()).Int("rows", len(rows)).Msg("Published rows to sink")
+	}
+	return errors.Join(errs...)
+}
+
+// applications returns the applications to process: the pinned snapshot
+// when PinnedAppsFile is set, otherwise the live list from IQ Server, listed
+// per organization with ListAppsByOrganization. A complete live list is
+// saved to SaveAppsFile when configured. A partial per-organization listing
+// is returned with a *PartialListingError.
+func (s *IQReportService) applications(ctx context.Context) ([]client.Application, error) {
+	if s.opts.PinnedAppsFile != "" {
+		apps, err := LoadApplicationList(s.opts.PinnedAppsFile)
+		if err != nil {
+			return nil, fmt.Errorf("load pinned applications: %w", err)
+		}
+		s.logger.Info().Str("file", s.opts.PinnedAppsFile).Msg("Using pinned application list")
+		return apps, nil
+	}
+
+	if s.opts.ListAppsByOrganization {
+		apps, err := s.listApplicationsByOrganization(ctx)
+		if err != nil {
+			// Never pin an incomplete list
+			return apps, err
+		}
+		return apps, s.saveApplications(apps)
+	}
+
+	apps, err := s.clients.Default().GetApplications(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get applications: %w", err)
+	}
+	return apps, s.saveApplications(apps)
+}
+
+// saveApplications saves the live application list to SaveAppsFile when
+// configured.
+func (s *IQReportService) saveApplications(apps []client.Application) error {
+	if s.opts.SaveAppsFile != "" {
+		if err := SaveApplicationList(s.opts.SaveAppsFile, apps); err != nil {
+			return err
+		}
+		s.logger.Info().Str("file", s.opts.SaveAppsFile).Msg("Saved application list snapshot")
+	}
+	return nil
+}
+
+// fetchApplications processes apps concurrently, at most opts.Concurrency at
+// a time, and sends one result per application on the returned channel,
+// which is closed once all are done. Tags and owners are attached to the
+// rows. Applications not started or finished when ctx ends send no result.
+//
+// Applications are dispatched to the workers round-robin by organization
+// (see interleaveByOrganization), so that a large organization does not
+// hold every worker while the rows of small ones arrive only at the end.
+func (s *IQReportService) fetchApplications(ctx context.Context, apps []client.Application, orgIDToName, tagNames map[string]string, owners *ownerLookup, fetches *violationFetches) <-chan AppReportResult {
+	resultsChan := make(chan AppReportResult, len(apps))
+	jobs := make(chan client.Application)
+	var wg sync.WaitGroup
 
-			// 2f. Send successful results to the channel
+	// Dispatch applications in fair order until all are taken or ctx ends
+	go func() {
+		defer close(jobs)
+		for _, app := range interleaveByOrganization(apps) {
 			select {
-			case resultsChan <- AppReportResult{Rows: clientRows}:
+			case jobs <- app:
 			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	// Workers process one application at a time
+	for range min(s.opts.Concurrency, len(apps)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for app := range jobs {
+				// Check for context cancellation/timeout early
+				if ctx.Err() != nil {
+					return
+				}
+				res := s.fetchApplication(ctx, app, orgIDToName, tagNames, owners, fetches)
+				// The channel is buffered, so a send never blocks; results of
+				// applications cut short by the context are dropped, so that
+				// the aggregator sees them as unfinished rather than as failed
+				if ctx.Err() != nil {
+					return
+				}
+				resultsChan <- res
 			}
 		}()
 	}
 
-	// Wait for all goroutines to finish, then close the channel in a non-blocking way
+	// Wait for all workers to finish, then close the channel in a non-blocking way
 	go func() {
 		wg.Wait()
 		close(resultsChan)
 	}()
+	return resultsChan
+}
 
-	// Aggregate results
-	var allViolationRows []report.Row
+// fetchApplication processes app and attaches its tags and owners to the
+// rows.
+func (s *IQReportService) fetchApplication(ctx context.Context, app client.Application, orgIDToName, tagNames map[string]string, owners *ownerLookup, fetches *violationFetches) AppReportResult {
+	res := s.processApp(ctx, app, orgIDToName, fetches)
+	res.Application, res.Organization = app.PublicID, orgIDToName[app.OrganizationID]
+	if res.Organization == "" {
+		res.Organization = app.OrganizationID
+	}
+	if tags := applicationTags(app, tagNames, s.opts.AppTagColumns); tags != nil {
+		for i := range res.Rows {
+			res.Rows[i].Tags = tags
+		}
+	}
+	if owners != nil && len(res.Rows) > 0 {
+		names, emails := s.applicationOwners(ctx, owners, app)
+		for i := range res.Rows {
+			res.Rows[i].OwnerName, res.Rows[i].OwnerEmail = names, emails
+		}
+	}
+	s.progress.appDone(app, res)
+	return res
+}
 
-	// Aggregate results and collect any errors
-	var errs []error
-	for res := range resultsChan {
-		if res.Err != nil {
-			errs = append(errs, res.Err)
-			continue
+// interleaveByOrganization orders apps round-robin by organization: the
+// first application of each organization, then the second of each, and so
+// on. Organizations keep the order of their first application, and
+// applications their order within the organization.
+func interleaveByOrganization(apps []client.Application) []client.Application {
+	var orgs []string
+	byOrg := make(map[string][]client.Application)
+	for _, app := range apps {
+		if _, ok := byOrg[app.OrganizationID]; !ok {
+			orgs = append(orgs, app.OrganizationID)
 		}
-		allViolationRows = append(allViolationRows, res.Rows...)
+		byOrg[app.OrganizationID] = append(byOrg[app.OrganizationID], app)
 	}
+	out := make([]client.Application, 0, len(apps))
+	for round := 0; len(out) < len(apps); round++ {
+		for _, org := range orgs {
+			if round < len(byOrg[org]) {
+				out = append(out, byOrg[org][round])
+			}
+		}
+	}
+	return out
+}
 
-	// =================================================================
-	// 3. CSV GENERATION AND FINAL PATH RETURN
-	// =================================================================
+// processApp fetches the latest report of a single application and returns
+// its violation rows. When ReportStages is set, the latest report of each
+// listed stage is fetched and the rows are merged, flagged by stage. Errors
+// are returned in the result for the aggregator. Violations of a report
+// referenced more than once are fetched once via fetches.
+func (s *IQReportService) processApp(ctx context.Context, app client.Application, orgIDToName map[string]string, fetches *violationFetches) AppReportResult {
+	appLogger := s.logger.With().Str("appPublicID", app.PublicID).Str("appInternalID", app.ID).Logger()
+
+	// Use the organization's scoped credentials when configured
+	appClient := s.clients.For(app.OrganizationID)
+	appCtx := client.WithApplication(ctx, app.PublicID)
+
+	// 2a. Fetch latest report info per stage, or with SetAsOf the reports
+	// that were latest at that time
+	var reportInfos []client.ReportInfo
+	var history []client.HistoricalReport // fetched once for SetAsOf or when validating counts
+	var err error
+	if s.asOf.IsZero() {
+		reportInfos, err = appClient.GetReportInfos(appCtx, app.ID)
+	} else if history, err = appClient.GetReportHistory(appCtx, app.ID); err == nil {
+		reportInfos = reportsAsOf(history, s.asOf)
+	}
+	if err != nil {
+		if res, ok := s.removedResult(appLogger, err); ok {
+			return res
+		}
+		return AppReportResult{Err: fmt.Errorf("app %s: %w", app.ID, err)}
+	}
+	selected := s.selectReports(reportInfos)
+
+	// 2b. Look up organization name
+	orgName, ok := orgIDToName[app.OrganizationID]
+	if !ok {
+		orgName = app.OrganizationID
+		// fallback to ID
+		appLogger.Debug().Str("orgID", app.OrganizationID).Msg("organization name not found, using ID as fallback")
+	}
+	scan := report.ApplicationScan{Application: app.PublicID, Organization: orgName}
+
+	// Skip if no report available
+	if len(selected) == 0 {
+		// No report found: return empty rows without error
+		return AppReportResult{Rows: nil, Scan: scan}
+	}
+
+	var rows []report.Row
+	var mismatches []report.CountMismatch
+	for _, reportInfo := range selected {
+		// 2c. Extract report ID and validate
+		reportID, err := reportInfo.ReportID()
+		if err != nil {
+			return AppReportResult{Err: fmt.Errorf("app %s: %w", app.ID, err)}
+		}
+		appLogger.Debug().Str("reportID", reportID).Str("stage", reportInfo.Stage).Msg("Parsed report ID")
+
+		// 2d. Fetch policy violations (returns []report.Row)
+		clientRows, err := fetches.get(app.PublicID, reportID, func() ([]report.Row, error) {
+			if !s.opts.ArchiveRawJSON {
+				return appClient.GetPolicyViolations(appCtx, app.PublicID, reportID, orgName)
+			}
+			var raw []byte
+			rawCtx := client.WithRawResponse(appCtx, func(body []byte) { raw = body })
+			rows, err := appClient.GetPolicyViolations(rawCtx, app.PublicID, reportID, orgName)
+			// Reports decoded as a stream (OVERSIZED_REPORTS) are not archived
+			if err == nil && raw != nil {
+				s.archiveRaw(appCtx, appLogger, app, reportInfo, reportID, raw)
+			}
+			return rows, err
+		})
+		if err != nil {
+			if res, ok := s.removedResult(appLogger, err); ok {
+				return res
+			}
+			return AppReportResult{Err: fmt.Errorf("app %s: get policy violations: %w", app.ID, err)}
+		}
+		if s.opts.ValidateCounts {
+			if history == nil {
+				if history, err = appClient.GetReportHistory(appCtx, app.ID); err != nil {
+					// Validation is a check on top of the export; keep the rows
+					appLogger.Warn().Err(err).Msg("Could not fetch report history, counts not validated")
+					history = []client.HistoricalReport{}
+				}
+			}
+			if m, ok := countMismatch(history, app.PublicID, reportInfo.Stage, reportID, clientRows); ok {
+				mismatches = append(mismatches, m)
+			}
+		}
+		for i := range clientRows {
+			clientRows[i].Stage = reportInfo.Stage
+		}
+		scan.Stages = append(scan.Stages, reportInfo.Stage)
+		if t := evaluationTime(reportInfo); t.After(scan.ScanDate) {
+			scan.ScanDate = t
+		}
+		appLogger.Debug().Int("rowsCount", len(clientRows)).Str("stage", reportInfo.Stage).Msg("Fetched policy violations")
 
-	target := filepath.Join(s.cfg.OutputDir, filename)
-	s.logger.Info().Str("path", target).Int("totalRows", len(allViolationRows)).Msg("Writing CSV report")
+		// 2e. Archive the PDF rendered by IQ Server
+		if s.opts.DownloadPDF {
+			s.archivePDF(appCtx, appClient, appLogger, app, reportInfo, reportID)
+		}
+		rows = append(rows, clientRows...)
+	}
 
-	if err := report.WriteCSV(target, allViolationRows, s.logger); err != nil {
-		return "", fmt.Errorf("write csv: %w", err)
+	// 2f. Join waiver expiry and creator for waived violations
+	if s.needsWaivers() && hasWaivedRows(rows) {
+		waivers, err := appClient.GetPolicyWaivers(appCtx, app.ID)
+		if err != nil {
+			// Waiver details are informational; keep the rows without them
+			appLogger.Warn().Err(err).Msg("Could not fetch policy waivers")
+		} else {
+			applyWaivers(rows, waivers)
+		}
 	}
 
-	s.logger.Info().Str("path", target).Msg("Report written successfully")
+	return AppReportResult{Rows: rows, Scan: scan, Mismatches: mismatches}
+}
 
-	if len(errs) > 0 {
-		return target, fmt.Errorf("encountered errors while fetching reports: %w", errors.Join(errs...))
+// archivePDF downloads the PDF of a report into OutputDir/pdf/ unless an
+// earlier run archived it already. Failures are logged only: the PDF is an
+// additional artifact and must not fail the report.
+func (s *IQReportService) archivePDF(ctx context.Context, cl *client.Client, logger zerolog.Logger, app client.Application, info client.ReportInfo, reportID string) {
+	dest := report.PDFPath(s.opts.OutputDir, app.PublicID, info.Stage, reportID)
+	if _, err := os.Stat(dest); err == nil {
+		logger.Debug().Str("path", dest).Msg("Report PDF already archived")
+		return
 	}
+	err := report.WritePDF(ctx, dest, logger, func(w io.Writer) error {
+		_, err := cl.DownloadReportPDF(ctx, info, app.PublicID, reportID, w)
+		return err
+	})
+	if err != nil {
+		logger.Warn().Err(err).Str("reportID", reportID).Msg("Could not download report PDF")
+		return
+	}
+	logger.Debug().Str("path", dest).Msg("Archived report PDF")
+}
 
-	return target, nil
+// archiveRaw stores the raw policy report response of a report, compressed,
+// in OutputDir/raw/ so that disputed rows can be traced back to what IQ
+// Server returned. Failures are logged only.
+func (s *IQReportService) archiveRaw(ctx context.Context, logger zerolog.Logger, app client.Application, info client.ReportInfo, reportID string, body []byte) {
+	dest := report.RawPath(s.opts.OutputDir, app.PublicID, info.Stage, reportID)
+	if err := report.WriteRawJSON(ctx, dest, body, logger); err != nil {
+		logger.Warn().Err(err).Str("reportID", reportID).Msg("Could not archive raw policy report")
+		return
+	}
+	logger.Debug().Str("path", dest).Msg("Archived raw policy report")
+}
+
+// removedResult turns a 404 from a report endpoint into a SkipRemoved result
+// unless REPORT_NOT_FOUND is set to "fail". The application or its report was
+// most likely deleted after the application list was fetched.
+func (s *IQReportService) removedResult(logger zerolog.Logger, err error) (AppReportResult, bool) {
+	if !errors.Is(err, client.ErrNotFound) || s.opts.NotFoundAction == config.NotFoundFail {
+		return AppReportResult{}, false
+	}
+	logger.Warn().Err(err).Msg("Skipped: application or report removed during run")
+	return AppReportResult{Skipped: SkipRemoved}, true
+}
+
+// writeExport writes rows in the report format of the service next to the
+// CSV report at target, returning the path written; the CSV format writes
+// nothing more.
+func (s *IQReportService) writeExport(ctx context.Context, target string, rows []report.Row, csvOpts []report.CSVOption) (string, error) {
+	switch s.opts.ReportFormat {
+	case "", report.ReportFormatCSV:
+		return "", nil
+	case report.ReportFormatXLSX:
+		return report.WriteXLSX(ctx, report.XLSXPath(target), rows, s.logger, csvOpts...)
+	case report.ReportFormatJSON:
+		return report.WriteJSON(ctx, report.JSONPath(target), rows, s.logger, csvOpts...)
+	case report.ReportFormatNDJSON:
+		return report.WriteNDJSON(ctx, report.NDJSONPath(target), rows, s.logger, csvOpts...)
+	default:
+		return "", fmt.Errorf("unknown report format %q", s.opts.ReportFormat)
+	}
+}
+
+// optionalColumns returns the optional columns of the service's reports:
+// those of CSVOptionalColumns, plus Stage when ReportStages merges the
+// reports of several stages.
+func (s *IQReportService) optionalColumns() []string {
+	if len(s.opts.ReportStages) == 0 || slices.Contains(s.opts.CSVOptionalColumns, report.ColumnStage) {
+		return s.opts.CSVOptionalColumns
+	}
+	return append(slices.Clone(s.opts.CSVOptionalColumns), report.ColumnStage)
+}
+
+// csvOptions returns the CSV writer options of the service's reports.
+func (s *IQReportService) csvOptions() []report.CSVOption {
+	return []report.CSVOption{
+		report.WithEmptyValue(s.opts.CSVEmptyValue),
+		report.WithColumnEmptyValues(s.opts.CSVEmptyValues),
+		report.WithOptionalColumns(s.optionalColumns()...),
+		report.WithTagColumns(s.opts.AppTagColumns...),
+		report.WithThreatFormat(s.opts.ThreatFormat),
+		report.WithRowNumbers(!s.opts.OmitRowNumbers),
+		report.WithFormulaEscaping(s.opts.EscapeFormulas),
+		report.WithUTF8BOM(s.opts.UTF8BOM),
+	}
 }
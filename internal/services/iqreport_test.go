@@ -4,17 +4,21 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/anmicius0/iqserver-report-fetch-go/internal/client"
 	"github.com/anmicius0/iqserver-report-fetch-go/internal/config"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/report"
 	"github.com/rs/zerolog"
 )
 
@@ -93,10 +97,11 @@ func TestGenerateLatestPolicyReport_Integration(t *testing.T) {
 
 	tmpDir := t.TempDir()
 	cfg := &config.Config{
-		IQServerURL: baseURL,
-		IQUsername:  "u",
-		IQPassword:  "p",
-		OutputDir:   tmpDir,
+		IQServerURL:   baseURL,
+		IQUsername:    "u",
+		IQPassword:    "p",
+		OutputDir:     tmpDir,
+		RollupReports: true,
 	}
 
 	svc := NewIQReportService(cfg, iqClient, testLogger())
@@ -123,6 +128,37 @@ func TestGenerateLatestPolicyReport_Integration(t *testing.T) {
 	if !strings.Contains(content, "maven") {
 		t.Errorf("format field 'maven' missing from output")
 	}
+
+	ab, err := os.ReadFile(filepath.Join(tmpDir, "report.applications.csv"))
+	if err != nil {
+		t.Fatalf("read application rollup: %v", err)
+	}
+	if !strings.Contains(string(ab), "apid-1,personal,build,,0,1,0,0,5") {
+		t.Errorf("unexpected application rollup:\n%s", ab)
+	}
+
+	ob, err := os.ReadFile(filepath.Join(tmpDir, "report.organizations.csv"))
+	if err != nil {
+		t.Fatalf("read organization rollup: %v", err)
+	}
+	if !strings.Contains(string(ob), "personal,1,0,1,0,0,1,5.00,apid-1,5") {
+		t.Errorf("unexpected organization rollup:\n%s", ob)
+	}
+
+	mb, err := os.ReadFile(filepath.Join(tmpDir, "report.manifest.json"))
+	if err != nil {
+		t.Fatalf("read manifest: %v", err)
+	}
+	var manifest report.Manifest
+	if err := json.Unmarshal(mb, &manifest); err != nil {
+		t.Fatalf("decode manifest: %v", err)
+	}
+	if manifest.Rows != 1 || manifest.Transfer.TotalBytes == 0 || manifest.Transfer.ByApplication["apid-1"] == 0 {
+		t.Errorf("unexpected manifest: %#v", manifest)
+	}
+	if !slices.Contains(manifest.Companions, filepath.Join(tmpDir, "report.vulnerabilities.csv")) {
+		t.Errorf("companions = %v, want the rollups and the vulnerability view", manifest.Companions)
+	}
 }
 
 func TestGenerateLatestPolicyReport_GetApplicationsError(t *testing.T) {
@@ -174,6 +210,219 @@ func TestGenerateLatestPolicyReport_NoApplicationsFound(t *testing.T) {
 	}
 }
 
+func TestGenerateLatestPolicyReport_UsesOrganizationClient(t *testing.T) {
+	// Report endpoints only accept the scoped service account for org-2
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		user, _, _ := r.BasicAuth()
+		switch r.URL.Path {
+		case "/api/v2/applications":
+			_, _ = w.Write([]byte(`{"applications": [{"id": "aid-2", "publicId": "apid-2", "organizationId": "org-2"}]}`))
+		case "/api/v2/organizations":
+			_, _ = w.Write([]byte(`{"organizations": [{"id": "org-2", "name": "scoped"}]}`))
+		case "/api/v2/reports/applications/aid-2":
+			if user != "svc-2" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			_, _ = w.Write([]byte(`[{"stage": "build", "reportHtmlUrl": "https://stub/report/rpt-2"}]`))
+		case "/api/v2/applications/apid-2/reports/rpt-2/policy":
+			if user != "svc-2" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			_, _ = w.Write([]byte(`{"components": []}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	baseURL := server.URL + "/api/v2"
+	defClient, _ := client.NewClient(baseURL, "admin", "p", testLogger())
+	scopedClient, _ := client.NewClient(baseURL, "svc-2", "p", testLogger())
+	pool := client.NewPool(defClient)
+	pool.Add("org-2", scopedClient)
+
+	cfg := &config.Config{OutputDir: t.TempDir()}
+	svc := NewIQReportServiceWithPool(cfg, pool, testLogger())
+
+	if _, err := svc.GenerateLatestPolicyReport(rCtx(t), "report.csv"); err != nil {
+		t.Fatalf("GenerateLatestPolicyReport: %v", err)
+	}
+}
+
+func TestGenerateLatestPolicyReport_RemovedApplication(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v2/applications":
+			_, _ = w.Write([]byte(`{"applications": [{"id": "aid-1", "publicId": "apid-1", "organizationId": "org-1"}]}`))
+		case "/api/v2/organizations":
+			_, _ = w.Write([]byte(`{"organizations": []}`))
+		default:
+			// Application deleted after listing
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	iqClient, _ := client.NewClient(server.URL+"/api/v2", "u", "p", testLogger())
+
+	t.Run("Warn", func(t *testing.T) {
+		dir := t.TempDir()
+		svc := NewIQReportService(&config.Config{OutputDir: dir, NotFoundAction: config.NotFoundWarn}, iqClient, testLogger())
+		if _, err := svc.GenerateLatestPolicyReport(rCtx(t), "report.csv"); err != nil {
+			t.Fatalf("GenerateLatestPolicyReport: %v", err)
+		}
+		b, err := os.ReadFile(filepath.Join(dir, "report.manifest.json"))
+		if err != nil {
+			t.Fatalf("read manifest: %v", err)
+		}
+		var manifest report.Manifest
+		if err := json.Unmarshal(b, &manifest); err != nil {
+			t.Fatalf("decode manifest: %v", err)
+		}
+		if manifest.Skipped[SkipRemoved] != 1 || manifest.Errors != 0 {
+			t.Errorf("unexpected manifest: %#v", manifest)
+		}
+	})
+
+	t.Run("Fail", func(t *testing.T) {
+		svc := NewIQReportService(&config.Config{OutputDir: t.TempDir(), NotFoundAction: config.NotFoundFail}, iqClient, testLogger())
+		_, err := svc.GenerateLatestPolicyReport(rCtx(t), "report.csv")
+		if !errors.Is(err, client.ErrNotFound) {
+			t.Fatalf("expected not found error, got %v", err)
+		}
+	})
+}
+
+func TestGenerateLatestPolicyReport_PinnedApplicationList(t *testing.T) {
+	var listed bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v2/applications":
+			listed = true
+			_, _ = w.Write([]byte(`{"applications": []}`))
+		case "/api/v2/organizations":
+			_, _ = w.Write([]byte(`{"organizations": []}`))
+		case "/api/v2/reports/applications/aid-9":
+			_, _ = w.Write([]byte(`[]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	pinned := filepath.Join(dir, "apps.json")
+	if err := SaveApplicationList(pinned, []client.Application{{ID: "aid-9", PublicID: "apid-9", OrganizationID: "org-1"}}); err != nil {
+		t.Fatalf("SaveApplicationList: %v", err)
+	}
+
+	iqClient, _ := client.NewClient(server.URL+"/api/v2", "u", "p", testLogger())
+	svc := NewIQReportService(&config.Config{OutputDir: dir, PinnedAppsFile: pinned}, iqClient, testLogger())
+	if _, err := svc.GenerateLatestPolicyReport(rCtx(t), "report.csv"); err != nil {
+		t.Fatalf("GenerateLatestPolicyReport: %v", err)
+	}
+	if listed {
+		t.Error("applications were listed despite pinned snapshot")
+	}
+}
+
+func TestGenerateLatestPolicyReport_MergesStages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v2/applications":
+			_, _ = w.Write([]byte(`{"applications": [{"id": "aid-1", "publicId": "apid-1", "organizationId": "org-1"}]}`))
+		case "/api/v2/organizations":
+			_, _ = w.Write([]byte(`{"organizations": []}`))
+		case "/api/v2/reports/applications/aid-1":
+			_, _ = w.Write([]byte(`[
+				{"stage": "build", "reportHtmlUrl": "https://stub/report/rpt-build"},
+				{"stage": "release", "reportHtmlUrl": "https://stub/report/rpt-release"},
+				{"stage": "operate", "reportDataUrl": "api/v2/applications/apid-1/reports/rpt-operate"}
+			]`))
+		case "/api/v2/applications/apid-1/reports/rpt-build/policy", "/api/v2/applications/apid-1/reports/rpt-operate/policy":
+			_, _ = w.Write([]byte(`{"components": [{"displayName": "comp", "violations": [{"policyName": "P", "policyThreatLevel": 9, "constraints": [{"constraintName": "C"}]}]}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	iqClient, _ := client.NewClient(server.URL+"/api/v2", "u", "p", testLogger())
+	cfg := &config.Config{OutputDir: dir, ReportStages: []string{"build", "operate"}}
+	path, err := NewIQReportService(cfg, iqClient, testLogger()).GenerateLatestPolicyReport(rCtx(t), "report.csv")
+	if err != nil {
+		t.Fatalf("GenerateLatestPolicyReport: %v", err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read csv: %v", err)
+	}
+	content := string(b)
+	if header, _, _ := strings.Cut(content, "\n"); !strings.HasSuffix(header, ","+report.ColumnStage) {
+		t.Errorf("header = %q, want the Stage column enabled by ReportStages", header)
+	}
+	if !strings.Contains(content, ",build") || !strings.Contains(content, ",operate") || strings.Contains(content, ",release") {
+		t.Errorf("expected build and operate rows only, got:\n%s", content)
+	}
+}
+
+// recordingSink is a Sink that records published rows and optionally fails.
+type recordingSink struct {
+	rows []report.Row
+	err  error
+}
+
+func (r *recordingSink) Name() string { return "recording" }
+
+func (r *recordingSink) Publish(_ context.Context, rows []report.Row) error {
+	r.rows = rows
+	return r.err
+}
+
+func TestGenerateLatestPolicyReport_PublishesToSinks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v2/applications":
+			_, _ = w.Write([]byte(`{"applications": [{"id": "aid-1", "publicId": "apid-1", "organizationId": "org-1"}]}`))
+		case "/api/v2/organizations":
+			_, _ = w.Write([]byte(`{"organizations": []}`))
+		case "/api/v2/reports/applications/aid-1":
+			_, _ = w.Write([]byte(`[{"stage": "build", "reportHtmlUrl": "https://stub/report/rpt-1"}]`))
+		case "/api/v2/applications/apid-1/reports/rpt-1/policy":
+			_, _ = w.Write([]byte(`{"components": [{"displayName": "comp", "violations": [{"policyName": "P", "policyThreatLevel": 9, "constraints": [{"constraintName": "C"}]}]}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	iqClient, _ := client.NewClient(server.URL+"/api/v2", "u", "p", testLogger())
+	svc := NewIQReportService(&config.Config{OutputDir: t.TempDir()}, iqClient, testLogger())
+	ok := &recordingSink{}
+	failing := &recordingSink{err: errors.New("boom")}
+	svc.AddSink(failing)
+	svc.AddSink(ok)
+
+	path, err := svc.GenerateLatestPolicyReport(rCtx(t), "report.csv")
+	if err == nil || !strings.Contains(err.Error(), "sink recording: boom") {
+		t.Fatalf("expected sink error, got %v", err)
+	}
+	if path == "" {
+		t.Error("report path not returned despite sink failure")
+	}
+	if len(ok.rows) != 1 || ok.rows[0].Policy != "P" {
+		t.Errorf("sink after failing sink got rows %#v", ok.rows)
+	}
+}
+
 // rCtx returns a cancellable context with a small timeout and ensures cancel via t.Cleanup.
 func rCtx(t *testing.T) context.Context {
 	t.Helper()
@@ -181,3 +430,211 @@ func rCtx(t *testing.T) context.Context {
 	t.Cleanup(cancel)
 	return ctx
 }
+
+// BenchmarkGenerateLatestPolicyReport measures the concurrent fetch and
+// aggregation path end to end against a stub server: 200 applications with
+// 500 violations each (100k rows).
+func BenchmarkGenerateLatestPolicyReport(b *testing.B) {
+	const apps, violations = 200, 500
+
+	var appList strings.Builder
+	appList.WriteString(`{"applications": [`)
+	for i := range apps {
+		if i > 0 {
+			appList.WriteString(",")
+		}
+		fmt.Fprintf(&appList, `{"id": "aid-%d", "publicId": "app-%d", "organizationId": "org-1"}`, i, i)
+	}
+	appList.WriteString(`]}`)
+
+	var policy strings.Builder
+	policy.WriteString(`{"components": [`)
+	for i := range violations {
+		if i > 0 {
+			policy.WriteString(",")
+		}
+		fmt.Fprintf(&policy, `{"displayName": "org.example:lib-%d:1.0.0", "componentIdentifier": {"format": "maven"}, "violations": [{"policyViolationId": "pv-%d", "policyName": "Security-High", "policyThreatLevel": %d, "policyThreatCategory": "SECURITY", "constraints": [{"constraintName": "High Risk CVEs", "conditions": [{"conditionSummary": "CVE Count >= 1"}]}]}]}`, i, i, i%11)
+	}
+	policy.WriteString(`]}`)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/api/v2/applications":
+			_, _ = w.Write([]byte(appList.String()))
+		case r.URL.Path == "/api/v2/organizations":
+			_, _ = w.Write([]byte(`{"organizations": [{"id": "org-1", "name": "Org"}]}`))
+		case strings.HasPrefix(r.URL.Path, "/api/v2/reports/applications/"):
+			_, _ = w.Write([]byte(`[{"stage": "build", "reportDataUrl": "api/v2/applications/x/reports/rpt-1"}]`))
+		case strings.HasSuffix(r.URL.Path, "/policy"):
+			_, _ = w.Write([]byte(policy.String()))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	iqClient, _ := client.NewClient(server.URL, "u", "p", testLogger())
+	svc := NewIQReportService(&config.Config{OutputDir: b.TempDir()}, iqClient, testLogger())
+
+	b.ReportAllocs()
+	n := 0
+	for b.Loop() {
+		n++
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		_, err := svc.GenerateLatestPolicyReport(ctx, fmt.Sprintf("bench-%d.csv", n))
+		cancel()
+		if err != nil {
+			b.Fatalf("GenerateLatestPolicyReport: %v", err)
+		}
+	}
+}
+
+func TestGenerateLatestPolicyReport_ArchivesArtifacts(t *testing.T) {
+	downloads := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v2/applications":
+			_, _ = w.Write([]byte(`{"applications": [{"id": "aid-1", "publicId": "app-1"}]}`))
+		case "/api/v2/organizations":
+			_, _ = w.Write([]byte(`{"organizations": []}`))
+		case "/api/v2/reports/applications/aid-1":
+			_, _ = w.Write([]byte(`[{"stage": "build", "reportHtmlUrl": "https://stub/report/rpt-1", "reportPdfUrl": "ui/links/application/app-1/report/rpt-1/pdf"}]`))
+		case "/api/v2/applications/app-1/reports/rpt-1/policy":
+			_, _ = w.Write([]byte(`{"components": []}`))
+		case "/ui/links/application/app-1/report/rpt-1/pdf":
+			downloads++
+			w.Header().Set("Content-Type", "application/pdf")
+			_, _ = w.Write([]byte("%PDF-1.4"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	iqClient, _ := client.NewClient(server.URL, "u", "p", testLogger())
+	svc := NewIQReportService(&config.Config{OutputDir: dir, DownloadPDF: true, ArchiveRawJSON: true}, iqClient, testLogger())
+	for _, name := range []string{"first.csv", "second.csv"} {
+		if _, err := svc.GenerateLatestPolicyReport(rCtx(t), name); err != nil {
+			t.Fatalf("GenerateLatestPolicyReport: %v", err)
+		}
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, "pdf", "app-1_build_rpt-1.pdf"))
+	if err != nil || string(b) != "%PDF-1.4" {
+		t.Fatalf("archived PDF = %q, %v", b, err)
+	}
+	if downloads != 1 {
+		t.Errorf("PDF downloaded %d times, want once", downloads)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "raw", "app-1_build_rpt-1.json.gz")); err != nil {
+		t.Errorf("raw policy report not archived: %v", err)
+	}
+}
+
+func TestGenerateLatestPolicyReport_ChunksCSV(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v2/applications":
+			_, _ = w.Write([]byte(`{"applications": [{"id": "aid-1", "publicId": "app-1"}]}`))
+		case "/api/v2/organizations":
+			_, _ = w.Write([]byte(`{"organizations": []}`))
+		case "/api/v2/reports/applications/aid-1":
+			_, _ = w.Write([]byte(`[{"stage": "build", "reportHtmlUrl": "https://stub/report/rpt-1"}]`))
+		case "/api/v2/applications/app-1/reports/rpt-1/policy":
+			_, _ = w.Write([]byte(`{"components": [
+				{"displayName": "a", "violations": [{"policyName": "P", "policyThreatLevel": 9, "constraints": [{"constraintName": "C"}]}]},
+				{"displayName": "b", "violations": [{"policyName": "P", "policyThreatLevel": 9, "constraints": [{"constraintName": "C"}]}]},
+				{"displayName": "c", "violations": [{"policyName": "P", "policyThreatLevel": 9, "constraints": [{"constraintName": "C"}]}]}
+			]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	iqClient, _ := client.NewClient(server.URL, "u", "p", testLogger())
+	svc := NewIQReportService(&config.Config{OutputDir: dir, CSVChunkRows: 2}, iqClient, testLogger())
+	path, err := svc.GenerateLatestPolicyReport(rCtx(t), "report.csv")
+	if err != nil {
+		t.Fatalf("GenerateLatestPolicyReport: %v", err)
+	}
+	if path != filepath.Join(dir, "report.index.csv") {
+		t.Errorf("path = %q, want the chunk index", path)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "report.csv")); !os.IsNotExist(err) {
+		t.Errorf("unchunked report written: %v", err)
+	}
+	m, err := report.ReadManifest(report.ManifestPath(filepath.Join(dir, "report.csv")))
+	if err != nil {
+		t.Fatalf("ReadManifest: %v", err)
+	}
+	if len(m.Chunks) != 2 || m.ReportPath != path || m.Rows != 3 {
+		t.Errorf("manifest = %+v", m)
+	}
+	// Rollups are only written with RollupReports
+	if _, err := os.Stat(report.ApplicationsPath(filepath.Join(dir, "report.csv"))); !os.IsNotExist(err) {
+		t.Errorf("application rollup written by default: %v", err)
+	}
+	if len(m.Companions) != 0 {
+		t.Errorf("companions = %v, want none", m.Companions)
+	}
+}
+
+func TestGenerateLatestPolicyReport_ReportFormat(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v2/applications":
+			_, _ = w.Write([]byte(`{"applications": [{"id": "aid-1", "publicId": "app-1"}]}`))
+		case "/api/v2/organizations":
+			_, _ = w.Write([]byte(`{"organizations": []}`))
+		case "/api/v2/reports/applications/aid-1":
+			_, _ = w.Write([]byte(`[{"stage": "build", "reportHtmlUrl": "https://stub/report/rpt-1"}]`))
+		case "/api/v2/applications/app-1/reports/rpt-1/policy":
+			_, _ = w.Write([]byte(`{"components": [
+				{"displayName": "a", "violations": [{"policyName": "P", "policyThreatLevel": 9, "constraints": [{"constraintName": "C"}]}]},
+				{"displayName": "b", "violations": [{"policyName": "P", "policyThreatLevel": 7, "constraints": [{"constraintName": "C"}]}]}
+			]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	for format, name := range map[string]string{"xlsx": "report.xlsx", "json": "report.json", "ndjson": "report.ndjson"} {
+		t.Run(format, func(t *testing.T) {
+			dir := t.TempDir()
+			iqClient, _ := client.NewClient(server.URL, "u", "p", testLogger())
+			svc := NewIQReportService(&config.Config{OutputDir: dir, ReportFormat: format, ThreatFormat: report.ThreatFormatLabeled}, iqClient, testLogger())
+			path, err := svc.GenerateLatestPolicyReport(rCtx(t), "report.csv")
+			if err != nil {
+				t.Fatalf("GenerateLatestPolicyReport: %v", err)
+			}
+			if path != filepath.Join(dir, "report.csv") {
+				t.Errorf("path = %q, want the CSV report", path)
+			}
+			m, err := report.ReadManifest(report.ManifestPath(path))
+			if err != nil {
+				t.Fatalf("ReadManifest: %v", err)
+			}
+			if m.Export != filepath.Join(dir, name) {
+				t.Errorf("manifest export = %q, want %s", m.Export, name)
+			}
+			if fi, err := os.Stat(m.Export); err != nil || fi.Size() == 0 {
+				t.Errorf("export not written: %v", err)
+			}
+			// THREAT_FORMAT applies to the JSON formats too
+			if format != "xlsx" {
+				data, _ := os.ReadFile(m.Export)
+				if !strings.Contains(string(data), `"threat":9,"threatLabel":"Critical (9)"`) {
+					t.Errorf("export lacks the labeled threat:\n%s", data)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+// internal/services/layout.go
+package services
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/report"
+)
+
+// writeSplitOutputs writes one CSV per processed application at the path
+// given by OutputLayout below OutputDir. Applications without
+// violations get a header-only file. reportPath names the {{report}}
+// placeholder. It returns the number of files written.
+func (s *IQReportService) writeSplitOutputs(ctx context.Context, reportPath string, scans []report.ApplicationScan, rows []report.Row, now time.Time, opts []report.CSVOption) (int, error) {
+	byApp := make(map[string][]report.Row)
+	for _, r := range rows {
+		byApp[r.Application] = append(byApp[r.Application], r)
+	}
+	orgs := make(map[string]string, len(scans))
+	for _, sc := range scans {
+		orgs[sc.Application] = sc.Organization
+		if _, ok := byApp[sc.Application]; !ok {
+			byApp[sc.Application] = nil
+		}
+	}
+
+	base := filepath.Base(reportPath)
+	vars := report.LayoutVars{
+		Date:   now.Format(time.DateOnly),
+		Report: strings.TrimSuffix(base, filepath.Ext(base)),
+	}
+	written := make(map[string]string, len(byApp))
+	for app, appRows := range byApp {
+		vars.Application = app
+		vars.Organization = orgs[app]
+		if vars.Organization == "" && len(appRows) > 0 {
+			vars.Organization = appRows[0].Organization
+		}
+		dest := report.LayoutPath(s.opts.OutputDir, s.opts.OutputLayout, vars)
+		// Two applications must not overwrite each other's file
+		if other, ok := written[dest]; ok {
+			return len(written), fmt.Errorf("output layout maps applications %s and %s to %s; include {{app}}", other, app, dest)
+		}
+		if _, err := report.WriteCSV(ctx, dest, appRows, s.logger, opts...); err != nil {
+			return len(written), fmt.Errorf("app %s: %w", app, err)
+		}
+		written[dest] = app
+	}
+	return len(written), nil
+}
@@ -0,0 +1,42 @@
+// internal/services/layout_test.go
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/report"
+)
+
+func TestWriteSplitOutputs(t *testing.T) {
+	dir := t.TempDir()
+	svc := NewIQReportServiceWithOptions(ServiceOptions{OutputDir: dir, OutputLayout: "{{org}}/{{app}}/{{date}}/policy.csv"}, nil, testLogger())
+	scans := []report.ApplicationScan{
+		{Application: "app-1", Organization: "Org A"},
+		{Application: "app-2", Organization: "Org B"},
+	}
+	rows := []report.Row{{Application: "app-1", Organization: "Org A", Policy: "P"}}
+	now := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	n, err := svc.writeSplitOutputs(rCtx(t), filepath.Join(dir, "report.csv"), scans, rows, now, nil)
+	if err != nil || n != 2 {
+		t.Fatalf("writeSplitOutputs = %d, %v", n, err)
+	}
+	b, err := os.ReadFile(filepath.Join(dir, "Org A", "app-1", "2025-01-15", "policy.csv"))
+	if err != nil || !strings.Contains(string(b), "1,app-1,Org A,P,") {
+		t.Errorf("app-1 report = %q, %v", b, err)
+	}
+	b, err = os.ReadFile(filepath.Join(dir, "Org B", "app-2", "2025-01-15", "policy.csv"))
+	if err != nil || strings.Count(string(b), "\n") != 1 {
+		t.Errorf("app-2 report should be header-only, got %q, %v", b, err)
+	}
+
+	svc.opts.OutputLayout = "{{org}}/policy.csv"
+	scans[1].Organization = "Org A"
+	if _, err := svc.writeSplitOutputs(rCtx(t), filepath.Join(dir, "report.csv"), scans, rows, now, nil); err == nil {
+		t.Error("expected error when applications share a path")
+	}
+}
@@ -0,0 +1,100 @@
+// internal/services/listing.go
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/client"
+)
+
+// PartialListingError reports organizations whose applications could not be
+// listed. The applications of the other organizations are returned along
+// with it and can be processed.
+type PartialListingError struct {
+	Failed map[string]error // organization ID -> error
+}
+
+// Error lists the failed organizations.
+func (e *PartialListingError) Error() string {
+	ids := e.Organizations()
+	return fmt.Sprintf("could not list applications of %d organization(s): %s", len(ids), strings.Join(ids, ", "))
+}
+
+// Unwrap returns the errors of the failed organizations.
+func (e *PartialListingError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Failed))
+	for _, id := range e.Organizations() {
+		errs = append(errs, e.Failed[id])
+	}
+	return errs
+}
+
+// Organizations returns the IDs of the failed organizations, sorted.
+func (e *PartialListingError) Organizations() []string {
+	ids := make([]string, 0, len(e.Failed))
+	for id := range e.Failed {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// listApplicationsByOrganization lists the applications of every
+// organization concurrently, at most Concurrency at a time, and merges them
+// in organization order. When only some organizations fail, their errors are
+// returned as a *PartialListingError together with the other applications.
+func (s *IQReportService) listApplicationsByOrganization(ctx context.Context) ([]client.Application, error) {
+	orgs, err := s.clients.Default().GetOrganizations(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get organizations: %w", err)
+	}
+
+	perOrg := make([][]client.Application, len(orgs))
+	failed := make(map[string]error)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, s.opts.Concurrency)
+	for i, org := range orgs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				return
+			}
+			apps, err := s.clients.For(org.ID).GetApplicationsByOrganization(ctx, org.ID)
+			if err != nil {
+				mu.Lock()
+				failed[org.ID] = fmt.Errorf("organization %s: %w", org.ID, err)
+				mu.Unlock()
+				return
+			}
+			perOrg[i] = apps
+		}()
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("list applications: %w", err)
+	}
+
+	var apps []client.Application
+	for _, orgApps := range perOrg {
+		apps = append(apps, orgApps...)
+	}
+	s.logger.Info().Int("organizations", len(orgs)).Int("failed", len(failed)).Int("applications", len(apps)).Msg("Listed applications per organization")
+	if len(failed) == 0 {
+		return apps, nil
+	}
+	partial := &PartialListingError{Failed: failed}
+	if len(failed) == len(orgs) {
+		return nil, fmt.Errorf("list applications: %w", errors.Join(partial.Unwrap()...))
+	}
+	return apps, partial
+}
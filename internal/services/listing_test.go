@@ -0,0 +1,94 @@
+// internal/services/listing_test.go
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/client"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/config"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/report"
+)
+
+func listingStub(t *testing.T, failOrgs ...string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v2/applications":
+			t.Errorf("instance-wide listing called")
+			http.Error(w, "timeout", http.StatusGatewayTimeout)
+		case "/api/v2/organizations":
+			_, _ = w.Write([]byte(`{"organizations": [{"id": "org-1", "name": "One"}, {"id": "org-2", "name": "Two"}]}`))
+		case "/api/v2/applications/organization/org-1", "/api/v2/applications/organization/org-2":
+			org := filepath.Base(r.URL.Path)
+			if slices.Contains(failOrgs, org) {
+				http.Error(w, "boom", http.StatusInternalServerError)
+				return
+			}
+			_, _ = w.Write([]byte(`{"applications": [{"id": "aid-` + org + `", "publicId": "app-` + org + `", "organizationId": "` + org + `"}]}`))
+		case "/api/v2/reports/applications/aid-org-1", "/api/v2/reports/applications/aid-org-2":
+			_, _ = w.Write([]byte(`[]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestListApplicationsByOrganization(t *testing.T) {
+	server := listingStub(t)
+	iqClient, _ := client.NewClient(server.URL, "u", "p", testLogger())
+	svc := NewIQReportServiceWithOptions(ServiceOptions{ListAppsByOrganization: true}, client.NewPool(iqClient), testLogger())
+
+	apps, err := svc.applications(rCtx(t))
+	if err != nil {
+		t.Fatalf("applications: %v", err)
+	}
+	if len(apps) != 2 || apps[0].PublicID != "app-org-1" || apps[1].PublicID != "app-org-2" {
+		t.Errorf("apps = %+v", apps)
+	}
+
+	ctx, cancel := context.WithCancel(rCtx(t))
+	cancel()
+	if _, err := svc.applications(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected cancellation error, got %v", err)
+	}
+}
+
+func TestGenerateLatestPolicyReport_PartialListing(t *testing.T) {
+	server := listingStub(t, "org-2")
+	iqClient, _ := client.NewClient(server.URL, "u", "p", testLogger())
+	dir := t.TempDir()
+	svc := NewIQReportService(&config.Config{OutputDir: dir, ListAppsByOrganization: true}, iqClient, testLogger())
+
+	path, err := svc.GenerateLatestPolicyReport(rCtx(t), "report.csv")
+	var partial *PartialListingError
+	if !errors.As(err, &partial) || !slices.Equal(partial.Organizations(), []string{"org-2"}) {
+		t.Fatalf("expected partial listing error for org-2, got %v", err)
+	}
+	if path == "" {
+		t.Fatal("report of the listed applications not written")
+	}
+	m, err := report.ReadManifest(report.ManifestPath(filepath.Join(dir, "report.csv")))
+	if err != nil {
+		t.Fatalf("ReadManifest: %v", err)
+	}
+	if m.Applications != 1 || !slices.Equal(m.UnlistedOrganizations, []string{"org-2"}) {
+		t.Errorf("manifest = %+v", m)
+	}
+
+	// All organizations failing is a listing failure
+	server = listingStub(t, "org-1", "org-2")
+	iqClient, _ = client.NewClient(server.URL, "u", "p", testLogger())
+	svc = NewIQReportService(&config.Config{OutputDir: dir, ListAppsByOrganization: true}, iqClient, testLogger())
+	if path, err := svc.GenerateLatestPolicyReport(rCtx(t), "other.csv"); err == nil || path != "" {
+		t.Errorf("expected failure, got %q, %v", path, err)
+	}
+}
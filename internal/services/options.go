@@ -0,0 +1,157 @@
+// internal/services/options.go
+package services
+
+import (
+	"time"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/client"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/config"
+	"github.com/rs/zerolog"
+)
+
+// DefaultConcurrency is the number of applications processed in parallel
+// when ServiceOptions.Concurrency is zero.
+const DefaultConcurrency = 10
+
+// ServiceOptions configures an IQReportService. The zero value of every
+// field is a working default, so library users only set what they need;
+// OptionsFromConfig derives the options from the environment configuration.
+// String-valued choices take the values of the corresponding config
+// constants (e.g. config.NotFoundFail).
+type ServiceOptions struct {
+	// Output directory of the report and its companion files.
+	OutputDir string
+	// Applications processed in parallel; zero uses DefaultConcurrency.
+	Concurrency int
+
+	// Application list snapshots: load the list from PinnedAppsFile instead
+	// of listing applications, or save the live list to SaveAppsFile.
+	PinnedAppsFile string
+	SaveAppsFile   string
+	// List applications per organization, concurrently, instead of with a
+	// single call that times out on very large instances.
+	ListAppsByOrganization bool
+	// Look up organizations referenced by applications but missing from the
+	// organization list by ID, instead of falling back to the raw ID.
+	ResolveOrphanedOrgs bool
+	// Sharding: process only the applications of shard ShardIndex (from
+	// zero) of ShardTotal; a ShardTotal below 2 processes all applications.
+	ShardIndex int
+	ShardTotal int
+
+	// Report selection: stages to export (empty exports one report) and the
+	// policy choosing among candidates (config.Select*, default first).
+	ReportStages          []string
+	ReportSelection       string
+	ReportStagePreference []string
+
+	// Row filters: threat categories to keep (empty keeps all), whether to
+	// drop proprietary and InnerSource components, a filter expression (see
+	// report.ParseFilter) and a YAML file of accepted risks.
+	ThreatCategories   []string
+	ExcludeProprietary bool
+	Filter             string
+	SuppressionsFile   string
+
+	// Strictness: handling of applications removed during the run
+	// (config.NotFoundWarn by default), sanity checks before publishing
+	// (zero disables a check; config.SanityFail by default) and validation of
+	// parsed violation counts against IQ Server summaries.
+	NotFoundAction       string
+	SanityMinRows        int
+	SanityMinAppCoverage float64
+	SanityMaxRowDelta    float64
+	SanityAction         string
+	ValidateCounts       bool
+	// Free space check of the output directory before fetching
+	// (config.DiskSpace*; empty fails like config.DiskSpaceFail).
+	DiskSpaceCheck string
+
+	// Output format and artifacts.
+	CVERows            string             // config.CVERowsAggregate (default) or config.CVERowsSplit
+	CSVEmptyValue      string             // placeholder for empty cells
+	CSVEmptyValues     map[string]string  // placeholder per column header
+	CSVOptionalColumns []string           // see report.OptionalColumns
+	OmitRowNumbers     bool               // leave out the "No." column
+	EscapeFormulas     bool               // see report.WithFormulaEscaping
+	UTF8BOM            bool               // see report.WithUTF8BOM
+	ThreatFormat       string             // see report.FormatThreat
+	AppTagColumns      []string           // application tag keys written as columns
+	OwnerRole          string             // role of application owners; empty uses DefaultOwnerRole
+	OwnerReports       bool               // write a personal report per owner
+	EnrichmentDir      string             // read enrichment snapshots from here, see BuildEnrichmentSnapshots
+	CSVChunkRows       int                // split the report into files of this many rows; zero disables
+	ReportFormat       string             // see report.ReportFormats; empty is report.ReportFormatCSV
+	OutputLayout       string             // per-application reports, see report.LayoutPath
+	LatestLink         bool               // see report.UpdateLatest
+	KeepReports        int                // see report.RotateReports; zero keeps all
+	RiskWeights        map[string]float64 // per threat band; nil uses report.DefaultRiskWeights
+	RollupReports      bool               // write the rollups and the vulnerability view
+	SLADays            map[string]int     // per threat band; writes the SLA breach report when set
+	DownloadPDF        bool
+	ArchiveRawJSON     bool
+
+	// AggregationTimeout bounds filtering and writing the outputs, separately
+	// from the deadline of the context used for fetching; zero disables it.
+	AggregationTimeout time.Duration
+}
+
+// OptionsFromConfig returns the service options set in cfg.
+func OptionsFromConfig(cfg *config.Config) ServiceOptions {
+	return ServiceOptions{
+		OutputDir:              cfg.OutputDir,
+		PinnedAppsFile:         cfg.PinnedAppsFile,
+		SaveAppsFile:           cfg.SaveAppsFile,
+		ListAppsByOrganization: cfg.ListAppsByOrganization,
+		ResolveOrphanedOrgs:    cfg.ResolveOrphanedOrgs,
+		ShardIndex:             cfg.ShardIndex,
+		ShardTotal:             cfg.ShardTotal,
+		ReportStages:           cfg.ReportStages,
+		ReportSelection:        cfg.ReportSelection,
+		ReportStagePreference:  cfg.ReportStagePreference,
+		ThreatCategories:       cfg.ThreatCategories,
+		ExcludeProprietary:     cfg.ExcludeProprietary,
+		Filter:                 cfg.Filter,
+		SuppressionsFile:       cfg.SuppressionsFile,
+		NotFoundAction:         cfg.NotFoundAction,
+		SanityMinRows:          cfg.SanityMinRows,
+		SanityMinAppCoverage:   cfg.SanityMinAppCoverage,
+		SanityMaxRowDelta:      cfg.SanityMaxRowDelta,
+		SanityAction:           cfg.SanityAction,
+		DiskSpaceCheck:         cfg.DiskSpaceCheck,
+		ValidateCounts:         cfg.ValidateCounts,
+		CVERows:                cfg.CVERows,
+		CSVEmptyValue:          cfg.CSVEmptyValue,
+		CSVEmptyValues:         cfg.CSVEmptyValues,
+		CSVOptionalColumns:     cfg.CSVOptionalColumns,
+		OmitRowNumbers:         cfg.CSVOmitRowNumbers,
+		EscapeFormulas:         cfg.CSVEscapeFormulas,
+		UTF8BOM:                cfg.CSVUTF8BOM,
+		ThreatFormat:           cfg.ThreatFormat,
+		AppTagColumns:          cfg.AppTagColumns,
+		OwnerRole:              cfg.OwnerRole,
+		OwnerReports:           cfg.OwnerReports,
+		EnrichmentDir:          cfg.EnrichmentDir,
+		CSVChunkRows:           cfg.CSVChunkRows,
+		ReportFormat:           cfg.ReportFormat,
+		OutputLayout:           cfg.OutputLayout,
+		LatestLink:             cfg.LatestLink,
+		KeepReports:            cfg.KeepReports,
+		RiskWeights:            cfg.RiskWeights,
+		RollupReports:          cfg.RollupReports,
+		SLADays:                cfg.SLADays,
+		DownloadPDF:            cfg.DownloadPDF,
+		ArchiveRawJSON:         cfg.ArchiveRawJSON,
+		AggregationTimeout:     time.Duration(cfg.AggregationTimeoutSeconds) * time.Second,
+	}
+}
+
+// NewIQReportServiceWithOptions creates a new IQReportService configured with
+// opts, for use without the environment-based configuration. The client is
+// selected per application organization from pool.
+func NewIQReportServiceWithOptions(opts ServiceOptions, pool *client.Pool, logger zerolog.Logger) *IQReportService {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DefaultConcurrency
+	}
+	return &IQReportService{opts: opts, clients: pool, logger: logger}
+}
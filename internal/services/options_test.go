@@ -0,0 +1,52 @@
+// internal/services/options_test.go
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/client"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/config"
+)
+
+func TestOptionsFromConfig(t *testing.T) {
+	cfg := &config.Config{OutputDir: "out", ReportStages: []string{"build"}, SanityAction: config.SanityWarn, CSVChunkRows: 5}
+	opts := OptionsFromConfig(cfg)
+	if opts.OutputDir != "out" || len(opts.ReportStages) != 1 || opts.SanityAction != config.SanityWarn || opts.CSVChunkRows != 5 {
+		t.Errorf("OptionsFromConfig = %+v", opts)
+	}
+	if svc := NewIQReportServiceWithOptions(opts, nil, testLogger()); svc.opts.Concurrency != DefaultConcurrency {
+		t.Errorf("Concurrency = %d, want default %d", svc.opts.Concurrency, DefaultConcurrency)
+	}
+}
+
+func TestNewIQReportServiceWithOptions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v2/applications":
+			_, _ = w.Write([]byte(`{"applications": [{"id": "aid-1", "publicId": "app-1"}]}`))
+		case "/api/v2/organizations":
+			_, _ = w.Write([]byte(`{"organizations": []}`))
+		case "/api/v2/reports/applications/aid-1":
+			_, _ = w.Write([]byte(`[{"stage": "build", "reportHtmlUrl": "https://stub/report/rpt-1"}]`))
+		case "/api/v2/applications/app-1/reports/rpt-1/policy":
+			_, _ = w.Write([]byte(`{"components": []}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	iqClient, _ := client.NewClient(server.URL, "u", "p", testLogger())
+	opts := ServiceOptions{OutputDir: t.TempDir(), Concurrency: 1}
+	path, err := NewIQReportServiceWithOptions(opts, client.NewPool(iqClient), testLogger()).GenerateLatestPolicyReport(rCtx(t), "report.csv")
+	if err != nil {
+		t.Fatalf("GenerateLatestPolicyReport: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("report not written: %v", err)
+	}
+}
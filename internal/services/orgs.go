@@ -0,0 +1,59 @@
+// internal/services/orgs.go
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/client"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/report"
+)
+
+// organizationNames maps the IDs of orgs to their names. Organizations that
+// apps reference but orgs does not list, e.g. because they were deleted, are
+// logged and returned as orphaned, sorted by ID. With ResolveOrphanedOrgs
+// they are looked up by ID first and, when found, added to the map;
+// applications of the others fall back to the organization ID as name.
+func (s *IQReportService) organizationNames(ctx context.Context, orgs []client.Organization, apps []client.Application) (map[string]string, []report.OrphanedOrganization) {
+	names := make(map[string]string, len(orgs))
+	for _, org := range orgs {
+		names[org.ID] = org.Name
+	}
+
+	byID := make(map[string]*report.OrphanedOrganization)
+	for _, app := range apps {
+		if _, ok := names[app.OrganizationID]; ok {
+			continue
+		}
+		o, ok := byID[app.OrganizationID]
+		if !ok {
+			o = &report.OrphanedOrganization{ID: app.OrganizationID}
+			byID[app.OrganizationID] = o
+		}
+		o.Applications = append(o.Applications, app.PublicID)
+	}
+
+	orphaned := make([]report.OrphanedOrganization, 0, len(byID))
+	for _, o := range byID {
+		slices.Sort(o.Applications)
+		if s.opts.ResolveOrphanedOrgs {
+			org, err := s.clients.For(o.ID).GetOrganization(ctx, o.ID)
+			switch {
+			case err == nil:
+				o.Name = org.Name
+				names[o.ID] = org.Name
+			case errors.Is(err, client.ErrNotFound):
+				// deleted: the ID remains the only name
+			default:
+				s.logger.Warn().Err(err).Str("orgId", o.ID).Msg("Could not look up organization by ID")
+			}
+		}
+		s.logger.Warn().Str("orgId", o.ID).Str("name", o.Name).Strs("applications", o.Applications).
+			Msg("Applications reference an organization missing from the organization list")
+		orphaned = append(orphaned, *o)
+	}
+	sort.Slice(orphaned, func(i, j int) bool { return orphaned[i].ID < orphaned[j].ID })
+	return names, orphaned
+}
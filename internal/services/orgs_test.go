@@ -0,0 +1,81 @@
+// internal/services/orgs_test.go
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/client"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/config"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/report"
+)
+
+func TestGenerateLatestPolicyReport_OrphanedOrganizations(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/api/v2/applications":
+			_, _ = w.Write([]byte(`{"applications": [
+				{"id": "aid-1", "publicId": "app-1", "organizationId": "org-1"},
+				{"id": "aid-2", "publicId": "app-2", "organizationId": "org-hidden"},
+				{"id": "aid-3", "publicId": "app-3", "organizationId": "org-gone"},
+				{"id": "aid-4", "publicId": "app-4", "organizationId": "org-gone"}]}`))
+		case r.URL.Path == "/api/v2/organizations":
+			_, _ = w.Write([]byte(`{"organizations": [{"id": "org-1", "name": "Org"}]}`))
+		case r.URL.Path == "/api/v2/organizations/org-hidden":
+			_, _ = w.Write([]byte(`{"id": "org-hidden", "name": "Hidden"}`))
+		case strings.HasPrefix(r.URL.Path, "/api/v2/reports/applications/"):
+			id := strings.TrimPrefix(r.URL.Path, "/api/v2/reports/applications/aid-")
+			_, _ = w.Write([]byte(`[{"stage": "build", "reportDataUrl": "api/v2/applications/app-` + id + `/reports/rpt-1"}]`))
+		case strings.HasSuffix(r.URL.Path, "/policy"):
+			_, _ = w.Write([]byte(`{"components": [{"displayName": "lib 1.0", "componentIdentifier": {"format": "maven"}, "violations": [{"policyName": "Security-High", "policyThreatLevel": 9, "constraints": [{"constraintName": "CVSS >= 7", "conditions": [{"conditionSummary": "CVE-2024-0001"}]}]}]}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	iqClient, err := client.NewClient(server.URL, "u", "p", testLogger())
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	for _, resolve := range []bool{false, true} {
+		cfg := &config.Config{OutputDir: t.TempDir(), ResolveOrphanedOrgs: resolve}
+		path, err := NewIQReportService(cfg, iqClient, testLogger()).GenerateLatestPolicyReport(rCtx(t), "report.csv")
+		if err != nil {
+			t.Fatalf("resolve=%v: GenerateLatestPolicyReport: %v", resolve, err)
+		}
+		b, err := os.ReadFile(report.ManifestPath(path))
+		if err != nil {
+			t.Fatalf("read manifest: %v", err)
+		}
+		var manifest report.Manifest
+		if err := json.Unmarshal(b, &manifest); err != nil {
+			t.Fatalf("decode manifest: %v", err)
+		}
+		orphaned := manifest.OrphanedOrganizations
+		if len(orphaned) != 2 || orphaned[0].ID != "org-gone" || orphaned[1].ID != "org-hidden" ||
+			strings.Join(orphaned[0].Applications, ",") != "app-3,app-4" || orphaned[0].Name != "" {
+			t.Fatalf("resolve=%v: orphaned organizations = %+v", resolve, orphaned)
+		}
+		wantName, wantHidden := "", "org-hidden"
+		if resolve {
+			wantName, wantHidden = "Hidden", "Hidden"
+		}
+		if orphaned[1].Name != wantName {
+			t.Errorf("resolve=%v: name of org-hidden = %q", resolve, orphaned[1].Name)
+		}
+
+		out, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("read report: %v", err)
+		}
+		if !strings.Contains(string(out), ","+wantHidden+",") || !strings.Contains(string(out), ",org-gone,") {
+			t.Errorf("resolve=%v: report does not use the expected organization names:\n%s", resolve, out)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+// internal/services/owners.go
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/client"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/report"
+	"github.com/rs/zerolog"
+)
+
+// DefaultOwnerRole is the IQ role whose members are reported as application
+// owners.
+const DefaultOwnerRole = "Owner"
+
+// needsOwners reports whether the owner columns or owner reports are
+// enabled, so that owners need to be fetched.
+func (s *IQReportService) needsOwners() bool {
+	return s.opts.OwnerReports ||
+		slices.Contains(s.opts.CSVOptionalColumns, report.ColumnOwnerName) ||
+		slices.Contains(s.opts.CSVOptionalColumns, report.ColumnOwnerEmail)
+}
+
+// ownerLookup resolves application owners: the users holding the owner role
+// on an application. Users are fetched once per run. With a snapshot, owners
+// are looked up there instead. It is safe for concurrent use.
+type ownerLookup struct {
+	roleID   string
+	snapshot *OwnerSnapshot
+	logger   zerolog.Logger
+
+	mu    sync.Mutex
+	users map[string]*client.User // by username; nil when the user could not be fetched
+}
+
+// newOwnerLookup finds the owner role, or reads the owner snapshot of
+// EnrichmentDir. It returns nil when neither can be resolved; owner columns
+// are informational, so this does not fail the run.
+func (s *IQReportService) newOwnerLookup(ctx context.Context) *ownerLookup {
+	if s.opts.EnrichmentDir != "" {
+		snap, err := ReadOwnerSnapshot(s.opts.EnrichmentDir)
+		if err != nil {
+			s.logger.Warn().Err(err).Msg("Could not read the owner snapshot, owner columns will be empty")
+			return nil
+		}
+		s.logger.Info().Time("generatedAt", snap.GeneratedAt).Int("applications", len(snap.Applications)).Msg("Reading owners from snapshot")
+		return &ownerLookup{snapshot: snap, logger: s.logger}
+	}
+	l, err := s.liveOwnerLookup(ctx)
+	if err != nil {
+		s.logger.Warn().Err(err).Msg("Owner columns will be empty")
+		return nil
+	}
+	return l
+}
+
+// liveOwnerLookup returns a lookup fetching owners from IQ Server.
+func (s *IQReportService) liveOwnerLookup(ctx context.Context) (*ownerLookup, error) {
+	role := s.opts.OwnerRole
+	if role == "" {
+		role = DefaultOwnerRole
+	}
+	roles, err := s.clients.Default().GetRoles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetch roles: %w", err)
+	}
+	for _, r := range roles {
+		if strings.EqualFold(r.Name, role) {
+			return &ownerLookup{roleID: r.ID, logger: s.logger, users: make(map[string]*client.User)}, nil
+		}
+	}
+	return nil, fmt.Errorf("owner role %q not found", role)
+}
+
+// applicationOwners returns the names and email addresses of the owners of
+// app, sorted by username and joined with ", ". Group members are listed by
+// group name without email. Lookup failures are logged and yield partial or
+// empty values.
+func (s *IQReportService) applicationOwners(ctx context.Context, l *ownerLookup, app client.Application) (names, emails string) {
+	if l == nil {
+		return "", ""
+	}
+	if l.snapshot != nil {
+		owners, ok := l.snapshot.Applications[app.PublicID]
+		if !ok {
+			s.logger.Debug().Str("appPublicID", app.PublicID).Msg("Application not in the owner snapshot")
+		}
+		return owners.Names, owners.Emails
+	}
+	names, emails, err := s.fetchOwners(ctx, l, app)
+	if err != nil {
+		s.logger.Warn().Err(err).Str("appPublicID", app.PublicID).Msg("Could not fetch application owners")
+	}
+	return names, emails
+}
+
+// fetchOwners fetches the owners of app from IQ Server, see
+// applicationOwners. Only failing to fetch the role memberships of app is
+// an error; owners whose details cannot be fetched are listed by username.
+func (s *IQReportService) fetchOwners(ctx context.Context, l *ownerLookup, app client.Application) (names, emails string, err error) {
+	cl := s.clients.For(app.OrganizationID)
+	memberships, err := cl.GetApplicationRoleMemberships(ctx, app.ID)
+	if err != nil {
+		return "", "", err
+	}
+
+	var members []client.RoleMember
+	for _, m := range memberships {
+		if m.RoleID == l.roleID {
+			members = append(members, m.Members...)
+		}
+	}
+	slices.SortFunc(members, func(a, b client.RoleMember) int { return strings.Compare(a.UserOrGroupName, b.UserOrGroupName) })
+
+	var nameList, emailList []string
+	for _, m := range members {
+		if m.Type != client.MemberUser {
+			nameList = append(nameList, m.UserOrGroupName)
+			continue
+		}
+		user := l.user(ctx, cl, m.UserOrGroupName)
+		if user == nil {
+			nameList = append(nameList, m.UserOrGroupName)
+			continue
+		}
+		nameList = append(nameList, user.DisplayName())
+		if user.Email != "" {
+			emailList = append(emailList, user.Email)
+		}
+	}
+	return strings.Join(slices.Compact(nameList), ", "), strings.Join(slices.Compact(emailList), ", "), nil
+}
+
+// user returns the user with the given username, fetching it on first use.
+// The lock is held while fetching, so that applications sharing an owner
+// fetch it only once.
+func (l *ownerLookup) user(ctx context.Context, cl *client.Client, username string) *client.User {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if user, ok := l.users[username]; ok {
+		return user
+	}
+
+	user, err := cl.GetUser(ctx, username)
+	if err != nil {
+		l.logger.Warn().Err(err).Str("username", username).Msg("Could not fetch application owner")
+		user = nil
+	}
+	l.users[username] = user
+	return user
+}
+
+// writeOwnerReports writes the personal report of every owner found in rows
+// and the owner index next to reportPath. The full report remains the
+// consolidated copy; rows without an owner appear only there.
+func (s *IQReportService) writeOwnerReports(ctx context.Context, reportPath string, rows []report.Row, opts []report.CSVOption) error {
+	owners, unowned := report.SplitByOwner(rows)
+	for _, o := range owners {
+		if _, err := report.WriteCSV(ctx, report.OwnerReportPath(reportPath, o.Email), o.Rows, s.logger, opts...); err != nil {
+			return fmt.Errorf("owner %s: %w", o.Email, err)
+		}
+	}
+	if err := report.WriteOwnersCSV(ctx, report.OwnersPath(reportPath), owners, reportPath, s.logger); err != nil {
+		return fmt.Errorf("write owner index: %w", err)
+	}
+	s.logger.Info().Int("owners", len(owners)).Int("unownedRows", unowned).Msg("Owner reports written")
+	return nil
+}
@@ -0,0 +1,128 @@
+// internal/services/owners_test.go
+package services
+
+import (
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/client"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/config"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/report"
+)
+
+func TestGenerateLatestPolicyReport_OwnerColumns(t *testing.T) {
+	var userFetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v2/applications":
+			_, _ = w.Write([]byte(`{"applications": [{"id": "aid-1", "publicId": "apid-1", "organizationId": "org-1"}, {"id": "aid-2", "publicId": "apid-2", "organizationId": "org-1"}]}`))
+		case "/api/v2/organizations":
+			_, _ = w.Write([]byte(`{"organizations": [{"id": "org-1", "name": "personal"}]}`))
+		case "/api/v2/roles":
+			_, _ = w.Write([]byte(`{"roles": [{"id": "r-owner", "name": "Owner"}, {"id": "r-dev", "name": "Developer"}]}`))
+		case "/api/v2/roleMemberships/application/aid-1", "/api/v2/roleMemberships/application/aid-2":
+			_, _ = w.Write([]byte(`{"memberMappings": [
+				{"roleId": "r-owner", "members": [{"type": "USER", "userOrGroupName": "jdoe"}, {"type": "GROUP", "userOrGroupName": "app-owners"}, {"type": "USER", "userOrGroupName": "gone"}]},
+				{"roleId": "r-dev", "members": [{"type": "USER", "userOrGroupName": "dev"}]}
+			]}`))
+		case "/api/v2/users/jdoe":
+			userFetches.Add(1)
+			_, _ = w.Write([]byte(`{"username": "jdoe", "firstName": "Jane", "lastName": "Doe", "email": "jane@example.com"}`))
+		case "/api/v2/reports/applications/aid-1":
+			_, _ = w.Write([]byte(`[{"stage": "build", "reportHtmlUrl": "ui/links/application/apid-1/report/rpt-1"}]`))
+		case "/api/v2/reports/applications/aid-2":
+			_, _ = w.Write([]byte(`[{"stage": "build", "reportHtmlUrl": "ui/links/application/apid-2/report/rpt-2"}]`))
+		case "/api/v2/applications/apid-1/reports/rpt-1/policy", "/api/v2/applications/apid-2/reports/rpt-2/policy":
+			_, _ = w.Write([]byte(`{"components": [{"displayName": "lib", "violations": [{"policyName": "P", "policyThreatLevel": 7, "constraints": [{"constraintName": "C"}]}]}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	iqClient, err := client.NewClient(server.URL, "u", "p", testLogger())
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	cfg := &config.Config{
+		OutputDir:          t.TempDir(),
+		CSVOptionalColumns: []string{report.ColumnOwnerName, report.ColumnOwnerEmail},
+		OwnerRole:          "owner",
+	}
+	svc := NewIQReportService(cfg, iqClient, testLogger())
+
+	path, err := svc.GenerateLatestPolicyReport(rCtx(t), "report.csv")
+	if err != nil {
+		t.Fatalf("GenerateLatestPolicyReport: %v", err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open report: %v", err)
+	}
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatalf("read report: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("got %d records, want header and 2 rows", len(records))
+	}
+	header := records[0]
+	n := len(header)
+	if header[n-2] != report.ColumnOwnerName || header[n-1] != report.ColumnOwnerEmail {
+		t.Fatalf("header = %v, want owner columns last", header)
+	}
+	for _, row := range records[1:] {
+		if row[n-2] != "app-owners, gone, Jane Doe" || row[n-1] != "jane@example.com" {
+			t.Errorf("owner cells = %q, %q", row[n-2], row[n-1])
+		}
+	}
+	if got := userFetches.Load(); got != 1 {
+		t.Errorf("user fetched %d times, want once", got)
+	}
+
+	cfg.CSVOptionalColumns = nil
+	cfg.OwnerReports = true
+	svc = NewIQReportService(cfg, iqClient, testLogger())
+	path, err = svc.GenerateLatestPolicyReport(rCtx(t), "report.csv")
+	if err != nil {
+		t.Fatalf("GenerateLatestPolicyReport with owner reports: %v", err)
+	}
+	data, err := os.ReadFile(report.OwnerReportPath(path, "jane@example.com"))
+	if err != nil {
+		t.Fatalf("read owner report: %v", err)
+	}
+	if records, err := csv.NewReader(strings.NewReader(string(data))).ReadAll(); err != nil || len(records) != 3 {
+		t.Errorf("owner report has %d records (err %v), want header and 2 rows", len(records), err)
+	}
+	if _, err := os.Stat(report.OwnersPath(path)); err != nil {
+		t.Errorf("owner index: %v", err)
+	}
+}
+
+func TestNewOwnerLookup_UnknownRole(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"roles": [{"id": "r-dev", "name": "Developer"}]}`))
+	}))
+	defer server.Close()
+
+	iqClient, err := client.NewClient(server.URL, "u", "p", testLogger())
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	svc := NewIQReportService(&config.Config{OutputDir: t.TempDir()}, iqClient, testLogger())
+	l := svc.newOwnerLookup(rCtx(t))
+	if l != nil {
+		t.Fatalf("lookup = %#v, want nil for a missing role", l)
+	}
+	if names, emails := svc.applicationOwners(rCtx(t), l, client.Application{ID: "aid-1"}); names != "" || emails != "" {
+		t.Errorf("owners = %q, %q, want empty", names, emails)
+	}
+}
@@ -0,0 +1,126 @@
+// internal/services/progress.go
+package services
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/client"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/report"
+)
+
+// Progress event types, see ProgressEvent.Event.
+const (
+	EventRunStarted   = "run_started"
+	EventAppCompleted = "app_completed"
+	EventAppFailed    = "app_failed"
+	EventAppSkipped   = "app_skipped"
+	EventRunFinished  = "run_finished"
+)
+
+// ProgressEvent is one line of the machine-readable progress stream.
+type ProgressEvent struct {
+	Time        time.Time `json:"time"`
+	Event       string    `json:"event"`
+	Application string    `json:"application,omitempty"` // public ID
+	Rows        int       `json:"rows,omitempty"`
+	Reason      string    `json:"reason,omitempty"` // skip reason
+	Error       string    `json:"error,omitempty"`
+	ErrorKind   string    `json:"errorKind,omitempty"`
+	Stats       *RunStats `json:"stats,omitempty"` // run_started and run_finished only
+}
+
+// RunStats summarizes a run in run_started and run_finished events.
+type RunStats struct {
+	Status       string `json:"status,omitempty"` // "ok" or "failed"
+	ReportPath   string `json:"reportPath,omitempty"`
+	Applications int    `json:"applications"`
+	Processed    int    `json:"processed"`
+	Failed       int    `json:"failed"`
+	Skipped      int    `json:"skipped"`
+	Rows         int    `json:"rows"`
+	DurationMS   int64  `json:"durationMs"`
+	Error        string `json:"error,omitempty"`
+	// PhasesMS is the duration of each completed phase of the run ("list",
+	// "fetch", "aggregate") in milliseconds.
+	PhasesMS map[string]int64 `json:"phasesMs,omitempty"`
+	// TopErrors groups application failures by kind, most frequent first.
+	TopErrors []ErrorSummary `json:"topErrors,omitempty"`
+	// Dispositions counts applications per disposition (report.Disposition*).
+	Dispositions map[string]int `json:"dispositions,omitempty"`
+	// Resources records the resources used by the run (run_finished only).
+	Resources *report.ResourceUsage `json:"resources,omitempty"`
+}
+
+// ErrorSummary counts application failures of one error kind.
+type ErrorSummary struct {
+	Kind    string `json:"kind"`
+	Count   int    `json:"count"`
+	Example string `json:"example"` // first error of the kind
+}
+
+// topErrors groups errs by error kind and returns at most n groups, most
+// frequent first.
+func topErrors(errs []error, n int) []ErrorSummary {
+	byKind := make(map[string]*ErrorSummary)
+	var order []*ErrorSummary
+	for _, err := range errs {
+		kind := client.KindName(err)
+		sum, ok := byKind[kind]
+		if !ok {
+			sum = &ErrorSummary{Kind: kind, Example: err.Error()}
+			byKind[kind] = sum
+			order = append(order, sum)
+		}
+		sum.Count++
+	}
+	sort.SliceStable(order, func(i, j int) bool { return order[i].Count > order[j].Count })
+
+	out := make([]ErrorSummary, 0, min(n, len(order)))
+	for i, sum := range order {
+		if i == n {
+			break
+		}
+		out = append(out, *sum)
+	}
+	return out
+}
+
+// progressWriter writes progress events as JSON lines. It is safe for
+// concurrent use; a nil *progressWriter discards events.
+type progressWriter struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+	now func() time.Time
+}
+
+// SetProgressWriter makes the service write progress events as JSON lines
+// to w, for pipelines that display live status without parsing logs.
+func (s *IQReportService) SetProgressWriter(w io.Writer) {
+	s.progress = &progressWriter{enc: json.NewEncoder(w), now: s.now}
+}
+
+func (p *progressWriter) emit(ev ProgressEvent) {
+	if p == nil {
+		return
+	}
+	ev.Time = p.now().UTC()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_ = p.enc.Encode(ev) // progress output is best effort
+}
+
+// appDone emits the completion, failure or skip event of one application.
+func (p *progressWriter) appDone(app client.Application, res AppReportResult) {
+	switch {
+	case res.Skipped != "":
+		p.emit(ProgressEvent{Event: EventAppSkipped, Application: app.PublicID, Reason: res.Skipped})
+	case res.Err != nil:
+		p.emit(ProgressEvent{Event: EventAppFailed, Application: app.PublicID, Error: res.Err.Error(), ErrorKind: client.KindName(res.Err)})
+	default:
+		p.emit(ProgressEvent{Event: EventAppCompleted, Application: app.PublicID, Rows: len(res.Rows)})
+	}
+}
@@ -0,0 +1,84 @@
+// internal/services/progress_test.go
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/client"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/config"
+)
+
+func TestGenerateLatestPolicyReport_ProgressEvents(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v2/applications":
+			_, _ = w.Write([]byte(`{"applications": [{"id": "aid-1", "publicId": "app-ok"}, {"id": "aid-2", "publicId": "app-bad"}]}`))
+		case "/api/v2/organizations":
+			_, _ = w.Write([]byte(`{"organizations": []}`))
+		case "/api/v2/reports/applications/aid-1":
+			_, _ = w.Write([]byte(`[{"stage": "build", "reportHtmlUrl": "https://stub/report/rpt-1"}]`))
+		case "/api/v2/applications/app-ok/reports/rpt-1/policy":
+			_, _ = w.Write([]byte(`{"components": [{"displayName": "comp", "violations": [{"policyName": "P", "policyThreatLevel": 9, "constraints": [{"constraintName": "C"}]}]}]}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	iqClient, _ := client.NewClient(server.URL, "u", "p", testLogger())
+	svc := NewIQReportService(&config.Config{OutputDir: t.TempDir()}, iqClient, testLogger())
+	var buf bytes.Buffer
+	svc.SetProgressWriter(&buf)
+
+	if _, err := svc.GenerateLatestPolicyReport(rCtx(t), "report.csv"); err == nil {
+		t.Fatal("expected error for the failing application")
+	}
+
+	var events []ProgressEvent
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		var ev ProgressEvent
+		if err := dec.Decode(&ev); err != nil {
+			t.Fatalf("decode event: %v", err)
+		}
+		events = append(events, ev)
+	}
+
+	byType := make(map[string]ProgressEvent)
+	for _, ev := range events {
+		byType[ev.Event] = ev
+	}
+	if len(events) != 4 || events[0].Event != EventRunStarted || events[3].Event != EventRunFinished {
+		t.Fatalf("unexpected event sequence: %+v", events)
+	}
+	if ev := byType[EventAppCompleted]; ev.Application != "app-ok" || ev.Rows != 1 {
+		t.Errorf("app_completed = %+v", ev)
+	}
+	if ev := byType[EventAppFailed]; ev.Application != "app-bad" || ev.ErrorKind != "server" {
+		t.Errorf("app_failed = %+v", ev)
+	}
+	stats := byType[EventRunFinished].Stats
+	if stats == nil || stats.Status != "failed" || stats.Processed != 1 || stats.Failed != 1 || stats.Rows != 1 || stats.ReportPath == "" {
+		t.Errorf("run_finished stats = %+v", stats)
+	}
+	if last := svc.LastRun(); last.Failed != 1 || len(last.TopErrors) != 1 || last.TopErrors[0].Kind != "server" {
+		t.Errorf("LastRun() = %+v", last)
+	}
+}
+
+func TestTopErrors(t *testing.T) {
+	errs := []error{errors.New("app a: boom"), errors.New("app b: bang")}
+	got := topErrors(errs, 5)
+	if len(got) != 1 || got[0].Kind != "other" || got[0].Count != 2 || got[0].Example != "app a: boom" {
+		t.Errorf("topErrors() = %+v", got)
+	}
+	if got := topErrors(errs, 0); len(got) != 0 {
+		t.Errorf("topErrors(n=0) = %+v", got)
+	}
+}
@@ -0,0 +1,55 @@
+// internal/services/reportid_test.go
+package services
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/client"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/config"
+)
+
+func TestGenerateReportByID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v2/applications":
+			_, _ = w.Write([]byte(`{"applications": [{"id": "aid-1", "publicId": "apid-1", "organizationId": "org-1"}]}`))
+		case "/api/v2/organizations":
+			_, _ = w.Write([]byte(`{"organizations": [{"id": "org-1", "name": "Org One"}]}`))
+		case "/api/v2/reports/applications/aid-1":
+			_, _ = w.Write([]byte(`[{"stage": "build", "reportHtmlUrl": "https://stub/report/rpt-latest"}]`))
+		case "/api/v2/applications/apid-1/reports/rpt-old/policy":
+			_, _ = w.Write([]byte(`{"components": [{"displayName": "comp", "violations": [{"policyName": "P", "policyThreatLevel": 9, "constraints": [{"constraintName": "C"}]}]}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	iqClient, _ := client.NewClient(server.URL+"/api/v2", "u", "p", testLogger())
+	svc := NewIQReportService(&config.Config{OutputDir: t.TempDir()}, iqClient, testLogger())
+
+	path, err := svc.GenerateReportByID(rCtx(t), "apid-1", "rpt-old", "report.csv")
+	if err != nil {
+		t.Fatalf("GenerateReportByID: %v", err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read csv: %v", err)
+	}
+	if !strings.Contains(string(b), "1,apid-1,Org One,P,") {
+		t.Errorf("expected the historical report's row, got:\n%s", b)
+	}
+
+	if _, err := svc.GenerateReportByID(rCtx(t), "missing", "rpt-old", "other.csv"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected application not found error, got %v", err)
+	}
+	if _, err := svc.GenerateReportByID(rCtx(t), "apid-1", "rpt-gone", "gone.csv"); !errors.Is(err, client.ErrNotFound) {
+		t.Errorf("expected not found error for unknown report, got %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+// internal/services/resources.go
+package services
+
+import (
+	"runtime"
+	"runtime/metrics"
+	"sync"
+	"time"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/report"
+)
+
+// resourceSampleInterval is how often a resourceMonitor samples the memory
+// and goroutines of the process.
+const resourceSampleInterval = 100 * time.Millisecond
+
+// resourceMonitor records the resources used by a run: peak memory and
+// goroutines, sampled in the background, and the HTTP calls and CPU time
+// spent since it was started.
+type resourceMonitor struct {
+	s        *IQReportService
+	calls    int           // HTTP calls made before the run
+	cpu      time.Duration // CPU time used before the run
+	stop     chan struct{}
+	done     chan struct{}
+	once     sync.Once
+	mu       sync.Mutex
+	peakMem  uint64
+	peakGo   int
+	samples  []metrics.Sample
+	cpuError bool
+}
+
+// startResourceMonitor starts sampling the resources of the run.
+func (s *IQReportService) startResourceMonitor() *resourceMonitor {
+	m := &resourceMonitor{
+		s:     s,
+		calls: s.httpCalls(),
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+		samples: []metrics.Sample{
+			{Name: "/memory/classes/total:bytes"},
+			{Name: "/memory/classes/heap/released:bytes"},
+		},
+	}
+	cpu, err := processCPUTime()
+	m.cpu, m.cpuError = cpu, err != nil
+	m.sample()
+	go func() {
+		defer close(m.done)
+		ticker := time.NewTicker(resourceSampleInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-m.stop:
+				return
+			case <-ticker.C:
+				m.sample()
+			}
+		}
+	}()
+	return m
+}
+
+// sample updates the peaks with the current memory held by the Go runtime
+// (mapped and not released to the OS) and goroutine count.
+func (m *resourceMonitor) sample() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	metrics.Read(m.samples)
+	mem := m.samples[0].Value.Uint64() - m.samples[1].Value.Uint64()
+	m.peakMem = max(m.peakMem, mem)
+	m.peakGo = max(m.peakGo, runtime.NumGoroutine())
+}
+
+// usage stops sampling and returns the resources used since the start. The
+// CPU time is left zero when the platform does not report it.
+func (m *resourceMonitor) usage() report.ResourceUsage {
+	m.once.Do(func() { close(m.stop) })
+	<-m.done
+	m.sample()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	u := report.ResourceUsage{
+		PeakMemoryBytes: m.peakMem,
+		PeakGoroutines:  m.peakGo,
+		HTTPCalls:       m.s.httpCalls() - m.calls,
+	}
+	if cpu, err := processCPUTime(); err == nil && !m.cpuError {
+		u.CPUSeconds = (cpu - m.cpu).Seconds()
+	}
+	return u
+}
+
+// httpCalls returns the number of requests made by the clients so far.
+func (s *IQReportService) httpCalls() int {
+	n := 0
+	for _, t := range s.clients.Timings() {
+		n += t.Requests
+	}
+	return n
+}
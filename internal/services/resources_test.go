@@ -0,0 +1,46 @@
+// internal/services/resources_test.go
+package services
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/client"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/config"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/report"
+)
+
+func TestGenerateLatestPolicyReport_ResourceUsage(t *testing.T) {
+	server := faultServer(t, 3)
+	iqClient, err := client.NewClient(server.URL, "u", "p", testLogger())
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	svc := NewIQReportService(&config.Config{OutputDir: t.TempDir()}, iqClient, testLogger())
+
+	// A second run counts only its own HTTP calls
+	for range 2 {
+		path, err := svc.GenerateLatestPolicyReport(rCtx(t), "report.csv")
+		if err != nil {
+			t.Fatalf("GenerateLatestPolicyReport: %v", err)
+		}
+		b, err := os.ReadFile(report.ManifestPath(path))
+		if err != nil {
+			t.Fatalf("read manifest: %v", err)
+		}
+		var manifest report.Manifest
+		if err := json.Unmarshal(b, &manifest); err != nil {
+			t.Fatalf("decode manifest: %v", err)
+		}
+		// applications, organizations, and the report list and policy report of each application
+		const wantCalls = 2 + 3*2
+		r := manifest.Resources
+		if r == nil || r.HTTPCalls != wantCalls || r.PeakMemoryBytes == 0 || r.PeakGoroutines == 0 || r.CPUSeconds < 0 {
+			t.Fatalf("manifest resources = %+v, want %d HTTP calls and non-zero peaks", r, wantCalls)
+		}
+		if stats := svc.LastRun(); stats.Resources == nil || stats.Resources.HTTPCalls != wantCalls {
+			t.Errorf("run stats resources = %+v", stats.Resources)
+		}
+	}
+}
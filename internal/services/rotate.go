@@ -0,0 +1,31 @@
+// internal/services/rotate.go
+package services
+
+import (
+	"context"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/report"
+	"github.com/rs/zerolog"
+)
+
+// maintainOutputs points the latest link at the report of a run without
+// errors, and rotates out the reports of old runs. Failures are logged
+// only: the report itself was written.
+func (s *IQReportService) maintainOutputs(ctx context.Context, logger zerolog.Logger, reportFile string, complete bool) {
+	if s.opts.LatestLink && complete {
+		if err := report.UpdateLatest(ctx, reportFile, s.logger); err != nil {
+			logger.Warn().Err(err).Msg("Could not update the latest report link")
+		} else {
+			logger.Info().Str("target", reportFile).Msg("Latest report link updated")
+		}
+	}
+	if s.opts.KeepReports > 0 {
+		removed, err := report.RotateReports(s.opts.OutputDir, s.opts.KeepReports, s.logger)
+		if err != nil {
+			logger.Warn().Err(err).Msg("Could not rotate old reports")
+		}
+		if len(removed) > 0 {
+			logger.Info().Int("files", len(removed)).Int("keep", s.opts.KeepReports).Msg("Old reports rotated out")
+		}
+	}
+}
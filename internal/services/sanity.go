@@ -0,0 +1,50 @@
+// internal/services/sanity.go
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/config"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/report"
+)
+
+// ErrSanityCheck is returned when a report looks suspicious (e.g. nearly
+// empty because of upstream issues) and SANITY_ACTION is "fail".
+var ErrSanityCheck = errors.New("sanity check failed")
+
+// checkSanity evaluates the configured sanity checks for a run with the given
+// row count and application coverage against the previous run, if any. It
+// returns nil when all checks pass or when violations only warrant a warning.
+func (s *IQReportService) checkSanity(rows, apps, processed int, previous *report.Manifest) error {
+	var violations []error
+
+	if min := s.opts.SanityMinRows; min > 0 && rows < min {
+		violations = append(violations, fmt.Errorf("%d rows, expected at least %d", rows, min))
+	}
+
+	if min := s.opts.SanityMinAppCoverage; min > 0 && apps > 0 {
+		coverage := float64(processed) / float64(apps) * 100
+		if coverage < min {
+			violations = append(violations, fmt.Errorf("application coverage %.1f%%, expected at least %.1f%%", coverage, min))
+		}
+	}
+
+	if max := s.opts.SanityMaxRowDelta; max > 0 && previous != nil && previous.Rows > 0 {
+		delta := math.Abs(float64(rows-previous.Rows)) / float64(previous.Rows) * 100
+		if delta > max {
+			violations = append(violations, fmt.Errorf("row count changed by %.1f%% (%d -> %d), expected at most %.1f%%", delta, previous.Rows, rows, max))
+		}
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+	err := fmt.Errorf("%w: %w", ErrSanityCheck, errors.Join(violations...))
+	if s.opts.SanityAction == config.SanityWarn {
+		s.logger.Warn().Err(err).Msg("Report failed sanity checks; publishing anyway")
+		return nil
+	}
+	return err
+}
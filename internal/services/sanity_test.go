@@ -0,0 +1,44 @@
+// internal/services/sanity_test.go
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/config"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/report"
+)
+
+func TestCheckSanity(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      config.Config
+		rows     int
+		apps     int
+		done     int
+		previous *report.Manifest
+		wantErr  bool
+	}{
+		{"Disabled", config.Config{}, 0, 10, 0, nil, false},
+		{"MinRowsMet", config.Config{SanityMinRows: 5}, 5, 1, 1, nil, false},
+		{"MinRowsViolated", config.Config{SanityMinRows: 5}, 4, 1, 1, nil, true},
+		{"CoverageViolated", config.Config{SanityMinAppCoverage: 90}, 100, 10, 8, nil, true},
+		{"CoverageMet", config.Config{SanityMinAppCoverage: 80}, 100, 10, 8, nil, false},
+		{"DeltaViolated", config.Config{SanityMaxRowDelta: 50}, 40, 1, 1, &report.Manifest{Rows: 100}, true},
+		{"DeltaNoPrevious", config.Config{SanityMaxRowDelta: 50}, 40, 1, 1, nil, false},
+		{"WarnOnly", config.Config{SanityMinRows: 5, SanityAction: config.SanityWarn}, 0, 1, 1, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewIQReportService(&tt.cfg, nil, testLogger())
+			err := svc.checkSanity(tt.rows, tt.apps, tt.done, tt.previous)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkSanity error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, ErrSanityCheck) {
+				t.Errorf("error %v is not ErrSanityCheck", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+// internal/services/selection.go
+package services
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/client"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/config"
+)
+
+// stageRank orders IQ Server stages along the delivery pipeline; later
+// stages rank higher. Unknown stages rank lowest.
+var stageRank = map[string]int{
+	"develop":       1,
+	"source":        1,
+	"build":         2,
+	"stage-release": 3,
+	"release":       4,
+	"operate":       5,
+}
+
+// selectReports picks the reports to export from an application's report
+// metadata. Without configured stages one report is chosen among all usable
+// reports; with ReportStages one report per listed stage, in the
+// configured order. The choice among candidates follows ReportSelection.
+func (s *IQReportService) selectReports(infos []client.ReportInfo) []client.ReportInfo {
+	var usable []client.ReportInfo
+	for _, info := range infos {
+		if strings.TrimSpace(info.ReportDataURL) != "" || strings.TrimSpace(info.ReportHTMLURL) != "" {
+			usable = append(usable, info)
+		}
+	}
+	if len(usable) == 0 {
+		return nil
+	}
+	if len(s.opts.ReportStages) == 0 {
+		return []client.ReportInfo{s.pickReport(usable)}
+	}
+
+	var selected []client.ReportInfo
+	for _, stage := range s.opts.ReportStages {
+		var candidates []client.ReportInfo
+		for _, info := range usable {
+			if info.Stage == stage {
+				candidates = append(candidates, info)
+			}
+		}
+		if len(candidates) > 0 {
+			selected = append(selected, s.pickReport(candidates))
+		}
+	}
+	return selected
+}
+
+// pickReport chooses one of the non-empty candidates according to the
+// selection policy. Ties keep the order returned by IQ Server.
+func (s *IQReportService) pickReport(candidates []client.ReportInfo) client.ReportInfo {
+	best := candidates[0]
+	switch s.selectionPolicy() {
+	case config.SelectLatest:
+		for _, c := range candidates[1:] {
+			if evaluationTime(c).After(evaluationTime(best)) {
+				best = c
+			}
+		}
+	case config.SelectHighestStage:
+		for _, c := range candidates[1:] {
+			if stageRank[c.Stage] > stageRank[best.Stage] {
+				best = c
+			}
+		}
+	case config.SelectPreference:
+		for _, stage := range s.opts.ReportStagePreference {
+			if i := slices.IndexFunc(candidates, func(c client.ReportInfo) bool { return c.Stage == stage }); i >= 0 {
+				return candidates[i]
+			}
+		}
+	}
+	return best
+}
+
+// selectionPolicy returns the configured report selection policy.
+func (s *IQReportService) selectionPolicy() string {
+	if s.opts.ReportSelection == "" {
+		return config.SelectFirst
+	}
+	return s.opts.ReportSelection
+}
+
+// evaluationTime parses the evaluation date of a report; unparsable dates
+// sort before all others.
+func evaluationTime(info client.ReportInfo) time.Time {
+	for _, layout := range []string{iqTimeLayout, time.RFC3339} {
+		if t, err := time.Parse(layout, info.EvaluationDate); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
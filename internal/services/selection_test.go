@@ -0,0 +1,46 @@
+// internal/services/selection_test.go
+package services
+
+import (
+	"testing"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/client"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/config"
+)
+
+func TestSelectReports_Policies(t *testing.T) {
+	infos := []client.ReportInfo{
+		{Stage: "build", EvaluationDate: "2025-01-02T10:00:00.000+0000", ReportHTMLURL: "http://iq/report/build-new"},
+		{Stage: "release", EvaluationDate: "2025-01-01T10:00:00.000+0000", ReportHTMLURL: "http://iq/report/release"},
+		{Stage: "source", EvaluationDate: "2025-01-03T10:00:00.000+0000", ReportHTMLURL: "http://iq/report/source"},
+		{Stage: "build", EvaluationDate: "2025-01-04T10:00:00.000+0000", ReportHTMLURL: "http://iq/report/build-newest"},
+		{Stage: "operate", ReportHTMLURL: ""}, // unusable
+	}
+
+	tests := []struct {
+		name string
+		cfg  config.Config
+		want []string
+	}{
+		{"Default", config.Config{}, []string{"build-new"}},
+		{"Latest", config.Config{ReportSelection: config.SelectLatest}, []string{"build-newest"}},
+		{"HighestStage", config.Config{ReportSelection: config.SelectHighestStage}, []string{"release"}},
+		{"Preference", config.Config{ReportSelection: config.SelectPreference, ReportStagePreference: []string{"operate", "source", "build"}}, []string{"source"}},
+		{"LatestPerStage", config.Config{ReportSelection: config.SelectLatest, ReportStages: []string{"release", "build"}}, []string{"release", "build-newest"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewIQReportService(&tt.cfg, nil, testLogger())
+			got := svc.selectReports(infos)
+			if len(got) != len(tt.want) {
+				t.Fatalf("selected %d reports, want %d: %#v", len(got), len(tt.want), got)
+			}
+			for i, info := range got {
+				if id, _ := info.ReportID(); id != tt.want[i] {
+					t.Errorf("report %d = %q, want %q", i, id, tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+// internal/services/shard.go
+package services
+
+import (
+	"hash/fnv"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/client"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/report"
+)
+
+// shard returns the shard processed by this service, or nil when the run
+// is not sharded.
+func (s *IQReportService) shard() *report.Shard {
+	if s.opts.ShardTotal <= 1 {
+		return nil
+	}
+	return &report.Shard{Index: s.opts.ShardIndex, Total: s.opts.ShardTotal}
+}
+
+// shardApplications returns the applications of the service's shard. An
+// application belongs to the shard chosen by a hash of its public ID, so
+// the partition does not depend on the order in which IQ Server lists
+// applications, and adding an application does not move the others.
+func (s *IQReportService) shardApplications(apps []client.Application) []client.Application {
+	shard := s.shard()
+	if shard == nil {
+		return apps
+	}
+	var out []client.Application
+	for _, app := range apps {
+		if applicationShard(app, shard.Total) == shard.Index {
+			out = append(out, app)
+		}
+	}
+	s.logger.Info().Str("shard", shard.String()).Int("applications", len(out)).Int("listed", len(apps)).Msg("Selected applications of shard")
+	return out
+}
+
+// applicationShard returns the shard, from zero to total-1, of app.
+func applicationShard(app client.Application, total int) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(app.PublicID))
+	return int(h.Sum32() % uint32(total))
+}
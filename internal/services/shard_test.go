@@ -0,0 +1,75 @@
+// internal/services/shard_test.go
+package services
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/client"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/config"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/report"
+)
+
+func TestShardApplications_Partition(t *testing.T) {
+	var apps []client.Application
+	for i := range 100 {
+		apps = append(apps, client.Application{ID: fmt.Sprintf("aid-%d", i), PublicID: fmt.Sprintf("app-%d", i)})
+	}
+
+	seen := make(map[string]int)
+	for index := range 3 {
+		svc := NewIQReportServiceWithOptions(ServiceOptions{ShardIndex: index, ShardTotal: 3}, nil, testLogger())
+		shard := svc.shardApplications(apps)
+		if len(shard) == 0 {
+			t.Errorf("shard %d is empty", index)
+		}
+		for _, app := range shard {
+			seen[app.PublicID]++
+		}
+	}
+	for _, app := range apps {
+		if seen[app.PublicID] != 1 {
+			t.Errorf("%s is in %d shards, want 1", app.PublicID, seen[app.PublicID])
+		}
+	}
+
+	svc := NewIQReportServiceWithOptions(ServiceOptions{}, nil, testLogger())
+	if got := svc.shardApplications(apps); len(got) != len(apps) {
+		t.Errorf("unsharded service kept %d of %d applications", len(got), len(apps))
+	}
+}
+
+func TestGenerateLatestPolicyReport_Sharded(t *testing.T) {
+	server := faultServer(t, 12)
+	iqClient, err := client.NewClient(server.URL, "u", "p", testLogger())
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	tmpDir := t.TempDir()
+	processed := 0
+	for index := range 3 {
+		cfg := &config.Config{OutputDir: tmpDir, ShardIndex: index, ShardTotal: 3}
+		svc := NewIQReportService(cfg, iqClient, testLogger())
+		shard := report.Shard{Index: index, Total: 3}
+		path, err := svc.GenerateLatestPolicyReport(rCtx(t), report.ShardPath("report.csv", shard))
+		if err != nil {
+			t.Fatalf("shard %d: %v", index, err)
+		}
+		m, err := report.ReadManifest(report.ManifestPath(path))
+		if err != nil {
+			t.Fatalf("shard %d: %v", index, err)
+		}
+		if m.Shard == nil || *m.Shard != shard {
+			t.Errorf("shard %d manifest shard = %v", index, m.Shard)
+		}
+		if filepath.Base(path) != "report.shard-"+shard.String()+".csv" {
+			t.Errorf("shard %d report path = %s", index, path)
+		}
+		processed += m.Processed
+	}
+	if processed != 12 {
+		t.Errorf("shards processed %d applications, want 12", processed)
+	}
+}
@@ -0,0 +1,89 @@
+// internal/services/stream.go
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/config"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/report"
+)
+
+// StreamLatestPolicyRows fetches the latest policy violations of all
+// applications like GenerateLatestPolicyReport, but hands every row to fn
+// as soon as its application is processed instead of writing any files.
+// It is meant for embedders that push rows to their own queue.
+//
+// fn is called from the calling goroutine only, one application at a time
+// and in completion order. Filters, suppressions, tag and owner columns and
+// CVE splitting apply; the report files, sanity checks, sinks and manifest
+// do not. When fn returns an error, pending requests are cancelled and that
+// error is returned. Applications that fail do not stop the stream; their
+// errors are returned joined after all other rows were delivered.
+func (s *IQReportService) StreamLatestPolicyRows(ctx context.Context, fn func(report.Row) error) error {
+	rowFilter, err := report.ParseFilter(s.opts.Filter)
+	if err != nil {
+		return fmt.Errorf("FILTER: %w", err)
+	}
+	var suppressions []Suppression
+	if s.opts.SuppressionsFile != "" {
+		if suppressions, err = LoadSuppressions(s.opts.SuppressionsFile); err != nil {
+			return err
+		}
+	}
+	runTime := s.now()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	apps, err := s.applications(ctx)
+	var partial *PartialListingError
+	if err != nil && !errors.As(err, &partial) {
+		return err
+	}
+	listErr := err
+	apps = s.shardApplications(apps)
+
+	orgs, err := s.clients.Default().GetOrganizations(ctx)
+	if err != nil {
+		return fmt.Errorf("get organizations: %w", err)
+	}
+	orgIDToName, _ := s.organizationNames(ctx, orgs, apps)
+	var tagNames map[string]string
+	if len(s.opts.AppTagColumns) > 0 {
+		tagNames = s.tagNames(ctx, orgs)
+	}
+	var owners *ownerLookup
+	if s.needsOwners() {
+		owners = s.newOwnerLookup(ctx)
+	}
+	s.logger.Info().Int("appsToProcess", len(apps)).Int("maxConcurrent", s.opts.Concurrency).Msg("Streaming policy violation rows")
+
+	var errs []error
+	received := 0
+	for res := range s.fetchApplications(ctx, apps, orgIDToName, tagNames, owners, newViolationFetches()) {
+		received++
+		if res.Err != nil {
+			errs = append(errs, res.Err)
+			continue
+		}
+		rows := s.filterRows(res.Rows, rowFilter)
+		rows, _ = suppressRows(rows, suppressions, runTime)
+		if s.opts.CVERows == config.CVERowsSplit {
+			rows = report.SplitCVERows(rows)
+		}
+		for _, r := range rows {
+			if err := fn(r); err != nil {
+				return err
+			}
+		}
+	}
+	if received < len(apps) && ctx.Err() != nil {
+		return fmt.Errorf("stream rows: %d of %d applications unfinished: %w", len(apps)-received, len(apps), ctx.Err())
+	}
+	if listErr != nil {
+		errs = append(errs, listErr)
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,78 @@
+// internal/services/stream_test.go
+package services
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/client"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/config"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/report"
+)
+
+func TestStreamLatestPolicyRows(t *testing.T) {
+	server := faultServer(t, 5)
+	iqClient, err := client.NewClient(server.URL, "u", "p", testLogger())
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	tmpDir := t.TempDir()
+	cfg := &config.Config{OutputDir: tmpDir, Filter: `Application != "app-3"`}
+	svc := NewIQReportService(cfg, iqClient, testLogger())
+
+	seen := make(map[string]int)
+	err = svc.StreamLatestPolicyRows(rCtx(t), func(r report.Row) error {
+		seen[r.Application]++
+		if r.Organization != "Org" || r.Threat != 9 {
+			t.Errorf("unexpected row: %+v", r)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamLatestPolicyRows: %v", err)
+	}
+	if len(seen) != 4 || seen["app-3"] != 0 || seen["app-0"] != 1 {
+		t.Errorf("rows per application = %v, want one each except the filtered app-3", seen)
+	}
+	if entries, _ := os.ReadDir(tmpDir); len(entries) != 0 {
+		t.Errorf("streaming wrote %d files to the output directory", len(entries))
+	}
+
+	// An error from the callback stops the stream and is returned as is
+	stop := errors.New("queue full")
+	calls := 0
+	err = svc.StreamLatestPolicyRows(rCtx(t), func(report.Row) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Errorf("err = %v after %d calls, want the callback error after 1 call", err, calls)
+	}
+}
+
+func TestStreamLatestPolicyRows_FailedApplications(t *testing.T) {
+	server := faultServer(t, 20)
+	injector := newFaultInjector(faultConfig{Seed: 1, ErrorRate: 0.3}, nil)
+	iqClient, err := client.NewClient(server.URL, "u", "p", testLogger(), client.WithTransport(injector))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	svc := NewIQReportService(&config.Config{OutputDir: t.TempDir()}, iqClient, testLogger())
+
+	rows := 0
+	err = svc.StreamLatestPolicyRows(rCtx(t), func(report.Row) error {
+		rows++
+		return nil
+	})
+	failed := injector.Injected()[faultError]
+	if failed == 0 {
+		t.Fatal("no faults injected; adjust the seed")
+	}
+	if !errors.Is(err, client.ErrServer) {
+		t.Errorf("err = %v, want the joined application errors", err)
+	}
+	if rows == 0 || rows >= 20 {
+		t.Errorf("streamed %d rows with %d injected faults", rows, failed)
+	}
+}
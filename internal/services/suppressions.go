@@ -0,0 +1,113 @@
+// internal/services/suppressions.go
+package services
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/report"
+	"gopkg.in/yaml.v3"
+)
+
+// Suppression accepts the risk of violations matching all of its patterns
+// until it expires. Patterns use shell glob syntax (see path.Match); an empty
+// pattern matches everything.
+type Suppression struct {
+	Application   string `yaml:"application"`
+	Component     string `yaml:"component"`
+	Policy        string `yaml:"policy"`
+	Expires       string `yaml:"expires"` // YYYY-MM-DD, last day the suppression applies
+	Justification string `yaml:"justification"`
+
+	expires time.Time
+}
+
+// suppressionFile is the layout of the YAML suppression file.
+type suppressionFile struct {
+	Suppressions []Suppression `yaml:"suppressions"`
+}
+
+// LoadSuppressions reads and validates a YAML suppression file. Every entry
+// needs a justification and an expiry date so that accepted risks are
+// reviewed again.
+func LoadSuppressions(file string) ([]Suppression, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("read suppressions: %w", err)
+	}
+	var f suppressionFile
+	if err := yaml.Unmarshal(b, &f); err != nil {
+		return nil, fmt.Errorf("decode suppressions %s: %w", file, err)
+	}
+
+	for i := range f.Suppressions {
+		sup := &f.Suppressions[i]
+		if sup.Justification == "" {
+			return nil, fmt.Errorf("suppression %d: justification is required", i+1)
+		}
+		if sup.Expires == "" {
+			return nil, fmt.Errorf("suppression %d: expires is required", i+1)
+		}
+		sup.expires, err = time.Parse(time.DateOnly, sup.Expires)
+		if err != nil {
+			return nil, fmt.Errorf("suppression %d: invalid expires %q: %w", i+1, sup.Expires, err)
+		}
+		for _, p := range []string{sup.Application, sup.Component, sup.Policy} {
+			if _, err := path.Match(p, ""); err != nil {
+				return nil, fmt.Errorf("suppression %d: invalid pattern %q: %w", i+1, p, err)
+			}
+		}
+	}
+	return f.Suppressions, nil
+}
+
+// Expired reports whether the suppression no longer applies at now.
+func (sup Suppression) Expired(now time.Time) bool {
+	return !now.Before(sup.expires.AddDate(0, 0, 1))
+}
+
+// Matches reports whether r matches all patterns of the suppression.
+func (sup Suppression) Matches(r report.Row) bool {
+	return globMatch(sup.Application, r.Application) &&
+		globMatch(sup.Component, r.Component) &&
+		globMatch(sup.Policy, r.Policy)
+}
+
+func globMatch(pattern, value string) bool {
+	if pattern == "" {
+		return true
+	}
+	ok, _ := path.Match(pattern, value)
+	return ok
+}
+
+// suppressRows removes rows matched by an unexpired suppression and returns
+// the remaining rows with the number of suppressed rows. The input slice is
+// not modified.
+func suppressRows(rows []report.Row, sups []Suppression, now time.Time) ([]report.Row, int) {
+	active := make([]Suppression, 0, len(sups))
+	for _, sup := range sups {
+		if !sup.Expired(now) {
+			active = append(active, sup)
+		}
+	}
+	if len(active) == 0 {
+		return rows, 0
+	}
+
+	out := make([]report.Row, 0, len(rows))
+	suppressed := 0
+rows:
+	for _, r := range rows {
+		for _, sup := range active {
+			if sup.Matches(r) {
+				suppressed++
+				continue rows
+			}
+		}
+		out = append(out, r)
+	}
+	return out, suppressed
+}
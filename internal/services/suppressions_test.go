@@ -0,0 +1,73 @@
+// internal/services/suppressions_test.go
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/report"
+)
+
+func writeSuppressions(t *testing.T, content string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "suppressions.yaml")
+	if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+		t.Fatalf("write suppressions: %v", err)
+	}
+	return file
+}
+
+func TestSuppressRows(t *testing.T) {
+	file := writeSuppressions(t, `
+suppressions:
+  - application: app-a
+    component: "commons-*"
+    policy: Security-High
+    expires: 2030-06-30
+    justification: Not reachable, see SEC-123
+  - application: "*"
+    component: log4j-core:2.14.1
+    expires: 2020-01-01
+    justification: Expired acceptance
+`)
+	sups, err := LoadSuppressions(file)
+	if err != nil {
+		t.Fatalf("LoadSuppressions: %v", err)
+	}
+
+	rows := []report.Row{
+		{Application: "app-a", Component: "commons-beanutils:1.9.4", Policy: "Security-High"},
+		{Application: "app-a", Component: "commons-beanutils:1.9.4", Policy: "License-Banned"},
+		{Application: "app-b", Component: "commons-beanutils:1.9.4", Policy: "Security-High"},
+		{Application: "app-b", Component: "log4j-core:2.14.1", Policy: "Security-Critical"},
+	}
+	now := time.Date(2030, 6, 30, 23, 0, 0, 0, time.UTC)
+	got, suppressed := suppressRows(rows, sups, now)
+	if suppressed != 1 || len(got) != 3 {
+		t.Fatalf("suppressed=%d remaining=%d, want 1 and 3", suppressed, len(got))
+	}
+	if got[0].Policy != "License-Banned" {
+		t.Errorf("unexpected remaining rows: %#v", got)
+	}
+
+	// The day after expiry the suppression no longer applies.
+	if _, suppressed := suppressRows(rows, sups, now.Add(2*time.Hour)); suppressed != 0 {
+		t.Errorf("suppressed after expiry = %d, want 0", suppressed)
+	}
+}
+
+func TestLoadSuppressions_Validation(t *testing.T) {
+	cases := map[string]string{
+		"missing justification": "suppressions:\n  - application: a\n    expires: 2030-01-01\n",
+		"missing expiry":        "suppressions:\n  - application: a\n    justification: ok\n",
+		"invalid expiry":        "suppressions:\n  - application: a\n    expires: soon\n    justification: ok\n",
+		"invalid pattern":       "suppressions:\n  - component: \"[\"\n    expires: 2030-01-01\n    justification: ok\n",
+	}
+	for name, content := range cases {
+		if _, err := LoadSuppressions(writeSuppressions(t, content)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
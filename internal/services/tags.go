@@ -0,0 +1,63 @@
+// internal/services/tags.go
+package services
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/client"
+)
+
+// tagNames returns the names of the application categories (tags) defined
+// in orgs by ID. Organizations whose categories cannot be fetched are logged
+// and skipped: tag columns are informational.
+func (s *IQReportService) tagNames(ctx context.Context, orgs []client.Organization) map[string]string {
+	names := make(map[string]string)
+	for _, org := range orgs {
+		categories, err := s.clients.For(org.ID).GetApplicationCategories(ctx, org.ID)
+		if err != nil {
+			s.logger.Warn().Err(err).Str("orgId", org.ID).Msg("Could not fetch application categories, tag columns may be incomplete")
+			continue
+		}
+		for _, c := range categories {
+			names[c.ID] = c.Name
+		}
+	}
+	return names
+}
+
+// parseTag splits a tag name into key and value at the first ':' or '=',
+// e.g. "costCenter:4711". A tag without separator is a flag with value
+// "true".
+func parseTag(name string) (key, value string) {
+	if i := strings.IndexAny(name, ":="); i >= 0 {
+		return strings.TrimSpace(name[:i]), strings.TrimSpace(name[i+1:])
+	}
+	return strings.TrimSpace(name), "true"
+}
+
+// applicationTags returns the values of the tags of app whose key is one of
+// keys, by key. Several values of one key are joined with ", ".
+func applicationTags(app client.Application, names map[string]string, keys []string) map[string]string {
+	if len(keys) == 0 {
+		return nil
+	}
+	values := make(map[string][]string)
+	for _, t := range app.ApplicationTags {
+		name, ok := names[t.TagID]
+		if !ok {
+			continue
+		}
+		key, value := parseTag(name)
+		if slices.Contains(keys, key) {
+			values[key] = append(values[key], value)
+		}
+	}
+	tags := make(map[string]string, len(values))
+	for key, v := range values {
+		slices.Sort(v)
+		tags[key] = strings.Join(v, ", ")
+	}
+	return tags
+}
@@ -0,0 +1,96 @@
+// internal/services/tags_test.go
+package services
+
+import (
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/client"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/config"
+)
+
+func TestApplicationTags(t *testing.T) {
+	names := map[string]string{
+		"t1": "costCenter:4711",
+		"t2": "owner = team-a",
+		"t3": "owner=team-b",
+		"t4": "PCI",
+		"t5": "region:eu",
+	}
+	app := client.Application{ApplicationTags: []client.ApplicationTag{
+		{TagID: "t1"}, {TagID: "t3"}, {TagID: "t2"}, {TagID: "t4"}, {TagID: "t5"}, {TagID: "unknown"},
+	}}
+
+	got := applicationTags(app, names, []string{"costCenter", "owner", "PCI", "missing"})
+	want := map[string]string{"costCenter": "4711", "owner": "team-a, team-b", "PCI": "true"}
+	if len(got) != len(want) {
+		t.Fatalf("tags = %v, want %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("tag %s = %q, want %q", k, got[k], v)
+		}
+	}
+	if applicationTags(app, names, nil) != nil {
+		t.Error("expected no tags without tag columns")
+	}
+}
+
+func TestGenerateLatestPolicyReport_TagColumns(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v2/applications":
+			_, _ = w.Write([]byte(`{"applications": [{"id": "aid-1", "publicId": "apid-1", "organizationId": "org-1", "applicationTags": [{"tagId": "t1"}]}]}`))
+		case "/api/v2/organizations":
+			_, _ = w.Write([]byte(`{"organizations": [{"id": "org-1", "name": "personal"}]}`))
+		case "/api/v2/applicationCategories/organization/org-1":
+			_, _ = w.Write([]byte(`[{"id": "t1", "name": "costCenter:4711"}]`))
+		case "/api/v2/reports/applications/aid-1":
+			_, _ = w.Write([]byte(`[{"stage": "build", "reportHtmlUrl": "ui/links/application/apid-1/report/rpt-1"}]`))
+		case "/api/v2/applications/apid-1/reports/rpt-1/policy":
+			_, _ = w.Write([]byte(`{"components": [{"displayName": "lib", "violations": [{"policyName": "P", "policyThreatLevel": 7, "constraints": [{"constraintName": "C"}]}]}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	iqClient, err := client.NewClient(server.URL, "u", "p", testLogger())
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	cfg := &config.Config{OutputDir: t.TempDir(), AppTagColumns: []string{"costCenter", "owner"}}
+	svc := NewIQReportService(cfg, iqClient, testLogger())
+
+	path, err := svc.GenerateLatestPolicyReport(rCtx(t), "report.csv")
+	if err != nil {
+		t.Fatalf("GenerateLatestPolicyReport: %v", err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open report: %v", err)
+	}
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatalf("read report: %v", err)
+	}
+	header, row := records[0], records[1]
+	n := len(header)
+	if header[n-2] != "costCenter" || header[n-1] != "owner" {
+		t.Fatalf("header = %v, want tag columns last", header)
+	}
+	if row[n-2] != "4711" || row[n-1] != "" {
+		t.Errorf("tag cells = %q, %q", row[n-2], row[n-1])
+	}
+
+	cfg.AppTagColumns = []string{"Application"}
+	svc = NewIQReportService(cfg, iqClient, testLogger())
+	if _, err := svc.GenerateLatestPolicyReport(rCtx(t), "report.csv"); err == nil {
+		t.Error("expected error for a tag column named like a report column")
+	}
+}
@@ -0,0 +1,30 @@
+// internal/services/validation.go
+package services
+
+import (
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/client"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/report"
+)
+
+// countMismatch compares the violations parsed from a report with the
+// summary counts of the same report in the application's history. It
+// returns the mismatch and true when they differ; reports missing from the
+// history cannot be validated and pass.
+func countMismatch(history []client.HistoricalReport, app, stage, reportID string, rows []report.Row) (report.CountMismatch, bool) {
+	for _, h := range history {
+		if id, err := h.ReportID(); err != nil || id != reportID {
+			continue
+		}
+		expected := report.ViolationCounts{
+			Critical: h.PolicyEvaluationResult.CriticalPolicyViolationCount,
+			Severe:   h.PolicyEvaluationResult.SeverePolicyViolationCount,
+			Moderate: h.PolicyEvaluationResult.ModeratePolicyViolationCount,
+		}
+		parsed := report.CountViolations(rows)
+		if parsed == expected {
+			return report.CountMismatch{}, false
+		}
+		return report.CountMismatch{Application: app, Stage: stage, ReportID: reportID, Expected: expected, Parsed: parsed}, true
+	}
+	return report.CountMismatch{}, false
+}
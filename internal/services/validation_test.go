@@ -0,0 +1,66 @@
+// internal/services/validation_test.go
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/client"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/config"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/report"
+)
+
+func TestGenerateLatestPolicyReport_ValidatesCounts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v2/applications":
+			_, _ = w.Write([]byte(`{"applications": [{"id": "aid-1", "publicId": "app-1"}, {"id": "aid-2", "publicId": "app-2"}]}`))
+		case "/api/v2/organizations":
+			_, _ = w.Write([]byte(`{"organizations": []}`))
+		case "/api/v2/reports/applications/aid-1":
+			_, _ = w.Write([]byte(`[{"stage": "build", "reportHtmlUrl": "https://stub/report/rpt-1"}]`))
+		case "/api/v2/reports/applications/aid-2":
+			_, _ = w.Write([]byte(`[{"stage": "build", "reportHtmlUrl": "https://stub/report/rpt-2"}]`))
+		case "/api/v2/reports/applications/aid-1/history":
+			_, _ = w.Write([]byte(`{"reports": [{"stage": "build", "reportHtmlUrl": "https://stub/report/rpt-1", "policyEvaluationResult": {"criticalPolicyViolationCount": 1}}]}`))
+		case "/api/v2/reports/applications/aid-2/history":
+			// A violation without constraints yields no row, so one critical is lost
+			_, _ = w.Write([]byte(`{"reports": [{"stage": "build", "reportHtmlUrl": "https://stub/report/rpt-2", "policyEvaluationResult": {"criticalPolicyViolationCount": 2}}]}`))
+		case "/api/v2/applications/app-1/reports/rpt-1/policy":
+			_, _ = w.Write([]byte(`{"components": [{"displayName": "a", "violations": [{"policyViolationId": "v1", "policyName": "P", "policyThreatLevel": 9, "constraints": [{"constraintName": "C1"}, {"constraintName": "C2"}]}]}]}`))
+		case "/api/v2/applications/app-2/reports/rpt-2/policy":
+			_, _ = w.Write([]byte(`{"components": [{"displayName": "a", "violations": [
+				{"policyViolationId": "v2", "policyName": "P", "policyThreatLevel": 9, "constraints": [{"constraintName": "C"}]},
+				{"policyViolationId": "v3", "policyName": "P", "policyThreatLevel": 9}
+			]}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	iqClient, _ := client.NewClient(server.URL, "u", "p", testLogger())
+	svc := NewIQReportService(&config.Config{OutputDir: dir, ValidateCounts: true}, iqClient, testLogger())
+	if _, err := svc.GenerateLatestPolicyReport(rCtx(t), "report.csv"); err != nil {
+		t.Fatalf("GenerateLatestPolicyReport: %v", err)
+	}
+
+	m, err := report.ReadManifest(report.ManifestPath(filepath.Join(dir, "report.csv")))
+	if err != nil {
+		t.Fatalf("ReadManifest: %v", err)
+	}
+	want := report.CountMismatch{
+		Application: "app-2",
+		Stage:       "build",
+		ReportID:    "rpt-2",
+		Expected:    report.ViolationCounts{Critical: 2},
+		Parsed:      report.ViolationCounts{Critical: 1},
+	}
+	if len(m.CountMismatches) != 1 || m.CountMismatches[0] != want {
+		t.Errorf("mismatches = %+v, want [%+v]", m.CountMismatches, want)
+	}
+}
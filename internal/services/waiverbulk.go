@@ -0,0 +1,246 @@
+// internal/services/waiverbulk.go
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/client"
+	"gopkg.in/yaml.v3"
+)
+
+// BulkWaiver is one entry of a reviewed bulk waiver file: the risk of a
+// policy violation accepted until it expires.
+type BulkWaiver struct {
+	Application string `yaml:"application"` // public ID
+	ViolationID string `yaml:"violationId"` // the Row ID of the report
+	Scope       string `yaml:"scope"`       // see WaiverScopes; empty is "component"
+	Comment     string `yaml:"comment"`
+	Expires     string `yaml:"expires"` // YYYY-MM-DD, last day the waiver applies
+
+	expires time.Time
+}
+
+// bulkWaiverFile is the layout of the YAML bulk waiver file.
+type bulkWaiverFile struct {
+	Waivers []BulkWaiver `yaml:"waivers"`
+}
+
+// waiverScopes maps the scopes of a bulk waiver file to IQ matcher
+// strategies.
+var waiverScopes = map[string]string{
+	"component":      client.WaiverExactComponent,
+	"all-versions":   client.WaiverAllVersions,
+	"all-components": client.WaiverAllComponents,
+}
+
+// WaiverScopes returns the scopes accepted in bulk waiver files.
+func WaiverScopes() []string {
+	return []string{"component", "all-versions", "all-components"}
+}
+
+// LoadBulkWaivers reads and validates a bulk waiver file: CSV when the file
+// name ends in .csv, YAML otherwise. Every entry needs an application, a
+// violation ID, a comment and an expiry date, and a violation may only be
+// listed once.
+func LoadBulkWaivers(file string) ([]BulkWaiver, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("read waivers: %w", err)
+	}
+	var waivers []BulkWaiver
+	if strings.EqualFold(filepath.Ext(file), ".csv") {
+		waivers, err = parseBulkWaiversCSV(strings.NewReader(string(b)))
+	} else {
+		var f bulkWaiverFile
+		err = yaml.Unmarshal(b, &f)
+		waivers = f.Waivers
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decode waivers %s: %w", file, err)
+	}
+
+	seen := make(map[string]int, len(waivers))
+	for i := range waivers {
+		w := &waivers[i]
+		switch {
+		case w.Application == "":
+			return nil, fmt.Errorf("waiver %d: application is required", i+1)
+		case w.ViolationID == "":
+			return nil, fmt.Errorf("waiver %d: violationId is required", i+1)
+		case strings.TrimSpace(w.Comment) == "":
+			return nil, fmt.Errorf("waiver %d: comment is required", i+1)
+		case w.Expires == "":
+			return nil, fmt.Errorf("waiver %d: expires is required", i+1)
+		}
+		if w.Scope == "" {
+			w.Scope = "component"
+		}
+		if _, ok := waiverScopes[w.Scope]; !ok {
+			return nil, fmt.Errorf("waiver %d: unknown scope %q (expected one of %s)", i+1, w.Scope, strings.Join(WaiverScopes(), ", "))
+		}
+		w.expires, err = time.Parse(time.DateOnly, w.Expires)
+		if err != nil {
+			return nil, fmt.Errorf("waiver %d: invalid expires %q: %w", i+1, w.Expires, err)
+		}
+		key := w.Application + "\x1f" + w.ViolationID
+		if first, ok := seen[key]; ok {
+			return nil, fmt.Errorf("waiver %d: violation %s of %s is already listed in waiver %d", i+1, w.ViolationID, w.Application, first)
+		}
+		seen[key] = i + 1
+	}
+	return waivers, nil
+}
+
+// parseBulkWaiversCSV reads bulk waivers from CSV with the header columns
+// Application, Violation ID, Scope, Comment and Expires, in any order.
+// Header names ignore case and spaces; Row ID and Expiry are accepted as
+// aliases, so that rows copied from the report can be used.
+func parseBulkWaiversCSV(r io.Reader) ([]BulkWaiver, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	cols := make(map[string]int)
+	for i, h := range records[0] {
+		switch strings.ToLower(strings.ReplaceAll(strings.TrimSpace(h), " ", "")) {
+		case "application":
+			cols["application"] = i
+		case "violationid", "rowid":
+			cols["violationId"] = i
+		case "scope":
+			cols["scope"] = i
+		case "comment":
+			cols["comment"] = i
+		case "expires", "expiry":
+			cols["expires"] = i
+		}
+	}
+	for _, name := range []string{"application", "violationId", "comment", "expires"} {
+		if _, ok := cols[name]; !ok {
+			return nil, fmt.Errorf("missing column %s", name)
+		}
+	}
+	cell := func(record []string, name string) string {
+		i, ok := cols[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	waivers := make([]BulkWaiver, 0, len(records)-1)
+	for _, record := range records[1:] {
+		waivers = append(waivers, BulkWaiver{
+			Application: cell(record, "application"),
+			ViolationID: cell(record, "violationId"),
+			Scope:       cell(record, "scope"),
+			Comment:     cell(record, "comment"),
+			Expires:     cell(record, "expires"),
+		})
+	}
+	return waivers, nil
+}
+
+// Outcomes of a bulk waiver, see BulkWaiverResult.
+const (
+	WaiverPlanned  = "planned"  // would be created; dry run
+	WaiverApplied  = "applied"  // created
+	WaiverExisting = "existing" // the violation is already waived
+	WaiverFailed   = "failed"
+)
+
+// BulkWaiverResult is the outcome of one bulk waiver.
+type BulkWaiverResult struct {
+	Waiver BulkWaiver
+	Status string
+	Err    error // set when Status is WaiverFailed
+}
+
+// ApplyBulkWaivers creates the given waivers in IQ Server, or only checks
+// them when apply is false. Violations that are already waived are left
+// alone. Expired entries fail the whole batch before anything is created,
+// and applying is refused on a read-only client. Waivers are created one at
+// a time; failures do not stop the batch and are returned joined.
+func (s *IQReportService) ApplyBulkWaivers(ctx context.Context, waivers []BulkWaiver, apply bool) ([]BulkWaiverResult, error) {
+	if apply && s.clients.Default().ReadOnly() {
+		return nil, fmt.Errorf("bulk waivers cannot be applied in read-only mode (READ_ONLY)")
+	}
+	today := s.now().UTC().Truncate(24 * time.Hour)
+	for i, w := range waivers {
+		if w.expires.Before(today) {
+			return nil, fmt.Errorf("waiver %d: expired on %s", i+1, w.Expires)
+		}
+	}
+
+	results := make([]BulkWaiverResult, len(waivers))
+	type appState struct {
+		app     *client.Application
+		waived  map[string]bool
+		lookErr error
+	}
+	apps := make(map[string]*appState)
+	var errs []error
+	for i, w := range waivers {
+		if err := ctx.Err(); err != nil {
+			return results[:i], err
+		}
+		results[i].Waiver = w
+		logger := s.logger.With().Str("appPublicID", w.Application).Str("violationId", w.ViolationID).Logger()
+
+		st, ok := apps[w.Application]
+		if !ok {
+			st = &appState{}
+			apps[w.Application] = st
+			st.app, st.lookErr = s.clients.Default().GetApplicationByPublicID(ctx, w.Application)
+			if st.lookErr == nil {
+				var existing []client.PolicyWaiver
+				existing, st.lookErr = s.clients.For(st.app.OrganizationID).GetPolicyWaivers(ctx, st.app.ID)
+				st.waived = make(map[string]bool, len(existing))
+				for _, e := range existing {
+					st.waived[e.PolicyViolationID] = true
+				}
+			}
+		}
+		switch {
+		case st.lookErr != nil:
+			results[i].Status, results[i].Err = WaiverFailed, fmt.Errorf("app %s: %w", w.Application, st.lookErr)
+		case st.waived[w.ViolationID]:
+			results[i].Status = WaiverExisting
+		case !apply:
+			results[i].Status = WaiverPlanned
+		default:
+			nw := client.NewPolicyWaiver{
+				Comment:         w.Comment,
+				MatcherStrategy: waiverScopes[w.Scope],
+				// The waiver applies through the whole expiry day
+				ExpiryTime: w.expires.Add(24*time.Hour - time.Millisecond).Format(iqTimeLayout),
+			}
+			if err := s.clients.For(st.app.OrganizationID).CreatePolicyWaiver(ctx, st.app.ID, w.ViolationID, nw); err != nil {
+				results[i].Status, results[i].Err = WaiverFailed, fmt.Errorf("app %s: violation %s: %w", w.Application, w.ViolationID, err)
+				break
+			}
+			results[i].Status = WaiverApplied
+			st.waived[w.ViolationID] = true
+			logger.Info().Str("scope", w.Scope).Str("expires", w.Expires).Msg("Policy waiver created")
+		}
+		if results[i].Err != nil {
+			logger.Warn().Err(results[i].Err).Msg("Policy waiver not created")
+			errs = append(errs, results[i].Err)
+		}
+	}
+	if len(errs) > 0 {
+		return results, fmt.Errorf("%d of %d waivers failed: %w", len(errs), len(waivers), errors.Join(errs...))
+	}
+	return results, nil
+}
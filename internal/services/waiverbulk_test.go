@@ -0,0 +1,172 @@
+// internal/services/waiverbulk_test.go
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/client"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/config"
+)
+
+func writeWaiverFile(t *testing.T, name, content string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+		t.Fatalf("write waivers: %v", err)
+	}
+	return file
+}
+
+func TestLoadBulkWaivers(t *testing.T) {
+	csvFile := writeWaiverFile(t, "waivers.csv", "Application,Row ID,Comment,Expiry,Scope\n"+
+		"app-a,pv-1,Accepted in RISK-1,2030-01-31,\n"+
+		"app-a,pv-2,Accepted in RISK-1,2030-01-31,all-versions\n")
+	waivers, err := LoadBulkWaivers(csvFile)
+	if err != nil {
+		t.Fatalf("LoadBulkWaivers(csv): %v", err)
+	}
+	if len(waivers) != 2 || waivers[0].ViolationID != "pv-1" || waivers[0].Scope != "component" || waivers[1].Scope != "all-versions" {
+		t.Errorf("unexpected csv waivers: %+v", waivers)
+	}
+
+	yamlFile := writeWaiverFile(t, "waivers.yaml", `
+waivers:
+  - application: app-a
+    violationId: pv-1
+    scope: all-components
+    comment: Accepted in RISK-2
+    expires: 2030-06-30
+`)
+	waivers, err = LoadBulkWaivers(yamlFile)
+	if err != nil {
+		t.Fatalf("LoadBulkWaivers(yaml): %v", err)
+	}
+	if len(waivers) != 1 || waivers[0].Scope != "all-components" || waivers[0].Comment != "Accepted in RISK-2" {
+		t.Errorf("unexpected yaml waivers: %+v", waivers)
+	}
+}
+
+func TestLoadBulkWaivers_Validation(t *testing.T) {
+	cases := map[string]string{
+		"missing comment":   "waivers:\n  - application: a\n    violationId: v\n    expires: 2030-01-01\n",
+		"missing expiry":    "waivers:\n  - application: a\n    violationId: v\n    comment: ok\n",
+		"missing violation": "waivers:\n  - application: a\n    comment: ok\n    expires: 2030-01-01\n",
+		"invalid expiry":    "waivers:\n  - application: a\n    violationId: v\n    comment: ok\n    expires: soon\n",
+		"unknown scope":     "waivers:\n  - application: a\n    violationId: v\n    comment: ok\n    expires: 2030-01-01\n    scope: everything\n",
+		"duplicate":         "waivers:\n  - {application: a, violationId: v, comment: ok, expires: 2030-01-01}\n  - {application: a, violationId: v, comment: again, expires: 2030-01-01}\n",
+	}
+	for name, content := range cases {
+		if _, err := LoadBulkWaivers(writeWaiverFile(t, "waivers.yaml", content)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+	if _, err := LoadBulkWaivers(writeWaiverFile(t, "waivers.csv", "Application,Comment,Expires\na,ok,2030-01-01\n")); err == nil {
+		t.Error("missing CSV column: expected error")
+	}
+}
+
+func TestApplyBulkWaivers(t *testing.T) {
+	var mu sync.Mutex
+	created := make(map[string]client.NewPolicyWaiver)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v2/applications" && r.URL.Query().Get("publicId") == "app-a":
+			_, _ = w.Write([]byte(`{"applications": [{"id": "aid-a", "publicId": "app-a", "organizationId": "org-1"}]}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v2/applications":
+			_, _ = w.Write([]byte(`{"applications": []}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v2/policyWaivers/application/aid-a":
+			_, _ = w.Write([]byte(`[{"policyWaiverId": "w-1", "policyViolationId": "pv-waived"}]`))
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v2/policyWaivers/application/aid-a/pv-fail":
+			w.WriteHeader(http.StatusBadRequest)
+		case r.Method == http.MethodPost:
+			var body client.NewPolicyWaiver
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("decode waiver: %v", err)
+			}
+			mu.Lock()
+			created[r.URL.Path] = body
+			mu.Unlock()
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	file := writeWaiverFile(t, "waivers.csv", "Application,Violation ID,Scope,Comment,Expires\n"+
+		"app-a,pv-1,all-versions,Accepted in RISK-1,2025-06-30\n"+
+		"app-a,pv-waived,,Accepted in RISK-1,2025-06-30\n"+
+		"app-a,pv-fail,,Accepted in RISK-1,2025-06-30\n"+
+		"app-gone,pv-2,,Accepted in RISK-1,2025-06-30\n")
+	waivers, err := LoadBulkWaivers(file)
+	if err != nil {
+		t.Fatalf("LoadBulkWaivers: %v", err)
+	}
+
+	iqClient, err := client.NewClient(server.URL, "u", "p", testLogger())
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	svc := NewIQReportService(&config.Config{OutputDir: t.TempDir()}, iqClient, testLogger())
+	svc.SetClock(FixedClock(time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)))
+
+	// Dry run: nothing is created
+	results, err := svc.ApplyBulkWaivers(rCtx(t), waivers, false)
+	if err == nil {
+		t.Error("dry run: expected error for the unknown application")
+	}
+	want := []string{WaiverPlanned, WaiverExisting, WaiverPlanned, WaiverFailed}
+	for i, r := range results {
+		if r.Status != want[i] {
+			t.Errorf("dry run waiver %d status = %s, want %s", i+1, r.Status, want[i])
+		}
+	}
+	if len(created) != 0 {
+		t.Fatalf("dry run created waivers: %v", created)
+	}
+
+	results, err = svc.ApplyBulkWaivers(rCtx(t), waivers, true)
+	if err == nil {
+		t.Error("apply: expected error for the failed waivers")
+	}
+	want = []string{WaiverApplied, WaiverExisting, WaiverFailed, WaiverFailed}
+	for i, r := range results {
+		if r.Status != want[i] {
+			t.Errorf("waiver %d status = %s (%v), want %s", i+1, r.Status, r.Err, want[i])
+		}
+	}
+	got, ok := created["/api/v2/policyWaivers/application/aid-a/pv-1"]
+	if !ok || len(created) != 1 {
+		t.Fatalf("created = %v, want only pv-1", created)
+	}
+	if got.MatcherStrategy != client.WaiverAllVersions || got.Comment != "Accepted in RISK-1" || got.ExpiryTime != "2025-06-30T23:59:59.999+0000" {
+		t.Errorf("created waiver = %+v", got)
+	}
+
+	// Expired entries fail the batch up front
+	svc.SetClock(FixedClock(time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)))
+	if _, err := svc.ApplyBulkWaivers(rCtx(t), waivers, true); err == nil {
+		t.Error("expected error for expired waivers")
+	}
+
+	roClient, err := client.NewClient(server.URL, "u", "p", testLogger(), client.WithReadOnly(true))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	svc = NewIQReportService(&config.Config{OutputDir: t.TempDir()}, roClient, testLogger())
+	svc.SetClock(FixedClock(time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)))
+	if _, err := svc.ApplyBulkWaivers(rCtx(t), waivers, true); err == nil {
+		t.Error("expected applying to be refused in read-only mode")
+	}
+	if _, err := svc.ApplyBulkWaivers(rCtx(t), waivers[:1], false); err != nil {
+		t.Errorf("dry run in read-only mode: %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+// internal/services/waivers.go
+package services
+
+import (
+	"slices"
+	"time"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/client"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/report"
+)
+
+// iqTimeLayout is the timestamp layout used by IQ Server, e.g.
+// "2025-01-31T00:00:00.000+0000".
+const iqTimeLayout = "2006-01-02T15:04:05.000-0700"
+
+// needsWaivers reports whether the waiver expiry or creator column is
+// enabled, so that waivers need to be fetched.
+func (s *IQReportService) needsWaivers() bool {
+	return slices.Contains(s.opts.CSVOptionalColumns, report.ColumnWaiverExpiry) ||
+		slices.Contains(s.opts.CSVOptionalColumns, report.ColumnWaiverCreator)
+}
+
+// hasWaivedRows reports whether any row belongs to a waived violation.
+func hasWaivedRows(rows []report.Row) bool {
+	return slices.ContainsFunc(rows, func(r report.Row) bool { return r.Waived })
+}
+
+// applyWaivers fills the waiver expiry and creator of waived rows from the
+// waiver matching the row's policy violation ID.
+func applyWaivers(rows []report.Row, waivers []client.PolicyWaiver) {
+	byViolation := make(map[string]client.PolicyWaiver, len(waivers))
+	for _, w := range waivers {
+		byViolation[w.PolicyViolationID] = w
+	}
+	for i := range rows {
+		if !rows[i].Waived {
+			continue
+		}
+		w, ok := byViolation[rows[i].ViolationID]
+		if !ok {
+			continue
+		}
+		rows[i].WaiverExpiry = waiverExpiryDate(w.ExpiryTime)
+		rows[i].WaiverCreator = w.CreatorName
+	}
+}
+
+// waiverExpiryDate formats an IQ expiry timestamp as a date. Waivers without
+// expiry are reported as "never"; unparseable values are kept as they are.
+func waiverExpiryDate(expiry string) string {
+	if expiry == "" {
+		return "never"
+	}
+	for _, layout := range []string{iqTimeLayout, time.RFC3339} {
+		if t, err := time.Parse(layout, expiry); err == nil {
+			return t.UTC().Format(time.DateOnly)
+		}
+	}
+	return expiry
+}
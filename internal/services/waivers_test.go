@@ -0,0 +1,95 @@
+// internal/services/waivers_test.go
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/client"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/config"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/report"
+)
+
+func TestApplyWaivers(t *testing.T) {
+	rows := []report.Row{
+		{ViolationID: "pv-1", Waived: true},
+		{ViolationID: "pv-2", Waived: true},
+		{ViolationID: "pv-3"},
+	}
+	if !hasWaivedRows(rows) {
+		t.Fatal("hasWaivedRows = false")
+	}
+
+	applyWaivers(rows, []client.PolicyWaiver{
+		{PolicyViolationID: "pv-1", ExpiryTime: "2025-01-31T10:00:00.000+0000", CreatorName: "Jane"},
+		{PolicyViolationID: "pv-2", CreatorName: "Joe"},
+		{PolicyViolationID: "pv-3", ExpiryTime: "2025-01-31T10:00:00.000+0000", CreatorName: "Ignored"},
+	})
+
+	if rows[0].WaiverExpiry != "2025-01-31" || rows[0].WaiverCreator != "Jane" {
+		t.Errorf("row 0 = %#v", rows[0])
+	}
+	if rows[1].WaiverExpiry != "never" || rows[1].WaiverCreator != "Joe" {
+		t.Errorf("row 1 = %#v", rows[1])
+	}
+	if rows[2].WaiverExpiry != "" || rows[2].WaiverCreator != "" {
+		t.Errorf("unwaived row was modified: %#v", rows[2])
+	}
+}
+
+func TestGenerateLatestPolicyReport_WaiverColumns(t *testing.T) {
+	var waiverFetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v2/applications":
+			_, _ = w.Write([]byte(`{"applications": [{"id": "aid-1", "publicId": "apid-1"}]}`))
+		case "/api/v2/organizations":
+			_, _ = w.Write([]byte(`{"organizations": []}`))
+		case "/api/v2/reports/applications/aid-1":
+			_, _ = w.Write([]byte(`[{"stage": "build", "reportHtmlUrl": "ui/links/application/apid-1/report/rpt-1"}]`))
+		case "/api/v2/applications/apid-1/reports/rpt-1/policy":
+			_, _ = w.Write([]byte(`{"components": [{"displayName": "lib", "violations": [{"policyViolationId": "pv-1", "waived": true, "policyName": "P", "policyThreatLevel": 7, "constraints": [{"constraintName": "C"}]}]}]}`))
+		case "/api/v2/policyWaivers/application/aid-1":
+			waiverFetches.Add(1)
+			_, _ = w.Write([]byte(`[{"policyViolationId": "pv-1", "expiryTime": "2025-01-31T00:00:00.000+0000", "creatorName": "Jane"}]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	iqClient, err := client.NewClient(server.URL, "u", "p", testLogger())
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	// Without the waiver columns, waivers are not fetched
+	cfg := &config.Config{OutputDir: t.TempDir()}
+	if _, err := NewIQReportService(cfg, iqClient, testLogger()).GenerateLatestPolicyReport(rCtx(t), "report.csv"); err != nil {
+		t.Fatalf("GenerateLatestPolicyReport: %v", err)
+	}
+	if n := waiverFetches.Load(); n != 0 {
+		t.Errorf("waivers fetched %d times without the waiver columns", n)
+	}
+
+	cfg.CSVOptionalColumns = []string{report.ColumnWaived, report.ColumnWaiverExpiry, report.ColumnWaiverCreator}
+	path, err := NewIQReportService(cfg, iqClient, testLogger()).GenerateLatestPolicyReport(rCtx(t), "report.csv")
+	if err != nil {
+		t.Fatalf("GenerateLatestPolicyReport with waiver columns: %v", err)
+	}
+	if n := waiverFetches.Load(); n != 1 {
+		t.Errorf("waivers fetched %d times, want once", n)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read report: %v", err)
+	}
+	if !strings.Contains(string(data), ",true,2025-01-31,Jane\n") {
+		t.Errorf("report lacks the waiver cells:\n%s", data)
+	}
+}
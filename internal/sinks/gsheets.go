@@ -0,0 +1,274 @@
+// internal/sinks/gsheets.go
+package sinks
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/report"
+	"github.com/go-resty/resty/v2"
+	"github.com/rs/zerolog"
+)
+
+// Worksheet modes of the Google Sheets sink.
+const (
+	SheetModeOverwrite = "overwrite" // replace the contents of one worksheet
+	SheetModeNew       = "new"       // add a worksheet per run
+)
+
+const (
+	sheetsAPI   = "https://sheets.googleapis.com/v4/spreadsheets/"
+	sheetsScope = "https://www.googleapis.com/auth/spreadsheets"
+)
+
+// GoogleSheetsConfig configures the Google Sheets sink.
+type GoogleSheetsConfig struct {
+	CredentialsFile string // service account key file (JSON)
+	SpreadsheetID   string
+	Mode            string // "overwrite" or "new"
+	Worksheet       string // worksheet replaced in overwrite mode
+	APIURL          string // Sheets API base URL; defaults to the public API
+	ThreatFormat    string // see report.FormatThreat; empty writes the number
+}
+
+// serviceAccount is the subset of a service account key file used for the
+// JWT bearer token flow.
+type serviceAccount struct {
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// GoogleSheets writes the report rows to a Google spreadsheet, either by
+// overwriting a fixed worksheet or by adding a worksheet per run.
+type GoogleSheets struct {
+	cfg     GoogleSheetsConfig
+	account serviceAccount
+	key     *rsa.PrivateKey
+	http    *resty.Client
+	logger  zerolog.Logger
+	now     func() time.Time
+}
+
+// NewGoogleSheets creates a Google Sheets sink authenticating with the
+// service account in cfg.CredentialsFile.
+func NewGoogleSheets(cfg GoogleSheetsConfig, logger zerolog.Logger) (*GoogleSheets, error) {
+	if cfg.SpreadsheetID == "" {
+		return nil, fmt.Errorf("gsheets: spreadsheet ID is required")
+	}
+	switch cfg.Mode {
+	case "":
+		cfg.Mode = SheetModeOverwrite
+	case SheetModeOverwrite, SheetModeNew:
+	default:
+		return nil, fmt.Errorf("gsheets: unsupported mode %q", cfg.Mode)
+	}
+	if cfg.Worksheet == "" {
+		cfg.Worksheet = "Report"
+	}
+	if cfg.APIURL == "" {
+		cfg.APIURL = sheetsAPI
+	}
+
+	b, err := os.ReadFile(cfg.CredentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf("gsheets: read credentials: %w", err)
+	}
+	var account serviceAccount
+	if err := json.Unmarshal(b, &account); err != nil {
+		return nil, fmt.Errorf("gsheets: decode credentials %s: %w", cfg.CredentialsFile, err)
+	}
+	if account.ClientEmail == "" || account.TokenURI == "" {
+		return nil, fmt.Errorf("gsheets: credentials %s lack client_email or token_uri", cfg.CredentialsFile)
+	}
+	key, err := parsePrivateKey(account.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("gsheets: %w", err)
+	}
+
+	cfg.APIURL = strings.TrimRight(cfg.APIURL, "/") + "/"
+	r := resty.New().
+		SetHeader("Accept", "application/json").
+		SetTimeout(60 * time.Second)
+
+	return &GoogleSheets{cfg: cfg, account: account, key: key, http: r, logger: logger, now: time.Now}, nil
+}
+
+// Name identifies the sink in logs and errors.
+func (g *GoogleSheets) Name() string { return "gsheets" }
+
+// Publish writes rows, including the header, to the target worksheet.
+func (g *GoogleSheets) Publish(ctx context.Context, rows []report.Row) error {
+	token, err := g.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	sheet := g.cfg.Worksheet
+	if g.cfg.Mode == SheetModeNew {
+		sheet = g.now().Format("2006-01-02 15:04:05")
+		if err := g.addSheet(ctx, token, sheet); err != nil {
+			return err
+		}
+	} else {
+		titles, err := g.sheetTitles(ctx, token)
+		if err != nil {
+			return err
+		}
+		if !slices.Contains(titles, sheet) {
+			if err := g.addSheet(ctx, token, sheet); err != nil {
+				return err
+			}
+		}
+		if err := g.call(ctx, token, "clear worksheet", g.http.R().SetBody(map[string]any{}),
+			"POST", g.cfg.SpreadsheetID+"/values/"+sheetRange(sheet)+":clear"); err != nil {
+			return err
+		}
+	}
+
+	body := map[string]any{
+		"range":          quoteSheet(sheet) + "!A1",
+		"majorDimension": "ROWS",
+		"values":         report.Table(rows, report.WithThreatFormat(g.cfg.ThreatFormat)),
+	}
+	req := g.http.R().SetQueryParam("valueInputOption", "RAW").SetBody(body)
+	if err := g.call(ctx, token, "write values", req, "PUT", g.cfg.SpreadsheetID+"/values/"+sheetRange(sheet)); err != nil {
+		return err
+	}
+	g.logger.Debug().Str("worksheet", sheet).Int("rows", len(rows)).Msg("Wrote worksheet")
+	return nil
+}
+
+// sheetTitles lists the worksheet titles of the spreadsheet.
+func (g *GoogleSheets) sheetTitles(ctx context.Context, token string) ([]string, error) {
+	var meta struct {
+		Sheets []struct {
+			Properties struct {
+				Title string `json:"title"`
+			} `json:"properties"`
+		} `json:"sheets"`
+	}
+	req := g.http.R().SetQueryParam("fields", "sheets.properties.title").SetResult(&meta)
+	if err := g.call(ctx, token, "get spreadsheet", req, "GET", g.cfg.SpreadsheetID); err != nil {
+		return nil, err
+	}
+	titles := make([]string, 0, len(meta.Sheets))
+	for _, s := range meta.Sheets {
+		titles = append(titles, s.Properties.Title)
+	}
+	return titles, nil
+}
+
+// addSheet adds a worksheet named title to the spreadsheet.
+func (g *GoogleSheets) addSheet(ctx context.Context, token, title string) error {
+	body := map[string]any{
+		"requests": []any{
+			map[string]any{"addSheet": map[string]any{"properties": map[string]any{"title": title}}},
+		},
+	}
+	return g.call(ctx, token, "add worksheet", g.http.R().SetBody(body), "POST", g.cfg.SpreadsheetID+":batchUpdate")
+}
+
+// call executes req against the Sheets API and maps failures to errors.
+func (g *GoogleSheets) call(ctx context.Context, token, what string, req *resty.Request, method, path string) error {
+	// Paths such as "<id>:batchUpdate" would parse as a URL scheme, so the
+	// full URL is built here instead of relying on resty's base URL.
+	resp, err := req.SetContext(ctx).SetAuthToken(token).Execute(method, g.cfg.APIURL+path)
+	if err != nil {
+		return fmt.Errorf("%s: %w", what, err)
+	}
+	if resp.IsError() {
+		return fmt.Errorf("%s: HTTP %d: %s", what, resp.StatusCode(), strings.TrimSpace(resp.String()))
+	}
+	return nil
+}
+
+// accessToken exchanges a signed JWT assertion for an OAuth access token.
+func (g *GoogleSheets) accessToken(ctx context.Context) (string, error) {
+	assertion, err := g.signJWT()
+	if err != nil {
+		return "", err
+	}
+	var tok struct {
+		AccessToken string `json:"access_token"`
+	}
+	resp, err := g.http.R().
+		SetContext(ctx).
+		SetFormData(map[string]string{
+			"grant_type": "urn:ietf:params:oauth:grant-type:jwt-bearer",
+			"assertion":  assertion,
+		}).
+		SetResult(&tok).
+		Post(g.account.TokenURI)
+	if err != nil {
+		return "", fmt.Errorf("fetch access token: %w", err)
+	}
+	if resp.IsError() || tok.AccessToken == "" {
+		return "", fmt.Errorf("fetch access token: HTTP %d: %s", resp.StatusCode(), strings.TrimSpace(resp.String()))
+	}
+	return tok.AccessToken, nil
+}
+
+// signJWT builds the RS256-signed assertion of the JWT bearer flow.
+func (g *GoogleSheets) signJWT() (string, error) {
+	now := g.now()
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	claims, _ := json.Marshal(map[string]any{
+		"iss":   g.account.ClientEmail,
+		"scope": sheetsScope,
+		"aud":   g.account.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	enc := base64.RawURLEncoding
+	unsigned := enc.EncodeToString(header) + "." + enc.EncodeToString(claims)
+	sum := sha256.Sum256([]byte(unsigned))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, g.key, crypto.SHA256, sum[:])
+	if err != nil {
+		return "", fmt.Errorf("sign token assertion: %w", err)
+	}
+	return unsigned + "." + enc.EncodeToString(sig), nil
+}
+
+// parsePrivateKey decodes the PEM private key of a service account.
+func parsePrivateKey(pemKey string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(pemKey))
+	if block == nil {
+		return nil, fmt.Errorf("credentials contain no PEM private key")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key is not an RSA key")
+	}
+	return key, nil
+}
+
+// quoteSheet quotes a worksheet title for use in A1 notation.
+func quoteSheet(title string) string {
+	return "'" + strings.ReplaceAll(title, "'", "''") + "'"
+}
+
+// sheetRange returns the path-escaped A1 range covering a whole worksheet.
+func sheetRange(title string) string {
+	return url.PathEscape(quoteSheet(title))
+}
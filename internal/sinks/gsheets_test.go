@@ -0,0 +1,136 @@
+// internal/sinks/gsheets_test.go
+package sinks
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/report"
+	"github.com/rs/zerolog"
+)
+
+func writeServiceAccount(t *testing.T, tokenURI string) string {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+	b, _ := json.Marshal(map[string]string{
+		"client_email": "iqfetch@example.iam.gserviceaccount.com",
+		"private_key":  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"token_uri":    tokenURI,
+	})
+	file := filepath.Join(t.TempDir(), "sa.json")
+	if err := os.WriteFile(file, b, 0o600); err != nil {
+		t.Fatalf("write credentials: %v", err)
+	}
+	return file
+}
+
+func TestGoogleSheets_Publish(t *testing.T) {
+	var mu sync.Mutex
+	var calls []string
+	var values [][]string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/token" {
+			_ = r.ParseForm()
+			if strings.Count(r.Form.Get("assertion"), ".") != 2 {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.Write([]byte(`{"access_token": "at"}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer at" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		calls = append(calls, r.Method+" "+r.URL.EscapedPath())
+		switch {
+		case r.Method == http.MethodGet:
+			w.Write([]byte(`{"sheets": [{"properties": {"title": "Sheet1"}}]}`))
+		case r.Method == http.MethodPut:
+			var body struct {
+				Values [][]string `json:"values"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			values = body.Values
+			w.Write([]byte(`{}`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	rows := []report.Row{{Application: "app-a", Component: "lib", Threat: 9}}
+
+	// overwrite: the missing worksheet is added, cleared and written
+	sink, err := NewGoogleSheets(GoogleSheetsConfig{
+		CredentialsFile: writeServiceAccount(t, server.URL+"/token"),
+		SpreadsheetID:   "sheet-id",
+		Worksheet:       "IQ Report",
+		APIURL:          server.URL + "/v4/spreadsheets/",
+	}, zerolog.New(io.Discard))
+	if err != nil {
+		t.Fatalf("NewGoogleSheets: %v", err)
+	}
+	if err := sink.Publish(rCtx(t), rows); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	want := []string{
+		"GET /v4/spreadsheets/sheet-id",
+		"POST /v4/spreadsheets/sheet-id:batchUpdate",
+		"POST /v4/spreadsheets/sheet-id/values/%27IQ%20Report%27:clear",
+		"PUT /v4/spreadsheets/sheet-id/values/%27IQ%20Report%27",
+	}
+	if strings.Join(calls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("calls:\n%s\nwant:\n%s", strings.Join(calls, "\n"), strings.Join(want, "\n"))
+	}
+	if len(values) != 2 || values[0][1] != "Application" || values[1][1] != "app-a" {
+		t.Errorf("unexpected values: %v", values)
+	}
+
+	// new: a worksheet named after the run is added and written
+	calls = nil
+	sink.cfg.Mode = SheetModeNew
+	sink.now = func() time.Time { return time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC) }
+	if err := sink.Publish(rCtx(t), rows); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if len(calls) != 2 || calls[1] != "PUT /v4/spreadsheets/sheet-id/values/%272025-03-01%2008:00:00%27" {
+		t.Errorf("unexpected calls: %v", calls)
+	}
+}
+
+func TestNewGoogleSheets_Validation(t *testing.T) {
+	creds := writeServiceAccount(t, "https://oauth2.example/token")
+	if _, err := NewGoogleSheets(GoogleSheetsConfig{CredentialsFile: creds}, zerolog.New(io.Discard)); err == nil {
+		t.Error("expected error for missing spreadsheet ID")
+	}
+	if _, err := NewGoogleSheets(GoogleSheetsConfig{CredentialsFile: creds, SpreadsheetID: "x", Mode: "append"}, zerolog.New(io.Discard)); err == nil {
+		t.Error("expected error for unsupported mode")
+	}
+	if _, err := NewGoogleSheets(GoogleSheetsConfig{CredentialsFile: "missing.json", SpreadsheetID: "x"}, zerolog.New(io.Discard)); err == nil {
+		t.Error("expected error for missing credentials file")
+	}
+}
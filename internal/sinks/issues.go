@@ -0,0 +1,236 @@
+// internal/sinks/issues.go
+package sinks
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/report"
+	"github.com/go-resty/resty/v2"
+	"github.com/rs/zerolog"
+)
+
+// Issue tracker providers supported by IssueSync.
+const (
+	ProviderGitHub = "github"
+	ProviderGitLab = "gitlab"
+)
+
+// IssueSyncConfig configures the issue tracker sink.
+type IssueSyncConfig struct {
+	Provider  string // "github" or "gitlab"
+	BaseURL   string // API base URL; defaults to the public github.com/gitlab.com API
+	Token     string
+	Label     string            // label identifying issues managed by the sink
+	MinThreat int               // only violations at or above this threat level are listed
+	Repos     map[string]string // application public ID -> "owner/repo" (GitHub) or project path (GitLab)
+}
+
+// IssueSync files one issue per application summarizing its critical
+// violations in the application's repository, and updates that issue on
+// subsequent runs. Applications without a repository mapping are skipped.
+type IssueSync struct {
+	cfg    IssueSyncConfig
+	http   *resty.Client
+	logger zerolog.Logger
+}
+
+// NewIssueSync creates an issue tracker sink.
+func NewIssueSync(cfg IssueSyncConfig, logger zerolog.Logger) (*IssueSync, error) {
+	if cfg.Token == "" {
+		return nil, fmt.Errorf("issues: token is required")
+	}
+	if cfg.Label == "" {
+		cfg.Label = "iqfetch"
+	}
+	r := resty.New().
+		SetHeader("Accept", "application/json").
+		SetTimeout(30 * time.Second)
+
+	switch cfg.Provider {
+	case ProviderGitHub:
+		if cfg.BaseURL == "" {
+			cfg.BaseURL = "https://api.github.com"
+		}
+		r.SetAuthToken(cfg.Token).SetHeader("Accept", "application/vnd.github+json")
+	case ProviderGitLab:
+		if cfg.BaseURL == "" {
+			cfg.BaseURL = "https://gitlab.com/api/v4"
+		}
+		r.SetHeader("PRIVATE-TOKEN", cfg.Token)
+	default:
+		return nil, fmt.Errorf("issues: unsupported provider %q", cfg.Provider)
+	}
+	r.SetBaseURL(strings.TrimRight(cfg.BaseURL, "/"))
+
+	return &IssueSync{cfg: cfg, http: r, logger: logger}, nil
+}
+
+// Name identifies the sink in logs and errors.
+func (s *IssueSync) Name() string { return "issues-" + s.cfg.Provider }
+
+// Publish creates or updates the issue of every mapped application with
+// critical violations.
+func (s *IssueSync) Publish(ctx context.Context, rows []report.Row) error {
+	byApp := make(map[string][]report.Row)
+	for _, r := range rows {
+		if r.Threat < s.cfg.MinThreat {
+			continue
+		}
+		if _, ok := s.cfg.Repos[r.Application]; !ok {
+			continue
+		}
+		byApp[r.Application] = append(byApp[r.Application], r)
+	}
+
+	apps := make([]string, 0, len(byApp))
+	for app := range byApp {
+		apps = append(apps, app)
+	}
+	sort.Strings(apps)
+
+	for _, app := range apps {
+		repo := s.cfg.Repos[app]
+		title := issueTitle(app)
+		body := issueBody(app, byApp[app])
+		var err error
+		if s.cfg.Provider == ProviderGitHub {
+			err = s.upsertGitHub(ctx, repo, title, body)
+		} else {
+			err = s.upsertGitLab(ctx, repo, title, body)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: app %s: %w", s.Name(), app, err)
+		}
+	}
+	return nil
+}
+
+type githubIssue struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+}
+
+func (s *IssueSync) upsertGitHub(ctx context.Context, repo, title, body string) error {
+	var open []githubIssue
+	resp, err := s.http.R().
+		SetContext(ctx).
+		SetQueryParams(map[string]string{"state": "open", "labels": s.cfg.Label, "per_page": "100"}).
+		SetResult(&open).
+		Get("/repos/" + repo + "/issues")
+	if err != nil {
+		return err
+	}
+	if resp.IsError() {
+		return fmt.Errorf("list issues in %s: HTTP %d: %s", repo, resp.StatusCode(), resp.Status())
+	}
+
+	for _, issue := range open {
+		if issue.Title == title {
+			resp, err = s.http.R().
+				SetContext(ctx).
+				SetBody(map[string]any{"body": body}).
+				Patch(fmt.Sprintf("/repos/%s/issues/%d", repo, issue.Number))
+			if err != nil {
+				return err
+			}
+			if resp.IsError() {
+				return fmt.Errorf("update issue %s#%d: HTTP %d: %s", repo, issue.Number, resp.StatusCode(), resp.Status())
+			}
+			s.logger.Debug().Str("repo", repo).Int("issue", issue.Number).Msg("Updated issue")
+			return nil
+		}
+	}
+
+	resp, err = s.http.R().
+		SetContext(ctx).
+		SetBody(map[string]any{"title": title, "body": body, "labels": []string{s.cfg.Label}}).
+		Post("/repos/" + repo + "/issues")
+	if err != nil {
+		return err
+	}
+	if resp.IsError() {
+		return fmt.Errorf("create issue in %s: HTTP %d: %s", repo, resp.StatusCode(), resp.Status())
+	}
+	s.logger.Debug().Str("repo", repo).Msg("Created issue")
+	return nil
+}
+
+type gitlabIssue struct {
+	IID   int    `json:"iid"`
+	Title string `json:"title"`
+}
+
+func (s *IssueSync) upsertGitLab(ctx context.Context, project, title, body string) error {
+	projectPath := "/projects/" + url.PathEscape(project) + "/issues"
+
+	var open []gitlabIssue
+	resp, err := s.http.R().
+		SetContext(ctx).
+		SetQueryParams(map[string]string{"state": "opened", "labels": s.cfg.Label, "per_page": "100"}).
+		SetResult(&open).
+		Get(projectPath)
+	if err != nil {
+		return err
+	}
+	if resp.IsError() {
+		return fmt.Errorf("list issues in %s: HTTP %d: %s", project, resp.StatusCode(), resp.Status())
+	}
+
+	for _, issue := range open {
+		if issue.Title == title {
+			resp, err = s.http.R().
+				SetContext(ctx).
+				SetBody(map[string]any{"description": body}).
+				Put(fmt.Sprintf("%s/%d", projectPath, issue.IID))
+			if err != nil {
+				return err
+			}
+			if resp.IsError() {
+				return fmt.Errorf("update issue %s#%d: HTTP %d: %s", project, issue.IID, resp.StatusCode(), resp.Status())
+			}
+			s.logger.Debug().Str("project", project).Int("issue", issue.IID).Msg("Updated issue")
+			return nil
+		}
+	}
+
+	resp, err = s.http.R().
+		SetContext(ctx).
+		SetBody(map[string]any{"title": title, "description": body, "labels": s.cfg.Label}).
+		Post(projectPath)
+	if err != nil {
+		return err
+	}
+	if resp.IsError() {
+		return fmt.Errorf("create issue in %s: HTTP %d: %s", project, resp.StatusCode(), resp.Status())
+	}
+	s.logger.Debug().Str("project", project).Msg("Created issue")
+	return nil
+}
+
+// issueTitle is the stable title used to find an application's issue again.
+func issueTitle(app string) string {
+	return "IQ Server policy violations: " + app
+}
+
+// issueBody renders the critical violations of an application as Markdown.
+func issueBody(app string, rows []report.Row) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Critical policy violations of **%s** found by the latest IQ Server report.\n\n", app)
+	b.WriteString("| Component | Policy | Threat | Constraint | CVE |\n")
+	b.WriteString("| --- | --- | --- | --- | --- |\n")
+	for _, r := range rows {
+		fmt.Fprintf(&b, "| %s | %s | %d | %s | %s |\n", mdCell(r.Component), mdCell(r.Policy), r.Threat, mdCell(r.ConstraintName), mdCell(r.CVE))
+	}
+	fmt.Fprintf(&b, "\n_Updated %s by iqfetch._\n", time.Now().UTC().Format(time.DateOnly))
+	return b.String()
+}
+
+// mdCell escapes a value for use in a Markdown table cell.
+func mdCell(v string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(v)
+}
@@ -0,0 +1,88 @@
+// internal/sinks/issues_test.go
+package sinks
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/report"
+	"github.com/rs/zerolog"
+)
+
+func TestIssueSync_GitHubCreatesAndUpdates(t *testing.T) {
+	var mu sync.Mutex
+	var created []string
+	var updated []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Header.Get("Authorization") != "Bearer tok" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/acme/app-a/issues":
+			w.Write([]byte(`[]`))
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/acme/app-b/issues":
+			w.Write([]byte(`[{"number": 7, "title": "IQ Server policy violations: app-b"}]`))
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/acme/app-a/issues":
+			var body map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			created = append(created, body["title"].(string))
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{}`))
+		case r.Method == http.MethodPatch && r.URL.Path == "/repos/acme/app-b/issues/7":
+			var body map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			updated = append(updated, body["body"].(string))
+			w.Write([]byte(`{}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	sink, err := NewIssueSync(IssueSyncConfig{
+		Provider:  ProviderGitHub,
+		BaseURL:   server.URL,
+		Token:     "tok",
+		MinThreat: 8,
+		Repos:     map[string]string{"app-a": "acme/app-a", "app-b": "acme/app-b"},
+	}, zerolog.New(io.Discard))
+	if err != nil {
+		t.Fatalf("NewIssueSync: %v", err)
+	}
+
+	rows := []report.Row{
+		{Application: "app-a", Component: "lib|x", Policy: "Security-Critical", Threat: 9},
+		{Application: "app-b", Component: "lib", Policy: "Security-Critical", Threat: 10, CVE: "CVE-2024-0001"},
+		{Application: "app-b", Component: "minor", Policy: "Security-Low", Threat: 2},
+		{Application: "unmapped", Component: "lib", Policy: "Security-Critical", Threat: 10},
+	}
+	if err := sink.Publish(rCtx(t), rows); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	if len(created) != 1 || created[0] != "IQ Server policy violations: app-a" {
+		t.Errorf("created = %v", created)
+	}
+	if len(updated) != 1 || !strings.Contains(updated[0], "CVE-2024-0001") || strings.Contains(updated[0], "minor") {
+		t.Errorf("updated = %v", updated)
+	}
+}
+
+func TestNewIssueSync_Validation(t *testing.T) {
+	if _, err := NewIssueSync(IssueSyncConfig{Provider: "bitbucket", Token: "t"}, zerolog.New(io.Discard)); err == nil {
+		t.Error("expected error for unsupported provider")
+	}
+	if _, err := NewIssueSync(IssueSyncConfig{Provider: ProviderGitLab}, zerolog.New(io.Discard)); err == nil {
+		t.Error("expected error for missing token")
+	}
+}
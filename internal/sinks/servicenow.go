@@ -0,0 +1,197 @@
+// internal/sinks/servicenow.go
+package sinks
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/report"
+	"github.com/go-resty/resty/v2"
+	"github.com/rs/zerolog"
+)
+
+// ServiceNowConfig configures the ServiceNow sink.
+type ServiceNowConfig struct {
+	InstanceURL string // e.g. https://example.service-now.com
+	Username    string
+	Password    string
+	Table       string            // target table, e.g. "incident" or "sn_vul_vulnerable_item"
+	MinThreat   int               // only violations at or above this threat level are ticketed
+	CIMapping   map[string]string // application public ID -> configuration item (cmdb_ci)
+}
+
+// ServiceNow opens or updates one record per application, component and
+// policy for critical violations via the ServiceNow Table API. Records are
+// matched across runs by their correlation_id.
+type ServiceNow struct {
+	cfg    ServiceNowConfig
+	http   *resty.Client
+	logger zerolog.Logger
+}
+
+// NewServiceNow creates a ServiceNow sink.
+func NewServiceNow(cfg ServiceNowConfig, logger zerolog.Logger) (*ServiceNow, error) {
+	if strings.TrimSpace(cfg.InstanceURL) == "" {
+		return nil, fmt.Errorf("servicenow: instance URL is required")
+	}
+	if cfg.Username == "" || cfg.Password == "" {
+		return nil, fmt.Errorf("servicenow: username and password are required")
+	}
+	if cfg.Table == "" {
+		cfg.Table = "incident"
+	}
+
+	r := resty.New().
+		SetBaseURL(strings.TrimRight(cfg.InstanceURL, "/")+"/api/now/table/").
+		SetBasicAuth(cfg.Username, cfg.Password).
+		SetHeader("Accept", "application/json").
+		SetTimeout(30 * time.Second)
+
+	return &ServiceNow{cfg: cfg, http: r, logger: logger}, nil
+}
+
+// Name identifies the sink in logs and errors.
+func (s *ServiceNow) Name() string { return "servicenow" }
+
+// snowRecord is the subset of Table API record fields used by the sink.
+type snowRecord struct {
+	SysID            string `json:"sys_id,omitempty"`
+	ShortDescription string `json:"short_description,omitempty"`
+	Description      string `json:"description,omitempty"`
+	CorrelationID    string `json:"correlation_id,omitempty"`
+	CMDBCI           string `json:"cmdb_ci,omitempty"`
+	Impact           string `json:"impact,omitempty"`
+	Urgency          string `json:"urgency,omitempty"`
+	WorkNotes        string `json:"work_notes,omitempty"`
+}
+
+type snowResult struct {
+	Result []snowRecord `json:"result"`
+}
+
+// finding groups the rows of one application, component and policy.
+type finding struct {
+	key  string
+	rows []report.Row
+}
+
+// Publish creates or updates a record for every critical finding in rows.
+func (s *ServiceNow) Publish(ctx context.Context, rows []report.Row) error {
+	findings := groupFindings(rows, s.cfg.MinThreat)
+	s.logger.Info().Int("findings", len(findings)).Str("table", s.cfg.Table).Msg("Publishing findings to ServiceNow")
+
+	for _, f := range findings {
+		if err := s.upsert(ctx, f); err != nil {
+			return fmt.Errorf("servicenow: %w", err)
+		}
+	}
+	return nil
+}
+
+// upsert updates the open record with the finding's correlation ID or
+// creates a new one.
+func (s *ServiceNow) upsert(ctx context.Context, f finding) error {
+	first := f.rows[0]
+	correlationID := "iqfetch-" + f.key
+
+	var existing snowResult
+	resp, err := s.http.R().
+		SetContext(ctx).
+		SetQueryParams(map[string]string{
+			"sysparm_query":  "correlation_id=" + correlationID + "^active=true",
+			"sysparm_fields": "sys_id",
+			"sysparm_limit":  "1",
+		}).
+		SetResult(&existing).
+		Get(s.cfg.Table)
+	if err != nil {
+		return err
+	}
+	if resp.IsError() {
+		return fmt.Errorf("query %s: HTTP %d: %s", s.cfg.Table, resp.StatusCode(), resp.Status())
+	}
+
+	description := describeFinding(f.rows)
+	if len(existing.Result) > 0 {
+		sysID := existing.Result[0].SysID
+		resp, err = s.http.R().
+			SetContext(ctx).
+			SetBody(snowRecord{Description: description, WorkNotes: "Updated by iqfetch run"}).
+			Patch(s.cfg.Table + "/" + sysID)
+		if err != nil {
+			return err
+		}
+		if resp.IsError() {
+			return fmt.Errorf("update %s/%s: HTTP %d: %s", s.cfg.Table, sysID, resp.StatusCode(), resp.Status())
+		}
+		s.logger.Debug().Str("sysId", sysID).Str("correlationId", correlationID).Msg("Updated ServiceNow record")
+		return nil
+	}
+
+	record := snowRecord{
+		ShortDescription: fmt.Sprintf("%s: %s violates %s (threat %d)", first.Application, first.Component, first.Policy, first.Threat),
+		Description:      description,
+		CorrelationID:    correlationID,
+		CMDBCI:           s.cfg.CIMapping[first.Application],
+		Impact:           "1",
+		Urgency:          "1",
+	}
+	resp, err = s.http.R().
+		SetContext(ctx).
+		SetBody(record).
+		Post(s.cfg.Table)
+	if err != nil {
+		return err
+	}
+	if resp.IsError() {
+		return fmt.Errorf("create %s: HTTP %d: %s", s.cfg.Table, resp.StatusCode(), resp.Status())
+	}
+	s.logger.Debug().Str("correlationId", correlationID).Msg("Created ServiceNow record")
+	return nil
+}
+
+// groupFindings groups rows at or above minThreat by application, component
+// and policy, in a stable order.
+func groupFindings(rows []report.Row, minThreat int) []finding {
+	byKey := make(map[string]*finding)
+	for _, r := range rows {
+		if r.Threat < minThreat {
+			continue
+		}
+		sum := sha1.Sum([]byte(r.Application + "\x00" + r.Component + "\x00" + r.Policy))
+		key := hex.EncodeToString(sum[:])
+		f, ok := byKey[key]
+		if !ok {
+			f = &finding{key: key}
+			byKey[key] = f
+		}
+		f.rows = append(f.rows, r)
+	}
+
+	out := make([]finding, 0, len(byKey))
+	for _, f := range byKey {
+		out = append(out, *f)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].key < out[j].key })
+	return out
+}
+
+// describeFinding renders the violated constraints of a finding as text.
+func describeFinding(rows []report.Row) string {
+	first := rows[0]
+	var b strings.Builder
+	fmt.Fprintf(&b, "Application: %s\nOrganization: %s\nComponent: %s\nPolicy: %s (threat %d)\n\n", first.Application, first.Organization, first.Component, first.Policy, first.Threat)
+	for _, r := range rows {
+		fmt.Fprintf(&b, "- %s: %s", r.ConstraintName, r.Condition)
+		if r.CVE != "" {
+			fmt.Fprintf(&b, " [%s]", r.CVE)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
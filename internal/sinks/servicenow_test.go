@@ -0,0 +1,89 @@
+// internal/sinks/servicenow_test.go
+package sinks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/report"
+	"github.com/rs/zerolog"
+)
+
+func TestServiceNow_CreatesAndUpdatesRecords(t *testing.T) {
+	var mu sync.Mutex
+	var created []snowRecord
+	var patched []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/now/table/incident":
+			// Only the finding for app-b already has an open incident
+			if strings.Contains(r.URL.Query().Get("sysparm_query"), existingKey) {
+				w.Write([]byte(`{"result": [{"sys_id": "sys-1"}]}`))
+				return
+			}
+			w.Write([]byte(`{"result": []}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/api/now/table/incident":
+			var rec snowRecord
+			_ = json.NewDecoder(r.Body).Decode(&rec)
+			created = append(created, rec)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"result": {}}`))
+		case r.Method == http.MethodPatch && r.URL.Path == "/api/now/table/incident/sys-1":
+			patched = append(patched, "sys-1")
+			w.Write([]byte(`{"result": {}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	rows := []report.Row{
+		{Application: "app-a", Component: "comp", Policy: "Security-Critical", Threat: 9, ConstraintName: "c1"},
+		{Application: "app-a", Component: "comp", Policy: "Security-Critical", Threat: 9, ConstraintName: "c2"},
+		{Application: "app-b", Component: "lib", Policy: "Security-Critical", Threat: 10},
+		{Application: "app-c", Component: "lib", Policy: "Security-Medium", Threat: 5},
+	}
+
+	sink, err := NewServiceNow(ServiceNowConfig{
+		InstanceURL: server.URL,
+		Username:    "u",
+		Password:    "p",
+		MinThreat:   8,
+		CIMapping:   map[string]string{"app-a": "ci-app-a"},
+	}, zerolog.New(io.Discard))
+	if err != nil {
+		t.Fatalf("NewServiceNow: %v", err)
+	}
+	if err := sink.Publish(rCtx(t), rows); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	if len(created) != 1 || created[0].CMDBCI != "ci-app-a" || !strings.Contains(created[0].Description, "c2") {
+		t.Errorf("unexpected created records: %#v", created)
+	}
+	if len(patched) != 1 {
+		t.Errorf("expected 1 update, got %v", patched)
+	}
+}
+
+// existingKey is the finding key of app-b's critical violation.
+var existingKey = groupFindings([]report.Row{{Application: "app-b", Component: "lib", Policy: "Security-Critical", Threat: 10}}, 0)[0].key
+
+// rCtx returns a cancellable context with a small timeout and ensures cancel via t.Cleanup.
+func rCtx(t *testing.T) context.Context {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	t.Cleanup(cancel)
+	return ctx
+}
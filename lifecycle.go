@@ -0,0 +1,54 @@
+// lifecycle.go
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/report"
+	"github.com/rs/zerolog"
+)
+
+// runLifecycle implements "lifecycle [--dir <dir>] [-o <lifecycle.csv>]": it
+// follows violations across the runs stored in the output directory and
+// writes the monthly lifecycle report with its summary sheet. It works on
+// local files only and does not contact IQ Server.
+func runLifecycle(args []string) int {
+	fs := flag.NewFlagSet("lifecycle", flag.ContinueOnError)
+	dir := fs.String("dir", "reports_output", "directory holding the reports and manifests of past runs")
+	out := fs.String("o", "", "path of the lifecycle report (default <dir>/lifecycle.csv)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *out == "" {
+		*out = filepath.Join(*dir, "lifecycle.csv")
+	}
+
+	logger := zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr}).With().Timestamp().Logger()
+	snaps, err := report.ReadSnapshots(*dir, logger)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "FATAL: %v\n", err) //nolint:errcheck
+		return 1
+	}
+	if len(snaps) < 2 {
+		fmt.Fprintf(os.Stderr, "FATAL: found %d runs with manifests in %s, need at least 2\n", len(snaps), *dir) //nolint:errcheck
+		return 1
+	}
+
+	rows := report.Lifecycle(snaps)
+	if err := report.WriteLifecycleCSV(context.Background(), *out, rows, logger); err != nil {
+		fmt.Fprintf(os.Stderr, "FATAL: %v\n", err) //nolint:errcheck
+		return 1
+	}
+	summary := report.SummarizeLifecycle(rows)
+	if err := report.WriteLifecycleSummaryCSV(context.Background(), report.LifecycleSummaryPath(*out), summary, logger); err != nil {
+		fmt.Fprintf(os.Stderr, "FATAL: %v\n", err) //nolint:errcheck
+		return 1
+	}
+	fmt.Printf("Followed violations across %d runs from %s to %s into %s (%d periods)\n", //nolint:errcheck
+		len(snaps), snaps[0].Time.Format("2006-01-02"), snaps[len(snaps)-1].Time.Format("2006-01-02"), filepath.Clean(*out), len(summary))
+	return 0
+}
@@ -3,47 +3,380 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"flag"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/anmicius0/iqserver-report-fetch-go/internal/client"
 	"github.com/anmicius0/iqserver-report-fetch-go/internal/config"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/report"
 	"github.com/anmicius0/iqserver-report-fetch-go/internal/services"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/sinks"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
 
 func main() {
-	// main is the entrypoint for the CLI. It wires together configuration,
-	// logging, and the internal services to execute the report fetching
-	// workflow. Keep main small: create dependencies, handle errors and
-	// call the higher-level service function that performs the work.
+	// main is the entrypoint for the CLI. It dispatches to a subcommand; the
+	// default command ("run") generates the policy violation report. Keep
+	// main small: subcommands create dependencies, handle errors and call the
+	// higher-level service functions that perform the work.
+	args := os.Args[1:]
+	cmd := "run"
+	if len(args) > 0 && args[0] != "" && args[0][0] != '-' {
+		cmd, args = args[0], args[1:]
+	}
+
+	switch cmd {
+	case "run":
+		os.Exit(runReport(args))
+	case "list":
+		os.Exit(runList(args))
+	case "history":
+		os.Exit(runHistory(args))
+	case "enrich":
+		os.Exit(runEnrich(args))
+	case "waive":
+		os.Exit(runWaive(args))
+	case "merge":
+		os.Exit(runMerge(args))
+	case "lifecycle":
+		os.Exit(runLifecycle(args))
+	case "config":
+		os.Exit(runConfig(args))
+	case "completion":
+		os.Exit(runCompletion(args))
+	case "version":
+		os.Exit(runVersion(args))
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q (expected run, list, history, enrich, waive, merge, lifecycle, config, completion or version)\n", cmd) //nolint:errcheck
+		os.Exit(2)
+	}
+}
+
+// runReport generates the latest policy violation report and returns the
+// process exit code.
+func runReport(args []string) int {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	profileDir := fs.String("profile", "", "write CPU and heap profiles (cpu.pprof, heap.pprof) to this directory")
+	quiet := fs.Bool("quiet", false, "only print the report path, or an error summary on failure; logs still go to app.log")
+	appID := fs.String("app", "", "export a specific report of this application (public ID); requires --report-id")
+	reportID := fs.String("report-id", "", "ID of the report to export with --app, e.g. a historical scan")
+	oneshot := fs.Bool("oneshot", false, "write a result file and exit with a distinct code per failure category, e.g. for Kubernetes CronJobs")
+	resultFile := fs.String("result-file", "", "result file written with --oneshot (default <REPORT_OUTPUT_DIR>/result.json)")
+	runID := fs.String("run-id", "", "ID of this run, sent to IQ Server and recorded in logs and the manifest (default generated)")
+	triggeredBy := fs.String("triggered-by", os.Getenv("USER"), "user or system that triggered the run")
+	reason := fs.String("reason", "", "why the run was triggered, e.g. a ticket reference")
+	asOfFlag := fs.String("as-of", "", "export the reports that were the latest at this date (YYYY-MM-DD, inclusive), time (RFC 3339) or duration ago (e.g. 90d, 36h), e.g. to reproduce a past audit")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if (*appID == "") != (*reportID == "") {
+		fmt.Fprintln(os.Stderr, "--app and --report-id must be given together") //nolint:errcheck
+		return 2
+	}
+	var asOf time.Time
+	if *asOfFlag != "" {
+		if *appID != "" {
+			fmt.Fprintln(os.Stderr, "--as-of cannot be combined with --app and --report-id") //nolint:errcheck
+			return 2
+		}
+		var err error
+		if asOf, err = parseAsOf(*asOfFlag, time.Now()); err != nil {
+			fmt.Fprintf(os.Stderr, "--as-of: %v\n", err) //nolint:errcheck
+			return 2
+		}
+	}
+
+	var consoleOut io.Writer = os.Stdout
+	if *quiet {
+		consoleOut = nil
+	}
+	cfg, pool, cleanup, err := setup(consoleOut)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "FATAL: %v\n", err) //nolint:errcheck
+		return 1
+	}
+	defer cleanup()
+
+	if *profileDir != "" {
+		stopProfiling, err := startProfiling(*profileDir)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to start profiling")
+			return 1
+		}
+		defer func() {
+			if err := stopProfiling(); err != nil {
+				log.Error().Err(err).Msg("failed to write profiles")
+				return
+			}
+			log.Info().Str("dir", *profileDir).Msg("Profiles written")
+		}()
+	}
+
+	// Service
+	reportService := services.NewIQReportServiceWithPool(cfg, pool, log.Logger)
+	log.Info().Str("outputDir", cfg.OutputDir).Msg("Report service initialized")
+	if !asOf.IsZero() {
+		reportService.SetAsOf(asOf)
+		log.Info().Time("asOf", asOf).Msg("Exporting the reports that were the latest at the given time")
+	}
+
+	if err := addSinks(reportService, cfg); err != nil {
+		log.Error().Err(err).Msg("failed to configure sinks")
+		return 1
+	}
+
+	// Progress events for CI pipelines; on stdout, the report path is
+	// printed to stderr so that stdout only contains events
+	resultOut := os.Stdout
+	switch cfg.ProgressEvents {
+	case "":
+	case "-":
+		reportService.SetProgressWriter(os.Stdout)
+		resultOut = os.Stderr
+	default:
+		events, err := os.OpenFile(cfg.ProgressEvents, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to open progress events file")
+			return 1
+		}
+		defer events.Close() //nolint:errcheck
+		reportService.SetProgressWriter(events)
+	}
+
+	// Run metadata lets IQ admins trace who triggered the report pull
+	if *runID == "" {
+		*runID = newRunID()
+	}
+	md := client.Metadata{RunID: *runID, TriggeredBy: *triggeredBy, Reason: *reason}
+	// runContext returns the context of an attempt of the run, with the
+	// fetch timeout
+	runContext := func() (context.Context, context.CancelFunc) {
+		ctx, cancel := fetchContext(cfg)
+		return client.WithMetadata(ctx, md), cancel
+	}
+	log.Info().Str("runId", *runID).Str("triggeredBy", *triggeredBy).Str("reason", *reason).Msg("Run metadata set")
+
+	// Output filename
+	filename := time.Now().Format("2006-01-02_15-04-05") + ".csv"
+	if cfg.ShardTotal > 1 {
+		filename = report.ShardPath(filename, report.Shard{Index: cfg.ShardIndex, Total: cfg.ShardTotal})
+	}
+	log.Info().Str("filename", filename).Msg("Report filename set")
+
+	// Ensure output directory exists
+	_ = os.MkdirAll(cfg.OutputDir, 0o755)
+
+	// finish maps the outcome of the run to the exit code; with --oneshot
+	// the outcome is also written to the result file
+	finish := func(path string, err error, stats services.RunStats) int {
+		if !*oneshot {
+			if err != nil {
+				return 1
+			}
+			return 0
+		}
+		code, reason := exitCode(path, err)
+		if stats.Status == "" {
+			stats.Status, stats.ReportPath = "ok", path
+			if err != nil {
+				stats.Status, stats.Error = "failed", err.Error()
+			}
+		}
+		dest := *resultFile
+		if dest == "" {
+			dest = filepath.Join(cfg.OutputDir, "result.json")
+		}
+		if err := writeResult(dest, oneshotResult{ExitCode: code, ExitReason: reason, RunStats: stats}); err != nil {
+			log.Error().Err(err).Msg("failed to write result file")
+		}
+		return code
+	}
+
+	// Stay off IQ Server during its blackout windows, e.g. backups
+	windows, err := services.ParseBlackoutWindows(cfg.BlackoutWindows)
+	if err != nil {
+		log.Error().Err(err).Msg("invalid BLACKOUT_WINDOWS")
+		return 1
+	}
+	if w, end, ok := services.ActiveBlackout(windows, time.Now()); ok {
+		if cfg.BlackoutAction == config.BlackoutSkip {
+			err := fmt.Errorf("%w %s until %s", services.ErrBlackout, w, end.Format(time.DateTime))
+			log.Warn().Err(err).Msg("Run skipped")
+			if *quiet {
+				fmt.Fprintf(os.Stderr, "run skipped: %v\n", err) //nolint:errcheck
+			}
+			return finish("", err, services.RunStats{})
+		}
+		log.Warn().Str("window", w.String()).Time("until", end).Msg("Run started in a blackout window of IQ Server")
+	}
+
+	// Keep overlapping runs (e.g. cron firing while the previous run is still
+	// going) from writing the same output
+	lock, err := report.AcquireLock(context.Background(), cfg.OutputDir,
+		time.Duration(cfg.LockWaitSeconds)*time.Second,
+		time.Duration(cfg.LockStaleMinutes)*time.Minute,
+		log.Logger)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to lock output directory")
+		if *quiet {
+			fmt.Fprintf(os.Stderr, "failed to lock output directory: %v\n", err) //nolint:errcheck
+		}
+		return finish("", err, services.RunStats{})
+	}
+	defer func() {
+		if err := lock.Release(); err != nil {
+			log.Warn().Err(err).Msg("failed to release output directory lock")
+		}
+	}()
+
+	// Remove temporary files left by crashed runs
+	if cfg.TempMaxAgeHours > 0 {
+		maxAge := time.Duration(cfg.TempMaxAgeHours) * time.Hour
+		n, err := report.CleanupTempFiles(cfg.OutputDir, maxAge, log.Logger)
+		if err != nil {
+			log.Warn().Err(err).Msg("Could not remove all orphaned temp files")
+		}
+		if n > 0 {
+			log.Info().Int("removed", n).Msg("Orphaned temp files removed")
+		}
+	}
+
+	// Export a specific report instead of the latest ones
+	if *appID != "" {
+		log.Info().Str("app", *appID).Str("reportId", *reportID).Msg("Exporting report by ID")
+		ctx, cancel := runContext()
+		defer cancel()
+		path, err := reportService.GenerateReportByID(ctx, *appID, *reportID, filename)
+		if err != nil {
+			log.Error().Err(err).Msg("report export failed")
+			if *quiet {
+				fmt.Fprintf(os.Stderr, "report export failed: %v\n", err) //nolint:errcheck
+			}
+			return finish("", err, services.RunStats{})
+		}
+		fmt.Fprintf(resultOut, "Wrote report: %s\n", filepath.Clean(path)) //nolint:errcheck
+		return finish(path, nil, services.RunStats{})
+	}
+
+	// Generate report
+	log.Info().Msg("Starting report generation")
+	path, err := generateWithRetry(reportService, runContext, filename, retryPolicy{
+		Interval:  time.Duration(cfg.RunRetryIntervalSeconds) * time.Second,
+		Window:    time.Duration(cfg.RunRetryWindowMinutes) * time.Minute,
+		Blackouts: windows,
+	})
+	logTimings(pool.Timings())
+	stats := reportService.LastRun()
+	logSummary(stats)
+	if err != nil {
+		log.Error().Err(err).Msg("report generation failed")
+		if *quiet {
+			fmt.Fprintf(os.Stderr, "report generation failed: %v\n", err) //nolint:errcheck
+			printErrors(os.Stderr, stats.TopErrors)
+		} else {
+			printSummary(resultOut, stats)
+		}
+		return finish(path, err, stats)
+	}
+
+	log.Info().Str("path", filepath.Clean(path)).Msg("Report generation completed")
+	if !*quiet {
+		printSummary(resultOut, stats)
+	}
+	fmt.Fprintf(resultOut, "Wrote report: %s\n", filepath.Clean(path)) //nolint:errcheck
+	return finish(path, nil, stats)
+}
+
+// newRunID returns a run ID made of the UTC start time and a random suffix,
+// e.g. "20250131T120000Z-1a2b3c4d".
+func newRunID() string {
+	b := make([]byte, 4)
+	_, _ = rand.Read(b)
+	return time.Now().UTC().Format("20060102T150405Z") + "-" + hex.EncodeToString(b)
+}
+
+// parseAsOf parses the --as-of flag at now: a date covers the whole day in
+// local time, a RFC 3339 time is taken as is and a duration, in Go syntax or
+// whole days such as 90d, goes back that long from now. Dates and times after
+// now are rejected, as no report was evaluated then.
+func parseAsOf(v string, now time.Time) (time.Time, error) {
+	if t, err := time.ParseInLocation(time.DateOnly, v, now.Location()); err == nil {
+		if t.After(now) {
+			return time.Time{}, fmt.Errorf("date %s is in the future", v)
+		}
+		return t.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		if t.After(now) {
+			return time.Time{}, fmt.Errorf("time %s is in the future", v)
+		}
+		return t, nil
+	}
+	if days, ok := strings.CutSuffix(v, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n > 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	} else if d, err := time.ParseDuration(v); err == nil && d > 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q, expected YYYY-MM-DD, RFC 3339 or a positive duration such as 90d", v)
+}
+
+// fetchContext returns the context of requests to IQ Server, cancelled after
+// FETCH_TIMEOUT_SECONDS; zero does not bound it.
+func fetchContext(cfg *config.Config) (context.Context, context.CancelFunc) {
+	if cfg.FetchTimeoutSeconds == 0 {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithTimeout(context.Background(), time.Duration(cfg.FetchTimeoutSeconds)*time.Second)
+}
+
+// setup loads the configuration, configures the global logger (console
+// output to consoleOut, or stderr when progress events go to stdout, none
+// when consoleOut is nil; JSON to app.log) and builds the client pool. The
+// returned function closes the clients and the log file.
+func setup(consoleOut io.Writer) (*config.Config, *client.Pool, func(), error) {
 	// Load config from config/.env and environment
 	cfg, err := config.Load()
 	if err != nil {
-		// Log fatal failure to standard error stream, as slog setup hasn't completed yet
-		fmt.Fprintf(os.Stderr, "FATAL: failed to load config: %v\n", err) //nolint:errcheck
-		os.Exit(1)
+		return nil, nil, nil, fmt.Errorf("failed to load config: %w", err)
 	}
 
 	// Open project-root/app.log for append; create if missing
 	logFile, err := os.OpenFile("app.log", os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
 	if err != nil {
-		// Log fatal failure to standard error stream, as logger setup hasn't completed yet
-		fmt.Fprintf(os.Stderr, "FATAL: failed to open app.log: %v\n", err) //nolint:errcheck
-		os.Exit(1)
+		return nil, nil, nil, fmt.Errorf("failed to open app.log: %w", err)
 	}
-	defer logFile.Close()
+	closeLog := func() { _ = logFile.Close() }
 
-	// Logger setup (console writer for stdout, json for file)
-	consoleWriter := zerolog.ConsoleWriter{Out: os.Stdout, TimeFormat: time.RFC3339}
-	multiWriter := zerolog.MultiLevelWriter(consoleWriter, logFile)
+	// Logger setup (console output in LOG_FORMAT, json for file). Progress
+	// events streamed to stdout must not be mixed with log lines.
+	if consoleOut != nil && cfg.ProgressEvents == "-" {
+		consoleOut = os.Stderr
+	}
+	level, err := zerolog.ParseLevel(cfg.LogLevel)
+	if err != nil {
+		closeLog()
+		return nil, nil, nil, fmt.Errorf("invalid LOG_LEVEL: %w", err)
+	}
+	writers := []io.Writer{logFile}
+	if consoleOut != nil {
+		writers = append(writers, consoleWriter(consoleOut, cfg.LogFormat))
+	}
 
 	// Configure global logger
-	log.Logger = zerolog.New(multiWriter).With().Timestamp().Logger()
-	zerolog.SetGlobalLevel(zerolog.DebugLevel)
+	log.Logger = zerolog.New(zerolog.MultiLevelWriter(writers...)).With().Timestamp().Logger()
+	zerolog.SetGlobalLevel(level)
 
 	log.Info().
 		Str("IQServerURL", cfg.IQServerURL).
@@ -51,34 +384,183 @@ func main() {
 
 	// Build client
 	log.Info().Str("url", cfg.IQServerURL).Msg("Creating IQ client")
-	iqClient, err := client.NewClient(cfg.IQServerURL, cfg.IQUsername, cfg.IQPassword, log.Logger)
+	bodyLevel := zerolog.Disabled
+	if cfg.LogBodyLevel != config.LogBodyOff {
+		bodyLevel, _ = zerolog.ParseLevel(cfg.LogBodyLevel) // validated by config.Load
+	}
+	clientOpts := []client.Option{
+		client.WithStrictBaseURL(cfg.StrictBaseURL),
+		client.WithBodyLogging(client.BodyLogging{Level: bodyLevel, MaxBytes: cfg.LogBodyMaxBytes}),
+		client.WithReadOnly(cfg.ReadOnly),
+		client.WithSessionAuth(cfg.AuthMode == config.AuthSession),
+		client.WithHostOverrides(cfg.Hosts),
+		client.WithLocalAddress(cfg.LocalAddress),
+		client.WithPathOverrides(cfg.PathOverrides),
+		client.WithRateLimiter(client.NewRateLimiter(cfg.RateLimit)),
+		client.WithRetry(client.RetryPolicy{
+			MaxAttempts: cfg.RetryMaxAttempts,
+			BaseDelay:   time.Duration(cfg.RetryBackoffMS) * time.Millisecond,
+			MaxDelay:    time.Duration(cfg.RetryMaxBackoffSeconds) * time.Second,
+			Jitter:      cfg.RetryJitter,
+		}),
+		client.WithResponseLimit(client.ResponseLimit{MaxBytes: cfg.MaxResponseBytes, StreamPolicyReports: cfg.OversizedReports == config.OversizedStream}),
+	}
+	if cfg.AuthMode == config.AuthHeader {
+		clientOpts = append(clientOpts, client.WithHeaderAuth(client.HeaderAuth{Header: cfg.AuthUserHeader, Extra: cfg.AuthHeaders}))
+	}
+	// The user token replaces the default credentials only, not those of IQ_ORG_CREDENTIALS
+	defaultOpts := clientOpts
+	if cfg.IQUserCode != "" {
+		defaultOpts = append(slices.Clip(clientOpts), client.WithUserToken(client.UserToken{UserCode: cfg.IQUserCode, PassCode: cfg.IQPassCode}))
+	}
+	iqClient, err := client.NewClient(cfg.IQServerURL, cfg.IQUsername, cfg.IQPassword, log.Logger, defaultOpts...)
 	if err != nil {
-		log.Fatal().Err(err).Msg("failed to create client")
+		closeLog()
+		return nil, nil, nil, fmt.Errorf("failed to create client: %w", err)
 	}
 	log.Info().Msg("IQ client created")
 
-	// Service
-	reportService := services.NewIQReportService(cfg, iqClient, log.Logger)
-	log.Info().Str("outputDir", cfg.OutputDir).Msg("Report service initialized")
+	// Per-organization clients for scoped service accounts
+	pool := client.NewPool(iqClient)
+	for orgID, creds := range cfg.OrgCredentials {
+		orgClient, err := client.NewClient(cfg.IQServerURL, creds.Username, creds.Password, log.Logger.With().Str("orgId", orgID).Logger(), clientOpts...)
+		if err != nil {
+			_ = pool.Close()
+			closeLog()
+			return nil, nil, nil, fmt.Errorf("failed to create client for organization %s: %w", orgID, err)
+		}
+		pool.Add(orgID, orgClient)
+	}
+	log.Info().Int("scopedOrgs", len(cfg.OrgCredentials)).Msg("Client pool ready")
+
+	cleanup := func() {
+		_ = pool.Close()
+		closeLog()
+	}
+	return cfg, pool, cleanup, nil
+}
 
-	// Context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+// consoleWriter returns the console log writer for a LOG_FORMAT value.
+func consoleWriter(out io.Writer, format string) io.Writer {
+	switch format {
+	case config.LogFormatJSON:
+		return out
+	case config.LogFormatConsole:
+		return zerolog.ConsoleWriter{Out: out, TimeFormat: time.RFC3339, NoColor: true}
+	default:
+		return zerolog.ConsoleWriter{Out: out, TimeFormat: time.RFC3339}
+	}
+}
 
-	// Output filename
-	filename := time.Now().Format("2006-01-02_15-04-05") + ".csv"
-	log.Info().Str("filename", filename).Msg("Report filename set")
+// logTimings logs the slowest endpoints of the run with their network/server
+// time breakdown.
+func logTimings(timings []client.EndpointTiming) {
+	const top = 5
+	for i, t := range timings {
+		if i == top {
+			break
+		}
+		log.Info().
+			Str("endpoint", t.Endpoint).
+			Int("requests", t.Requests).
+			Dur("total", t.Total).
+			Dur("slowest", t.Slowest).
+			Dur("dns", t.DNSLookup).
+			Dur("connect", t.Connect).
+			Dur("tls", t.TLSHandshake).
+			Dur("ttfb", t.ServerTime).
+			Msg("Endpoint timing")
+	}
+}
 
-	// Ensure output directory exists
-	_ = os.MkdirAll(cfg.OutputDir, 0o755)
+// logSummary logs the outcome of the run as a single structured entry.
+func logSummary(stats services.RunStats) {
+	ev := log.Info().
+		Str("status", stats.Status).
+		Int("applications", stats.Applications).
+		Int("processed", stats.Processed).
+		Int("skipped", stats.Skipped).
+		Int("failed", stats.Failed).
+		Int("rows", stats.Rows).
+		Dur("duration", time.Duration(stats.DurationMS)*time.Millisecond).
+		Str("path", stats.ReportPath)
+	for _, e := range stats.TopErrors {
+		ev = ev.Int("errors."+e.Kind, e.Count)
+	}
+	if r := stats.Resources; r != nil {
+		ev = ev.Uint64("peakMemoryBytes", r.PeakMemoryBytes).Int("peakGoroutines", r.PeakGoroutines).
+			Int("httpCalls", r.HTTPCalls).Float64("cpuSeconds", r.CPUSeconds)
+	}
+	ev.Msg("Run summary")
+}
 
-	// Generate report
-	log.Info().Msg("Starting report generation")
-	path, err := reportService.GenerateLatestPolicyReport(ctx, filename)
-	if err != nil {
-		log.Fatal().Err(err).Msg("report generation failed")
+// printSummary prints the outcome of the run as a human-readable block.
+func printSummary(w io.Writer, stats services.RunStats) {
+	duration := time.Duration(stats.DurationMS) * time.Millisecond
+	fmt.Fprintf(w, "Run summary (%s)\n", stats.Status)                                //nolint:errcheck
+	fmt.Fprintf(w, "  Applications: %d total, %d processed, %d skipped, %d failed\n", //nolint:errcheck
+		stats.Applications, stats.Processed, stats.Skipped, stats.Failed)
+	fmt.Fprintf(w, "  Rows written: %d\n", stats.Rows) //nolint:errcheck
+	fmt.Fprintf(w, "  Duration:     %s\n", duration)   //nolint:errcheck
+	if stats.ReportPath != "" {
+		fmt.Fprintf(w, "  Output:       %s\n", filepath.Clean(stats.ReportPath)) //nolint:errcheck
 	}
+	printErrors(w, stats.TopErrors)
+}
 
-	log.Info().Str("path", filepath.Clean(path)).Msg("Report generation completed")
-	fmt.Printf("Wrote report: %s\n", filepath.Clean(path))
+// printErrors prints application failures grouped by kind with an example.
+func printErrors(w io.Writer, errs []services.ErrorSummary) {
+	if len(errs) == 0 {
+		return
+	}
+	fmt.Fprintln(w, "  Top errors:") //nolint:errcheck
+	for _, e := range errs {
+		fmt.Fprintf(w, "    %dx %s: %s\n", e.Count, e.Kind, e.Example) //nolint:errcheck
+	}
+}
+
+// addSinks registers the integrations enabled in cfg with the service.
+func addSinks(svc *services.IQReportService, cfg *config.Config) error {
+	if cfg.SnowInstanceURL != "" {
+		snow, err := sinks.NewServiceNow(sinks.ServiceNowConfig{
+			InstanceURL: cfg.SnowInstanceURL,
+			Username:    cfg.SnowUsername,
+			Password:    cfg.SnowPassword,
+			Table:       cfg.SnowTable,
+			MinThreat:   cfg.SnowMinThreat,
+			CIMapping:   cfg.SnowCIMapping,
+		}, log.Logger.With().Str("sink", "servicenow").Logger())
+		if err != nil {
+			return err
+		}
+		svc.AddSink(snow)
+	}
+	if cfg.IssuesProvider != "" {
+		issues, err := sinks.NewIssueSync(sinks.IssueSyncConfig{
+			Provider:  cfg.IssuesProvider,
+			BaseURL:   cfg.IssuesBaseURL,
+			Token:     cfg.IssuesToken,
+			Label:     cfg.IssuesLabel,
+			MinThreat: cfg.IssuesMinThreat,
+			Repos:     cfg.IssuesRepos,
+		}, log.Logger.With().Str("sink", "issues").Logger())
+		if err != nil {
+			return err
+		}
+		svc.AddSink(issues)
+	}
+	if cfg.GSheetsSpreadsheetID != "" {
+		sheets, err := sinks.NewGoogleSheets(sinks.GoogleSheetsConfig{
+			CredentialsFile: cfg.GSheetsCredentialsFile,
+			SpreadsheetID:   cfg.GSheetsSpreadsheetID,
+			Mode:            cfg.GSheetsMode,
+			Worksheet:       cfg.GSheetsWorksheet,
+			ThreatFormat:    cfg.ThreatFormat,
+		}, log.Logger.With().Str("sink", "gsheets").Logger())
+		if err != nil {
+			return err
+		}
+		svc.AddSink(sheets)
+	}
+	return nil
 }
@@ -0,0 +1,50 @@
+// main_test.go
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseAsOf(t *testing.T) {
+	loc := time.FixedZone("UTC+2", 2*60*60)
+	now := time.Date(2025, 3, 15, 10, 30, 0, 0, loc)
+
+	tests := []struct {
+		name    string
+		in      string
+		want    time.Time
+		wantErr bool
+	}{
+		{"Date", "2024-06-30", time.Date(2024, 6, 30, 23, 59, 59, 999999999, loc), false},
+		{"Today", "2025-03-15", time.Date(2025, 3, 15, 23, 59, 59, 999999999, loc), false},
+		{"RFC3339", "2024-06-30T12:00:00Z", time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC), false},
+		{"RFC3339Offset", "2024-06-30T12:00:00+02:00", time.Date(2024, 6, 30, 12, 0, 0, 0, loc), false},
+		{"Now", "2025-03-15T08:30:00Z", now, false},
+		{"Days", "90d", now.AddDate(0, 0, -90), false},
+		{"Hours", "36h", now.Add(-36 * time.Hour), false},
+		{"HoursMinutes", "1h30m", now.Add(-90 * time.Minute), false},
+		{"FutureDate", "2025-03-16", time.Time{}, true},
+		{"FutureTime", "2025-03-15T08:31:00Z", time.Time{}, true},
+		{"ZeroDays", "0d", time.Time{}, true},
+		{"NegativeDays", "-3d", time.Time{}, true},
+		{"NegativeDuration", "-2h", time.Time{}, true},
+		{"FractionalDays", "1.5d", time.Time{}, true},
+		{"Empty", "", time.Time{}, true},
+		{"Garbage", "yesterday", time.Time{}, true},
+		{"InvalidDate", "2024-02-30", time.Time{}, true},
+		{"OtherLayout", "30/06/2024", time.Time{}, true},
+		{"TimeWithoutZone", "2024-06-30T12:00:00", time.Time{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseAsOf(tt.in, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseAsOf(%q) error = %v, wantErr %t", tt.in, err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("parseAsOf(%q) = %s, want %s", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+// merge.go
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/report"
+	"github.com/rs/zerolog"
+)
+
+// runMerge implements "merge -o <merged.csv> <report.csv>...": it combines
+// partial reports, e.g. of shards, into one report with a merged manifest.
+// It works on local files only and does not contact IQ Server.
+func runMerge(args []string) int {
+	fs := flag.NewFlagSet("merge", flag.ContinueOnError)
+	out := fs.String("o", "", "path of the merged report (required)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: iqfetch merge -o <merged.csv> <report.csv|report.index.csv>...") //nolint:errcheck
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *out == "" || fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	logger := zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr}).With().Timestamp().Logger()
+	stats, err := report.MergeReports(context.Background(), *out, fs.Args(), logger)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "FATAL: %v\n", err) //nolint:errcheck
+		return 1
+	}
+	fmt.Printf("Merged %d reports into %s: %d rows, %d duplicates dropped\n", stats.Inputs, filepath.Clean(*out), stats.Rows, stats.Duplicates) //nolint:errcheck
+	if stats.Manifests < stats.Inputs {
+		fmt.Printf("%d of %d reports had no manifest; the merged manifest does not cover them\n", stats.Inputs-stats.Manifests, stats.Inputs) //nolint:errcheck
+	}
+	return 0
+}
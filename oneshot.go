@@ -0,0 +1,77 @@
+// oneshot.go
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/client"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/report"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/services"
+)
+
+// Exit codes of a --oneshot run, distinct per failure category so that
+// alerting (e.g. on Kubernetes CronJob failures) can tell them apart.
+const (
+	exitOK          = 0
+	exitFailure     = 1 // any other failure
+	exitPartial     = 3 // report written, but some applications failed
+	exitAuth        = 4
+	exitUnavailable = 5 // IQ Server unreachable, timing out or failing
+	exitSanity      = 6
+	exitLocked      = 7
+	exitDiskSpace   = 8
+	exitBlackout    = 9
+)
+
+// oneshotResult is the content of the --oneshot result file.
+type oneshotResult struct {
+	ExitCode   int    `json:"exitCode"`
+	ExitReason string `json:"exitReason"`
+	services.RunStats
+}
+
+// exitCode maps the outcome of a run to its --oneshot exit code and a short
+// reason. path is the report written, if any.
+func exitCode(path string, err error) (int, string) {
+	switch {
+	case err == nil:
+		return exitOK, "ok"
+	case errors.Is(err, report.ErrLocked):
+		return exitLocked, "locked"
+	case errors.Is(err, client.ErrAuth):
+		return exitAuth, "auth"
+	case errors.Is(err, services.ErrSanityCheck):
+		return exitSanity, "sanity_check"
+	case errors.Is(err, services.ErrInsufficientDiskSpace):
+		return exitDiskSpace, "disk_space"
+	case errors.Is(err, services.ErrBlackout):
+		return exitBlackout, "blackout"
+	case path != "":
+		return exitPartial, "partial"
+	case errors.Is(err, client.ErrNetwork), errors.Is(err, client.ErrTimeout),
+		errors.Is(err, client.ErrServer), errors.Is(err, context.DeadlineExceeded):
+		return exitUnavailable, "unavailable"
+	default:
+		return exitFailure, "failed"
+	}
+}
+
+// writeResult writes the --oneshot result file at path.
+func writeResult(path string, res oneshotResult) error {
+	data, err := json.MarshalIndent(res, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode result: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("prepare result dir: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("write result: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+// oneshot_test.go
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/client"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/report"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/services"
+)
+
+func TestExitCode(t *testing.T) {
+	auth := fmt.Errorf("app-1: %w", &client.Error{Kind: client.ErrAuth})
+	server := fmt.Errorf("app-2: %w", &client.Error{Kind: client.ErrServer})
+	fetchErrs := func(errs ...error) error {
+		return fmt.Errorf("encountered errors while fetching reports: %w", errors.Join(errs...))
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		err        error
+		wantCode   int
+		wantReason string
+	}{
+		{"OK", "report.csv", nil, exitOK, "ok"},
+		{"Failure", "", errors.New("boom"), exitFailure, "failed"},
+		{"Partial", "report.csv", fetchErrs(server), exitPartial, "partial"},
+		{"PartialUnclassified", "report.csv", fetchErrs(errors.New("boom")), exitPartial, "partial"},
+		{"Auth", "", auth, exitAuth, "auth"},
+		{"AuthAbovePartial", "report.csv", fetchErrs(server, auth), exitAuth, "auth"},
+		{"Unavailable", "", server, exitUnavailable, "unavailable"},
+		{"Network", "", &client.Error{Kind: client.ErrNetwork}, exitUnavailable, "unavailable"},
+		{"Timeout", "", &client.Error{Kind: client.ErrTimeout}, exitUnavailable, "unavailable"},
+		{"Deadline", "", fmt.Errorf("list applications: %w", context.DeadlineExceeded), exitUnavailable, "unavailable"},
+		{"RateLimited", "", &client.Error{Kind: client.ErrRateLimited}, exitFailure, "failed"},
+		{"Sanity", "", services.ErrSanityCheck, exitSanity, "sanity_check"},
+		{"SanityAbovePartial", "report.csv", errors.Join(services.ErrSanityCheck, server), exitSanity, "sanity_check"},
+		{"AuthAboveSanity", "", errors.Join(services.ErrSanityCheck, auth), exitAuth, "auth"},
+		{"Locked", "", report.ErrLocked, exitLocked, "locked"},
+		{"LockedAboveAuth", "", errors.Join(auth, report.ErrLocked), exitLocked, "locked"},
+		{"DiskSpace", "", services.ErrInsufficientDiskSpace, exitDiskSpace, "disk_space"},
+		{"DiskSpaceAboveUnavailable", "", errors.Join(server, services.ErrInsufficientDiskSpace), exitDiskSpace, "disk_space"},
+		{"Blackout", "", services.ErrBlackout, exitBlackout, "blackout"},
+		{"BlackoutAbovePartial", "report.csv", errors.Join(services.ErrBlackout, server), exitBlackout, "blackout"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, reason := exitCode(tt.path, tt.err)
+			if code != tt.wantCode || reason != tt.wantReason {
+				t.Errorf("exitCode() = %d, %q; want %d, %q", code, reason, tt.wantCode, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestWriteResult(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name string
+		path string
+		err  error
+	}{
+		{"OK", "", nil},
+		{"Partial", "report.csv", fmt.Errorf("fetch: %w", &client.Error{Kind: client.ErrServer})},
+		{"Auth", "", &client.Error{Kind: client.ErrAuth}},
+		{"Locked", "", report.ErrLocked},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := oneshotResult{RunStats: services.RunStats{ReportPath: tt.path, Applications: 2, Failed: 1}}
+			res.ExitCode, res.ExitReason = exitCode(tt.path, tt.err)
+			dest := filepath.Join(dir, tt.name, "result.json")
+			if err := writeResult(dest, res); err != nil {
+				t.Fatalf("writeResult: %v", err)
+			}
+			data, err := os.ReadFile(dest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasSuffix(string(data), "}\n") {
+				t.Errorf("result file does not end with a newline: %q", data)
+			}
+			var got oneshotResult
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatalf("decode result: %v", err)
+			}
+			if got.ExitCode != res.ExitCode || got.ExitReason != res.ExitReason || got.ReportPath != tt.path || got.Applications != 2 || got.Failed != 1 {
+				t.Errorf("result = %+v, want %+v", got, res)
+			}
+		})
+	}
+
+	// The result directory cannot be created below a file
+	blocker := filepath.Join(dir, "file")
+	if err := os.WriteFile(blocker, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeResult(filepath.Join(blocker, "result.json"), oneshotResult{}); err == nil {
+		t.Error("expected error when the result directory cannot be created")
+	}
+}
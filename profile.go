@@ -0,0 +1,46 @@
+// profile.go
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+)
+
+// startProfiling starts a CPU profile written to dir/cpu.pprof. The returned
+// function stops it and writes a heap profile to dir/heap.pprof; it must be
+// called once the work to be measured has finished.
+func startProfiling(dir string) (func() error, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create profile dir: %w", err)
+	}
+	cpuFile, err := os.Create(filepath.Join(dir, "cpu.pprof"))
+	if err != nil {
+		return nil, fmt.Errorf("create cpu profile: %w", err)
+	}
+	if err := pprof.StartCPUProfile(cpuFile); err != nil {
+		_ = cpuFile.Close()
+		return nil, fmt.Errorf("start cpu profile: %w", err)
+	}
+
+	return func() error {
+		pprof.StopCPUProfile()
+		if err := cpuFile.Close(); err != nil {
+			return fmt.Errorf("close cpu profile: %w", err)
+		}
+
+		heapFile, err := os.Create(filepath.Join(dir, "heap.pprof"))
+		if err != nil {
+			return fmt.Errorf("create heap profile: %w", err)
+		}
+		defer heapFile.Close() //nolint:errcheck
+		// Collect first for up-to-date statistics of live objects
+		runtime.GC()
+		if err := pprof.Lookup("allocs").WriteTo(heapFile, 0); err != nil {
+			return fmt.Errorf("write heap profile: %w", err)
+		}
+		return heapFile.Close()
+	}, nil
+}
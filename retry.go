@@ -0,0 +1,64 @@
+// retry.go
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/client"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/services"
+	"github.com/rs/zerolog/log"
+)
+
+// retryPolicy repeats runs failing while IQ Server is unavailable, e.g. in
+// a maintenance window, instead of leaving the report to the next scheduled
+// run.
+type retryPolicy struct {
+	Interval time.Duration // zero never retries
+	Window   time.Duration // measured from the first attempt
+	// Blackouts postpone attempts that would start in one to its end.
+	Blackouts []services.BlackoutWindow
+}
+
+// delay returns how long to wait before the next attempt of a run that
+// returned path and err, and false when the run is not retried: it
+// succeeded, wrote a report, failed for another reason than unavailability,
+// or the next attempt would start after the window. A Retry-After delay of
+// the server takes precedence over the interval, and an attempt that would
+// start in a blackout window waits for its end.
+func (p retryPolicy) delay(path string, err error, start, now time.Time) (time.Duration, bool) {
+	if p.Interval <= 0 || err == nil {
+		return 0, false
+	}
+	if code, _ := exitCode(path, err); code != exitUnavailable {
+		return 0, false
+	}
+	wait := p.Interval
+	if d := client.RetryAfter(err); d > 0 {
+		wait = d
+	}
+	if _, end, ok := services.ActiveBlackout(p.Blackouts, now.Add(wait)); ok {
+		wait = end.Sub(now)
+	}
+	if now.Add(wait).After(start.Add(p.Window)) {
+		return 0, false
+	}
+	return wait, true
+}
+
+// generateWithRetry generates the latest policy report, retrying per policy
+// with a fresh context from newCtx for each attempt.
+func generateWithRetry(svc *services.IQReportService, newCtx func() (context.Context, context.CancelFunc), filename string, policy retryPolicy) (string, error) {
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		ctx, cancel := newCtx()
+		path, err := svc.GenerateLatestPolicyReport(ctx, filename)
+		cancel()
+		wait, ok := policy.delay(path, err, start, time.Now())
+		if !ok {
+			return path, err
+		}
+		log.Warn().Err(err).Int("attempt", attempt).Dur("retryIn", wait).Msg("IQ Server unavailable; retrying the run")
+		time.Sleep(wait)
+	}
+}
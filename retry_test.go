@@ -0,0 +1,55 @@
+// retry_test.go
+package main
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/client"
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/services"
+)
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	blackouts, err := services.ParseBlackoutWindows([]string{"0 2 * * * 1h"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2025, 3, 1, 1, 0, 0, 0, time.Local)
+	unavailable := fmt.Errorf("list applications: %w", &client.Error{Kind: client.ErrServer})
+	retryAfter := fmt.Errorf("list applications: %w", &client.Error{Kind: client.ErrServer, RetryAfter: 10 * time.Minute})
+	policy := retryPolicy{Interval: 5 * time.Minute, Window: time.Hour}
+
+	tests := []struct {
+		name   string
+		policy retryPolicy
+		path   string
+		err    error
+		now    time.Time
+		want   time.Duration
+		retry  bool
+	}{
+		{"Success", policy, "report.csv", nil, start, 0, false},
+		{"Disabled", retryPolicy{Window: time.Hour}, "", unavailable, start, 0, false},
+		{"OtherFailure", policy, "", errors.New("boom"), start, 0, false},
+		{"Auth", policy, "", &client.Error{Kind: client.ErrAuth}, start, 0, false},
+		{"ReportWritten", policy, "report.csv", unavailable, start, 0, false},
+		{"Unavailable", policy, "", unavailable, start, 5 * time.Minute, true},
+		{"Timeout", policy, "", &client.Error{Kind: client.ErrTimeout}, start.Add(30 * time.Minute), 5 * time.Minute, true},
+		{"RetryAfter", policy, "", retryAfter, start, 10 * time.Minute, true},
+		{"LastAttemptInWindow", policy, "", unavailable, start.Add(55 * time.Minute), 5 * time.Minute, true},
+		{"WindowExhausted", policy, "", unavailable, start.Add(56 * time.Minute), 0, false},
+		{"RetryAfterPastWindow", policy, "", retryAfter, start.Add(51 * time.Minute), 0, false},
+		{"Blackout", retryPolicy{Interval: 5 * time.Minute, Window: 2 * time.Hour, Blackouts: blackouts}, "", unavailable, start.Add(57 * time.Minute), 63 * time.Minute, true},
+		{"BlackoutPastWindow", retryPolicy{Interval: 5 * time.Minute, Window: time.Hour, Blackouts: blackouts}, "", unavailable, start.Add(57 * time.Minute), 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, retry := tt.policy.delay(tt.path, tt.err, start, tt.now)
+			if got != tt.want || retry != tt.retry {
+				t.Errorf("delay() = %s, %t; want %s, %t", got, retry, tt.want, tt.retry)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+// version.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/client"
+)
+
+// Build metadata, set with -ldflags "-X main.version=... -X main.commit=...
+// -X main.buildDate=..." (see Makefile). Commit and date fall back to the VCS
+// information embedded by the Go toolchain.
+var (
+	version   = "dev"
+	commit    = ""
+	buildDate = ""
+)
+
+// runVersion implements "version [--server]": it prints the build metadata
+// and, with --server, the IQ Server release and whether it is supported.
+func runVersion(args []string) int {
+	fs := flag.NewFlagSet("version", flag.ContinueOnError)
+	server := fs.Bool("server", false, "also query the IQ Server version and check compatibility")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	fmt.Println(buildInfo()) //nolint:errcheck
+	if !*server {
+		return 0
+	}
+
+	cfg, pool, cleanup, err := setup(nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "FATAL: %v\n", err) //nolint:errcheck
+		return 1
+	}
+	defer cleanup()
+
+	ctx, cancel := fetchContext(cfg)
+	defer cancel()
+	v, err := pool.Default().GetServerVersion(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "IQ Server at %s: version unavailable: %v\n", cfg.IQServerURL, err) //nolint:errcheck
+		return 1
+	}
+	fmt.Printf("IQ Server %s at %s\n", v.Version, cfg.IQServerURL) //nolint:errcheck
+	if !v.Compatible() {
+		fmt.Printf("Compatibility: not supported, requires IQ Server %s or newer\n", client.MinServerVersion) //nolint:errcheck
+		return 1
+	}
+	fmt.Printf("Compatibility: supported (requires IQ Server %s or newer)\n", client.MinServerVersion) //nolint:errcheck
+	return 0
+}
+
+// buildInfo returns a one-line description of the binary, e.g.
+// "iqfetch v1.4.0 (commit 1a2b3c4, built 2025-01-15T10:00:00Z) go1.25.1 linux/amd64".
+func buildInfo() string {
+	rev, date, dirty := commit, buildDate, false
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range info.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				if rev == "" {
+					rev = s.Value
+				}
+			case "vcs.time":
+				if date == "" {
+					date = s.Value
+				}
+			case "vcs.modified":
+				dirty = s.Value == "true"
+			}
+		}
+	}
+	if rev == "" {
+		rev = "unknown"
+	}
+	if len(rev) > 7 {
+		rev = rev[:7]
+	}
+	if dirty {
+		rev += "-dirty"
+	}
+	if date == "" {
+		date = "unknown"
+	}
+	return fmt.Sprintf("iqfetch %s (commit %s, built %s) %s %s/%s", version, rev, date, runtime.Version(), runtime.GOOS, runtime.GOARCH)
+}
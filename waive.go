@@ -0,0 +1,72 @@
+// waive.go
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/anmicius0/iqserver-report-fetch-go/internal/services"
+	"github.com/rs/zerolog/log"
+)
+
+// runWaive implements "waive [--apply] <file>": it checks the waivers of a
+// reviewed bulk waiver file against IQ Server and, with --apply, creates
+// them. Without --apply nothing is changed.
+func runWaive(args []string) int {
+	fs := flag.NewFlagSet("waive", flag.ContinueOnError)
+	apply := fs.Bool("apply", false, "create the waivers; without it the file is only checked (dry run)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: iqfetch waive [--apply] <file.csv|file.yaml>") //nolint:errcheck
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	waivers, err := services.LoadBulkWaivers(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "FATAL: %v\n", err) //nolint:errcheck
+		return 1
+	}
+
+	cfg, pool, cleanup, err := setup(os.Stdout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "FATAL: %v\n", err) //nolint:errcheck
+		return 1
+	}
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	reportService := services.NewIQReportServiceWithPool(cfg, pool, log.Logger)
+	results, err := reportService.ApplyBulkWaivers(ctx, waivers, *apply)
+
+	counts := make(map[string]int)
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "STATUS\tAPPLICATION\tVIOLATION\tSCOPE\tEXPIRES") //nolint:errcheck
+	for _, r := range results {
+		counts[r.Status]++
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", r.Status, r.Waiver.Application, r.Waiver.ViolationID, r.Waiver.Scope, r.Waiver.Expires) //nolint:errcheck
+	}
+	_ = tw.Flush()
+
+	fmt.Printf("%d planned, %d applied, %d already waived, %d failed\n", //nolint:errcheck
+		counts[services.WaiverPlanned], counts[services.WaiverApplied], counts[services.WaiverExisting], counts[services.WaiverFailed])
+	if !*apply && err == nil {
+		fmt.Println("Dry run: nothing was changed. Re-run with --apply to create the waivers.") //nolint:errcheck
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("bulk waivers failed")
+		return 1
+	}
+	return 0
+}